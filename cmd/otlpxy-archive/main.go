@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"zep-logger/internal/archive"
+	"zep-logger/pkg/logger"
+)
+
+// otlpxy-archive exports spooled/archived payloads into a portable bundle for
+// offline transfer, and imports a bundle back into a spool directory. This is
+// intended for air-gapped environments that ship telemetry on physical media
+// rather than over the network.
+func main() {
+	if len(os.Args) < 4 {
+		usage()
+		os.Exit(1)
+	}
+
+	cmd := os.Args[1]
+	src := os.Args[2]
+	dst := os.Args[3]
+
+	switch cmd {
+	case "export":
+		if err := archive.Export(src, dst); err != nil {
+			logger.Fatal("Failed to export bundle: %v", err)
+		}
+		logger.Info("Exported spool %s to bundle %s", src, dst)
+	case "import":
+		if err := archive.Import(src, dst); err != nil {
+			logger.Fatal("Failed to import bundle: %v", err)
+		}
+		logger.Info("Imported bundle %s into spool %s", src, dst)
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: otlpxy-archive export <spoolDir> <bundle.tar>")
+	fmt.Fprintln(os.Stderr, "       otlpxy-archive import <bundle.tar> <spoolDir>")
+}
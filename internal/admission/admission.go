@@ -0,0 +1,96 @@
+// Package admission implements cost-aware load shedding for the async
+// forwarding path: as a forwarder's queue fills, the most expensive pending
+// requests are rejected first instead of every request uniformly getting a
+// 503 the instant the queue is completely full.
+package admission
+
+import "math/rand"
+
+const (
+	// costFloor is the cost always admitted, even a moment before the queue
+	// hits maxQueueDepth and the hard queue-full rejection takes over
+	costFloor = 4 * 1024
+	// costCeiling is the cost shed as soon as the queue holds anything at all
+	costCeiling = 4 * 1024 * 1024
+)
+
+// Controller decides whether an incoming request should be shed before it
+// reaches the forwarder's queue, based on the request's estimated cost and
+// how saturated the queue already is
+type Controller struct {
+	maxQueueDepth int
+
+	shedThreshold   float64
+	shedProbability float64
+}
+
+// NewController creates a Controller that sheds requests as queueDepth
+// approaches maxQueueDepth. maxQueueDepth <= 0 disables shedding: every
+// request is admitted, leaving the forwarder's own queue-full check as the
+// only backpressure (the original behavior).
+func NewController(maxQueueDepth int) *Controller {
+	return &Controller{maxQueueDepth: maxQueueDepth}
+}
+
+// EstimateCost scores a request for shedding purposes from its body size and
+// whether it's part of a synchronous (session-replay-critical) request.
+// Async fire-and-forget traffic costs twice as much as an equivalently-sized
+// sync one, so it's shed first under pressure - matching the existing
+// priority given to logs (sync-capable) over traces (always async) elsewhere
+// in the proxy handler
+func EstimateCost(bodyBytes int, sync bool) int {
+	cost := bodyBytes
+	if !sync {
+		cost *= 2
+	}
+	return cost
+}
+
+// ShouldShed reports whether a request of the given cost should be rejected
+// before being queued, given the forwarder's current depth. Saturation rises
+// linearly from 0% (empty queue) to 100% (queueDepth == maxQueueDepth); the
+// maximum admissible cost falls linearly from costCeiling at 0% saturation to
+// costFloor at 100%, so expensive requests are shed progressively as the
+// queue fills rather than admission flipping uniformly from "accept
+// everything" to "reject everything"
+func (c *Controller) ShouldShed(queueDepth int, cost int) bool {
+	if c.maxQueueDepth <= 0 {
+		return false
+	}
+	if queueDepth >= c.maxQueueDepth {
+		return true
+	}
+
+	saturation := float64(queueDepth) / float64(c.maxQueueDepth)
+	maxAdmissibleCost := costFloor + (1-saturation)*(costCeiling-costFloor)
+	return float64(cost) > maxAdmissibleCost
+}
+
+// SetProbabilisticShedding configures a cost-blind coin-flip shed, checked by
+// ShouldProbabilisticallyShed on top of ShouldShed's cost-based check: once the queue crosses
+// thresholdFraction of maxQueueDepth (e.g. 0.8 for 80%), a fraction
+// dropProbability of requests (e.g. 0.2 for 20%) are shed regardless of
+// their individual cost. Unlike ShouldShed, this doesn't spare cheap
+// requests - it exists for traffic classes (fire-and-forget trace payloads)
+// where degrading gracefully by volume matters more than degrading by
+// request cost. thresholdFraction <= 0 or dropProbability <= 0 disables it
+// (the original behavior: only ShouldShed's cost-based check applies).
+func (c *Controller) SetProbabilisticShedding(thresholdFraction, dropProbability float64) {
+	c.shedThreshold = thresholdFraction
+	c.shedProbability = dropProbability
+}
+
+// ShouldProbabilisticallyShed reports whether a request should be shed under
+// the coin-flip policy configured via SetProbabilisticShedding, given the
+// forwarder's current queue depth. Always false when disabled, when
+// maxQueueDepth isn't configured, or below the configured threshold.
+func (c *Controller) ShouldProbabilisticallyShed(queueDepth int) bool {
+	if c.maxQueueDepth <= 0 || c.shedThreshold <= 0 || c.shedProbability <= 0 {
+		return false
+	}
+	saturation := float64(queueDepth) / float64(c.maxQueueDepth)
+	if saturation < c.shedThreshold {
+		return false
+	}
+	return rand.Float64() < c.shedProbability
+}
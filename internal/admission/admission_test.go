@@ -0,0 +1,83 @@
+package admission
+
+import "testing"
+
+// TestController_ShouldShed_DisabledWhenMaxQueueDepthIsZero verifies shedding
+// is a no-op unless a positive maxQueueDepth is configured
+func TestController_ShouldShed_DisabledWhenMaxQueueDepthIsZero(t *testing.T) {
+	c := NewController(0)
+	if c.ShouldShed(1_000_000, EstimateCost(10*1024*1024, false)) {
+		t.Error("expected shedding disabled when maxQueueDepth is 0")
+	}
+}
+
+// TestController_ShouldShed_AdmitsSmallRequestsAtLowSaturation verifies a
+// cheap request is admitted even with pending queue depth
+func TestController_ShouldShed_AdmitsSmallRequestsAtLowSaturation(t *testing.T) {
+	c := NewController(1000)
+	cost := EstimateCost(100, true)
+	if c.ShouldShed(10, cost) {
+		t.Error("expected a small request to be admitted at low saturation")
+	}
+}
+
+// TestController_ShouldShed_ShedsExpensiveRequestsBeforeQueueIsFull verifies
+// large requests are shed before the queue reaches maxQueueDepth
+func TestController_ShouldShed_ShedsExpensiveRequestsBeforeQueueIsFull(t *testing.T) {
+	c := NewController(1000)
+	cost := EstimateCost(10*1024*1024, false)
+	if !c.ShouldShed(500, cost) {
+		t.Error("expected an oversized request to be shed at 50% saturation")
+	}
+}
+
+// TestController_ShouldShed_AlwaysShedsAtOrAboveMaxQueueDepth verifies the
+// hard cutoff regardless of cost
+func TestController_ShouldShed_AlwaysShedsAtOrAboveMaxQueueDepth(t *testing.T) {
+	c := NewController(1000)
+	if !c.ShouldShed(1000, EstimateCost(1, true)) {
+		t.Error("expected even a tiny request to be shed once queueDepth reaches maxQueueDepth")
+	}
+}
+
+// TestEstimateCost_AsyncCostsMoreThanEquivalentSync verifies async traffic is
+// weighted higher so it's shed before sync traffic of the same size
+func TestEstimateCost_AsyncCostsMoreThanEquivalentSync(t *testing.T) {
+	syncCost := EstimateCost(1024, true)
+	asyncCost := EstimateCost(1024, false)
+	if asyncCost <= syncCost {
+		t.Errorf("expected async cost (%d) to exceed sync cost (%d) for the same body size", asyncCost, syncCost)
+	}
+}
+
+// TestController_ShouldProbabilisticallyShed_DisabledByDefault verifies the
+// coin-flip check never sheds unless SetProbabilisticShedding is called
+func TestController_ShouldProbabilisticallyShed_DisabledByDefault(t *testing.T) {
+	c := NewController(1000)
+	for i := 0; i < 100; i++ {
+		if c.ShouldProbabilisticallyShed(999) {
+			t.Fatal("expected probabilistic shedding disabled until configured")
+		}
+	}
+}
+
+// TestController_ShouldProbabilisticallyShed_NeverShedsBelowThreshold
+// verifies the coin flip only applies once saturation crosses the
+// configured threshold
+func TestController_ShouldProbabilisticallyShed_NeverShedsBelowThreshold(t *testing.T) {
+	c := NewController(1000)
+	c.SetProbabilisticShedding(0.8, 1.0) // 100% drop rate above 80% saturation
+	if c.ShouldProbabilisticallyShed(799) {
+		t.Error("expected no shedding just below the 80% threshold")
+	}
+}
+
+// TestController_ShouldProbabilisticallyShed_AlwaysShedsAtFullDropRate
+// verifies a 100% drop rate sheds every request once past the threshold
+func TestController_ShouldProbabilisticallyShed_AlwaysShedsAtFullDropRate(t *testing.T) {
+	c := NewController(1000)
+	c.SetProbabilisticShedding(0.8, 1.0)
+	if !c.ShouldProbabilisticallyShed(800) {
+		t.Error("expected shedding at exactly the 80% threshold with a 100% drop rate")
+	}
+}
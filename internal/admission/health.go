@@ -0,0 +1,121 @@
+package admission
+
+import (
+    "math/rand"
+    "sync"
+    "time"
+)
+
+// healthEWMAWeight is the smoothing factor applied to each new latency/error
+// sample: higher weight makes the score react faster to a collector's
+// recent behavior, at the cost of more jitter from a single slow request
+const healthEWMAWeight = 0.2
+
+// HealthScorer combines a forwarding target's recent latency and error rate,
+// plus a simple consecutive-failure breaker, into a single 0 (down) to 1
+// (fully healthy) score. ShouldShedForHealth uses the score to reject a
+// growing fraction of requests as the collector degrades, instead of every
+// request succeeding right up until a hard cutover to rejecting everything.
+type HealthScorer struct {
+    mu sync.Mutex
+
+    latencyThreshold time.Duration
+    breakerThreshold int
+    breakerCooldown  time.Duration
+
+    latencyEWMA         float64 // seconds
+    errorEWMA           float64 // 0-1
+    consecutiveFailures int
+    breakerOpenUntil    time.Time
+}
+
+// NewHealthScorer creates a HealthScorer. latencyThreshold is the response
+// time at or above which a request counts as fully unhealthy for the latency
+// component of the score. breakerThreshold is the number of consecutive
+// failures that trips the breaker (score forced to 0 for breakerCooldown);
+// breakerThreshold <= 0 disables the breaker, leaving latency/error rate as
+// the only signals.
+func NewHealthScorer(latencyThreshold time.Duration, breakerThreshold int, breakerCooldown time.Duration) *HealthScorer {
+    if latencyThreshold <= 0 {
+        latencyThreshold = 2 * time.Second
+    }
+    if breakerCooldown <= 0 {
+        breakerCooldown = 30 * time.Second
+    }
+    return &HealthScorer{
+        latencyThreshold: latencyThreshold,
+        breakerThreshold: breakerThreshold,
+        breakerCooldown:  breakerCooldown,
+    }
+}
+
+// RecordSuccess folds a successful delivery's latency into the rolling
+// averages and resets the consecutive-failure count
+func (s *HealthScorer) RecordSuccess(latency time.Duration) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.latencyEWMA = ewma(s.latencyEWMA, latency.Seconds())
+    s.errorEWMA = ewma(s.errorEWMA, 0)
+    s.consecutiveFailures = 0
+}
+
+// RecordFailure folds a failed delivery into the rolling error rate and, once
+// breakerThreshold consecutive failures have been seen, trips the breaker so
+// Score reports 0 for breakerCooldown regardless of latency/error rate
+func (s *HealthScorer) RecordFailure() {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.errorEWMA = ewma(s.errorEWMA, 1)
+    s.consecutiveFailures++
+    if s.breakerThreshold > 0 && s.consecutiveFailures >= s.breakerThreshold {
+        s.breakerOpenUntil = time.Now().Add(s.breakerCooldown)
+    }
+}
+
+// Score returns the current health score in [0,1]: 0 means the breaker is
+// open (or the target is otherwise fully unhealthy), 1 means recent
+// deliveries have been fast and error-free
+func (s *HealthScorer) Score() float64 {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if !s.breakerOpenUntil.IsZero() && time.Now().Before(s.breakerOpenUntil) {
+        return 0
+    }
+
+    latencyRatio := s.latencyEWMA / s.latencyThreshold.Seconds()
+    if latencyRatio > 1 {
+        latencyRatio = 1
+    }
+    latencyHealth := 1 - latencyRatio
+    errorHealth := 1 - s.errorEWMA
+
+    score := (latencyHealth + errorHealth) / 2
+    if score < 0 {
+        return 0
+    }
+    if score > 1 {
+        return 1
+    }
+    return score
+}
+
+// ewma folds sample into prior using healthEWMAWeight
+func ewma(prior, sample float64) float64 {
+    return healthEWMAWeight*sample + (1-healthEWMAWeight)*prior
+}
+
+// ShouldShedForHealth probabilistically rejects a request based on a health
+// score in [0,1] (1 = fully healthy, 0 = down): the chance of shedding rises
+// as the score falls, so a degrading collector sheds a growing fraction of
+// traffic instead of every request succeeding right up until it cliff-edges
+// into a hard 503 once some threshold trips
+func ShouldShedForHealth(score float64) bool {
+    if score >= 1 {
+        return false
+    }
+    if score <= 0 {
+        return true
+    }
+    return rand.Float64() > score
+}
@@ -0,0 +1,107 @@
+package admission
+
+import (
+    "testing"
+    "time"
+)
+
+// TestHealthScorer_Score_StartsFullyHealthy verifies a scorer with no
+// recorded deliveries yet reports full health
+func TestHealthScorer_Score_StartsFullyHealthy(t *testing.T) {
+    s := NewHealthScorer(2*time.Second, 5, time.Second)
+    if score := s.Score(); score != 1 {
+        t.Errorf("expected a fresh scorer to report score 1, got %v", score)
+    }
+}
+
+// TestHealthScorer_Score_DegradesOnSlowSuccesses verifies latency above the
+// configured threshold pulls the score down even without any failures
+func TestHealthScorer_Score_DegradesOnSlowSuccesses(t *testing.T) {
+    s := NewHealthScorer(100*time.Millisecond, 5, time.Second)
+    for i := 0; i < 20; i++ {
+        s.RecordSuccess(500 * time.Millisecond)
+    }
+    if score := s.Score(); score >= 0.6 {
+        t.Errorf("expected sustained slow successes to degrade score below 0.6, got %v", score)
+    }
+}
+
+// TestHealthScorer_Score_DegradesOnFailures verifies a rising error rate
+// pulls the score down
+func TestHealthScorer_Score_DegradesOnFailures(t *testing.T) {
+    s := NewHealthScorer(2*time.Second, 100, time.Second)
+    for i := 0; i < 20; i++ {
+        s.RecordFailure()
+    }
+    if score := s.Score(); score >= 0.6 {
+        t.Errorf("expected sustained failures to degrade score below 0.6, got %v", score)
+    }
+}
+
+// TestHealthScorer_Score_RecoversAfterSuccessesResumeAfterFailures verifies
+// the score isn't permanently stuck low once deliveries start succeeding
+// again
+func TestHealthScorer_Score_RecoversAfterSuccessesResumeAfterFailures(t *testing.T) {
+    s := NewHealthScorer(2*time.Second, 100, time.Second)
+    for i := 0; i < 10; i++ {
+        s.RecordFailure()
+    }
+    degraded := s.Score()
+    for i := 0; i < 50; i++ {
+        s.RecordSuccess(10 * time.Millisecond)
+    }
+    if recovered := s.Score(); recovered <= degraded {
+        t.Errorf("expected score to recover above degraded value %v after resumed successes, got %v", degraded, recovered)
+    }
+}
+
+// TestHealthScorer_Score_ZeroWhileBreakerOpen verifies the breaker forces the
+// score to 0 once breakerThreshold consecutive failures trip it, even before
+// the error-rate EWMA alone would justify it
+func TestHealthScorer_Score_ZeroWhileBreakerOpen(t *testing.T) {
+    s := NewHealthScorer(2*time.Second, 3, time.Minute)
+    s.RecordFailure()
+    s.RecordFailure()
+    if score := s.Score(); score == 0 {
+        t.Fatalf("expected breaker to still be closed before reaching the threshold, got score %v", score)
+    }
+    s.RecordFailure()
+    if score := s.Score(); score != 0 {
+        t.Errorf("expected breaker to trip and force score to 0 after %d consecutive failures, got %v", 3, score)
+    }
+}
+
+// TestHealthScorer_Score_BreakerClosesAgainAfterCooldown verifies the breaker
+// releases once breakerCooldown elapses, letting the score reflect the
+// underlying signals again
+func TestHealthScorer_Score_BreakerClosesAgainAfterCooldown(t *testing.T) {
+    s := NewHealthScorer(2*time.Second, 1, 10*time.Millisecond)
+    s.RecordFailure()
+    if score := s.Score(); score != 0 {
+        t.Fatalf("expected breaker to be open immediately after tripping, got score %v", score)
+    }
+    time.Sleep(20 * time.Millisecond)
+    if score := s.Score(); score == 0 {
+        t.Errorf("expected breaker to have closed after cooldown elapsed, got score %v", score)
+    }
+}
+
+// TestShouldShedForHealth_NeverShedsAtFullHealth verifies a score of 1 is
+// always admitted
+func TestShouldShedForHealth_NeverShedsAtFullHealth(t *testing.T) {
+    for i := 0; i < 100; i++ {
+        if ShouldShedForHealth(1) {
+            t.Fatal("expected a fully healthy score to never be shed")
+        }
+    }
+}
+
+// TestShouldShedForHealth_AlwaysShedsAtZeroHealth verifies a score of 0 is
+// always rejected
+func TestShouldShedForHealth_AlwaysShedsAtZeroHealth(t *testing.T) {
+    for i := 0; i < 100; i++ {
+        if !ShouldShedForHealth(0) {
+            t.Fatal("expected a fully unhealthy score to always be shed")
+        }
+    }
+}
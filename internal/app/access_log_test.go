@@ -0,0 +1,68 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TestECSAccessLog_SuccessfulRequest_EmitsExpectedFields verifies a 2xx
+// response is logged with the ECS field names the SIEM integration relies on
+func TestECSAccessLog_SuccessfulRequest_EmitsExpectedFields(t *testing.T) {
+	var buf bytes.Buffer
+	e := echo.New()
+	e.Use(ecsAccessLogMiddlewareTo(&buf))
+	e.POST("/v1/logs", func(c echo.Context) error { return c.NoContent(http.StatusAccepted) })
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader([]byte("hello")))
+	req.Header.Set("User-Agent", "otel-sdk/1.0")
+	req.ContentLength = 5
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var entry ecsAccessLogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to unmarshal access log entry: %v, raw=%s", err, buf.String())
+	}
+
+	if entry.HTTP.Request.Body.Bytes != 5 {
+		t.Errorf("expected http.request.body.bytes=5, got %d", entry.HTTP.Request.Body.Bytes)
+	}
+	if entry.HTTP.Response.StatusCode != http.StatusAccepted {
+		t.Errorf("expected http.response.status_code=%d, got %d", http.StatusAccepted, entry.HTTP.Response.StatusCode)
+	}
+	if entry.UserAgent.Original != "otel-sdk/1.0" {
+		t.Errorf("expected user_agent.original=%q, got %q", "otel-sdk/1.0", entry.UserAgent.Original)
+	}
+	if entry.Event.Outcome != "success" {
+		t.Errorf("expected event.outcome=success for a 2xx response, got %q", entry.Event.Outcome)
+	}
+	if entry.Client.IP == "" {
+		t.Error("expected client.ip to be populated")
+	}
+}
+
+// TestECSAccessLog_ErrorResponse_ReportsFailureOutcome verifies a non-2xx
+// response is logged with event.outcome=failure
+func TestECSAccessLog_ErrorResponse_ReportsFailureOutcome(t *testing.T) {
+	var buf bytes.Buffer
+	e := echo.New()
+	e.Use(ecsAccessLogMiddlewareTo(&buf))
+	e.GET("/v1/logs", func(c echo.Context) error { return c.NoContent(http.StatusServiceUnavailable) })
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/logs", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var entry ecsAccessLogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to unmarshal access log entry: %v, raw=%s", err, buf.String())
+	}
+	if entry.Event.Outcome != "failure" {
+		t.Errorf("expected event.outcome=failure for a 503 response, got %q", entry.Event.Outcome)
+	}
+}
@@ -2,10 +2,15 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"reflect"
 	"syscall"
 	"time"
 
@@ -13,13 +18,36 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/atomic"
+	"golang.org/x/time/rate"
 
 	"zep-logger/internal/config"
+	"zep-logger/internal/handler/http/admin"
 	"zep-logger/internal/handler/http/health"
 	httpiface "zep-logger/internal/handler/http/interface"
 	"zep-logger/internal/handler/http/proxy"
+	"zep-logger/internal/admission"
+	"zep-logger/internal/canary"
+	"zep-logger/internal/deadletter"
+	"zep-logger/internal/dedup"
+	"zep-logger/internal/diagnostics"
+	"zep-logger/internal/diskqueue"
 	"zep-logger/internal/forwarder"
+	"zep-logger/internal/archivesink"
+	"zep-logger/internal/gcpauth"
+	"zep-logger/internal/geoip"
+	"zep-logger/internal/spoolcrypto"
+	"zep-logger/internal/httpclient"
+	"zep-logger/internal/ingesttoken"
+	"zep-logger/internal/jobtracking"
 	"zep-logger/internal/metrics"
+	"zep-logger/internal/powchallenge"
+	"zep-logger/internal/ratelimitpersist"
+	"zep-logger/internal/reconciler"
+	"zep-logger/internal/sampling"
+	"zep-logger/internal/tlspolicy"
+	"zep-logger/internal/transform"
+	"zep-logger/internal/usage"
+	"zep-logger/internal/watchdog"
 	"zep-logger/internal/worker"
 	"zep-logger/pkg/logger"
 )
@@ -30,14 +58,72 @@ type App struct {
 	echo         *echo.Echo
 	readiness    *atomic.Bool
 	httpHandlers []httpiface.HttpRouter
+    proxyHandler *proxy.ProxyHandler
+    nethttpServer *http.Server
+    metricsEcho   *echo.Echo // Dedicated /metrics + health listener when config.MetricsPort > 0 (nil otherwise, the original behavior)
+    healthHandler *health.HealthHandler // Set (instead of appended to httpHandlers) when config.MetricsPort > 0, so buildMetricsEcho can register it on the internal listener
     workerPool   *worker.Pool
+    upstreamDiagnostics *diagnostics.Buffer // Ring buffer of recent non-2xx collector responses for GET /admin/debug/upstream-errors (nil unless forwarding_mode = "pool" and upstream_error_buffer_size > 0)
+    jobTracker   *jobtracking.Tracker // Bounded per-job delivery status (queued/sent/failed) for GET /admin/debug/jobs/:id (nil unless forwarding_mode = "pool" and job_tracking_enabled)
+    loopbackForwarder *forwarder.LoopbackForwarder // Recorded payloads for GET /admin/debug/loopback (nil unless forwarding_mode = "loopback")
     forwarder    forwarder.Forwarder
+    forwarderOverridden bool // Set by WithForwarder, so injectDependency doesn't overwrite the supplied forwarder with one built from forwarding_mode
+    signalForwarders []forwarder.Forwarder
+    watchdog     *watchdog.Watchdog
+    canary       *canary.Canary
+    reconciler   *reconciler.Reconciler
+    tailSampler  *sampling.Buffer
+    usageAccountant *usage.Accountant
+    rateLimitPersisters []*ratelimitpersist.Persister // Periodically snapshot a rate limiter's remaining tokens to disk (empty unless rate_limit_state_file and/or ingest_byte_rate_limit_state_file are set)
+    deduper      *dedup.Deduper // Suppresses a payload seen again within its sliding window before it's queued (nil unless dedup_enabled)
+    powVerifier  *powchallenge.Verifier // Verifies proof-of-work challenges on /v1/logs and /v1/traces (nil unless pow_challenge_enabled)
+    geoReader    *geoip.Reader // Resolves the client IP to a country for GeoIP-based routing/blocking (nil unless geoip_enabled)
+    ctx          context.Context // Application run context, canceled only at shutdown; passed to Forwarder.Submit so in-flight/queued deliveries abort at shutdown instead of outliving it
 	cancel       context.CancelFunc
 }
 
+// Option configures an App after its config-driven defaults are in place but
+// before injectDependency runs, so it can override a piece that would
+// otherwise be built from config alone. Options apply in the order given.
+type Option func(*App)
+
+// WithForwarder overrides the forwarder injectDependency would otherwise
+// build from forwarding_mode, so tests and embedders can inject a fake
+// instead of a concrete implementation wired from config. The
+// batching/mirroring/archive/backpressure decorators (and per-signal
+// overrides) still wrap the supplied forwarder, same as they would a
+// config-selected one.
+func WithForwarder(f forwarder.Forwarder) Option {
+	return func(a *App) {
+		a.forwarder = f
+		a.forwarderOverridden = true
+	}
+}
+
+// WithLogger redirects pkg/logger's output to w instead of the process's
+// stdout/stderr, so tests can assert on log lines instead of them going to
+// the test binary's own output.
+func WithLogger(w io.Writer) Option {
+	return func(a *App) {
+		logger.SetOutput(w)
+	}
+}
+
+// WithListener overrides the net.Listener the primary Echo server binds to
+// instead of creating its own via net.Listen on server_port, so tests can
+// run the server on a listener they control (e.g. one bound to an ephemeral
+// port) without touching config.
+func WithListener(l net.Listener) Option {
+	return func(a *App) {
+		a.echo.Listener = l
+	}
+}
+
 // NewApp creates a new App instance with the given configuration
 // Follows constructor injection pattern - all dependencies passed via parameters
-func NewApp(cfg *config.Config) *App {
+func NewApp(cfg *config.Config, opts ...Option) *App {
+	fillConfigDefaults(cfg)
+
 	e := echo.New()
 	e.HideBanner = true
 	e.HidePort = true
@@ -46,37 +132,672 @@ func NewApp(cfg *config.Config) *App {
 		config:    cfg,
 		echo:      e,
 		readiness: atomic.NewBool(false),
+		ctx:       context.Background(),
+	}
+
+	for _, opt := range opts {
+		opt(app)
 	}
 
 	return app
 }
 
+// fillConfigDefaults overlays config.Defaults() onto any zero-valued field of
+// cfg. Load() always fills in a full set of defaults via viper, but a Config
+// built directly - by an embedding user, or by a test constructing one
+// inline - has no such pass, so a forgotten field (a timeout, a pool size, a
+// queue depth) silently runs at Go's zero value instead of the default
+// Load() would have applied.
+func fillConfigDefaults(cfg *config.Config) {
+	defaults := config.Defaults()
+	dst := reflect.ValueOf(cfg).Elem()
+	src := reflect.ValueOf(defaults)
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Field(i)
+		if field.IsZero() {
+			field.Set(src.Field(i))
+		}
+	}
+}
+
+// httpClientConfig builds the shared HTTP client config from the configured
+// forwarder timeout knobs, for use by any forwarder or worker pool that talks
+// to the upstream collector over HTTP. A zero-valued field leaves the
+// corresponding httpclient default in place
+func (a *App) httpClientConfig() httpclient.Config {
+    tlsConfig, err := tlspolicy.Build(tlspolicy.Config{
+        MinVersion:       a.config.ForwarderTLSMinVersion,
+        CipherSuites:     a.config.ForwarderTLSCipherSuites,
+        CurvePreferences: a.config.ForwarderTLSCurvePreferences,
+        ClientCertFile:   a.config.ForwarderTLSClientCertFile,
+        ClientKeyFile:    a.config.ForwarderTLSClientKeyFile,
+    })
+    if err != nil {
+        // Config loading already replaces an unbuildable policy with the
+        // TLS 1.2 defaults, so this
+        // only guards against a build-tag mismatch (e.g. a fips-approved
+        // config loaded into a non-fips binary at a stricter policy).
+        logger.Error("Failed to build forwarder TLS policy, using Go's default: %v", err)
+        tlsConfig = nil
+    }
+    return httpclient.Config{
+        RequestTimeout:      time.Duration(a.config.ForwarderRequestTimeoutSeconds) * time.Second,
+        DialTimeout:         time.Duration(a.config.ForwarderDialTimeoutSeconds) * time.Second,
+        TLSHandshakeTimeout: time.Duration(a.config.ForwarderTLSHandshakeTimeoutSeconds) * time.Second,
+        IdleConnTimeout:     time.Duration(a.config.ForwarderIdleConnTimeoutSeconds) * time.Second,
+        TLSClientConfig:     tlsConfig,
+        MaxIdleConnsPerHost: a.config.ForwarderMaxIdleConnsPerHost,
+        MaxConnsPerHost:     a.config.ForwarderMaxConnsPerHost,
+        DisableHTTP2:        a.config.ForwarderDisableHTTP2,
+    }
+}
+
+// orDefault returns override if it's set (> 0), else fallback. Used to resolve
+// per-signal forwarder knobs (logs_worker_pool_size, etc.) against the shared
+// default when the signal-specific value is left unconfigured
+func orDefault(override int, fallback int) int {
+    if override > 0 {
+        return override
+    }
+    return fallback
+}
+
+// spoolEncryptor builds the shared spool encryption key set from config, or
+// returns nil if spool_encryption_enabled is false. Shared by the disk
+// queue, dead-letter store, and archive sink, so a single key rotation
+// (spool_encryption_active_key_id / spool_encryption_keys) applies
+// everywhere raw payload bytes touch disk.
+func (a *App) spoolEncryptor() *spoolcrypto.KeySet {
+    if !a.config.SpoolEncryptionEnabled {
+        return nil
+    }
+    keySet, err := spoolcrypto.NewKeySet(a.config.SpoolEncryptionActiveKeyID, a.config.SpoolEncryptionKeys)
+    if err != nil {
+        logger.Error("Failed to initialize spool encryption, leaving spool/capture files in plaintext: %v", err)
+        return nil
+    }
+    return keySet
+}
+
+// applyHooks wraps f with forwarder.NewHookForwarder if any hooks have been
+// compiled in via forwarder.RegisterHook, leaving f untouched otherwise so
+// the common case (no hooks registered) adds no extra layer.
+func (a *App) applyHooks(f forwarder.Forwarder) forwarder.Forwarder {
+    hooks := forwarder.RegisteredHooks()
+    if len(hooks) == 0 {
+        return f
+    }
+    return forwarder.NewHookForwarder(f, hooks)
+}
+
+// applyTenantConcurrencyLimit configures per-tenant concurrency isolation on
+// fwd if it's a SemaphoreForwarder or HybridForwarder and
+// tenant_concurrency_max_per_tenant is set; a no-op for every other
+// forwarder type or if the config is unset, since concurrency isolation
+// only makes sense where the forwarder itself manages a concurrency
+// semaphore.
+func (a *App) applyTenantConcurrencyLimit(fwd forwarder.Forwarder) {
+    if a.config.TenantConcurrencyMaxPerTenant <= 0 {
+        return
+    }
+    switch f := fwd.(type) {
+    case *forwarder.SemaphoreForwarder:
+        f.SetTenantConcurrencyLimit(a.config.TenantConcurrencyHeader, a.config.TenantConcurrencyMaxPerTenant)
+        logger.Info("Tenant concurrency isolation enabled: header=%s, max_per_tenant=%d", a.config.TenantConcurrencyHeader, a.config.TenantConcurrencyMaxPerTenant)
+    case *forwarder.HybridForwarder:
+        f.SetTenantConcurrencyLimit(a.config.TenantConcurrencyHeader, a.config.TenantConcurrencyMaxPerTenant)
+        logger.Info("Tenant concurrency isolation enabled: header=%s, max_per_tenant=%d", a.config.TenantConcurrencyHeader, a.config.TenantConcurrencyMaxPerTenant)
+    }
+}
+
+// buildSignalForwarder constructs an independent Forwarder for a single
+// signal (logs or traces), supporting the same forwarding modes as the shared
+// default forwarder built in injectDependency. It intentionally does not wire
+// up retry policy, dead-lettering, or disk-backed queueing in "pool" mode -
+// those are shared-default-forwarder concerns tied to a.workerPool. A signal
+// needing them should leave its forwarding mode override unset ("") to keep
+// using the shared default forwarder instead.
+func (a *App) buildSignalForwarder(signal string, mode string, workerPoolSize int, jobQueueSize int, semaphoreMaxConcurrent int, shutdownTimeout time.Duration) forwarder.Forwarder {
+    switch mode {
+    case "semaphore":
+        logger.Info("Using semaphore-based forwarder for %s (maxConcurrent=%d)", signal, semaphoreMaxConcurrent)
+        fwd := forwarder.NewSemaphoreForwarder(semaphoreMaxConcurrent, a.httpClientConfig())
+        a.applyTenantConcurrencyLimit(fwd)
+        return fwd
+    case "hybrid":
+        logger.Info("Using hybrid forwarder for %s (workers=%d, queueSize=%d, maxConcurrent=%d)", signal, workerPoolSize, jobQueueSize, semaphoreMaxConcurrent)
+        fwd := forwarder.NewHybridForwarder(workerPoolSize, jobQueueSize, semaphoreMaxConcurrent, a.httpClientConfig())
+        a.applyTenantConcurrencyLimit(fwd)
+        return fwd
+    case "adaptive":
+        logger.Info("Using adaptive forwarder for %s (min=%d, max=%d, latencyThreshold=%dms)", signal, a.config.AdaptiveMinConcurrent, a.config.AdaptiveMaxConcurrent, a.config.AdaptiveLatencyThresholdMS)
+        return forwarder.NewAdaptiveForwarder(a.config.AdaptiveMinConcurrent, a.config.AdaptiveMaxConcurrent, time.Duration(a.config.AdaptiveLatencyThresholdMS)*time.Millisecond)
+    case "grpc":
+        logger.Info("Using gRPC forwarder for %s (maxConcurrent=%d, requestTimeout=%ds)", signal, a.config.GRPCMaxConcurrent, a.config.GRPCRequestTimeoutSeconds)
+        return forwarder.NewGRPCForwarder(a.config.OtelCollectorAPIKey, a.config.GRPCMaxConcurrent, time.Duration(a.config.GRPCRequestTimeoutSeconds)*time.Second)
+    case "failover":
+        targets := make([]string, 0, len(a.config.OtelCollectorFailoverTargets)+1)
+        targets = append(targets, a.config.OtelCollectorTargetURL)
+        for _, t := range a.config.OtelCollectorFailoverTargets {
+            targets = append(targets, t.URL)
+        }
+        logger.Info("Using failover forwarder for %s (targets=%v)", signal, targets)
+        return forwarder.NewFailoverForwarder(targets, semaphoreMaxConcurrent)
+    case "loadbalance":
+        lbTargets := make([]string, 0, len(a.config.OtelCollectorLoadBalanceTargets)+1)
+        lbTargets = append(lbTargets, a.config.OtelCollectorTargetURL)
+        for _, t := range a.config.OtelCollectorLoadBalanceTargets {
+            lbTargets = append(lbTargets, t.URL)
+        }
+        logger.Info("Using load-balance forwarder for %s (targets=%v, strategy=%s)", signal, lbTargets, a.config.LoadBalancingStrategy)
+        return forwarder.NewLoadBalanceForwarder(lbTargets, a.config.LoadBalancingStrategy, semaphoreMaxConcurrent)
+    case "kafka":
+        logger.Info("Using Kafka forwarder for %s (broker=%s, topic=%s)", signal, a.config.KafkaBroker, a.config.KafkaTopic)
+        return forwarder.NewKafkaForwarder(a.config.KafkaBroker, a.config.KafkaTopic, a.config.KafkaClientID, time.Duration(orDefault(a.config.ForwarderRequestTimeoutSeconds, 10))*time.Second, semaphoreMaxConcurrent)
+    case "natsjs":
+        logger.Info("Using NATS JetStream forwarder for %s (addr=%s, subject=%s)", signal, a.config.NATSAddr, a.config.NATSSubject)
+        return forwarder.NewNATSForwarder(a.config.NATSAddr, a.config.NATSSubject, time.Duration(orDefault(a.config.ForwarderRequestTimeoutSeconds, 10))*time.Second, semaphoreMaxConcurrent)
+    case "loopback":
+        logger.Info("Using loopback forwarder for %s (no external collector)", signal)
+        return a.buildLoopbackForwarder()
+    default:
+        logger.Info("Using pool-based forwarder for %s (workers=%d, queueSize=%d)", signal, workerPoolSize, jobQueueSize)
+        return forwarder.NewPoolForwarder(worker.NewPool(workerPoolSize, jobQueueSize, shutdownTimeout, a.httpClientConfig()))
+    }
+}
+
+// buildLoopbackForwarder constructs the shared LoopbackForwarder used by
+// forwarding_mode = "loopback", tracked on the App so GET /admin/debug/loopback
+// can query it regardless of which signal(s) it's serving
+func (a *App) buildLoopbackForwarder() *forwarder.LoopbackForwarder {
+    if a.loopbackForwarder != nil {
+        return a.loopbackForwarder
+    }
+    var disk *deadletter.Store
+    if a.config.LoopbackDiskDir != "" {
+        var err error
+        disk, err = deadletter.New(a.config.LoopbackDiskDir, 0, a.spoolEncryptor())
+        if err != nil {
+            logger.Error("Failed to initialize loopback disk mirror at %s: %v", a.config.LoopbackDiskDir, err)
+        } else {
+            logger.Info("Loopback forwarder mirroring accepted payloads to disk: %s", a.config.LoopbackDiskDir)
+        }
+    }
+    a.loopbackForwarder = forwarder.NewLoopbackForwarder(a.config.LoopbackCapacity, disk)
+    return a.loopbackForwarder
+}
+
+// buildMetricsEcho constructs the dedicated /metrics + health listener used
+// when config.MetricsPort > 0, so a public load balancer route to the main
+// server never reaches these operational endpoints. Mirrors the middleware
+// the public Echo would otherwise apply to them (metrics protection, the
+// prometheus handler itself).
+func (a *App) buildMetricsEcho() *echo.Echo {
+    e := echo.New()
+    e.HideBanner = true
+    e.HidePort = true
+    a.healthHandler.SetupRoutes(e)
+    e.Use(echoprometheus.NewMiddleware("zep_logger"))
+    e.Use(metricsProtectionMiddleware(a.config))
+    e.GET("/metrics", echoprometheus.NewHandler())
+    return e
+}
+
 // injectDependency initializes all HTTP handlers and worker pool
 // This centralizes handler initialization and makes it easy to add new handlers
 func (a *App) injectDependency() {
 	// Initialize worker pool for async request forwarding
 	shutdownTimeout := time.Duration(a.config.ShutdownTimeoutSeconds) * time.Second
-    // Choose forwarder based on config
+    // Choose forwarder based on config, unless WithForwarder already supplied
+    // one - the batching/mirroring/archive/backpressure decorators below
+    // still wrap it either way, same as they wrap a config-selected forwarder
+    if !a.forwarderOverridden {
     switch a.config.ForwardingMode {
     case "semaphore":
         a.workerPool = nil
-        a.forwarder = forwarder.NewSemaphoreForwarder(a.config.SemaphoreMaxConcurrent, shutdownTimeout)
+        a.forwarder = forwarder.NewSemaphoreForwarder(a.config.SemaphoreMaxConcurrent, a.httpClientConfig())
+        a.applyTenantConcurrencyLimit(a.forwarder)
         logger.Info("Using semaphore-based forwarder (maxConcurrent=%d)", a.config.SemaphoreMaxConcurrent)
     case "hybrid":
         a.workerPool = nil
-        a.forwarder = forwarder.NewHybridForwarder(a.config.WorkerPoolSize, a.config.JobQueueSize, a.config.SemaphoreMaxConcurrent, shutdownTimeout)
+        a.forwarder = forwarder.NewHybridForwarder(a.config.WorkerPoolSize, a.config.JobQueueSize, a.config.SemaphoreMaxConcurrent, a.httpClientConfig())
+        a.applyTenantConcurrencyLimit(a.forwarder)
         logger.Info("Using hybrid forwarder (workers=%d, queueSize=%d, maxConcurrent=%d)", a.config.WorkerPoolSize, a.config.JobQueueSize, a.config.SemaphoreMaxConcurrent)
+    case "adaptive":
+        a.workerPool = nil
+        a.forwarder = forwarder.NewAdaptiveForwarder(a.config.AdaptiveMinConcurrent, a.config.AdaptiveMaxConcurrent, time.Duration(a.config.AdaptiveLatencyThresholdMS)*time.Millisecond)
+        logger.Info("Using adaptive forwarder (min=%d, max=%d, latencyThreshold=%dms)", a.config.AdaptiveMinConcurrent, a.config.AdaptiveMaxConcurrent, a.config.AdaptiveLatencyThresholdMS)
+    case "grpc":
+        a.workerPool = nil
+        a.forwarder = forwarder.NewGRPCForwarder(a.config.OtelCollectorAPIKey, a.config.GRPCMaxConcurrent, time.Duration(a.config.GRPCRequestTimeoutSeconds)*time.Second)
+        logger.Info("Using gRPC forwarder (maxConcurrent=%d, requestTimeout=%ds)", a.config.GRPCMaxConcurrent, a.config.GRPCRequestTimeoutSeconds)
+    case "failover":
+        a.workerPool = nil
+        targets := make([]string, 0, len(a.config.OtelCollectorFailoverTargets)+1)
+        targets = append(targets, a.config.OtelCollectorTargetURL)
+        for _, t := range a.config.OtelCollectorFailoverTargets {
+            targets = append(targets, t.URL)
+        }
+        a.forwarder = forwarder.NewFailoverForwarder(targets, a.config.SemaphoreMaxConcurrent)
+        logger.Info("Using failover forwarder (targets=%v)", targets)
+    case "loadbalance":
+        a.workerPool = nil
+        lbTargets := make([]string, 0, len(a.config.OtelCollectorLoadBalanceTargets)+1)
+        lbTargets = append(lbTargets, a.config.OtelCollectorTargetURL)
+        for _, t := range a.config.OtelCollectorLoadBalanceTargets {
+            lbTargets = append(lbTargets, t.URL)
+        }
+        a.forwarder = forwarder.NewLoadBalanceForwarder(lbTargets, a.config.LoadBalancingStrategy, a.config.SemaphoreMaxConcurrent)
+        logger.Info("Using load-balance forwarder (targets=%v, strategy=%s)", lbTargets, a.config.LoadBalancingStrategy)
+    case "kafka":
+        a.workerPool = nil
+        a.forwarder = forwarder.NewKafkaForwarder(a.config.KafkaBroker, a.config.KafkaTopic, a.config.KafkaClientID, time.Duration(orDefault(a.config.ForwarderRequestTimeoutSeconds, 10))*time.Second, a.config.SemaphoreMaxConcurrent)
+        logger.Info("Using Kafka forwarder (broker=%s, topic=%s)", a.config.KafkaBroker, a.config.KafkaTopic)
+    case "natsjs":
+        a.workerPool = nil
+        a.forwarder = forwarder.NewNATSForwarder(a.config.NATSAddr, a.config.NATSSubject, time.Duration(orDefault(a.config.ForwarderRequestTimeoutSeconds, 10))*time.Second, a.config.SemaphoreMaxConcurrent)
+        logger.Info("Using NATS JetStream forwarder (addr=%s, subject=%s)", a.config.NATSAddr, a.config.NATSSubject)
+    case "loopback":
+        a.workerPool = nil
+        a.forwarder = a.buildLoopbackForwarder()
+        logger.Info("Using loopback forwarder (no external collector)")
     default:
-        a.workerPool = worker.NewPool(a.config.WorkerPoolSize, a.config.JobQueueSize, shutdownTimeout)
+        a.workerPool = worker.NewPool(a.config.WorkerPoolSize, a.config.JobQueueSize, shutdownTimeout, a.httpClientConfig())
+        a.workerPool.SetRetryPolicy(worker.RetryPolicy{
+            MaxAttempts:    a.config.WorkerPoolRetryMaxAttempts,
+            InitialBackoff: time.Duration(a.config.WorkerPoolRetryInitialBackoffMS) * time.Millisecond,
+            MaxBackoff:     time.Duration(a.config.WorkerPoolRetryMaxBackoffMS) * time.Millisecond,
+            JitterFraction: a.config.WorkerPoolRetryJitterFraction,
+        })
+        if a.config.DeadLetterDir != "" {
+            dl, err := deadletter.New(a.config.DeadLetterDir, a.config.DeadLetterMaxSizeMB, a.spoolEncryptor())
+            if err != nil {
+                logger.Error("Failed to initialize dead-letter store at %s: %v", a.config.DeadLetterDir, err)
+            } else {
+                a.workerPool.SetDeadLetterWriter(dl)
+                logger.Info("Dead-letter store enabled: dir=%s, max_size_mb=%d", a.config.DeadLetterDir, a.config.DeadLetterMaxSizeMB)
+            }
+        }
+        if a.config.WorkerPoolQueueMode == "disk" {
+            dq, err := diskqueue.New(a.config.WorkerPoolDiskQueueDir, a.spoolEncryptor())
+            if err != nil {
+                logger.Error("Failed to initialize disk-backed job queue at %s: %v", a.config.WorkerPoolDiskQueueDir, err)
+            } else {
+                a.workerPool.SetDiskQueue(dq)
+                logger.Info("Disk-backed job queue enabled: dir=%s", a.config.WorkerPoolDiskQueueDir)
+            }
+        }
+        if a.config.RateLimitEnabled {
+            requestRateLimiter := rate.NewLimiter(rate.Limit(a.config.RateLimitRequestsPerSecond), a.config.RateLimitBurst)
+            if a.config.RateLimitStateFile != "" {
+                ratelimitpersist.Restore(a.config.RateLimitStateFile, requestRateLimiter)
+                p := ratelimitpersist.NewPersister(a.config.RateLimitStateFile, requestRateLimiter, time.Duration(a.config.RateLimitStatePersistIntervalSeconds)*time.Second)
+                p.Start()
+                a.rateLimitPersisters = append(a.rateLimitPersisters, p)
+            }
+            a.workerPool.SetRateLimiter(requestRateLimiter)
+            logger.Info("Upstream rate limiting enabled: requests_per_second=%.1f, burst=%d", a.config.RateLimitRequestsPerSecond, a.config.RateLimitBurst)
+        }
+        if a.config.WorkerPoolLazySpawn {
+            a.workerPool.SetLazySpawn(time.Duration(a.config.WorkerPoolIdleTimeoutSeconds) * time.Second)
+            logger.Info("Lazy worker spawning enabled: idle_timeout=%ds", a.config.WorkerPoolIdleTimeoutSeconds)
+        }
+        if a.config.JobTTLEnabled {
+            a.workerPool.SetJobTTL(time.Duration(a.config.JobTTLSeconds) * time.Second)
+            logger.Info("Job TTL enabled: job_ttl=%ds", a.config.JobTTLSeconds)
+        }
+        if a.config.WorkerPoolShutdownSpillFile != "" {
+            a.workerPool.SetSpillFile(a.config.WorkerPoolShutdownSpillFile)
+            logger.Info("Shutdown spill file enabled: %s", a.config.WorkerPoolShutdownSpillFile)
+        }
+        if a.config.QueueFullPolicy == "drop-oldest" {
+            a.workerPool.SetQueueFullPolicy(a.config.QueueFullPolicy)
+            logger.Info("Queue-full policy: drop-oldest (evict oldest queued job instead of rejecting new submissions)")
+        }
+        if a.config.WorkerPoolMaxQueuedBytes > 0 {
+            a.workerPool.SetMaxQueuedBytes(int64(a.config.WorkerPoolMaxQueuedBytes))
+            logger.Info("Worker pool queued-bytes cap enabled: max_queued_bytes=%d", a.config.WorkerPoolMaxQueuedBytes)
+        }
+        if a.config.UpstreamErrorBufferSize > 0 {
+            a.upstreamDiagnostics = diagnostics.NewBuffer(a.config.UpstreamErrorBufferSize)
+            a.workerPool.SetDiagnostics(a.upstreamDiagnostics)
+        }
+        if a.config.JobTrackingEnabled {
+            a.jobTracker = jobtracking.NewTracker(a.config.JobTrackingCapacity)
+            a.workerPool.SetJobTracker(a.jobTracker)
+        }
+        if a.config.HealthSheddingEnabled {
+            a.workerPool.SetHealthScorer(admission.NewHealthScorer(
+                time.Duration(a.config.HealthSheddingLatencyThresholdMS)*time.Millisecond,
+                a.config.HealthSheddingBreakerThreshold,
+                time.Duration(a.config.HealthSheddingBreakerCooldownSeconds)*time.Second,
+            ))
+            logger.Info("Health-based load shedding enabled: latency_threshold=%dms, breaker_threshold=%d, breaker_cooldown=%ds", a.config.HealthSheddingLatencyThresholdMS, a.config.HealthSheddingBreakerThreshold, a.config.HealthSheddingBreakerCooldownSeconds)
+        }
+        if a.config.TenantQueueShareMaxPending > 0 {
+            a.workerPool.SetTenantQueueShare(a.config.TenantQueueShareHeader, a.config.TenantQueueShareMaxPending)
+            logger.Info("Tenant queue share limiting enabled: header=%s, max_pending=%d", a.config.TenantQueueShareHeader, a.config.TenantQueueShareMaxPending)
+        }
+        if len(a.config.TenantWeights) > 0 {
+            a.workerPool.SetTenantWeights(a.config.TenantWeights)
+            logger.Info("Tenant weighted fair scheduling enabled: weights=%v", a.config.TenantWeights)
+        }
         a.forwarder = forwarder.NewPoolForwarder(a.workerPool)
         logger.Info("Using pool-based forwarder (workers=%d, queueSize=%d)", a.config.WorkerPoolSize, a.config.JobQueueSize)
     }
+    } else {
+        logger.Info("Using forwarder supplied via app.WithForwarder, ignoring forwarding_mode")
+    }
+
+    // Circuit breaker: diverts Submit to a fallback collector once the
+    // primary forwarder's HealthReporter score reports unhealthy for
+    // circuit_breaker_open_threshold consecutive checks, instead of
+    // continuing to hand it jobs it can't deliver. Wraps the raw per-mode
+    // forwarder directly (before batching/mirroring/archive/backpressure),
+    // since those decorators don't forward the underlying HealthReporter.
+    if a.config.CircuitBreakerFallbackURL != "" {
+        fallback := forwarder.NewSemaphoreForwarder(a.config.SemaphoreMaxConcurrent, a.httpClientConfig())
+        a.forwarder = forwarder.NewCircuitBreakerForwarder(a.forwarder, a.config.OtelCollectorTargetURL, fallback, a.config.CircuitBreakerFallbackURL,
+            a.config.CircuitBreakerOpenThreshold, time.Duration(a.config.CircuitBreakerCooldownSeconds)*time.Second)
+        logger.Info("Circuit breaker forwarder enabled (fallback_url=%s, open_threshold=%d, cooldown=%ds)", a.config.CircuitBreakerFallbackURL, a.config.CircuitBreakerOpenThreshold, a.config.CircuitBreakerCooldownSeconds)
+    }
+
+    // Batching layer: merges queued payloads bound for the same target into
+    // larger upstream requests, so it wraps whichever forwarder was chosen above
+    if a.config.BatchingEnabled {
+        a.forwarder = forwarder.NewBatchingForwarder(a.forwarder, forwarder.BatchConfig{
+            MaxSize: a.config.BatchMaxSize,
+            MaxAge:  time.Duration(a.config.BatchMaxAgeMS) * time.Millisecond,
+        })
+        logger.Info("Batching forwarder enabled (max_size=%d, max_age=%s)", a.config.BatchMaxSize, time.Duration(a.config.BatchMaxAgeMS)*time.Millisecond)
+    }
+
+    // Mirroring: tees every forwarded payload to a shadow collector, best-effort
+    // and non-blocking, so it wraps whichever forwarder (and batching layer, if
+    // enabled) was chosen above
+    if a.config.MirrorTargetURL != "" {
+        a.forwarder = forwarder.NewMirrorForwarder(a.forwarder, a.config.OtelCollectorTargetURL, a.config.MirrorTargetURL, a.config.MirrorMaxConcurrent)
+        logger.Info("Mirror forwarder enabled (target=%s, max_concurrent=%d)", a.config.MirrorTargetURL, a.config.MirrorMaxConcurrent)
+    }
+
+    // Archival: records every forwarded payload (ndjson of base64 bodies +
+    // metadata) to rotating local files, optionally uploaded to S3, for
+    // compliance retention and replay. Wraps whichever forwarder (and
+    // batching/mirroring decorators, if enabled) was chosen above
+    if a.config.ArchiveEnabled {
+        var uploader archivesink.Uploader
+        if a.config.ArchiveS3Bucket != "" {
+            uploader = archivesink.NewS3Uploader(archivesink.S3Config{
+                Bucket:          a.config.ArchiveS3Bucket,
+                Region:          a.config.ArchiveS3Region,
+                AccessKeyID:     a.config.ArchiveS3AccessKeyID,
+                SecretAccessKey: a.config.ArchiveS3SecretAccessKey,
+                Endpoint:        a.config.ArchiveS3Endpoint,
+                Prefix:          a.config.ArchiveS3Prefix,
+            })
+        }
+        sink := archivesink.New(a.config.ArchiveOutputDir, a.config.ArchiveMaxFileSizeMB, uploader, a.spoolEncryptor())
+        a.forwarder = forwarder.NewArchiveForwarder(a.forwarder, sink)
+        logger.Info("Archive forwarder enabled (output_dir=%s, max_file_size_mb=%d, s3_bucket=%q)", a.config.ArchiveOutputDir, a.config.ArchiveMaxFileSizeMB, a.config.ArchiveS3Bucket)
+    }
+
+    // Backpressure ceiling: rejects Submit once the active forwarder's queue
+    // depth reaches a configured limit, so client-visible behavior under
+    // sustained overload doesn't change when forwarding_mode does. Wraps
+    // whichever forwarder (and batching/mirroring decorators, if enabled)
+    // was chosen above
+    if a.config.ForwarderMaxPendingJobs > 0 || a.config.ForwarderSoftMaxPendingJobs > 0 {
+        a.forwarder = forwarder.NewBackpressureForwarder(a.forwarder, a.config.ForwarderSoftMaxPendingJobs, a.config.ForwarderMaxPendingJobs, a.config.ForwarderBackpressureRetryAfterSeconds)
+        logger.Info("Backpressure forwarder enabled (soft_max_pending_jobs=%d, max_pending_jobs=%d)", a.config.ForwarderSoftMaxPendingJobs, a.config.ForwarderMaxPendingJobs)
+    }
+
+    a.forwarder = a.applyHooks(a.forwarder)
+
+    // Per-signal forwarder overrides: /v1/logs and /v1/traces can each use a
+    // different forwarding mode/worker count/queue size than the shared
+    // default above (e.g. a low-latency semaphore forwarder for session-replay
+    // logs alongside a deep pool queue for traces). Unset falls back to the
+    // shared forwarder built above, batching/mirroring decorators included.
+    logsForwarder := a.forwarder
+    if a.config.LogsForwardingMode != "" {
+        logsForwarder = a.buildSignalForwarder("logs", a.config.LogsForwardingMode,
+            orDefault(a.config.LogsWorkerPoolSize, a.config.WorkerPoolSize),
+            orDefault(a.config.LogsJobQueueSize, a.config.JobQueueSize),
+            orDefault(a.config.LogsSemaphoreMaxConcurrent, a.config.SemaphoreMaxConcurrent),
+            shutdownTimeout)
+        if a.config.ForwarderMaxPendingJobs > 0 || a.config.ForwarderSoftMaxPendingJobs > 0 {
+            logsForwarder = forwarder.NewBackpressureForwarder(logsForwarder, a.config.ForwarderSoftMaxPendingJobs, a.config.ForwarderMaxPendingJobs, a.config.ForwarderBackpressureRetryAfterSeconds)
+        }
+        logsForwarder = a.applyHooks(logsForwarder)
+        a.signalForwarders = append(a.signalForwarders, logsForwarder)
+    }
+
+    tracesForwarder := a.forwarder
+    if a.config.TracesForwardingMode != "" {
+        tracesForwarder = a.buildSignalForwarder("traces", a.config.TracesForwardingMode,
+            orDefault(a.config.TracesWorkerPoolSize, a.config.WorkerPoolSize),
+            orDefault(a.config.TracesJobQueueSize, a.config.JobQueueSize),
+            orDefault(a.config.TracesSemaphoreMaxConcurrent, a.config.SemaphoreMaxConcurrent),
+            shutdownTimeout)
+        if a.config.ForwarderMaxPendingJobs > 0 || a.config.ForwarderSoftMaxPendingJobs > 0 {
+            tracesForwarder = forwarder.NewBackpressureForwarder(tracesForwarder, a.config.ForwarderSoftMaxPendingJobs, a.config.ForwarderMaxPendingJobs, a.config.ForwarderBackpressureRetryAfterSeconds)
+        }
+        tracesForwarder = a.applyHooks(tracesForwarder)
+        a.signalForwarders = append(a.signalForwarders, tracesForwarder)
+    }
+
+    rewriteRules := make([]transform.RewriteRule, 0, len(a.config.ResourceAttributeRewriteRules))
+    for _, rule := range a.config.ResourceAttributeRewriteRules {
+        rewriteRules = append(rewriteRules, transform.RewriteRule{
+            Action:  transform.RuleAction(rule.Action),
+            FromKey: rule.FromKey,
+            ToKey:   rule.ToKey,
+            Key:     rule.Key,
+            Value:   rule.Value,
+        })
+    }
+
+    if a.config.DataResidencyRegion != "" {
+        rewriteRules = append(rewriteRules, transform.RewriteRule{
+            Action: transform.ActionSetDefault,
+            Key:    a.config.DataResidencyAttributeKey,
+            Value:  a.config.DataResidencyRegion,
+        })
+        logger.Info("Data residency stamping enabled: region=%s, attribute=%s", a.config.DataResidencyRegion, a.config.DataResidencyAttributeKey)
+    }
+
+    schemaNorm := transform.SchemaNormalization{
+        TargetSchemaURL:  a.config.SchemaNormalizationTargetSchemaURL,
+        AttributeAliases: a.config.SchemaNormalizationAttributeAliases,
+    }
+
+    routingRules := make([]transform.ResourceRoute, 0, len(a.config.ResourceRoutingRules))
+    for _, rule := range a.config.ResourceRoutingRules {
+        routingRules = append(routingRules, transform.ResourceRoute{
+            Key:       rule.Key,
+            Value:     rule.Value,
+            TargetURL: rule.TargetURL,
+        })
+    }
+
+    geoCountryRoutes := make([]proxy.GeoCountryRoute, 0, len(a.config.GeoIPCountryRoutes))
+    for _, route := range a.config.GeoIPCountryRoutes {
+        geoCountryRoutes = append(geoCountryRoutes, proxy.GeoCountryRoute{
+            Country:   route.Country,
+            TargetURL: route.TargetURL,
+        })
+    }
+
+    // Experimental tail-based sampling buffer: holds spans per trace for a
+    // short window and forwards only errored/slow traces plus a sample of
+    // the rest, for deployments without a sampling-capable collector tier
+    if a.config.TailSamplingEnabled {
+        headers := http.Header{"Content-Type": []string{"application/x-protobuf"}}
+        if a.config.OtelCollectorAPIKey != "" {
+            headers.Set("Authorization", a.config.OtelCollectorAPIKey)
+        }
+        a.tailSampler = sampling.New(sampling.Rules{
+            Window:            time.Duration(a.config.TailSamplingWindowSeconds) * time.Second,
+            SlowThreshold:     time.Duration(a.config.TailSamplingSlowThresholdMS) * time.Millisecond,
+            ErrorSampleRate:   a.config.TailSamplingErrorSampleRate,
+            DefaultSampleRate: a.config.TailSamplingDefaultSampleRate,
+        }, tracesForwarder, a.config.OtelCollectorTargetURL+"/v1/traces", headers)
+    }
+
+    // Per-tenant billing/usage accounting: aggregates bytes/records per tenant
+    // and periodically flushes usage reports to disk and/or a billing endpoint
+    if a.config.UsageAccountingEnabled {
+        a.usageAccountant = usage.New(time.Duration(a.config.UsageAccountingIntervalSeconds)*time.Second, a.config.UsageAccountingOutputDir, a.config.UsageAccountingBillingURL)
+    }
+
+    // Avoid passing a typed-nil *sampling.Buffer as a non-nil proxy.TailSampler interface
+    var tailSampler proxy.TailSampler
+    if a.tailSampler != nil {
+        tailSampler = a.tailSampler
+    }
+
+    // Avoid passing a typed-nil *usage.Accountant as a non-nil proxy.UsageAccountant interface
+    var usageAccountant proxy.UsageAccountant
+    if a.usageAccountant != nil {
+        usageAccountant = a.usageAccountant
+    }
+
+    largePayloadThresholdBytes := 0
+    if a.config.LargePayloadOffloadEnabled {
+        largePayloadThresholdBytes = a.config.LargePayloadThresholdBytes
+    }
+
+    var admissionController *admission.Controller
+    if a.config.LoadSheddingMaxQueueDepth > 0 {
+        admissionController = admission.NewController(a.config.LoadSheddingMaxQueueDepth)
+        if a.config.ProbabilisticSheddingThresholdPercent > 0 {
+            admissionController.SetProbabilisticShedding(
+                float64(a.config.ProbabilisticSheddingThresholdPercent)/100,
+                float64(a.config.ProbabilisticSheddingDropPercent)/100,
+            )
+        }
+    }
+
+    var byteRateLimiter *rate.Limiter
+    if a.config.IngestByteRateLimitEnabled {
+        byteRateLimiter = rate.NewLimiter(rate.Limit(a.config.IngestByteRateLimitBytesPerSecond), a.config.IngestByteRateLimitBurstBytes)
+        if a.config.IngestByteRateLimitStateFile != "" {
+            ratelimitpersist.Restore(a.config.IngestByteRateLimitStateFile, byteRateLimiter)
+            p := ratelimitpersist.NewPersister(a.config.IngestByteRateLimitStateFile, byteRateLimiter, time.Duration(a.config.RateLimitStatePersistIntervalSeconds)*time.Second)
+            p.Start()
+            a.rateLimitPersisters = append(a.rateLimitPersisters, p)
+        }
+        logger.Info("Ingest byte rate limiting enabled: bytes_per_second=%.0f, burst_bytes=%d", a.config.IngestByteRateLimitBytesPerSecond, a.config.IngestByteRateLimitBurstBytes)
+    }
+
+    // Dedup: suppresses a /v1/logs or /v1/traces payload seen again within a
+    // sliding window, so a browser SDK retrying on a flaky network doesn't
+    // produce duplicate telemetry downstream
+    if a.config.DedupEnabled {
+        a.deduper = dedup.NewDeduper(time.Duration(a.config.DedupWindowSeconds) * time.Second)
+    }
+
+    // Proof-of-work challenge: raises the cost of scripted telemetry spam
+    // from unauthenticated RUM ingest
+    if a.config.PowChallengeEnabled {
+        a.powVerifier = powchallenge.NewVerifier(a.config.PowChallengeSigningSecret, time.Duration(a.config.PowChallengeTTLSeconds)*time.Second, a.config.PowChallengeDifficultyBits)
+    }
+
+    // GeoIP: resolves the client IP to a country for data-residency routing
+    // and blocking. A database that fails to open is logged and the feature
+    // silently no-ops rather than failing startup, since geoip_enabled with a
+    // bad path shouldn't take the proxy down.
+    if a.config.GeoIPEnabled {
+        reader, err := geoip.Open(a.config.GeoIPDatabasePath)
+        if err != nil {
+            logger.Error("Failed to open GeoIP database %s, GeoIP routing/blocking disabled: %v", a.config.GeoIPDatabasePath, err)
+        } else {
+            a.geoReader = reader
+        }
+    }
+
+    // GCP ID token auth: takes priority over the static api key when
+    // configured, for a collector running on Cloud Run behind IAM.
+    var authProvider proxy.AuthProvider
+    if a.config.GCPIDTokenAudience != "" {
+        authProvider = gcpauth.NewIDTokenSource(a.config.GCPIDTokenAudience, 0)
+    }
+
+    var tokenIssuer *ingesttoken.Issuer
+    if a.config.IngestTokenIssuanceEnabled {
+        tokenIssuer = ingesttoken.NewIssuer(a.config.IngestTokenSigningSecret, time.Duration(a.config.IngestTokenTTLSeconds)*time.Second)
+    }
+    var ingestTokenEnforcer *ingesttoken.Issuer
+    if a.config.IngestTokenEnforcementEnabled {
+        ingestTokenEnforcer = tokenIssuer
+    }
+
+    a.proxyHandler = proxy.NewProxyHandler(a.ctx, a.config.OtelCollectorTargetURL, a.config.OtelCollectorAPIKey, logsForwarder, tracesForwarder, a.config.SyncLogsDebug, rewriteRules, schemaNorm, routingRules, tailSampler, usageAccountant, a.config.UsageAccountingTenantHeader, largePayloadThresholdBytes, a.config.LargePayloadTempDir, admissionController, a.config.ForwardCompression, byteRateLimiter, a.config.MaxDecompressedBodyBytes, a.deduper, a.config.DedupIdempotencyHeader, a.powVerifier, a.geoReader, a.config.GeoIPBlockedCountries, geoCountryRoutes, a.config.DataResidencyRegion, a.config.DataResidencyTargetRegions, authProvider, a.config.AdminAPIKey, a.config.TargetOverrides, a.config.SyncLogsStreamingEnabled, time.Duration(a.config.LogsForwardTimeoutMS)*time.Millisecond, time.Duration(a.config.TracesForwardTimeoutMS)*time.Millisecond, a.config.OverloadStatusCode, a.config.OverloadRetryAfterSeconds, ingestTokenEnforcer)
+
+    healthHandler := health.NewHealthHandler(a.readiness)
+    healthHandler.RegisterCheck("forwarder_queue", func() error {
+        if a.config.WatchdogQueueDepthThreshold <= 0 {
+            return nil
+        }
+        if depth := a.forwarder.GetQueueDepth(); depth >= a.config.WatchdogQueueDepthThreshold {
+            return fmt.Errorf("queue depth %d at or above threshold %d", depth, a.config.WatchdogQueueDepthThreshold)
+        }
+        return nil
+    })
+    if a.config.WorkerPoolShutdownSpillFile != "" {
+        spillDir := filepath.Dir(a.config.WorkerPoolShutdownSpillFile)
+        healthHandler.RegisterCheck("spool_disk", func() error {
+            if _, err := os.Stat(spillDir); err != nil {
+                return fmt.Errorf("spool directory %s unavailable: %w", spillDir, err)
+            }
+            return nil
+        })
+    }
+    if a.config.ForwarderReadinessCheckEnabled {
+        healthHandler.RegisterCheck("forwarder_health", func() error {
+            if state := forwarder.EvaluateHealth(a.forwarder); state == forwarder.HealthStateFailing {
+                return fmt.Errorf("forwarder health state is %s", state)
+            }
+            return nil
+        })
+    }
 
     a.httpHandlers = []httpiface.HttpRouter{
-        health.NewHealthHandler(a.readiness),
-        proxy.NewProxyHandler(a.config.OtelCollectorTargetURL, a.config.OtelCollectorAPIKey, a.forwarder, a.config.SyncLogsDebug),
-        // Future handlers will be added here:
-        // admin.NewAdminHandler(...) in Story 1.7
+        a.proxyHandler,
+        admin.NewAdminHandler(a.config.DebugDumpDir, a.upstreamDiagnostics, a.jobTracker, tokenIssuer, a.config.AdminAPIKey, a.loopbackForwarder, a.forwarder),
+    }
+    if a.config.MetricsPort <= 0 {
+        // Health endpoints stay on the public server alongside everything
+        // else (the original behavior); buildMetricsEcho below owns them
+        // instead once metrics_port is configured
+        a.httpHandlers = append([]httpiface.HttpRouter{healthHandler}, a.httpHandlers...)
+    } else {
+        a.healthHandler = healthHandler
+    }
+
+    // Memory/queue-depth watchdog: captures a heap profile on threshold breach
+    a.watchdog = watchdog.New(watchdog.Config{
+        RSSThresholdMB:      a.config.WatchdogRSSThresholdMB,
+        QueueDepthThreshold: a.config.WatchdogQueueDepthThreshold,
+        CheckInterval:       time.Duration(a.config.WatchdogCheckIntervalSeconds) * time.Second,
+        MinDumpInterval:     time.Duration(a.config.WatchdogMinDumpIntervalSeconds) * time.Second,
+        DumpDir:             a.config.DebugDumpDir,
+    }, a.forwarder)
+
+    // Continuous soak-test canary: exercises the real forwarding path against
+    // an internal mock collector and reconciles delivery counts
+    if a.config.CanaryEnabled {
+        c, err := canary.New(a.forwarder, time.Duration(a.config.CanaryIntervalSeconds)*time.Second)
+        if err != nil {
+            logger.Error("Failed to start canary: %v", err)
+        } else {
+            a.canary = c
+        }
+    }
+
+    // Delivery reconciliation: compares forwarded-vs-accepted counts against the collector's own metrics
+    if a.config.ReconciliationEnabled {
+        a.reconciler = reconciler.New(a.config.ReconciliationCollectorMetricsURL, a.config.ReconciliationMetricName, time.Duration(a.config.ReconciliationIntervalSeconds)*time.Second)
     }
 }
 
@@ -85,10 +806,48 @@ func (a *App) injectDependency() {
 func (a *App) preProcess() {
 	logger.Info("Preparing to start server...")
 
+	// Pre-establish connections to the collector before accepting HTTP
+	// traffic, so the first spike of real traffic doesn't pay handshake
+	// latency across hundreds of workers all dialing at once. Only
+	// forwarding_mode = "pool" exposes its *http.Client for reuse this way;
+	// the other modes each build their own client/connection type
+	// internally and aren't covered here.
+    if a.workerPool != nil && a.config.ConnectionWarmupCount > 0 {
+        logger.Info("Warming up %d connection(s) to %s", a.config.ConnectionWarmupCount, a.config.OtelCollectorTargetURL)
+        a.workerPool.WarmUp(a.ctx, a.config.OtelCollectorTargetURL, a.config.ConnectionWarmupCount)
+    }
+
 	// Start worker pool before accepting HTTP traffic
     if a.forwarder != nil {
         a.forwarder.Start()
     }
+    for _, f := range a.signalForwarders {
+        f.Start()
+    }
+
+    a.watchdog.Start()
+
+    if a.canary != nil {
+        a.canary.Start()
+    }
+
+    if a.reconciler != nil {
+        a.reconciler.Start()
+    }
+
+    if a.tailSampler != nil {
+        a.tailSampler.Start()
+    }
+
+    if a.usageAccountant != nil {
+        a.usageAccountant.Start()
+    }
+    if a.deduper != nil {
+        a.deduper.Start()
+    }
+    if a.powVerifier != nil {
+        a.powVerifier.Start()
+    }
 }
 
 // postProcess is called after shutdown signal is received
@@ -101,7 +860,8 @@ func (a *App) postProcess() {
 // This implements the full lifecycle: startup -> run -> graceful shutdown
 func (a *App) Run() error {
 	// Create context for application lifecycle management
-	_, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	a.ctx = ctx
 	a.cancel = cancel
 
 	// Initialize all dependencies
@@ -116,7 +876,15 @@ func (a *App) Run() error {
 		// Add middleware in correct order (Story 1.9 - CORS must be FIRST)
 		// Per ADR-005: CORS first to handle preflight before auth/validation
 
-		// 1. CORS middleware (Story 1.9)
+		// 1. Private Network Access preflight support: must run before CORS
+		// since CORS answers OPTIONS preflights itself
+		e.Use(privateNetworkAccessMiddleware(a.config))
+
+		// 2. CORS diagnostics: logs/counts requests with a rejected Origin
+		// before CORS processes them, so misconfigured origins are visible
+		e.Use(corsDiagnosticsMiddleware(a.config))
+
+		// 3. CORS middleware (Story 1.9)
         e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
             AllowOrigins:     a.config.AllowedOrigins,
             AllowMethods:     []string{http.MethodPost, http.MethodOptions},
@@ -124,18 +892,22 @@ func (a *App) Run() error {
             AllowCredentials: true, // Enable cookies/credentials for browser RUM
         }))
 
-		// 2. Body size limit middleware (Story 1.9)
+		// 4. Body size limit middleware (Story 1.9)
 		// Protects against memory exhaustion from large payloads
 		limit := fmt.Sprintf("%dM", a.config.MaxRequestSizeMB)
 		e.Use(middleware.BodyLimit(limit))
 
-		// 3. Logging
-		e.Use(middleware.Logger())
+		// 5. Logging
+		if a.config.AccessLogFormat == "ecs" {
+			e.Use(ecsAccessLogMiddleware())
+		} else {
+			e.Use(middleware.Logger())
+		}
 
-		// 4. Panic recovery
+		// 6. Panic recovery
 		e.Use(middleware.Recover())
 
-		// 5. Readiness check middleware (Story 1.6 - Graceful Shutdown)
+		// 7. Readiness check middleware (Story 1.6 - Graceful Shutdown)
 		// This middleware rejects requests when readiness=false, except for health endpoints
 		e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 			return func(c echo.Context) error {
@@ -151,22 +923,35 @@ func (a *App) Run() error {
 			}
 		})
 
-		// 6. Prometheus metrics middleware (Story 1.8)
-		// This automatically tracks HTTP requests and exposes /metrics endpoint
-		e.Use(echoprometheus.NewMiddleware("zep_logger"))
-		e.GET("/metrics", echoprometheus.NewHandler())
+		// 8. Prometheus metrics middleware (Story 1.8)
+		// This automatically tracks HTTP requests and exposes /metrics endpoint.
+		// Skipped when metrics_port is set: buildMetricsEcho serves /metrics
+		// (and health) on that dedicated internal listener instead.
+		if a.config.MetricsPort <= 0 {
+			e.Use(echoprometheus.NewMiddleware("zep_logger"))
+			e.Use(metricsProtectionMiddleware(a.config))
+			e.GET("/metrics", echoprometheus.NewHandler())
+		}
 
-		// 7. Update queue depth metric on each request
+		// 9. Update queue depth metric on each request
 		e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 			return func(c echo.Context) error {
                 if a.forwarder != nil {
-                    metrics.QueueDepthGauge.Set(float64(a.forwarder.GetQueueDepth()))
+                    depth := a.forwarder.GetQueueDepth()
+                    for _, f := range a.signalForwarders {
+                        depth += f.GetQueueDepth()
+                    }
+                    metrics.QueueDepthGauge.Set(float64(depth))
 				}
 				return next(c)
 			}
 		})
 
-		// 8. Setup all handler routes
+		// 10. Latency injection middleware (staging-only chaos testing)
+		// Config-gated: no-op unless latency_injection_enabled=true
+		e.Use(latencyInjectionMiddleware(a.config))
+
+		// 11. Setup all handler routes
 		for _, handler := range a.httpHandlers {
 			handler.SetupRoutes(e)
 		}
@@ -178,11 +963,65 @@ func (a *App) Run() error {
 
 		// Start server
 		// http.ErrServerClosed is expected during graceful shutdown, not an actual error
-		if err := e.Start(addr); err != nil && err != http.ErrServerClosed {
+		if a.config.TLSEnabled {
+			tlsConfig, err := tlspolicy.Build(tlspolicy.Config{
+				MinVersion:       a.config.TLSMinVersion,
+				CipherSuites:     a.config.TLSCipherSuites,
+				CurvePreferences: a.config.TLSCurvePreferences,
+			})
+			if err != nil {
+				logger.Error("Failed to build listener TLS policy, falling back to Go's default: %v", err)
+				tlsConfig = &tls.Config{}
+			}
+			cert, err := tls.LoadX509KeyPair(a.config.TLSCertFile, a.config.TLSKeyFile)
+			if err != nil {
+				logger.Error("Failed to load TLS certificate/key, server not started: %v", err)
+				return
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+			// Set directly on e.TLSServer (rather than using e.StartTLS,
+			// which always builds its own tls.Config and would silently
+			// drop our min version/cipher suite/curve policy) so that
+			// e.Shutdown still finds and closes this server.
+			e.TLSServer.Addr = addr
+			e.TLSServer.TLSConfig = tlsConfig
+			if err := e.StartServer(e.TLSServer); err != nil && err != http.ErrServerClosed {
+				logger.Error("Server error: %v", err)
+			}
+		} else if err := e.Start(addr); err != nil && err != http.ErrServerClosed {
 			logger.Error("Server error: %v", err)
 		}
 	}()
 
+    // Optional nethttp ingest engine: serves /v1/logs and /v1/traces off a
+    // plain net/http server on its own port, bypassing Echo's middleware chain
+    if a.config.IngestEngine == "nethttp" {
+        nethttpAddr := fmt.Sprintf(":%d", a.config.IngestNetHTTPPort)
+        a.nethttpServer = &http.Server{
+            Addr:    nethttpAddr,
+            Handler: proxy.NewNetHTTPHandler(a.proxyHandler),
+        }
+        go func() {
+            logger.Info("Starting nethttp ingest listener on %s", nethttpAddr)
+            if err := a.nethttpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+                logger.Error("nethttp ingest listener error: %v", err)
+            }
+        }()
+    }
+
+    // Optional dedicated metrics/health listener: keeps /metrics, /healthz,
+    // /readyz, and /healthz/details off the public server_port entirely
+    if a.config.MetricsPort > 0 {
+        metricsAddr := fmt.Sprintf(":%d", a.config.MetricsPort)
+        a.metricsEcho = a.buildMetricsEcho()
+        go func() {
+            logger.Info("Starting metrics listener on %s", metricsAddr)
+            if err := a.metricsEcho.Start(metricsAddr); err != nil && err != http.ErrServerClosed {
+                logger.Error("metrics listener error: %v", err)
+            }
+        }()
+    }
+
 	// Wait for interrupt signal (SIGINT or SIGTERM)
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
@@ -203,15 +1042,55 @@ func (a *App) Run() error {
 
 	// Step 3: Stop worker pool (finish in-flight jobs)
 	logger.Info("Stopping worker pool...")
+    shutdownTimeout := time.Duration(a.config.ShutdownTimeoutSeconds) * time.Second
+    // One shared deadline covers forwarder drain (Flush + Stop) and, below,
+    // Echo's own Shutdown - so a slow flush eats into the time left for Stop
+    // and the HTTP server shutdown instead of each getting its own fresh
+    // shutdownTimeout and the total wait silently multiplying.
+    shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+    defer shutdownCancel()
+
     if a.forwarder != nil {
-        a.forwarder.Stop()
+        if err := a.forwarder.Flush(shutdownCtx); err != nil {
+            logger.Warn("Shutdown: forwarder did not report idle within %v: %v", shutdownTimeout, err)
+        }
+        if err := a.forwarder.Stop(shutdownCtx); err != nil {
+            logger.Warn("Shutdown: forwarder did not stop cleanly within %v: %v", shutdownTimeout, err)
+        }
+    }
+    for _, f := range a.signalForwarders {
+        if err := f.Flush(shutdownCtx); err != nil {
+            logger.Warn("Shutdown: signal forwarder did not report idle within %v: %v", shutdownTimeout, err)
+        }
+        if err := f.Stop(shutdownCtx); err != nil {
+            logger.Warn("Shutdown: signal forwarder did not stop cleanly within %v: %v", shutdownTimeout, err)
+        }
+    }
+    a.watchdog.Stop()
+    if a.canary != nil {
+        a.canary.Stop()
+    }
+    if a.reconciler != nil {
+        a.reconciler.Stop()
+    }
+    if a.tailSampler != nil {
+        a.tailSampler.Stop()
+    }
+    if a.usageAccountant != nil {
+        a.usageAccountant.Stop()
+    }
+    if a.deduper != nil {
+        a.deduper.Stop()
+    }
+    if a.powVerifier != nil {
+        a.powVerifier.Stop()
+    }
+    for _, p := range a.rateLimitPersisters {
+        p.Stop()
     }
 
-	// Step 4: Shutdown Echo server with timeout
-	shutdownTimeout := time.Duration(a.config.ShutdownTimeoutSeconds) * time.Second
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
-	defer shutdownCancel()
-
+	// Step 4: Shutdown Echo server, sharing the same deadline forwarder drain
+	// used above
 	logger.Info("Shutting down Echo server...")
 	if err := a.echo.Shutdown(shutdownCtx); err != nil {
 		logger.Error("Shutdown error: %v", err)
@@ -219,6 +1098,20 @@ func (a *App) Run() error {
 		return err
 	}
 
+    if a.nethttpServer != nil {
+        logger.Info("Shutting down nethttp ingest listener...")
+        if err := a.nethttpServer.Shutdown(shutdownCtx); err != nil {
+            logger.Error("nethttp ingest listener shutdown error: %v", err)
+        }
+    }
+
+    if a.metricsEcho != nil {
+        logger.Info("Shutting down metrics listener...")
+        if err := a.metricsEcho.Shutdown(shutdownCtx); err != nil {
+            logger.Error("metrics listener shutdown error: %v", err)
+        }
+    }
+
 	// Step 5: Cancel application context (signals cleanup to other goroutines)
 	a.cancel()
 
@@ -1,14 +1,37 @@
 package app
 
 import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"go.uber.org/atomic"
 
 	"zep-logger/internal/config"
+	"zep-logger/internal/handler/http/health"
+	"zep-logger/pkg/logger"
 )
 
+// fakeForwarder is a minimal forwarder.Forwarder used to verify WithForwarder
+// injects it instead of injectDependency building one from forwarding_mode
+type fakeForwarder struct {
+	started bool
+}
+
+func (f *fakeForwarder) Start()             { f.started = true }
+func (f *fakeForwarder) Stop(ctx context.Context) error { return nil }
+func (f *fakeForwarder) GetQueueDepth() int { return 0 }
+func (f *fakeForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	return nil
+}
+func (f *fakeForwarder) Flush(ctx context.Context) error { return nil }
+
 // TestApp_ReadinessFlag_StartsAsFalse verifies readiness flag initialization
 // AC5: Graceful shutdown test verifies readiness flag starts as false
 func TestApp_ReadinessFlag_StartsAsFalse(t *testing.T) {
@@ -143,13 +166,41 @@ func TestApp_InjectDependency_CreatesHandlers(t *testing.T) {
 		t.Error("expected HTTP handlers to be created, got none")
 	}
 
-	// Expected handlers: HealthHandler, ProxyHandler
-	expectedHandlerCount := 2
+	// Expected handlers: HealthHandler, ProxyHandler, AdminHandler
+	expectedHandlerCount := 3
 	if len(app.httpHandlers) != expectedHandlerCount {
 		t.Errorf("expected %d handlers, got %d", expectedHandlerCount, len(app.httpHandlers))
 	}
 }
 
+// TestApp_InjectDependency_LogsForwardingModeOverride_CreatesDistinctForwarder
+// verifies logs_forwarding_mode builds an independent forwarder for /v1/logs
+// while /v1/traces keeps using the shared default forwarder
+func TestApp_InjectDependency_LogsForwardingModeOverride_CreatesDistinctForwarder(t *testing.T) {
+	cfg := &config.Config{
+		ServerPort:             8080,
+		OtelCollectorTargetURL: "http://localhost:4318",
+		ShutdownDrainSeconds:   1,
+		ShutdownTimeoutSeconds: 5,
+		WorkerPoolSize:         2,
+		JobQueueSize:           10,
+		AllowedOrigins:         []string{"*"},
+		MaxRequestSizeMB:       1,
+		LogsForwardingMode:     "semaphore",
+		LogsSemaphoreMaxConcurrent: 5,
+	}
+
+	app := NewApp(cfg)
+	app.injectDependency()
+
+	if len(app.signalForwarders) != 1 {
+		t.Fatalf("expected exactly 1 signal-specific forwarder override, got %d", len(app.signalForwarders))
+	}
+	if app.signalForwarders[0] == app.forwarder {
+		t.Error("expected logs override forwarder to be distinct from the shared default forwarder")
+	}
+}
+
 // TestApp_WorkerPool_Lifecycle verifies worker pool start/stop
 func TestApp_WorkerPool_Lifecycle(t *testing.T) {
 	cfg := &config.Config{
@@ -214,3 +265,102 @@ func TestApp_DrainPeriod_Duration(t *testing.T) {
 		}
 	}
 }
+
+// TestApp_BuildMetricsEcho_ServesHealthAndMetrics verifies the dedicated
+// metrics/health listener built when metrics_port is configured registers
+// both the health endpoints and /metrics on its own *echo.Echo
+func TestApp_BuildMetricsEcho_ServesHealthAndMetrics(t *testing.T) {
+	cfg := &config.Config{
+		ServerPort:             8080,
+		OtelCollectorTargetURL: "http://localhost:4318",
+		AllowedOrigins:         []string{"*"},
+		MaxRequestSizeMB:       1,
+		MetricsPort:            9464,
+	}
+	app := NewApp(cfg)
+	app.healthHandler = health.NewHealthHandler(app.readiness)
+
+	metricsEcho := app.buildMetricsEcho()
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	metricsEcho.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected /healthz to return 200, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec = httptest.NewRecorder()
+	metricsEcho.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected /metrics to return 200, got %d", rec.Code)
+	}
+}
+
+// TestApp_WithForwarder_OverridesForwardingMode verifies WithForwarder wins
+// over forwarding_mode-driven forwarder selection in injectDependency
+func TestApp_WithForwarder_OverridesForwardingMode(t *testing.T) {
+	cfg := &config.Config{
+		ServerPort:             8080,
+		OtelCollectorTargetURL: "http://localhost:4318",
+		ShutdownTimeoutSeconds: 5,
+		WorkerPoolSize:         2,
+		JobQueueSize:           10,
+		AllowedOrigins:         []string{"*"},
+		MaxRequestSizeMB:       1,
+		ForwardingMode:         "semaphore",
+	}
+	fake := &fakeForwarder{}
+
+	app := NewApp(cfg, WithForwarder(fake))
+	app.injectDependency()
+
+	if app.forwarder != fake {
+		t.Error("expected app.forwarder to be the fakeForwarder supplied via WithForwarder")
+	}
+	if app.workerPool != nil {
+		t.Error("expected no worker pool to be built when the forwarder was overridden")
+	}
+}
+
+// TestApp_WithLogger_RedirectsPackageLoggerOutput verifies WithLogger
+// redirects pkg/logger output instead of leaving it on stdout/stderr
+func TestApp_WithLogger_RedirectsPackageLoggerOutput(t *testing.T) {
+	var buf bytes.Buffer
+	defer logger.SetOutput(os.Stderr) // pkg/logger is package-global; restore so later tests in this package aren't silently redirected
+
+	cfg := &config.Config{
+		ServerPort:             8080,
+		OtelCollectorTargetURL: "http://localhost:4318",
+		AllowedOrigins:         []string{"*"},
+		MaxRequestSizeMB:       1,
+	}
+	NewApp(cfg, WithLogger(&buf))
+	logger.Info("hello from test")
+
+	if !strings.Contains(buf.String(), "hello from test") {
+		t.Errorf("expected redirected log output to contain the logged message, got %q", buf.String())
+	}
+}
+
+// TestApp_WithListener_SetsEchoListener verifies WithListener plumbs the
+// supplied net.Listener through to the primary Echo instance
+func TestApp_WithListener_SetsEchoListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open listener: %v", err)
+	}
+	defer ln.Close()
+
+	cfg := &config.Config{
+		ServerPort:             8080,
+		OtelCollectorTargetURL: "http://localhost:4318",
+		AllowedOrigins:         []string{"*"},
+		MaxRequestSizeMB:       1,
+	}
+	app := NewApp(cfg, WithListener(ln))
+
+	if app.echo.Listener != ln {
+		t.Error("expected app.echo.Listener to be the net.Listener supplied via WithListener")
+	}
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	"zep-logger/internal/config"
+	"zep-logger/internal/metrics"
+	"zep-logger/pkg/logger"
+)
+
+// corsDiagnosticsMaxOrigins bounds the number of distinct origin label values
+// CORSRejectedCounter will accumulate; beyond this, further offending origins
+// are counted under the "other" label to keep cardinality bounded
+const corsDiagnosticsMaxOrigins = 50
+
+var (
+	corsDiagnosticsSeenMu sync.Mutex
+	corsDiagnosticsSeen   = make(map[string]struct{})
+)
+
+// corsDiagnosticsMiddleware logs and counts requests whose Origin header does
+// not match allowed_origins, so a misconfigured origin shows up in metrics
+// and logs instead of just silently never arriving at the SDK's collector.
+// Runs before the CORS middleware so it sees the raw Origin header regardless
+// of what CORSWithConfig does with the request.
+func corsDiagnosticsMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			origin := c.Request().Header.Get("Origin")
+			if origin != "" && !originAllowed(origin, cfg.AllowedOrigins) {
+				logger.Info("DEBUG: CORS rejected origin=%q (missing header: Access-Control-Allow-Origin)", origin)
+				metrics.CORSRejectedCounter.WithLabelValues(corsOriginLabel(origin)).Inc()
+			}
+			return next(c)
+		}
+	}
+}
+
+// originAllowed reports whether origin matches one of allowedOrigins, honoring
+// the "*" wildcard used elsewhere for allowed_origins
+func originAllowed(origin string, allowedOrigins []string) bool {
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// corsOriginLabel returns origin as-is if we're still under
+// corsDiagnosticsMaxOrigins distinct labels, otherwise "other"
+func corsOriginLabel(origin string) string {
+	corsDiagnosticsSeenMu.Lock()
+	defer corsDiagnosticsSeenMu.Unlock()
+
+	if _, ok := corsDiagnosticsSeen[origin]; ok {
+		return origin
+	}
+	if len(corsDiagnosticsSeen) >= corsDiagnosticsMaxOrigins {
+		return "other"
+	}
+	corsDiagnosticsSeen[origin] = struct{}{}
+	return origin
+}
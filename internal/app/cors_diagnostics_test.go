@@ -0,0 +1,76 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"zep-logger/internal/config"
+	"zep-logger/internal/metrics"
+)
+
+// TestCORSDiagnostics_AllowedOrigin_NotCounted verifies a matching origin is
+// never counted or logged as rejected
+func TestCORSDiagnostics_AllowedOrigin_NotCounted(t *testing.T) {
+	e := echo.New()
+	cfg := &config.Config{AllowedOrigins: []string{"https://good.example.com"}}
+	e.Use(corsDiagnosticsMiddleware(cfg))
+	e.GET("/v1/logs", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	before := testutil.ToFloat64(metrics.CORSRejectedCounter.WithLabelValues("https://good.example.com"))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/logs", nil)
+	req.Header.Set("Origin", "https://good.example.com")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	after := testutil.ToFloat64(metrics.CORSRejectedCounter.WithLabelValues("https://good.example.com"))
+	if after != before {
+		t.Errorf("expected no increment for an allowed origin, before=%v after=%v", before, after)
+	}
+}
+
+// TestCORSDiagnostics_RejectedOrigin_Counted verifies a non-matching origin
+// increments CORSRejectedCounter labeled by that origin
+func TestCORSDiagnostics_RejectedOrigin_Counted(t *testing.T) {
+	e := echo.New()
+	cfg := &config.Config{AllowedOrigins: []string{"https://good.example.com"}}
+	e.Use(corsDiagnosticsMiddleware(cfg))
+	e.GET("/v1/logs", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	before := testutil.ToFloat64(metrics.CORSRejectedCounter.WithLabelValues("https://evil.example.com"))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/logs", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	after := testutil.ToFloat64(metrics.CORSRejectedCounter.WithLabelValues("https://evil.example.com"))
+	if after != before+1 {
+		t.Errorf("expected counter to increment by 1, before=%v after=%v", before, after)
+	}
+}
+
+// TestCORSDiagnostics_WildcardAllowed_NeverCounted verifies "*" in
+// allowed_origins matches any origin
+func TestCORSDiagnostics_WildcardAllowed_NeverCounted(t *testing.T) {
+	e := echo.New()
+	cfg := &config.Config{AllowedOrigins: []string{"*"}}
+	e.Use(corsDiagnosticsMiddleware(cfg))
+	e.GET("/v1/logs", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	before := testutil.ToFloat64(metrics.CORSRejectedCounter.WithLabelValues("https://anything.example.com"))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/logs", nil)
+	req.Header.Set("Origin", "https://anything.example.com")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	after := testutil.ToFloat64(metrics.CORSRejectedCounter.WithLabelValues("https://anything.example.com"))
+	if after != before {
+		t.Errorf("expected no increment when allowed_origins is wildcard, before=%v after=%v", before, after)
+	}
+}
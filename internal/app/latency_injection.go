@@ -0,0 +1,51 @@
+package app
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"zep-logger/internal/config"
+)
+
+// latencyInjectionMiddleware injects artificial latency/jitter into ingest
+// responses for a configured percentage of requests. Disabled by default;
+// intended for staging so SDK teams can exercise timeout/retry handling
+// against a real proxy instead of a mock.
+func latencyInjectionMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.LatencyInjectionEnabled && isIngestPath(c.Request().URL.Path) && shouldInject(cfg.LatencyInjectionPercent) {
+				time.Sleep(jitterDuration(cfg.LatencyInjectionMinMS, cfg.LatencyInjectionMaxMS))
+			}
+			return next(c)
+		}
+	}
+}
+
+// isIngestPath reports whether path is one of the OTLP ingest endpoints
+func isIngestPath(path string) bool {
+	return strings.HasPrefix(path, "/v1/")
+}
+
+// shouldInject randomly selects a request for latency injection based on percent (0-100)
+func shouldInject(percent int) bool {
+	if percent <= 0 {
+		return false
+	}
+	if percent >= 100 {
+		return true
+	}
+	return rand.Intn(100) < percent
+}
+
+// jitterDuration returns a random duration in [minMS, maxMS], clamped to a sane order
+func jitterDuration(minMS, maxMS int) time.Duration {
+	if maxMS <= minMS {
+		return time.Duration(minMS) * time.Millisecond
+	}
+	jittered := minMS + rand.Intn(maxMS-minMS+1)
+	return time.Duration(jittered) * time.Millisecond
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"zep-logger/internal/config"
+)
+
+// TestLatencyInjection_Disabled_NoDelay verifies no latency is added when disabled
+func TestLatencyInjection_Disabled_NoDelay(t *testing.T) {
+	e := echo.New()
+	cfg := &config.Config{LatencyInjectionEnabled: false}
+	e.Use(latencyInjectionMiddleware(cfg))
+	e.POST("/v1/logs", func(c echo.Context) error { return c.NoContent(http.StatusAccepted) })
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", nil)
+	rec := httptest.NewRecorder()
+
+	start := time.Now()
+	e.ServeHTTP(rec, req)
+	elapsed := time.Since(start)
+
+	if elapsed > 50*time.Millisecond {
+		t.Errorf("expected no injected delay when disabled, took %v", elapsed)
+	}
+}
+
+// TestLatencyInjection_AlwaysOn_DelaysIngestPath verifies delay is applied at 100%
+func TestLatencyInjection_AlwaysOn_DelaysIngestPath(t *testing.T) {
+	e := echo.New()
+	cfg := &config.Config{
+		LatencyInjectionEnabled: true,
+		LatencyInjectionPercent: 100,
+		LatencyInjectionMinMS:   50,
+		LatencyInjectionMaxMS:   50,
+	}
+	e.Use(latencyInjectionMiddleware(cfg))
+	e.POST("/v1/logs", func(c echo.Context) error { return c.NoContent(http.StatusAccepted) })
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", nil)
+	rec := httptest.NewRecorder()
+
+	start := time.Now()
+	e.ServeHTTP(rec, req)
+	elapsed := time.Since(start)
+
+	if elapsed < 50*time.Millisecond {
+		t.Errorf("expected at least 50ms injected delay, took %v", elapsed)
+	}
+}
+
+// TestLatencyInjection_NonIngestPath_NeverDelayed verifies non-ingest paths are unaffected
+func TestLatencyInjection_NonIngestPath_NeverDelayed(t *testing.T) {
+	e := echo.New()
+	cfg := &config.Config{
+		LatencyInjectionEnabled: true,
+		LatencyInjectionPercent: 100,
+		LatencyInjectionMinMS:   200,
+		LatencyInjectionMaxMS:   200,
+	}
+	e.Use(latencyInjectionMiddleware(cfg))
+	e.GET("/healthz", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+
+	start := time.Now()
+	e.ServeHTTP(rec, req)
+	elapsed := time.Since(start)
+
+	if elapsed > 50*time.Millisecond {
+		t.Errorf("expected no injected delay on non-ingest path, took %v", elapsed)
+	}
+}
+
+// TestJitterDuration_RespectsRange verifies returned durations stay within bounds
+func TestJitterDuration_RespectsRange(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := jitterDuration(10, 20)
+		if d < 10*time.Millisecond || d > 20*time.Millisecond {
+			t.Fatalf("jitterDuration out of range: %v", d)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"zep-logger/internal/config"
+)
+
+// metricsProtectionMiddleware restricts GET /metrics, since operational
+// metrics (queue depth, per-target latency, tenant labels) shouldn't be
+// readable by anyone who can reach the proxy on an internet-facing
+// deployment. No-op for every other path. When both metrics_auth_token and
+// metrics_allowed_cidrs are empty (the original behavior), /metrics stays
+// unrestricted.
+func metricsProtectionMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+	allowed := parseCIDRs(cfg.MetricsAllowedCIDRs)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().URL.Path != "/metrics" {
+				return next(c)
+			}
+			if len(allowed) > 0 && !ipAllowed(c.RealIP(), allowed) {
+				return c.NoContent(http.StatusForbidden)
+			}
+			if cfg.MetricsAuthToken != "" && bearerToken(c.Request()) != cfg.MetricsAuthToken {
+				return c.NoContent(http.StatusUnauthorized)
+			}
+			return next(c)
+		}
+	}
+}
+
+// parseCIDRs parses cidrs into *net.IPNet, skipping entries that fail to
+// parse - config.Load already rejects an invalid entry at startup, so this
+// only defends against being constructed with an unvalidated Config (e.g. in
+// tests)
+func parseCIDRs(cidrs []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			nets = append(nets, ipNet)
+		}
+	}
+	return nets
+}
+
+// ipAllowed reports whether ip (as returned by echo.Context.RealIP) falls
+// within any of allowed
+func ipAllowed(ip string, allowed []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range allowed {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header, empty if absent or malformed
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(auth, prefix)
+}
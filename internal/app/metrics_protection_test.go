@@ -0,0 +1,111 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"zep-logger/internal/config"
+)
+
+// TestMetricsProtection_Unrestricted_AllowsAnyCaller verifies /metrics stays
+// open when neither metrics_auth_token nor metrics_allowed_cidrs is set
+func TestMetricsProtection_Unrestricted_AllowsAnyCaller(t *testing.T) {
+	e := echo.New()
+	e.Use(metricsProtectionMiddleware(&config.Config{}))
+	e.GET("/metrics", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rec.Code)
+	}
+}
+
+// TestMetricsProtection_OtherPaths_NeverRestricted verifies the middleware
+// only ever inspects the /metrics path
+func TestMetricsProtection_OtherPaths_NeverRestricted(t *testing.T) {
+	e := echo.New()
+	e.Use(metricsProtectionMiddleware(&config.Config{MetricsAuthToken: "secret", MetricsAllowedCIDRs: []string{"10.0.0.0/8"}}))
+	e.GET("/healthz", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rec.Code)
+	}
+}
+
+// TestMetricsProtection_AuthToken_RejectsMissingOrWrongToken verifies the
+// endpoint requires the configured bearer token
+func TestMetricsProtection_AuthToken_RejectsMissingOrWrongToken(t *testing.T) {
+	e := echo.New()
+	e.Use(metricsProtectionMiddleware(&config.Config{MetricsAuthToken: "secret"}))
+	e.GET("/metrics", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 Unauthorized for wrong token, got %d", rec.Code)
+	}
+}
+
+// TestMetricsProtection_AuthToken_AllowsCorrectToken verifies a matching
+// bearer token is admitted
+func TestMetricsProtection_AuthToken_AllowsCorrectToken(t *testing.T) {
+	e := echo.New()
+	e.Use(metricsProtectionMiddleware(&config.Config{MetricsAuthToken: "secret"}))
+	e.GET("/metrics", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 OK for correct token, got %d", rec.Code)
+	}
+}
+
+// TestMetricsProtection_CIDR_RejectsOutsideAllowlist verifies a caller whose
+// RealIP falls outside every allowed CIDR is forbidden
+func TestMetricsProtection_CIDR_RejectsOutsideAllowlist(t *testing.T) {
+	e := echo.New()
+	e.Use(metricsProtectionMiddleware(&config.Config{MetricsAllowedCIDRs: []string{"10.0.0.0/8"}}))
+	e.GET("/metrics", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.RemoteAddr = "203.0.113.5:1234"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 Forbidden for IP outside allowlist, got %d", rec.Code)
+	}
+}
+
+// TestMetricsProtection_CIDR_AllowsInAllowlist verifies a caller whose RealIP
+// falls within an allowed CIDR is admitted
+func TestMetricsProtection_CIDR_AllowsInAllowlist(t *testing.T) {
+	e := echo.New()
+	e.Use(metricsProtectionMiddleware(&config.Config{MetricsAllowedCIDRs: []string{"10.0.0.0/8"}}))
+	e.GET("/metrics", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.RemoteAddr = "10.1.2.3:1234"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 OK for IP inside allowlist, got %d", rec.Code)
+	}
+}
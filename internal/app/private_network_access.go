@@ -0,0 +1,26 @@
+package app
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"zep-logger/internal/config"
+)
+
+// privateNetworkAccessMiddleware answers Chrome's Private Network Access
+// preflight check: when a public site's browser calls this proxy and Chrome
+// adds Access-Control-Request-Private-Network: true to the preflight, we must
+// echo Access-Control-Allow-Private-Network: true or the request is blocked.
+// Must run before the CORS middleware, since CORS answers OPTIONS preflights
+// itself and never calls next() for them - the header has to already be set
+// by the time that happens. Disabled by default since it only makes sense
+// for intranet-deployed proxies that expect public-site callers.
+func privateNetworkAccessMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.PrivateNetworkAccessEnabled && c.Request().Header.Get("Access-Control-Request-Private-Network") == "true" {
+				c.Response().Header().Set("Access-Control-Allow-Private-Network", "true")
+			}
+			return next(c)
+		}
+	}
+}
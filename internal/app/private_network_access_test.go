@@ -0,0 +1,67 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"zep-logger/internal/config"
+)
+
+// TestPrivateNetworkAccess_Disabled_HeaderNotSet verifies the header is never
+// added when the feature is disabled, even on a PNA preflight request
+func TestPrivateNetworkAccess_Disabled_HeaderNotSet(t *testing.T) {
+	e := echo.New()
+	cfg := &config.Config{PrivateNetworkAccessEnabled: false}
+	e.Use(privateNetworkAccessMiddleware(cfg))
+	e.OPTIONS("/v1/logs", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+	req := httptest.NewRequest(http.MethodOptions, "/v1/logs", nil)
+	req.Header.Set("Access-Control-Request-Private-Network", "true")
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Private-Network"); got != "" {
+		t.Errorf("expected no PNA header when disabled, got %q", got)
+	}
+}
+
+// TestPrivateNetworkAccess_Enabled_EchoesHeaderOnPreflight verifies the header
+// is echoed back when enabled and the request is a PNA preflight
+func TestPrivateNetworkAccess_Enabled_EchoesHeaderOnPreflight(t *testing.T) {
+	e := echo.New()
+	cfg := &config.Config{PrivateNetworkAccessEnabled: true}
+	e.Use(privateNetworkAccessMiddleware(cfg))
+	e.OPTIONS("/v1/logs", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+	req := httptest.NewRequest(http.MethodOptions, "/v1/logs", nil)
+	req.Header.Set("Access-Control-Request-Private-Network", "true")
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Private-Network"); got != "true" {
+		t.Errorf("expected Access-Control-Allow-Private-Network=true, got %q", got)
+	}
+}
+
+// TestPrivateNetworkAccess_Enabled_NoPreflightHeader_HeaderNotSet verifies the
+// header is only added in response to an actual PNA preflight request
+func TestPrivateNetworkAccess_Enabled_NoPreflightHeader_HeaderNotSet(t *testing.T) {
+	e := echo.New()
+	cfg := &config.Config{PrivateNetworkAccessEnabled: true}
+	e.Use(privateNetworkAccessMiddleware(cfg))
+	e.POST("/v1/logs", func(c echo.Context) error { return c.NoContent(http.StatusAccepted) })
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", nil)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Private-Network"); got != "" {
+		t.Errorf("expected no PNA header without the preflight request header, got %q", got)
+	}
+}
@@ -0,0 +1,204 @@
+package archive
+
+import (
+    "archive/tar"
+    "encoding/binary"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+// manifestFileName and payloadsFileName are the fixed entry names inside a bundle tar
+const (
+    manifestFileName = "manifest.json"
+    payloadsFileName = "payloads.bin"
+)
+
+// ManifestEntry describes one spooled payload inside a bundle
+type ManifestEntry struct {
+    ID          string `json:"id"`
+    TargetURL   string `json:"target_url"`
+    ContentType string `json:"content_type"`
+    Length      int64  `json:"length"`
+}
+
+// manifest is the top-level manifest.json structure
+type manifest struct {
+    Entries []ManifestEntry `json:"entries"`
+}
+
+// SpoolEntry is one payload read from (or written to) a spool directory
+// Operates over the directory layout shared by the dead-letter store and
+// disk-backed queue: "<id>.bin" holds the raw body, "<id>.json" holds metadata
+type SpoolEntry struct {
+    ID          string
+    TargetURL   string
+    ContentType string
+    Body        []byte
+}
+
+type spoolMeta struct {
+    TargetURL   string `json:"target_url"`
+    ContentType string `json:"content_type"`
+}
+
+// Export bundles every payload in spoolDir into a portable tar file at bundlePath
+// The bundle contains a manifest and a single stream of length-prefixed payloads,
+// so it can be shipped as one file to an air-gapped environment
+func Export(spoolDir string, bundlePath string) error {
+    entries, err := readSpoolDir(spoolDir)
+    if err != nil {
+        return err
+    }
+
+    out, err := os.Create(bundlePath)
+    if err != nil {
+        return fmt.Errorf("archive: failed to create bundle %s: %w", bundlePath, err)
+    }
+    defer out.Close()
+
+    tw := tar.NewWriter(out)
+    defer tw.Close()
+
+    m := manifest{}
+    var payloads []byte
+    for _, e := range entries {
+        m.Entries = append(m.Entries, ManifestEntry{ID: e.ID, TargetURL: e.TargetURL, ContentType: e.ContentType, Length: int64(len(e.Body))})
+
+        var lenPrefix [4]byte
+        binary.BigEndian.PutUint32(lenPrefix[:], uint32(len(e.Body)))
+        payloads = append(payloads, lenPrefix[:]...)
+        payloads = append(payloads, e.Body...)
+    }
+
+    manifestBytes, err := json.MarshalIndent(m, "", "  ")
+    if err != nil {
+        return fmt.Errorf("archive: failed to encode manifest: %w", err)
+    }
+
+    if err := writeTarEntry(tw, manifestFileName, manifestBytes); err != nil {
+        return err
+    }
+    if err := writeTarEntry(tw, payloadsFileName, payloads); err != nil {
+        return err
+    }
+
+    return nil
+}
+
+// Import extracts every payload from bundlePath back into spoolDir as individual files
+func Import(bundlePath string, spoolDir string) error {
+    f, err := os.Open(bundlePath)
+    if err != nil {
+        return fmt.Errorf("archive: failed to open bundle %s: %w", bundlePath, err)
+    }
+    defer f.Close()
+
+    var m manifest
+    var payloads []byte
+
+    tr := tar.NewReader(f)
+    for {
+        hdr, err := tr.Next()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return fmt.Errorf("archive: failed to read bundle: %w", err)
+        }
+
+        data, err := io.ReadAll(tr)
+        if err != nil {
+            return fmt.Errorf("archive: failed to read entry %s: %w", hdr.Name, err)
+        }
+
+        switch hdr.Name {
+        case manifestFileName:
+            if err := json.Unmarshal(data, &m); err != nil {
+                return fmt.Errorf("archive: failed to decode manifest: %w", err)
+            }
+        case payloadsFileName:
+            payloads = data
+        }
+    }
+
+    if err := os.MkdirAll(spoolDir, 0o755); err != nil {
+        return fmt.Errorf("archive: failed to create spool dir %s: %w", spoolDir, err)
+    }
+
+    offset := 0
+    for _, entry := range m.Entries {
+        if offset+4 > len(payloads) {
+            return fmt.Errorf("archive: bundle truncated at entry %s", entry.ID)
+        }
+        length := int(binary.BigEndian.Uint32(payloads[offset : offset+4]))
+        offset += 4
+        if offset+length > len(payloads) {
+            return fmt.Errorf("archive: bundle truncated at entry %s", entry.ID)
+        }
+        body := payloads[offset : offset+length]
+        offset += length
+
+        if err := writeSpoolEntry(spoolDir, SpoolEntry{ID: entry.ID, TargetURL: entry.TargetURL, ContentType: entry.ContentType, Body: body}); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+func writeTarEntry(tw *tar.Writer, name string, data []byte) error {
+    if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data))}); err != nil {
+        return fmt.Errorf("archive: failed to write tar header for %s: %w", name, err)
+    }
+    if _, err := tw.Write(data); err != nil {
+        return fmt.Errorf("archive: failed to write tar body for %s: %w", name, err)
+    }
+    return nil
+}
+
+func readSpoolDir(spoolDir string) ([]SpoolEntry, error) {
+    files, err := os.ReadDir(spoolDir)
+    if err != nil {
+        return nil, fmt.Errorf("archive: failed to read spool dir %s: %w", spoolDir, err)
+    }
+
+    var entries []SpoolEntry
+    for _, file := range files {
+        if file.IsDir() || !strings.HasSuffix(file.Name(), ".bin") {
+            continue
+        }
+        id := strings.TrimSuffix(file.Name(), ".bin")
+
+        body, err := os.ReadFile(filepath.Join(spoolDir, file.Name()))
+        if err != nil {
+            return nil, fmt.Errorf("archive: failed to read %s: %w", file.Name(), err)
+        }
+
+        var meta spoolMeta
+        if metaBytes, err := os.ReadFile(filepath.Join(spoolDir, id+".json")); err == nil {
+            _ = json.Unmarshal(metaBytes, &meta)
+        }
+
+        entries = append(entries, SpoolEntry{ID: id, TargetURL: meta.TargetURL, ContentType: meta.ContentType, Body: body})
+    }
+    return entries, nil
+}
+
+func writeSpoolEntry(spoolDir string, e SpoolEntry) error {
+    if err := os.WriteFile(filepath.Join(spoolDir, e.ID+".bin"), e.Body, 0o644); err != nil {
+        return fmt.Errorf("archive: failed to write %s.bin: %w", e.ID, err)
+    }
+
+    metaBytes, err := json.Marshal(spoolMeta{TargetURL: e.TargetURL, ContentType: e.ContentType})
+    if err != nil {
+        return fmt.Errorf("archive: failed to encode metadata for %s: %w", e.ID, err)
+    }
+    if err := os.WriteFile(filepath.Join(spoolDir, e.ID+".json"), metaBytes, 0o644); err != nil {
+        return fmt.Errorf("archive: failed to write %s.json: %w", e.ID, err)
+    }
+    return nil
+}
@@ -0,0 +1,77 @@
+package archive
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+// TestExportImport_RoundTrip verifies a spool directory survives an export
+// followed by an import into a fresh directory
+func TestExportImport_RoundTrip(t *testing.T) {
+    spoolDir := t.TempDir()
+    if err := writeSpoolEntry(spoolDir, SpoolEntry{ID: "job-1", TargetURL: "http://collector/v1/logs", ContentType: "application/x-protobuf", Body: []byte("payload-one")}); err != nil {
+        t.Fatalf("failed to seed spool entry: %v", err)
+    }
+    if err := writeSpoolEntry(spoolDir, SpoolEntry{ID: "job-2", TargetURL: "http://collector/v1/traces", ContentType: "application/x-protobuf", Body: []byte("payload-two")}); err != nil {
+        t.Fatalf("failed to seed spool entry: %v", err)
+    }
+
+    bundlePath := filepath.Join(t.TempDir(), "bundle.tar")
+    if err := Export(spoolDir, bundlePath); err != nil {
+        t.Fatalf("failed to export bundle: %v", err)
+    }
+
+    restoreDir := t.TempDir()
+    if err := Import(bundlePath, restoreDir); err != nil {
+        t.Fatalf("failed to import bundle: %v", err)
+    }
+
+    entries, err := readSpoolDir(restoreDir)
+    if err != nil {
+        t.Fatalf("failed to read restored spool dir: %v", err)
+    }
+    if len(entries) != 2 {
+        t.Fatalf("expected 2 restored entries, got %d", len(entries))
+    }
+
+    byID := make(map[string]SpoolEntry)
+    for _, e := range entries {
+        byID[e.ID] = e
+    }
+
+    got, ok := byID["job-1"]
+    if !ok {
+        t.Fatal("expected job-1 to be restored")
+    }
+    if string(got.Body) != "payload-one" || got.TargetURL != "http://collector/v1/logs" {
+        t.Errorf("job-1 restored incorrectly: %+v", got)
+    }
+}
+
+// TestExport_EmptySpoolDir verifies exporting an empty spool produces a valid, empty bundle
+func TestExport_EmptySpoolDir(t *testing.T) {
+    spoolDir := t.TempDir()
+    bundlePath := filepath.Join(t.TempDir(), "bundle.tar")
+
+    if err := Export(spoolDir, bundlePath); err != nil {
+        t.Fatalf("failed to export empty spool: %v", err)
+    }
+
+    if _, err := os.Stat(bundlePath); err != nil {
+        t.Fatalf("expected bundle file to exist: %v", err)
+    }
+
+    restoreDir := t.TempDir()
+    if err := Import(bundlePath, restoreDir); err != nil {
+        t.Fatalf("failed to import empty bundle: %v", err)
+    }
+
+    entries, err := readSpoolDir(restoreDir)
+    if err != nil {
+        t.Fatalf("failed to read restored spool dir: %v", err)
+    }
+    if len(entries) != 0 {
+        t.Errorf("expected 0 restored entries, got %d", len(entries))
+    }
+}
@@ -0,0 +1,115 @@
+package archivesink
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// S3Config configures an S3Uploader. No AWS SDK is vendored in this repo,
+// so uploads are signed by hand with SigV4 against the plain HTTP PUT
+// object API - the same approach internal/geoip and internal/ingesttoken
+// take to avoid a dependency that isn't already in go.mod.
+type S3Config struct {
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Endpoint        string // optional override for S3-compatible stores; defaults to https://s3.<region>.amazonaws.com
+	Prefix          string // optional key prefix, e.g. "otlpxy-archive/"
+}
+
+// S3Uploader uploads archive files to a single S3 bucket via signed PUT
+// requests. It implements Uploader.
+type S3Uploader struct {
+	cfg        S3Config
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewS3Uploader creates an S3Uploader from cfg.
+func NewS3Uploader(cfg S3Config) *S3Uploader {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	return &S3Uploader{
+		cfg:        cfg,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Upload reads localPath and PUTs it to the configured bucket under
+// Prefix+basename(localPath).
+func (u *S3Uploader) Upload(localPath string) error {
+	body, err := os.ReadFile(localPath)
+	if err != nil {
+		return fmt.Errorf("archivesink: read %s: %w", localPath, err)
+	}
+
+	key := u.cfg.Prefix + filepath.Base(localPath)
+	url := fmt.Sprintf("%s/%s/%s", u.endpoint, u.cfg.Bucket, key)
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("archivesink: build S3 request: %w", err)
+	}
+	signSigV4(req, body, u.cfg.Region, u.cfg.AccessKeyID, u.cfg.SecretAccessKey, time.Now().UTC())
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("archivesink: S3 PUT %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("archivesink: S3 PUT %s returned %d", key, resp.StatusCode)
+	}
+	return nil
+}
+
+// signSigV4 adds the Authorization, X-Amz-Date and X-Amz-Content-Sha256
+// headers implementing AWS Signature Version 4 for a single-chunk PUT,
+// per https://docs.aws.amazon.com/general/latest/gr/sigv4-signed-request.html.
+func signSigV4(req *http.Request, body []byte, region, accessKeyID, secretAccessKey string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("Host", req.URL.Host)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, payloadHash, amzDate)
+	canonicalRequest := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s",
+		req.Method, req.URL.EscapedPath(), req.URL.RawQuery, canonicalHeaders, signedHeaders, payloadHash)
+
+	credentialScope := fmt.Sprintf("%s/%s/s3/aws4_request", dateStamp, region)
+	stringToSign := fmt.Sprintf("AWS4-HMAC-SHA256\n%s\n%s\n%s", amzDate, credentialScope, sha256Hex([]byte(canonicalRequest)))
+
+	signingKey := hmacSHA256(hmacSHA256(hmacSHA256(hmacSHA256([]byte("AWS4"+secretAccessKey), dateStamp), region), "s3"), "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	authHeader := fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, credentialScope, signedHeaders, signature)
+	req.Header.Set("Authorization", authHeader)
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,188 @@
+// Package archivesink writes accepted payloads to rotating local ndjson
+// files, optionally uploading each completed file to an S3 bucket, for
+// compliance retention and replay independent of whatever forwarding mode
+// is delivering the same payload to a collector.
+package archivesink
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"zep-logger/internal/spoolcrypto"
+	"zep-logger/pkg/logger"
+)
+
+// Entry is one accepted payload recorded to the archive.
+type Entry struct {
+	Timestamp   time.Time         `json:"timestamp"`
+	TargetURL   string            `json:"target_url"`
+	ContentType string            `json:"content_type"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	BodyBase64  string            `json:"body_base64"`
+}
+
+// Uploader ships a completed local archive file elsewhere (e.g. S3) once
+// it's done being written to. Upload is called from its own goroutine, so
+// implementations don't need to worry about blocking Sink.Write.
+type Uploader interface {
+	Upload(localPath string) error
+}
+
+// Sink appends Entry records as ndjson to a local file in outputDir,
+// rotating to a new file once the current one reaches maxFileSizeBytes. If
+// an Uploader is configured, each rotated-out file is handed off to it in
+// the background and removed locally once the upload succeeds; a failed
+// upload leaves the file on disk for a future retry pass (this package
+// doesn't retry automatically).
+type Sink struct {
+	outputDir       string
+	maxFileSizeBytes int64
+	uploader        Uploader
+	encryptor       *spoolcrypto.KeySet // Encrypts each rotated-out file at rest, nil disables encryption (the original behavior)
+
+	mu          sync.Mutex
+	file        *os.File
+	currentPath string
+	currentSize int64
+
+	wg sync.WaitGroup
+}
+
+// New creates a Sink writing ndjson files into outputDir, rotating once a
+// file reaches maxFileSizeMB. uploader may be nil to keep archives local
+// only. encryptor may be nil to leave rotated files in plaintext (the
+// original behavior); when set, each file is sealed whole at rotation
+// time, before it's handed to uploader.
+func New(outputDir string, maxFileSizeMB int, uploader Uploader, encryptor *spoolcrypto.KeySet) *Sink {
+	if maxFileSizeMB <= 0 {
+		maxFileSizeMB = 100
+	}
+	return &Sink{
+		outputDir:        outputDir,
+		maxFileSizeBytes: int64(maxFileSizeMB) * 1024 * 1024,
+		uploader:         uploader,
+		encryptor:        encryptor,
+	}
+}
+
+// Write appends entry as one ndjson line, rotating the current file first
+// if it would exceed maxFileSizeBytes.
+func (s *Sink) Write(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("archivesink: marshal entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		if err := s.openLocked(); err != nil {
+			return err
+		}
+	} else if s.currentSize+int64(len(line)) > s.maxFileSizeBytes {
+		s.rotateLocked()
+		if err := s.openLocked(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.currentSize += int64(n)
+	if err != nil {
+		return fmt.Errorf("archivesink: write entry: %w", err)
+	}
+	return nil
+}
+
+// openLocked opens a new archive file named by the current time, so files
+// sort chronologically and never collide. Callers must hold s.mu.
+func (s *Sink) openLocked() error {
+	if err := os.MkdirAll(s.outputDir, 0o755); err != nil {
+		return fmt.Errorf("archivesink: create output dir %s: %w", s.outputDir, err)
+	}
+	path := filepath.Join(s.outputDir, fmt.Sprintf("archive-%d.ndjson", time.Now().UnixNano()))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("archivesink: open %s: %w", path, err)
+	}
+	s.file = f
+	s.currentPath = path
+	s.currentSize = 0
+	return nil
+}
+
+// rotateLocked closes the current file, encrypts it in place if an
+// encryptor is configured, and, if an uploader is configured, hands it off
+// for upload in the background. Callers must hold s.mu.
+func (s *Sink) rotateLocked() {
+	if s.file == nil {
+		return
+	}
+	s.file.Close()
+	closedPath := s.currentPath
+	s.file = nil
+	s.currentPath = ""
+	s.currentSize = 0
+
+	if s.encryptor != nil {
+		if err := s.encryptFile(closedPath); err != nil {
+			logger.Error("Archive sink: failed to encrypt %s, leaving it in plaintext: %v", closedPath, err)
+		}
+	}
+
+	if s.uploader != nil {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.upload(closedPath)
+		}()
+	}
+}
+
+// encryptFile seals path's contents whole and overwrites it in place. Done
+// synchronously (unlike upload) since it's local disk I/O, not a network
+// call, and later steps (upload) need the encrypted bytes.
+func (s *Sink) encryptFile(path string) error {
+	plaintext, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("archivesink: read %s: %w", path, err)
+	}
+	ciphertext, err := s.encryptor.Encrypt(plaintext)
+	if err != nil {
+		return fmt.Errorf("archivesink: encrypt %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, ciphertext, 0o644); err != nil {
+		return fmt.Errorf("archivesink: write encrypted %s: %w", path, err)
+	}
+	return nil
+}
+
+func (s *Sink) upload(path string) {
+	if err := s.uploader.Upload(path); err != nil {
+		return
+	}
+	os.Remove(path)
+}
+
+// Stop rotates out (and, if configured, uploads) any file still open, then
+// waits for pending uploads to finish.
+func (s *Sink) Stop() {
+	s.mu.Lock()
+	s.rotateLocked()
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// EncodeBody base64-encodes body for BodyBase64, the encoding ndjson
+// records use so archived payloads round-trip through JSON regardless of
+// content (protobuf, gzip, etc).
+func EncodeBody(body []byte) string {
+	return base64.StdEncoding.EncodeToString(body)
+}
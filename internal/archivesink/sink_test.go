@@ -0,0 +1,156 @@
+package archivesink
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"zep-logger/internal/spoolcrypto"
+)
+
+func TestSink_Write_AppendsNdjsonLine(t *testing.T) {
+	dir := t.TempDir()
+	sink := New(dir, 100, nil, nil)
+	defer sink.Stop()
+
+	entry := Entry{
+		Timestamp:   time.Unix(0, 0).UTC(),
+		TargetURL:   "https://collector.internal:4318/v1/logs",
+		ContentType: "application/x-protobuf",
+		Headers:     map[string]string{"x-tenant": "acme"},
+		BodyBase64:  EncodeBody([]byte("hello")),
+	}
+	if err := sink.Write(entry); err != nil {
+		t.Fatalf("Write returned unexpected error: %v", err)
+	}
+	sink.Stop()
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read output dir: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 archive file, got %d", len(files))
+	}
+
+	f, err := os.Open(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		t.Fatalf("failed to open archive file: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		t.Fatal("expected at least one line in the archive file")
+	}
+	var decoded Entry
+	if err := json.Unmarshal(scanner.Bytes(), &decoded); err != nil {
+		t.Fatalf("failed to unmarshal ndjson line: %v", err)
+	}
+	if decoded.TargetURL != entry.TargetURL || decoded.BodyBase64 != entry.BodyBase64 {
+		t.Errorf("decoded entry = %+v, want %+v", decoded, entry)
+	}
+}
+
+func TestSink_Write_RotatesOnceMaxSizeExceeded(t *testing.T) {
+	dir := t.TempDir()
+	// Every entry below serializes to well over a few bytes, so a 1-byte
+	// max forces a rotation on every write after the first.
+	sink := New(dir, 0, nil, nil)
+	sink.maxFileSizeBytes = 1
+	defer sink.Stop()
+
+	for i := 0; i < 3; i++ {
+		entry := Entry{Timestamp: time.Now(), TargetURL: "https://collector.internal:4318", BodyBase64: EncodeBody([]byte("x"))}
+		if err := sink.Write(entry); err != nil {
+			t.Fatalf("Write returned unexpected error: %v", err)
+		}
+	}
+	sink.Stop()
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read output dir: %v", err)
+	}
+	if len(files) != 3 {
+		t.Errorf("expected 3 rotated archive files, got %d", len(files))
+	}
+}
+
+type fakeUploader struct {
+	uploaded chan string
+}
+
+func (f *fakeUploader) Upload(localPath string) error {
+	f.uploaded <- localPath
+	return nil
+}
+
+func TestSink_Stop_UploadsAndRemovesRotatedFile(t *testing.T) {
+	dir := t.TempDir()
+	uploader := &fakeUploader{uploaded: make(chan string, 1)}
+	sink := New(dir, 100, uploader, nil)
+
+	entry := Entry{Timestamp: time.Now(), TargetURL: "https://collector.internal:4318", BodyBase64: EncodeBody([]byte("x"))}
+	if err := sink.Write(entry); err != nil {
+		t.Fatalf("Write returned unexpected error: %v", err)
+	}
+	sink.Stop()
+
+	select {
+	case path := <-uploader.uploaded:
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected local file %s to be removed after upload, stat err: %v", path, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("uploader never received the rotated file")
+	}
+}
+
+func TestSink_Stop_EncryptsRotatedFile(t *testing.T) {
+	dir := t.TempDir()
+	encryptor, err := spoolcrypto.NewKeySet("v1", map[string]string{"v1": "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"})
+	if err != nil {
+		t.Fatalf("NewKeySet returned unexpected error: %v", err)
+	}
+	sink := New(dir, 100, nil, encryptor)
+
+	entry := Entry{Timestamp: time.Now(), TargetURL: "https://collector.internal:4318", BodyBase64: EncodeBody([]byte("secret RUM payload"))}
+	if err := sink.Write(entry); err != nil {
+		t.Fatalf("Write returned unexpected error: %v", err)
+	}
+	sink.Stop()
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read output dir: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 archive file, got %d", len(files))
+	}
+
+	onDisk, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		t.Fatalf("failed to read archive file: %v", err)
+	}
+	if bytes.Contains(onDisk, []byte(entry.BodyBase64)) {
+		t.Fatal("expected the archive file on disk to not contain the plaintext ndjson")
+	}
+
+	decrypted, err := encryptor.Decrypt(onDisk)
+	if err != nil {
+		t.Fatalf("Decrypt returned unexpected error: %v", err)
+	}
+
+	var decoded Entry
+	if err := json.Unmarshal(bytes.TrimRight(decrypted, "\n"), &decoded); err != nil {
+		t.Fatalf("failed to unmarshal decrypted ndjson line: %v", err)
+	}
+	if decoded.BodyBase64 != entry.BodyBase64 {
+		t.Errorf("decoded entry BodyBase64 = %q, want %q", decoded.BodyBase64, entry.BodyBase64)
+	}
+}
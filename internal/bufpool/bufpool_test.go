@@ -0,0 +1,59 @@
+package bufpool
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadAll_ReturnsExpectedBytes(t *testing.T) {
+	got, err := ReadAll(strings.NewReader("hello world"))
+	if err != nil {
+		t.Fatalf("ReadAll returned error: %v", err)
+	}
+	if string(got) != "hello world" {
+		t.Errorf("expected %q, got %q", "hello world", got)
+	}
+}
+
+func TestReadAll_ResultSurvivesPoolReuse(t *testing.T) {
+	first, err := ReadAll(strings.NewReader("first payload"))
+	if err != nil {
+		t.Fatalf("ReadAll returned error: %v", err)
+	}
+
+	// Drive enough further reads through the pool to make reuse of the
+	// same underlying buffer likely, then confirm the earlier result
+	// wasn't mutated by a later read reusing its backing array.
+	for i := 0; i < 8; i++ {
+		if _, err := ReadAll(strings.NewReader("subsequent payload that is longer")); err != nil {
+			t.Fatalf("ReadAll returned error: %v", err)
+		}
+	}
+
+	if string(first) != "first payload" {
+		t.Errorf("expected earlier result to remain %q, got %q", "first payload", first)
+	}
+}
+
+func TestGetPut_BufferIsResetOnReuse(t *testing.T) {
+	buf := Get()
+	buf.WriteString("stale data")
+	Put(buf)
+
+	reused := Get()
+	if reused.Len() != 0 {
+		t.Errorf("expected reused buffer to be empty, got %q", reused.String())
+	}
+	Put(reused)
+}
+
+func TestReadAll_EmptyReader(t *testing.T) {
+	got, err := ReadAll(bytes.NewReader(nil))
+	if err != nil {
+		t.Fatalf("ReadAll returned error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected empty result, got %q", got)
+	}
+}
@@ -0,0 +1,134 @@
+package canary
+
+import (
+    "context"
+    "fmt"
+    "net"
+    "net/http"
+    "sync"
+    "time"
+
+    "go.uber.org/atomic"
+
+    "zep-logger/internal/forwarder"
+    "zep-logger/internal/metrics"
+    "zep-logger/pkg/logger"
+)
+
+// Canary continuously drives synthetic traffic through the proxy's real
+// forwarding path to an internal mock collector, then reconciles sent vs
+// received counts to detect silent data loss before real traffic does.
+// Intended to run as a continuous soak-test canary in pre-prod clusters.
+type Canary struct {
+    fwd       forwarder.Forwarder
+    interval  time.Duration
+    mockAddr  string
+    mockSrv   *http.Server
+    sent      atomic.Int64
+    received  atomic.Int64
+    stopCh    chan struct{}
+    wg        sync.WaitGroup
+    startOnce sync.Once
+    stopOnce  sync.Once
+}
+
+// New creates a Canary that submits synthetic requests via fwd at the given interval
+// Binds a lightweight mock collector to a loopback ephemeral port
+func New(fwd forwarder.Forwarder, interval time.Duration) (*Canary, error) {
+    if interval <= 0 {
+        interval = 30 * time.Second
+    }
+
+    ln, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        return nil, fmt.Errorf("canary: failed to bind mock collector: %w", err)
+    }
+
+    c := &Canary{
+        fwd:      fwd,
+        interval: interval,
+        mockAddr: ln.Addr().String(),
+        stopCh:   make(chan struct{}),
+    }
+
+    mux := http.NewServeMux()
+    mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+        c.received.Inc()
+        metrics.CanaryReceivedCounter.Inc()
+        w.WriteHeader(http.StatusOK)
+    })
+    c.mockSrv = &http.Server{Handler: mux}
+
+    go func() {
+        if err := c.mockSrv.Serve(ln); err != nil && err != http.ErrServerClosed {
+            logger.Error("Canary: mock collector server error: %v", err)
+        }
+    }()
+
+    return c, nil
+}
+
+// Start begins generating synthetic traffic and periodically reconciling delivery counts
+func (c *Canary) Start() {
+    c.startOnce.Do(func() {
+        logger.Info("Canary started: mockCollector=%s, interval=%v", c.mockAddr, c.interval)
+        c.wg.Add(1)
+        go c.run()
+    })
+}
+
+// Stop halts traffic generation and shuts down the mock collector
+func (c *Canary) Stop() {
+    c.stopOnce.Do(func() {
+        close(c.stopCh)
+        c.wg.Wait()
+
+        shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        if err := c.mockSrv.Shutdown(shutdownCtx); err != nil {
+            logger.Warn("Canary: mock collector shutdown error: %v", err)
+        }
+    })
+}
+
+func (c *Canary) run() {
+    defer c.wg.Done()
+
+    ticker := time.NewTicker(c.interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-c.stopCh:
+            return
+        case <-ticker.C:
+            c.sendSyntheticRequest()
+            c.reconcile()
+        }
+    }
+}
+
+// sendSyntheticRequest submits a minimal synthetic log payload through the
+// real forwarding path (the same Forwarder used for production traffic)
+func (c *Canary) sendSyntheticRequest() {
+    body := []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"canary"}}]}]}]}`)
+    headers := http.Header{"Content-Type": []string{"application/json"}}
+
+    if err := c.fwd.Submit(context.Background(), body, "http://"+c.mockAddr+"/v1/logs", headers); err != nil {
+        logger.Warn("Canary: failed to submit synthetic request: %v", err)
+        return
+    }
+    c.sent.Inc()
+    metrics.CanarySentCounter.Inc()
+}
+
+// reconcile compares sent vs received counts and emits a divergence metric
+// A brief lag is expected since delivery is async; sustained non-zero divergence
+// across windows is the signal worth alerting on.
+func (c *Canary) reconcile() {
+    divergence := c.sent.Load() - c.received.Load()
+    metrics.CanaryDivergenceGauge.Set(float64(divergence))
+    if divergence > 0 {
+        logger.Warn("Canary: divergence detected, sent=%d received=%d", c.sent.Load(), c.received.Load())
+    }
+}
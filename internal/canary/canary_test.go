@@ -0,0 +1,37 @@
+package canary
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "zep-logger/internal/forwarder"
+    "zep-logger/internal/httpclient"
+    "zep-logger/internal/worker"
+)
+
+// TestCanary_SendsAndReceivesSyntheticTraffic verifies synthetic requests flow
+// through the real forwarder to the internal mock collector
+func TestCanary_SendsAndReceivesSyntheticTraffic(t *testing.T) {
+    pool := worker.NewPool(2, 10, 5*time.Second, httpclient.Config{})
+    pf := forwarder.NewPoolForwarder(pool)
+    pf.Start()
+    defer pf.Stop(context.Background())
+
+    c, err := New(pf, 20*time.Millisecond)
+    if err != nil {
+        t.Fatalf("failed to create canary: %v", err)
+    }
+
+    c.Start()
+    defer c.Stop()
+
+    deadline := time.Now().Add(2 * time.Second)
+    for time.Now().Before(deadline) {
+        if c.sent.Load() > 0 && c.received.Load() > 0 {
+            return
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+    t.Fatalf("expected canary to send and receive synthetic traffic, sent=%d received=%d", c.sent.Load(), c.received.Load())
+}
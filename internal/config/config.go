@@ -1,16 +1,22 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net"
+	"sort"
 
 	"github.com/spf13/viper"
+
+	"zep-logger/internal/tlspolicy"
 )
 
 // Config holds all configuration values for the application
 type Config struct {
 	OtelCollectorTargetURL string `mapstructure:"otel_collector_target_url"`
 	OtelCollectorAPIKey    string `mapstructure:"otel_collector_api_key"`
+	GCPIDTokenAudience     string `mapstructure:"gcp_id_token_audience"` // When set, forward requests carry a Google-signed ID token (fetched from the GCE/Cloud Run metadata server, auto-refreshed) audienced to this value instead of otel_collector_api_key - for a collector running on Cloud Run behind IAM. Typically the collector's own https:// URL. Empty disables it (the original static-api-key-only behavior)
 	ShutdownDrainSeconds   int    `mapstructure:"shutdown_drain_seconds"`
 	ShutdownTimeoutSeconds int    `mapstructure:"shutdown_timeout_seconds"`
 	ServerPort             int    `mapstructure:"server_port"`
@@ -18,9 +24,388 @@ type Config struct {
 	JobQueueSize           int    `mapstructure:"job_queue_size"`
 	AllowedOrigins         []string `mapstructure:"allowed_origins"` // CORS allowed origins
 	MaxRequestSizeMB       int    `mapstructure:"max_request_size_mb"`      // Request body size limit in MB
-    ForwardingMode         string `mapstructure:"forwarding_mode"`          // "pool" or "semaphore"
+    PrivateNetworkAccessEnabled bool `mapstructure:"private_network_access_enabled"` // Answer Chrome's Private Network Access preflight for intranet-deployed proxies called from public sites
+    ForwardingMode         string `mapstructure:"forwarding_mode"`          // "pool", "semaphore", "hybrid", "grpc", "failover", "loadbalance", "adaptive", "kafka", "natsjs", or "loopback"
     SemaphoreMaxConcurrent int    `mapstructure:"semaphore_max_concurrent"` // Max concurrent requests in semaphore mode
+    AdaptiveMinConcurrent        int `mapstructure:"adaptive_min_concurrent"`         // Starting/floor concurrency limit in adaptive mode
+    AdaptiveMaxConcurrent        int `mapstructure:"adaptive_max_concurrent"`         // Ceiling concurrency limit in adaptive mode
+    AdaptiveLatencyThresholdMS   int `mapstructure:"adaptive_latency_threshold_ms"`   // Requests at/above this latency count as "slow" and shrink the limit
+    GRPCMaxConcurrent        int `mapstructure:"grpc_max_concurrent"`         // Max concurrent in-flight RPCs in grpc mode
+    GRPCRequestTimeoutSeconds int `mapstructure:"grpc_request_timeout_seconds"` // Per-RPC deadline in grpc mode
+    ForwarderRequestTimeoutSeconds      int `mapstructure:"forwarder_request_timeout_seconds"`       // Overall per-request deadline for pool/semaphore/hybrid forwarding modes (0 = use built-in default of 10s)
+    ForwarderDialTimeoutSeconds         int `mapstructure:"forwarder_dial_timeout_seconds"`          // TCP connect deadline for pool/semaphore/hybrid forwarding modes (0 = use built-in default of 30s)
+    ForwarderTLSHandshakeTimeoutSeconds int `mapstructure:"forwarder_tls_handshake_timeout_seconds"` // TLS handshake deadline for pool/semaphore/hybrid forwarding modes (0 = use built-in default of 10s)
+    ForwarderIdleConnTimeoutSeconds     int `mapstructure:"forwarder_idle_conn_timeout_seconds"`     // How long an idle keep-alive connection is kept for pool/semaphore/hybrid forwarding modes (0 = use built-in default of 90s)
+    ForwarderMaxIdleConnsPerHost        int `mapstructure:"forwarder_max_idle_conns_per_host"`       // Idle keep-alive connections kept per upstream host for pool/semaphore/hybrid forwarding modes (0 = use built-in default of one per worker/concurrent sender)
+    ForwarderMaxConnsPerHost            int `mapstructure:"forwarder_max_conns_per_host"`            // Total connections (idle + in-flight) allowed per upstream host for pool/semaphore/hybrid forwarding modes (0 = use built-in default of 2x worker/concurrent sender count)
+    ForwarderDisableHTTP2               bool `mapstructure:"forwarder_disable_http2"`                // Force HTTP/1.1 to the upstream collector for pool/semaphore/hybrid forwarding modes instead of attempting HTTP/2 upgrade; false preserves the original behavior
     SyncLogsDebug          bool   `mapstructure:"sync_logs_debug"`          // If true, /v1/logs forwards synchronously
+    SyncLogsStreamingEnabled bool `mapstructure:"sync_logs_streaming_enabled"` // If true (and sync_logs_debug), streams the /v1/logs body straight through to the collector via io.Pipe instead of buffering it with io.ReadAll, cutting memory per request for large payloads. Only takes effect when no feature that needs the full body first (resource attribute rewrites, schema normalization, resource routing rules, dedup, usage accounting, forward_compression) is configured - falls back to the buffered path otherwise.
+    DebugDumpDir           string `mapstructure:"debug_dump_dir"`           // Directory for admin-triggered diagnostic dumps
+    WatchdogRSSThresholdMB       int `mapstructure:"watchdog_rss_threshold_mb"`         // Capture heap profile once RSS exceeds this (0 = disabled)
+    WatchdogQueueDepthThreshold  int `mapstructure:"watchdog_queue_depth_threshold"`    // Capture heap profile once queue depth exceeds this (0 = disabled)
+    WatchdogCheckIntervalSeconds int `mapstructure:"watchdog_check_interval_seconds"`   // How often the watchdog samples RSS/queue depth
+    WatchdogMinDumpIntervalSeconds int `mapstructure:"watchdog_min_dump_interval_seconds"` // Minimum time between captured profiles (rate limit)
+    LatencyInjectionEnabled bool `mapstructure:"latency_injection_enabled"` // Staging-only: inject artificial latency into ingest responses
+    LatencyInjectionPercent int  `mapstructure:"latency_injection_percent"` // Percentage (0-100) of ingest requests affected
+    LatencyInjectionMinMS   int  `mapstructure:"latency_injection_min_ms"`  // Minimum injected delay in milliseconds
+    LatencyInjectionMaxMS   int  `mapstructure:"latency_injection_max_ms"`  // Maximum injected delay in milliseconds
+    CanaryEnabled         bool `mapstructure:"canary_enabled"`          // Continuously soak-test the forwarding path against an internal mock collector
+    CanaryIntervalSeconds int  `mapstructure:"canary_interval_seconds"` // How often synthetic canary requests are sent
+    ReconciliationEnabled           bool   `mapstructure:"reconciliation_enabled"`             // Compare forwarded-vs-accepted counts against the collector's own metrics
+    ReconciliationCollectorMetricsURL string `mapstructure:"reconciliation_collector_metrics_url"` // Collector's Prometheus metrics endpoint
+    ReconciliationMetricName        string `mapstructure:"reconciliation_metric_name"`         // Collector-side metric representing accepted requests
+    ReconciliationIntervalSeconds   int    `mapstructure:"reconciliation_interval_seconds"`    // How often to scrape and reconcile
+    ResourceAttributeRewriteRules []ResourceAttributeRewriteRule `mapstructure:"resource_attribute_rewrite_rules"` // Config-driven resource attribute rewrites applied to decoded logs/traces
+    WorkerPoolRetryMaxAttempts       int     `mapstructure:"worker_pool_retry_max_attempts"`        // Total delivery attempts before dropping a job (1 = no retries)
+    WorkerPoolRetryInitialBackoffMS  int     `mapstructure:"worker_pool_retry_initial_backoff_ms"`  // Delay before the first retry
+    WorkerPoolRetryMaxBackoffMS      int     `mapstructure:"worker_pool_retry_max_backoff_ms"`      // Backoff is capped at this value
+    WorkerPoolRetryJitterFraction    float64 `mapstructure:"worker_pool_retry_jitter_fraction"`     // Randomize each backoff by this fraction (0.0-1.0)
+    SchemaNormalizationTargetSchemaURL string            `mapstructure:"schema_normalization_target_schema_url"` // Overwrites every schema_url in decoded payloads (empty = leave untouched)
+    SchemaNormalizationAttributeAliases map[string]string `mapstructure:"schema_normalization_attribute_aliases"` // Maps old semconv attribute keys to their canonical spelling
+    DeadLetterDir       string `mapstructure:"dead_letter_dir"`        // Directory for permanently-failed jobs (empty = dead-lettering disabled)
+    DeadLetterMaxSizeMB int    `mapstructure:"dead_letter_max_size_mb"` // Total on-disk size cap for the dead-letter store (0 = unbounded)
+    LoopbackCapacity    int    `mapstructure:"loopback_capacity"`       // Entries retained in memory by forwarding_mode = "loopback", queryable via GET /admin/debug/loopback (0 = built-in default of 100)
+    LoopbackDiskDir     string `mapstructure:"loopback_disk_dir"`       // Directory forwarding_mode = "loopback" additionally mirrors accepted payloads to (empty = memory only, the original behavior)
+    MetricsAuthToken       string   `mapstructure:"metrics_auth_token"`        // Value required in the Authorization: Bearer header on GET /metrics; empty disables the check
+    MetricsAllowedCIDRs    []string `mapstructure:"metrics_allowed_cidrs"`     // Client IPs (from RealIP, honoring X-Forwarded-For) allowed to reach GET /metrics; empty allows any IP (the original behavior)
+    MetricsPort            int      `mapstructure:"metrics_port"`              // If > 0, GET /metrics and the health endpoints (/healthz, /readyz, /healthz/details) are served on this dedicated internal port instead of the public server_port, so a public load balancer never has a route to them (0 = serve on server_port, the original behavior)
+    ResourceRoutingRules []ResourceRoutingRule `mapstructure:"resource_routing_rules"` // Splits one OTLP request into per-target requests grouped by resource attribute
+    WorkerPoolQueueMode      string `mapstructure:"worker_pool_queue_mode"`       // "memory" (default) or "disk" - "disk" persists queued jobs so they survive a restart
+    WorkerPoolDiskQueueDir   string `mapstructure:"worker_pool_disk_queue_dir"`   // Directory for the write-ahead log when worker_pool_queue_mode = "disk"
+    TailSamplingEnabled           bool    `mapstructure:"tail_sampling_enabled"`             // Experimental: buffer spans per trace and apply tail sampling before forwarding
+    TailSamplingWindowSeconds     int     `mapstructure:"tail_sampling_window_seconds"`      // How long spans for a trace are buffered before a keep/drop decision
+    TailSamplingSlowThresholdMS   int     `mapstructure:"tail_sampling_slow_threshold_ms"`   // A trace is "slow" if any span's duration meets or exceeds this (0 = disabled)
+    TailSamplingErrorSampleRate   float64 `mapstructure:"tail_sampling_error_sample_rate"`   // Fraction (0.0-1.0) of errored/slow traces kept
+    TailSamplingDefaultSampleRate float64 `mapstructure:"tail_sampling_default_sample_rate"` // Fraction (0.0-1.0) of otherwise-uninteresting traces kept
+    BatchingEnabled bool `mapstructure:"batching_enabled"`  // Merge queued payloads bound for the same target into larger upstream requests
+    BatchMaxSize    int  `mapstructure:"batch_max_size"`    // Flush a batch once it holds this many payloads
+    BatchMaxAgeMS   int  `mapstructure:"batch_max_age_ms"`  // Flush a batch this long after its first payload, even if not full
+    UsageAccountingEnabled        bool   `mapstructure:"usage_accounting_enabled"`         // Aggregate per-tenant bytes/records and periodically flush usage reports
+    UsageAccountingIntervalSeconds int   `mapstructure:"usage_accounting_interval_seconds"` // How often usage is flushed
+    UsageAccountingOutputDir      string `mapstructure:"usage_accounting_output_dir"`      // Directory usage report JSON files are written to (empty = disabled)
+    UsageAccountingBillingURL     string `mapstructure:"usage_accounting_billing_url"`     // Endpoint usage reports are POSTed to as JSON (empty = disabled)
+    UsageAccountingTenantHeader   string `mapstructure:"usage_accounting_tenant_header"`   // Request header used to identify the tenant
+    OtelCollectorFailoverTargets []FailoverTarget `mapstructure:"otel_collector_failover_targets"` // Additional upstream targets tried in priority order when the primary times out or returns 5xx (forwarding_mode = "failover")
+    OtelCollectorLoadBalanceTargets []LoadBalanceTarget `mapstructure:"otel_collector_load_balance_targets"` // Pool of collector endpoints to fan out across (forwarding_mode = "loadbalance")
+    LoadBalancingStrategy           string              `mapstructure:"load_balancing_strategy"`             // "round_robin" (default) or "least_pending"
+    LargePayloadOffloadEnabled   bool   `mapstructure:"large_payload_offload_enabled"`   // Spool request bodies above large_payload_threshold_bytes to disk instead of buffering the read entirely in memory
+    LargePayloadThresholdBytes   int    `mapstructure:"large_payload_threshold_bytes"`   // Bodies at or below this size are read into memory as before
+    LargePayloadTempDir          string `mapstructure:"large_payload_temp_dir"`          // Directory for spooled bodies (empty = OS default temp dir)
+    MirrorTargetURL              string `mapstructure:"mirror_target_url"`               // Optional shadow collector every forwarded payload is also best-effort teed to (empty = disabled)
+    MirrorMaxConcurrent          int    `mapstructure:"mirror_max_concurrent"`           // Max concurrent in-flight mirror sends; excess mirror sends are dropped rather than queued
+    CircuitBreakerFallbackURL          string `mapstructure:"circuit_breaker_fallback_url"`           // Secondary collector Submit is diverted to once the primary forwarder's HealthReporter score drops to 0 for circuit_breaker_open_threshold consecutive checks (empty = disabled; requires the active forwarding_mode to implement HealthReporter, currently only forwarding_mode = "pool" with health_shedding_enabled)
+    CircuitBreakerOpenThreshold        int    `mapstructure:"circuit_breaker_open_threshold"`         // Consecutive unhealthy checks before diverting to circuit_breaker_fallback_url; ignored unless circuit_breaker_fallback_url is set
+    CircuitBreakerCooldownSeconds      int    `mapstructure:"circuit_breaker_cooldown_seconds"`       // How long the circuit stays open before probing the primary again; ignored unless circuit_breaker_fallback_url is set
+    LogsForwardingMode           string `mapstructure:"logs_forwarding_mode"`            // Overrides forwarding_mode for /v1/logs only (empty = use the shared forwarding_mode)
+    LogsWorkerPoolSize           int    `mapstructure:"logs_worker_pool_size"`           // Overrides worker_pool_size for /v1/logs only (0 = use worker_pool_size)
+    LogsJobQueueSize             int    `mapstructure:"logs_job_queue_size"`             // Overrides job_queue_size for /v1/logs only (0 = use job_queue_size)
+    LogsSemaphoreMaxConcurrent   int    `mapstructure:"logs_semaphore_max_concurrent"`   // Overrides semaphore_max_concurrent for /v1/logs only (0 = use semaphore_max_concurrent)
+    TracesForwardingMode         string `mapstructure:"traces_forwarding_mode"`          // Overrides forwarding_mode for /v1/traces only (empty = use the shared forwarding_mode)
+    TracesWorkerPoolSize         int    `mapstructure:"traces_worker_pool_size"`         // Overrides worker_pool_size for /v1/traces only (0 = use worker_pool_size)
+    TracesJobQueueSize           int    `mapstructure:"traces_job_queue_size"`           // Overrides job_queue_size for /v1/traces only (0 = use job_queue_size)
+    TracesSemaphoreMaxConcurrent int    `mapstructure:"traces_semaphore_max_concurrent"` // Overrides semaphore_max_concurrent for /v1/traces only (0 = use semaphore_max_concurrent)
+    LogsForwardTimeoutMS   int `mapstructure:"logs_forward_timeout_ms"`   // Per-job delivery deadline for /v1/logs, independent of whatever Timeout is baked into the forwarder's own http.Client (0 = disabled, no override); only takes effect when forwarding_mode (or logs_forwarding_mode) is "pool", the only mode that threads a per-job timeout through worker.Job
+    TracesForwardTimeoutMS int `mapstructure:"traces_forward_timeout_ms"` // Same as logs_forward_timeout_ms, for /v1/traces
+    IngestEngine     string `mapstructure:"ingest_engine"`      // "echo" (default) or "nethttp" - "nethttp" additionally serves /v1/logs and /v1/traces off a plain net/http server, bypassing Echo's middleware chain
+    IngestNetHTTPPort int   `mapstructure:"ingest_nethttp_port"` // Port the nethttp ingest listener binds to (ignored unless ingest_engine = "nethttp")
+    RateLimitEnabled            bool    `mapstructure:"rate_limit_enabled"`               // Cap requests/sec sent to the collector (forwarding_mode = "pool" only); excess jobs simply wait longer in the job queue
+    RateLimitRequestsPerSecond  float64 `mapstructure:"rate_limit_requests_per_second"`   // Sustained token bucket refill rate, ignored unless rate_limit_enabled
+    RateLimitBurst              int     `mapstructure:"rate_limit_burst"`                 // Token bucket capacity, allowing short bursts above the sustained rate
+    RateLimitStateFile          string  `mapstructure:"rate_limit_state_file"`            // Periodically persist rate_limit_enabled's token bucket to this path and restore it on startup, so a restart doesn't hand every caller a fresh burst allowance; empty disables persistence (the bucket always starts full, the original behavior)
+    WorkerPoolLazySpawn          bool `mapstructure:"worker_pool_lazy_spawn"`           // Spawn workers on demand up to worker_pool_size instead of all up front (forwarding_mode = "pool" only)
+    WorkerPoolIdleTimeoutSeconds int  `mapstructure:"worker_pool_idle_timeout_seconds"` // How long a lazily-spawned worker waits for a job before retiring, ignored unless worker_pool_lazy_spawn
+    JobTTLEnabled bool `mapstructure:"job_ttl_enabled"` // Drop jobs that sat in the queue longer than job_ttl_seconds instead of forwarding them (forwarding_mode = "pool" only)
+    JobTTLSeconds int  `mapstructure:"job_ttl_seconds"` // Max time a job may wait in the queue before being dropped, ignored unless job_ttl_enabled
+    QueueFullPolicy string `mapstructure:"queue_full_policy"` // What happens when the worker pool's queue is full (forwarding_mode = "pool" only): "reject" rejects the new submission (default, original behavior); "drop-oldest" evicts the oldest queued job to admit it instead, since for RUM the freshest data is often more valuable than data that's already been waiting
+    WorkerPoolShutdownSpillFile string `mapstructure:"worker_pool_shutdown_spill_file"` // File to spill still-queued jobs to if shutdown_timeout_seconds is exceeded, replayed on next start (forwarding_mode = "pool" only); empty disables spilling
+    WorkerPoolMaxQueuedBytes int `mapstructure:"worker_pool_max_queued_bytes"` // Reject a new job (forwarding_mode = "pool" only) once the combined size of every currently-queued job's body would exceed this, since job_queue_size alone doesn't bound memory - 10,000 jobs at 1MB each is 10GB regardless of how small the queue is. 0 (default) disables the cap
+    ConnectionWarmupCount int `mapstructure:"connection_warmup_count"` // Pre-establish this many connections (and complete their TLS handshakes) to otel_collector_target_url during startup (forwarding_mode = "pool" only), so the first traffic spike after deploy doesn't pay handshake latency across hundreds of workers. 0 (default) disables warm-up
+    ForwarderMaxPendingJobs int `mapstructure:"forwarder_max_pending_jobs"` // Reject Submit once the configured forwarder's queue depth reaches this hard watermark, applied uniformly regardless of forwarding_mode so client-visible backpressure behavior doesn't change when the mode does (0 = disabled, each mode keeps its own native behavior)
+    ForwarderSoftMaxPendingJobs int `mapstructure:"forwarder_soft_max_pending_jobs"` // Below forwarder_max_pending_jobs, still accept Submit once the queue depth reaches this soft watermark, but flag the forwarder as saturated (see forwarder_backpressure_retry_after_seconds) so callers can hint clients to slow down before backpressure becomes an outright rejection; 0 disables the soft watermark
+    ForwarderBackpressureRetryAfterSeconds int `mapstructure:"forwarder_backpressure_retry_after_seconds"` // Retry-After seconds suggested to clients once the forwarder is saturated (queue depth >= forwarder_soft_max_pending_jobs); ignored unless forwarder_soft_max_pending_jobs is set
+    OverloadStatusCode         int `mapstructure:"overload_status_code"`           // Status code returned when /v1/logs or /v1/traces rejects a request for queue/rate/quota pressure (load shedding or a full worker queue) - 429 (clients back off and retry) or 503 (retry-forever semantics, the original behavior). True unavailability (e.g. readiness=false) always returns 503 regardless of this setting.
+    OverloadRetryAfterSeconds  int `mapstructure:"overload_retry_after_seconds"`   // Retry-After seconds set alongside overload_status_code
+    UpstreamErrorBufferSize int `mapstructure:"upstream_error_buffer_size"` // Number of recent non-2xx collector responses retained for GET /admin/debug/upstream-errors (forwarding_mode = "pool" only); 0 disables capture
+    LoadSheddingMaxQueueDepth int `mapstructure:"load_shedding_max_queue_depth"` // Queue depth at which the async proxy path starts rejecting requests by estimated cost (body size, sync vs async) instead of only rejecting once the forwarder's own queue is completely full; 0 disables cost-aware shedding
+    ProbabilisticSheddingThresholdPercent int `mapstructure:"probabilistic_shedding_threshold_percent"` // Queue saturation (as a percent of load_shedding_max_queue_depth) above which trace payloads start being shed at random regardless of size, independent of the cost-based check above; requires load_shedding_max_queue_depth; 0 disables it
+    ProbabilisticSheddingDropPercent int `mapstructure:"probabilistic_shedding_drop_percent"` // Percent of trace payloads randomly dropped once probabilistic_shedding_threshold_percent is crossed; ignored unless probabilistic_shedding_threshold_percent is set
+    TenantQueueShareHeader string `mapstructure:"tenant_queue_share_header"` // Request header identifying the tenant a job is charged against for tenant_queue_share_max_pending (forwarding_mode = "pool" only); a job missing this header is bucketed as "unknown"
+    TenantQueueShareMaxPending int `mapstructure:"tenant_queue_share_max_pending"` // Max jobs a single tenant may have pending in the worker pool's queue at once, so one tenant's burst can't fill it and starve everyone else; 0 disables per-tenant limiting (a tenant may fill the whole queue, the original behavior)
+    TenantWeights map[string]int `mapstructure:"tenant_weights"` // Tenant (as identified by tenant_queue_share_header) -> relative dequeue weight (forwarding_mode = "pool" only); a higher-weight tenant is dequeued proportionally more often under contention. A tenant absent from this map defaults to weight 1. Empty disables weighted scheduling (plain FIFO, the original behavior).
+    ForwardCompression string `mapstructure:"forward_compression"` // Re-encode request bodies with this codec ("gzip" or "zstd") before forwarding to the collector, skipping bodies that arrived already encoded; reduces east-west bandwidth for large protobuf batches at the cost of CPU. Empty disables re-encoding (bodies are forwarded as-is, the original behavior).
+    TenantConcurrencyHeader string `mapstructure:"tenant_concurrency_header"` // Request header identifying the tenant a request is charged against for tenant_concurrency_max_per_tenant (forwarding_mode = "semaphore" or "hybrid" only); a request missing this header is bucketed as "unknown"
+    TenantConcurrencyMaxPerTenant int `mapstructure:"tenant_concurrency_max_per_tenant"` // Max requests a single tenant may have in flight at once, in addition to the forwarder's global concurrency limit, so a tenant with a slow dedicated downstream route can't consume every token; 0 disables per-tenant isolation (a tenant may consume up to the full global limit, the original behavior)
+    IngestByteRateLimitEnabled          bool    `mapstructure:"ingest_byte_rate_limit_enabled"`            // Cap total ingest bytes/sec across /v1/logs and /v1/traces combined, rejecting the excess with 429 + Retry-After instead of letting a traffic spike amplify unbounded downstream
+    IngestByteRateLimitBytesPerSecond   float64 `mapstructure:"ingest_byte_rate_limit_bytes_per_second"`   // Sustained token bucket refill rate in bytes/sec, ignored unless ingest_byte_rate_limit_enabled
+    IngestByteRateLimitBurstBytes       int     `mapstructure:"ingest_byte_rate_limit_burst_bytes"`        // Token bucket capacity in bytes, allowing short bursts above the sustained rate
+    IngestByteRateLimitStateFile        string  `mapstructure:"ingest_byte_rate_limit_state_file"`         // Periodically persist ingest_byte_rate_limit_enabled's token bucket to this path and restore it on startup, so a restart doesn't hand every caller a fresh burst allowance; empty disables persistence (the bucket always starts full, the original behavior)
+    RateLimitStatePersistIntervalSeconds int    `mapstructure:"rate_limit_state_persist_interval_seconds"` // How often a configured rate_limit_state_file/ingest_byte_rate_limit_state_file is rewritten with the limiter's current token count, ignored unless at least one of those is set
+    MaxDecompressedBodyBytes int `mapstructure:"max_decompressed_body_bytes"` // Reject a gzip/zstd/deflate-encoded ingest body once its decompressed size exceeds this, independent of the wire-level Content-Length limit which can't see past the encoding to catch a zip-bomb-style payload; 0 disables the cap (bodies decompress to whatever size they decode to, the original behavior)
+    JobTrackingEnabled  bool `mapstructure:"job_tracking_enabled"`  // Track each job's delivery status (queued/sent/failed) by id for GET /admin/debug/jobs/:id (forwarding_mode = "pool" only); false disables tracking (Submit's job id is always empty, the original behavior)
+    JobTrackingCapacity int  `mapstructure:"job_tracking_capacity"` // Max number of job statuses retained at once, oldest evicted first, ignored unless job_tracking_enabled
+    HealthSheddingEnabled            bool    `mapstructure:"health_shedding_enabled"`               // Probabilistically reject async requests as the collector's recent latency/error rate degrades, instead of only shedding on queue depth (forwarding_mode = "pool" only)
+    HealthSheddingLatencyThresholdMS int     `mapstructure:"health_shedding_latency_threshold_ms"`  // Delivery latency at or above which the health score treats the collector as fully slow, ignored unless health_shedding_enabled
+    HealthSheddingBreakerThreshold   int     `mapstructure:"health_shedding_breaker_threshold"`     // Consecutive delivery failures that force the health score to 0 for health_shedding_breaker_cooldown_seconds; 0 disables the breaker (latency/error rate remain the only signals)
+    HealthSheddingBreakerCooldownSeconds int `mapstructure:"health_shedding_breaker_cooldown_seconds"` // How long the breaker stays forced open once tripped, ignored unless health_shedding_breaker_threshold > 0
+    ForwarderReadinessCheckEnabled bool `mapstructure:"forwarder_readiness_check_enabled"` // Fail /readyz once the active forwarder's forwarder.EvaluateHealth reports HealthStateFailing, so Kubernetes stops routing to an instance whose collector is unreachable instead of only shedding/circuit-breaking new work; false leaves /readyz unaffected by forwarder health (the original behavior)
+    AccessLogFormat string `mapstructure:"access_log_format"` // Access log line format: "" for echo's default combined-log-style line (the original behavior), "ecs" for ECS-compatible structured JSON (client.ip, http.request.body.bytes, user_agent.original, event.outcome, ...) for SIEM ingestion
+    DedupEnabled            bool   `mapstructure:"dedup_enabled"`             // Suppress a /v1/logs or /v1/traces payload seen again within dedup_window_seconds, so a browser SDK retrying a flaky export doesn't produce duplicate telemetry downstream
+    DedupWindowSeconds      int    `mapstructure:"dedup_window_seconds"`      // Sliding window a dedup key is remembered for, ignored unless dedup_enabled
+    DedupIdempotencyHeader  string `mapstructure:"dedup_idempotency_header"`  // Request header carrying a client-supplied idempotency key to dedup on; empty falls back to hashing the request body (works without SDK changes, but can't distinguish two genuinely different payloads sent with identical bytes within the window - which never happens for real telemetry)
+    IngestTokenIssuanceEnabled   bool   `mapstructure:"ingest_token_issuance_enabled"`   // Enable POST /admin/ingest-tokens, which mints short-lived tenant/origin-bound tokens so browsers can present a token on /v1/* instead of a long-lived collector key baked into their bundle
+    IngestTokenSigningSecret     string `mapstructure:"ingest_token_signing_secret"`     // HMAC secret tokens are signed with; required when ingest_token_issuance_enabled
+    IngestTokenTTLSeconds        int    `mapstructure:"ingest_token_ttl_seconds"`        // Lifetime of an issued token, ignored unless ingest_token_issuance_enabled
+    IngestTokenEnforcementEnabled bool  `mapstructure:"ingest_token_enforcement_enabled"` // Require a valid X-Ingest-Token (bound to the request's Origin) on /v1/logs and /v1/traces, rejecting with 401 otherwise; ignored unless ingest_token_issuance_enabled, since there'd be nothing to present. False leaves ingest open to any bearer of the collector api_key (the original behavior) - true is what actually enforces the "no long-lived key in the bundle" goal issuance alone doesn't.
+    AdminAPIKey                string            `mapstructure:"admin_api_key"`    // Value required in X-Admin-Api-Key on POST /admin/ingest-tokens, and (if target_overrides is non-empty) on a /v1/logs or /v1/traces request carrying X-Target-Override; empty disables both checks
+    TargetOverrides            map[string]string `mapstructure:"target_overrides"` // Named target URLs (e.g. "collector-replica-2") a caller may select per-request via X-Target-Override, gated by admin_api_key; empty disables the feature
+    PowChallengeEnabled        bool   `mapstructure:"pow_challenge_enabled"`         // Require a solved proof-of-work challenge (issued via GET /v1/challenge) on /v1/logs and /v1/traces, raising the cost of scripted telemetry spam from unauthenticated RUM ingest
+    PowChallengeSigningSecret  string `mapstructure:"pow_challenge_signing_secret"`  // HMAC secret challenge nonces are signed with; required when pow_challenge_enabled
+    PowChallengeDifficultyBits int    `mapstructure:"pow_challenge_difficulty_bits"` // Required leading zero bits in a solution's SHA-256 hash; higher raises the client's CPU cost per accepted request
+    PowChallengeTTLSeconds     int    `mapstructure:"pow_challenge_ttl_seconds"`     // How long an issued nonce remains solvable and how long it's remembered as redeemed, ignored unless pow_challenge_enabled
+    GeoIPEnabled           bool                `mapstructure:"geoip_enabled"`            // Resolve each request's client IP to a country via geoip_database_path, for geoip_blocked_countries and geoip_country_routes
+    GeoIPDatabasePath      string              `mapstructure:"geoip_database_path"`      // Path to a local MaxMind DB (.mmdb) file; required when geoip_enabled
+    GeoIPBlockedCountries  []string            `mapstructure:"geoip_blocked_countries"`  // ISO 3166-1 alpha-2 country codes to reject with 451, ignored unless geoip_enabled
+    GeoIPCountryRoutes     []GeoIPCountryRoute `mapstructure:"geoip_country_routes"`     // Per-country target URL overrides for data-residency requirements, ignored unless geoip_enabled; a country matching no rule uses the default otel_collector_target_url
+    DataResidencyRegion         string            `mapstructure:"data_residency_region"`          // This instance's data residency region (e.g. "eu", "us"), stamped onto every resource's data_residency_attribute_key and checked against data_residency_target_regions before forwarding. Empty disables both stamping and enforcement (the original behavior).
+    DataResidencyAttributeKey   string            `mapstructure:"data_residency_attribute_key"`   // Resource attribute stamped with data_residency_region (set only if not already present, so an SDK or upstream hop's own tag is never overwritten); ignored unless data_residency_region is set
+    DataResidencyTargetRegions  map[string]string `mapstructure:"data_residency_target_regions"`  // Target URL -> its configured residency region; a resolved target whose region here doesn't match data_residency_region is refused instead of forwarded, guarding against accidental cross-region transfer. A target absent from this map is never enforced.
+    KafkaBroker   string `mapstructure:"kafka_broker"`    // host:port of the Kafka broker to publish to (forwarding_mode = "kafka"); required when forwarding_mode is "kafka"
+    KafkaTopic    string `mapstructure:"kafka_topic"`     // Topic raw OTLP payloads are published to, ignored unless forwarding_mode is "kafka"
+    KafkaClientID string `mapstructure:"kafka_client_id"` // Client id sent with every produce request, for broker-side logging/quotas
+    NATSAddr    string `mapstructure:"nats_addr"`    // host:port of the NATS server to publish to (forwarding_mode = "natsjs"); required when forwarding_mode is "natsjs"
+    NATSSubject string `mapstructure:"nats_subject"` // JetStream subject raw OTLP payloads are published to, ignored unless forwarding_mode is "natsjs"
+    ArchiveEnabled       bool   `mapstructure:"archive_enabled"`          // Write every accepted payload (ndjson of base64 bodies + metadata) to archive_output_dir alongside forwarding, for compliance retention and replay
+    ArchiveOutputDir     string `mapstructure:"archive_output_dir"`       // Directory rotating local archive files are written to; required when archive_enabled
+    ArchiveMaxFileSizeMB int    `mapstructure:"archive_max_file_size_mb"` // Roll over to a new archive file once the current one reaches this size
+    ArchiveS3Bucket          string `mapstructure:"archive_s3_bucket"`           // S3 bucket rotated archive files are uploaded to; empty keeps archives local-only
+    ArchiveS3Region          string `mapstructure:"archive_s3_region"`           // AWS region for archive_s3_bucket, ignored unless archive_s3_bucket is set
+    ArchiveS3AccessKeyID     string `mapstructure:"archive_s3_access_key_id"`     // AWS access key id used to sign S3 uploads
+    ArchiveS3SecretAccessKey string `mapstructure:"archive_s3_secret_access_key"` // AWS secret access key used to sign S3 uploads
+    ArchiveS3Endpoint        string `mapstructure:"archive_s3_endpoint"`         // Optional S3-compatible endpoint override (empty uses AWS's regional endpoint)
+    ArchiveS3Prefix          string `mapstructure:"archive_s3_prefix"`           // Optional key prefix applied to every uploaded object
+    SpoolEncryptionEnabled     bool              `mapstructure:"spool_encryption_enabled"`      // Encrypt (AES-256-GCM) job bodies before they touch disk: the worker pool's disk queue, the dead-letter store, and rotated archive_output_dir files, since raw RUM payloads may contain user content
+    SpoolEncryptionActiveKeyID string            `mapstructure:"spool_encryption_active_key_id"` // Which key in spool_encryption_keys new writes are sealed with; required when spool_encryption_enabled
+    SpoolEncryptionKeys        map[string]string `mapstructure:"spool_encryption_keys"`         // Key id -> hex-encoded 32-byte AES-256 key. To rotate, add a new key here, point spool_encryption_active_key_id at it, and keep the old key listed so files written under it can still be decrypted.
+    TLSEnabled          bool     `mapstructure:"tls_enabled"`           // Terminate TLS on the ingest listener instead of leaving that to an upstream load balancer
+    TLSCertFile         string   `mapstructure:"tls_cert_file"`         // PEM certificate file; required when tls_enabled
+    TLSKeyFile          string   `mapstructure:"tls_key_file"`          // PEM private key file; required when tls_enabled
+    TLSMinVersion       string   `mapstructure:"tls_min_version"`       // "1.2" or "1.3"; empty defaults to "1.2"
+    TLSCipherSuites     []string `mapstructure:"tls_cipher_suites"`     // Allowed cipher suite names (see crypto/tls.CipherSuiteName); empty uses Go's default policy
+    TLSCurvePreferences []string `mapstructure:"tls_curve_preferences"` // Allowed curve names ("X25519", "P256", "P384", "P521"); empty uses Go's default policy
+    ForwarderTLSMinVersion       string   `mapstructure:"forwarder_tls_min_version"`       // Same as tls_min_version, applied to the upstream collector connection
+    ForwarderTLSCipherSuites     []string `mapstructure:"forwarder_tls_cipher_suites"`     // Same as tls_cipher_suites, applied to the upstream collector connection
+    ForwarderTLSCurvePreferences []string `mapstructure:"forwarder_tls_curve_preferences"` // Same as tls_curve_preferences, applied to the upstream collector connection
+    ForwarderTLSClientCertFile   string   `mapstructure:"forwarder_tls_client_cert_file"`  // PEM client certificate presented to the collector for mTLS; required alongside forwarder_tls_client_key_file. Reloaded from disk on each handshake if its mtime has changed, so a rotated workload cert takes effect without restarting or dropping pooled connections.
+    ForwarderTLSClientKeyFile    string   `mapstructure:"forwarder_tls_client_key_file"`   // PEM private key matching forwarder_tls_client_cert_file; required alongside it
+}
+
+// LoadBalanceTarget is one collector endpoint in a load-balanced pool. Each
+// target gets its own HTTP transport/connection pool so one slow endpoint
+// can't starve connections meant for the others.
+type LoadBalanceTarget struct {
+	URL string `mapstructure:"url"`
+}
+
+// FailoverTarget is one additional upstream collector tried when higher-priority
+// targets time out or return 5xx. Lower Priority values are tried first; the
+// primary otel_collector_target_url is always tried before any of these.
+type FailoverTarget struct {
+	URL      string `mapstructure:"url"`
+	Priority int    `mapstructure:"priority"`
+}
+
+// ResourceRoutingRule sends resources matching Key=Value to TargetURL instead
+// of otel_collector_target_url. Rules are evaluated in order; the first match
+// wins. Resources matching no rule are still forwarded to the default target.
+type ResourceRoutingRule struct {
+	Key       string `mapstructure:"key"`
+	Value     string `mapstructure:"value"`
+	TargetURL string `mapstructure:"target_url"`
+}
+
+// GeoIPCountryRoute sends payloads from a given client country to a
+// region-specific collector instead of the default target URL, for
+// data-residency requirements. Rules are evaluated in order; the first
+// match wins.
+type GeoIPCountryRoute struct {
+	Country   string `mapstructure:"country"`
+	TargetURL string `mapstructure:"target_url"`
+}
+
+// ResourceAttributeRewriteRule is one config-driven rewrite applied to the resource
+// attributes of decoded OTLP payloads, centralizing conventions our many SDK
+// versions don't agree on. Action is one of "rename", "set_default", "delete";
+// only the fields relevant to Action need to be set.
+type ResourceAttributeRewriteRule struct {
+    Action  string `mapstructure:"action"`
+    FromKey string `mapstructure:"from_key"`
+    ToKey   string `mapstructure:"to_key"`
+    Key     string `mapstructure:"key"`
+    Value   string `mapstructure:"value"`
+}
+
+// setDefaults populates v with every configuration default. Both Load
+// (via the package-level viper instance) and Defaults (via a throwaway
+// one) call this, so the two can never drift apart.
+func setDefaults(v *viper.Viper) {
+	// Set default values
+	v.SetDefault("shutdown_drain_seconds", 2)
+	v.SetDefault("shutdown_timeout_seconds", 10)
+	v.SetDefault("server_port", 8080)
+	v.SetDefault("worker_pool_size", 0)    // 0 = auto-detect 50×NumCPU in worker.NewPool()
+	v.SetDefault("job_queue_size", 10000)  // Default job queue buffer size
+	v.SetDefault("allowed_origins", []string{"*"}) // Default wildcard for development
+	v.SetDefault("max_request_size_mb", 1) // Default 1MB request size limit
+    v.SetDefault("forwarding_mode", "pool") // Default to existing pool behavior
+    v.SetDefault("semaphore_max_concurrent", 10000)
+    v.SetDefault("adaptive_min_concurrent", 10)
+    v.SetDefault("adaptive_max_concurrent", 10000)
+    v.SetDefault("adaptive_latency_threshold_ms", 2000)
+    v.SetDefault("grpc_max_concurrent", 10000)
+    v.SetDefault("grpc_request_timeout_seconds", 10)
+    v.SetDefault("sync_logs_debug", false)
+    v.SetDefault("sync_logs_streaming_enabled", false)
+    v.SetDefault("debug_dump_dir", "/tmp/zep-logger-debug")
+    v.SetDefault("watchdog_rss_threshold_mb", 0)          // 0 = disabled
+    v.SetDefault("watchdog_queue_depth_threshold", 0)     // 0 = disabled
+    v.SetDefault("watchdog_check_interval_seconds", 10)
+    v.SetDefault("watchdog_min_dump_interval_seconds", 60)
+    v.SetDefault("latency_injection_enabled", false)
+    v.SetDefault("latency_injection_percent", 0)
+    v.SetDefault("latency_injection_min_ms", 0)
+    v.SetDefault("latency_injection_max_ms", 0)
+    v.SetDefault("canary_enabled", false)
+    v.SetDefault("canary_interval_seconds", 30)
+    v.SetDefault("reconciliation_enabled", false)
+    v.SetDefault("reconciliation_metric_name", "otelcol_receiver_accepted_log_records_total")
+    v.SetDefault("reconciliation_interval_seconds", 60)
+    v.SetDefault("worker_pool_retry_max_attempts", 1) // 1 = no retries, matches original drop-on-failure behavior
+    v.SetDefault("worker_pool_retry_initial_backoff_ms", 200)
+    v.SetDefault("worker_pool_retry_max_backoff_ms", 5000)
+    v.SetDefault("worker_pool_retry_jitter_fraction", 0.2)
+    v.SetDefault("schema_normalization_target_schema_url", "")
+    v.SetDefault("dead_letter_dir", "")
+    v.SetDefault("dead_letter_max_size_mb", 100)
+    v.SetDefault("worker_pool_queue_mode", "memory")
+    v.SetDefault("tail_sampling_enabled", false)
+    v.SetDefault("tail_sampling_window_seconds", 10)
+    v.SetDefault("tail_sampling_slow_threshold_ms", 0)
+    v.SetDefault("tail_sampling_error_sample_rate", 1.0)
+    v.SetDefault("tail_sampling_default_sample_rate", 0.1)
+    v.SetDefault("batching_enabled", false)
+    v.SetDefault("batch_max_size", 20)
+    v.SetDefault("batch_max_age_ms", 1000)
+    v.SetDefault("private_network_access_enabled", false)
+    v.SetDefault("load_balancing_strategy", "round_robin")
+    v.SetDefault("large_payload_offload_enabled", false)
+    v.SetDefault("large_payload_threshold_bytes", 512*1024)
+    v.SetDefault("large_payload_temp_dir", "")
+    v.SetDefault("mirror_target_url", "")
+    v.SetDefault("mirror_max_concurrent", 50)
+    v.SetDefault("circuit_breaker_fallback_url", "")
+    v.SetDefault("circuit_breaker_open_threshold", 3)
+    v.SetDefault("circuit_breaker_cooldown_seconds", 30)
+    v.SetDefault("logs_forwarding_mode", "")
+    v.SetDefault("traces_forwarding_mode", "")
+    v.SetDefault("logs_forward_timeout_ms", 0)
+    v.SetDefault("traces_forward_timeout_ms", 0)
+    v.SetDefault("ingest_engine", "echo")
+    v.SetDefault("ingest_nethttp_port", 8081)
+    v.SetDefault("usage_accounting_enabled", false)
+    v.SetDefault("usage_accounting_interval_seconds", 60)
+    v.SetDefault("usage_accounting_output_dir", "")
+    v.SetDefault("usage_accounting_billing_url", "")
+    v.SetDefault("usage_accounting_tenant_header", "X-Tenant-ID")
+    v.SetDefault("rate_limit_enabled", false)
+    v.SetDefault("rate_limit_requests_per_second", 1000.0)
+    v.SetDefault("rate_limit_burst", 1000)
+    v.SetDefault("rate_limit_state_file", "")
+    v.SetDefault("rate_limit_state_persist_interval_seconds", 30)
+    v.SetDefault("worker_pool_lazy_spawn", false)
+    v.SetDefault("worker_pool_idle_timeout_seconds", 60)
+    v.SetDefault("job_ttl_enabled", false)
+    v.SetDefault("job_ttl_seconds", 30)
+    v.SetDefault("queue_full_policy", "reject")
+    v.SetDefault("worker_pool_shutdown_spill_file", "")
+    v.SetDefault("worker_pool_max_queued_bytes", 0) // 0 = disabled, original behavior
+    v.SetDefault("connection_warmup_count", 0) // 0 = disabled, original behavior
+    v.SetDefault("forwarder_max_pending_jobs", 0)
+    v.SetDefault("forwarder_soft_max_pending_jobs", 0)
+    v.SetDefault("forwarder_backpressure_retry_after_seconds", 5)
+    v.SetDefault("overload_status_code", 429)
+    v.SetDefault("overload_retry_after_seconds", 5)
+    v.SetDefault("forwarder_request_timeout_seconds", 0)
+    v.SetDefault("forwarder_dial_timeout_seconds", 0)
+    v.SetDefault("forwarder_tls_handshake_timeout_seconds", 0)
+    v.SetDefault("forwarder_idle_conn_timeout_seconds", 0)
+    v.SetDefault("upstream_error_buffer_size", 100)
+    v.SetDefault("job_tracking_enabled", false)
+    v.SetDefault("job_tracking_capacity", 10000)
+    v.SetDefault("health_shedding_enabled", false)
+    v.SetDefault("health_shedding_latency_threshold_ms", 2000)
+    v.SetDefault("health_shedding_breaker_threshold", 0)
+    v.SetDefault("health_shedding_breaker_cooldown_seconds", 30)
+    v.SetDefault("forwarder_readiness_check_enabled", false)
+    v.SetDefault("access_log_format", "")
+    v.SetDefault("dedup_enabled", false)
+    v.SetDefault("dedup_window_seconds", 30)
+    v.SetDefault("dedup_idempotency_header", "")
+    v.SetDefault("ingest_token_issuance_enabled", false)
+    v.SetDefault("ingest_token_signing_secret", "")
+    v.SetDefault("ingest_token_ttl_seconds", 300)
+    v.SetDefault("ingest_token_enforcement_enabled", false)
+    v.SetDefault("admin_api_key", "")
+    v.SetDefault("pow_challenge_enabled", false)
+    v.SetDefault("pow_challenge_signing_secret", "")
+    v.SetDefault("pow_challenge_difficulty_bits", 16)
+    v.SetDefault("pow_challenge_ttl_seconds", 60)
+    v.SetDefault("geoip_enabled", false)
+    v.SetDefault("geoip_database_path", "")
+    v.SetDefault("geoip_blocked_countries", []string{})
+    v.SetDefault("data_residency_region", "")
+    v.SetDefault("data_residency_attribute_key", "data.residency.region")
+    v.SetDefault("data_residency_target_regions", map[string]string{})
+    v.SetDefault("kafka_broker", "")
+    v.SetDefault("kafka_topic", "")
+    v.SetDefault("kafka_client_id", "otlpxy")
+    v.SetDefault("nats_addr", "")
+    v.SetDefault("nats_subject", "")
+    v.SetDefault("archive_enabled", false)
+    v.SetDefault("archive_output_dir", "")
+    v.SetDefault("archive_max_file_size_mb", 100)
+    v.SetDefault("archive_s3_bucket", "")
+    v.SetDefault("archive_s3_region", "")
+    v.SetDefault("archive_s3_access_key_id", "")
+    v.SetDefault("archive_s3_secret_access_key", "")
+    v.SetDefault("archive_s3_endpoint", "")
+    v.SetDefault("archive_s3_prefix", "")
+    v.SetDefault("spool_encryption_enabled", false)
+    v.SetDefault("spool_encryption_active_key_id", "")
+    v.SetDefault("spool_encryption_keys", map[string]string{})
+    v.SetDefault("tls_enabled", false)
+    v.SetDefault("tls_cert_file", "")
+    v.SetDefault("tls_key_file", "")
+    v.SetDefault("tls_min_version", "1.2")
+    v.SetDefault("tls_cipher_suites", []string{})
+    v.SetDefault("tls_curve_preferences", []string{})
+    v.SetDefault("forwarder_tls_min_version", "1.2")
+    v.SetDefault("forwarder_tls_cipher_suites", []string{})
+    v.SetDefault("forwarder_tls_curve_preferences", []string{})
+    v.SetDefault("forwarder_tls_client_cert_file", "")
+    v.SetDefault("forwarder_tls_client_key_file", "")
+    v.SetDefault("load_shedding_max_queue_depth", 0)
+    v.SetDefault("tenant_queue_share_header", "X-Client-Id")
+    v.SetDefault("tenant_queue_share_max_pending", 0)
+    v.SetDefault("tenant_weights", map[string]int{})
+    v.SetDefault("forward_compression", "")
+    v.SetDefault("tenant_concurrency_header", "")
+    v.SetDefault("tenant_concurrency_max_per_tenant", 0)
+    v.SetDefault("ingest_byte_rate_limit_enabled", false)
+    v.SetDefault("ingest_byte_rate_limit_bytes_per_second", 52428800.0) // 50 MB/s
+    v.SetDefault("ingest_byte_rate_limit_burst_bytes", 104857600)      // 100 MB
+    v.SetDefault("ingest_byte_rate_limit_state_file", "")
+    v.SetDefault("max_decompressed_body_bytes", 52428800) // 50 MB
 }
 
 // Load reads configuration from config.toml file
@@ -31,17 +416,7 @@ func Load() (*Config, error) {
 	viper.AddConfigPath(".")
 	viper.AddConfigPath("./config")
 
-	// Set default values
-	viper.SetDefault("shutdown_drain_seconds", 2)
-	viper.SetDefault("shutdown_timeout_seconds", 10)
-	viper.SetDefault("server_port", 8080)
-	viper.SetDefault("worker_pool_size", 0)    // 0 = auto-detect 2×NumCPU in worker.NewPool()
-	viper.SetDefault("job_queue_size", 10000)  // Default job queue buffer size
-	viper.SetDefault("allowed_origins", []string{"*"}) // Default wildcard for development
-	viper.SetDefault("max_request_size_mb", 1) // Default 1MB request size limit
-    viper.SetDefault("forwarding_mode", "pool") // Default to existing pool behavior
-    viper.SetDefault("semaphore_max_concurrent", 10000)
-    viper.SetDefault("sync_logs_debug", false)
+	setDefaults(viper.GetViper())
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -52,20 +427,55 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
-	// Validate required configuration
+	// Validate required configuration. Collected rather than returned
+	// immediately, so a config with several mistakes reports all of them
+	// (each naming its TOML key) in one error instead of forcing a
+	// fix-rerun-fix cycle for each one in turn.
+	//
+	// Note: this only covers hard validation errors, not a restructuring of
+	// Config itself into nested server/forwarding/security/observability
+	// sections - Config's ~150 fields are read as flat a.config.XxxYyy
+	// selectors from dozens of packages (app.go alone touches most of them),
+	// and regrouping it is a large, separate migration best done on its own.
+	var validationErrors []error
+
 	if config.OtelCollectorTargetURL == "" {
-		return nil, fmt.Errorf("otel_collector_target_url is required in config file")
+		validationErrors = append(validationErrors, fmt.Errorf("otel_collector_target_url is required in config file"))
 	}
 
 	// Warn if API key is empty
-	if config.OtelCollectorAPIKey == "" {
+	if config.OtelCollectorAPIKey == "" && config.GCPIDTokenAudience == "" {
 		log.Printf("WARN:  otel_collector_api_key is empty - forwarding will not include authentication")
 	}
 
+	if config.GCPIDTokenAudience != "" {
+		log.Printf("INFO:    gcp_id_token_audience: %s (forwarding will use a Google-signed ID token instead of otel_collector_api_key)", config.GCPIDTokenAudience)
+	}
+
+	if len(config.TargetOverrides) > 0 && config.AdminAPIKey == "" {
+		log.Printf("WARN:  target_overrides is configured but admin_api_key is empty - X-Target-Override requests have no credential to check against and will always be rejected")
+	}
+
+	for _, cidr := range config.MetricsAllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			validationErrors = append(validationErrors, fmt.Errorf("invalid metrics_allowed_cidrs entry %q: %w", cidr, err))
+		}
+	}
+	if config.MetricsAuthToken == "" && len(config.MetricsAllowedCIDRs) == 0 && config.MetricsPort <= 0 {
+		log.Printf("WARN:  /metrics is unauthenticated and unrestricted by IP - set metrics_auth_token, metrics_allowed_cidrs, and/or metrics_port if the proxy is internet-facing")
+	}
+	if config.MetricsPort > 0 && config.MetricsPort == config.ServerPort {
+		validationErrors = append(validationErrors, fmt.Errorf("metrics_port (%d) must differ from server_port", config.MetricsPort))
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, errors.Join(validationErrors...)
+	}
+
 
     // Normalize/validate forwarding mode
     switch config.ForwardingMode {
-    case "pool", "semaphore", "hybrid":
+    case "pool", "semaphore", "hybrid", "grpc", "failover", "loadbalance", "adaptive", "kafka", "natsjs", "loopback":
         // ok
     case "":
         config.ForwardingMode = "pool"
@@ -79,6 +489,407 @@ func Load() (*Config, error) {
         config.SemaphoreMaxConcurrent = 10000
     }
 
+    if config.ForwardingMode == "grpc" {
+        if config.GRPCMaxConcurrent <= 0 {
+            log.Printf("WARN:  grpc_max_concurrent <= 0 (%d), defaulting to 10000", config.GRPCMaxConcurrent)
+            config.GRPCMaxConcurrent = 10000
+        }
+        if config.GRPCRequestTimeoutSeconds <= 0 {
+            log.Printf("WARN:  grpc_request_timeout_seconds <= 0 (%d), defaulting to 10", config.GRPCRequestTimeoutSeconds)
+            config.GRPCRequestTimeoutSeconds = 10
+        }
+        log.Printf("INFO:    grpc_max_concurrent: %d, grpc_request_timeout_seconds: %d", config.GRPCMaxConcurrent, config.GRPCRequestTimeoutSeconds)
+    }
+
+    if config.ForwardingMode == "adaptive" {
+        if config.AdaptiveMinConcurrent <= 0 {
+            log.Printf("WARN:  adaptive_min_concurrent <= 0 (%d), defaulting to 10", config.AdaptiveMinConcurrent)
+            config.AdaptiveMinConcurrent = 10
+        }
+        if config.AdaptiveMaxConcurrent < config.AdaptiveMinConcurrent {
+            log.Printf("WARN:  adaptive_max_concurrent (%d) < adaptive_min_concurrent (%d), defaulting to %d", config.AdaptiveMaxConcurrent, config.AdaptiveMinConcurrent, config.AdaptiveMinConcurrent*100)
+            config.AdaptiveMaxConcurrent = config.AdaptiveMinConcurrent * 100
+        }
+        if config.AdaptiveLatencyThresholdMS <= 0 {
+            log.Printf("WARN:  adaptive_latency_threshold_ms <= 0 (%d), defaulting to 2000", config.AdaptiveLatencyThresholdMS)
+            config.AdaptiveLatencyThresholdMS = 2000
+        }
+        log.Printf("INFO:    adaptive_min_concurrent: %d, adaptive_max_concurrent: %d, adaptive_latency_threshold_ms: %d", config.AdaptiveMinConcurrent, config.AdaptiveMaxConcurrent, config.AdaptiveLatencyThresholdMS)
+    }
+
+    if config.ForwardingMode == "failover" {
+        if len(config.OtelCollectorFailoverTargets) == 0 {
+            log.Printf("WARN:  forwarding_mode=failover but otel_collector_failover_targets is empty - falling back to 'pool'")
+            config.ForwardingMode = "pool"
+        } else {
+            sort.SliceStable(config.OtelCollectorFailoverTargets, func(i, j int) bool {
+                return config.OtelCollectorFailoverTargets[i].Priority < config.OtelCollectorFailoverTargets[j].Priority
+            })
+            log.Printf("INFO:    otel_collector_failover_targets: %d additional target(s) configured behind the primary", len(config.OtelCollectorFailoverTargets))
+        }
+    }
+
+    if config.ForwardingMode == "loadbalance" {
+        if len(config.OtelCollectorLoadBalanceTargets) == 0 {
+            log.Printf("WARN:  forwarding_mode=loadbalance but otel_collector_load_balance_targets is empty - falling back to 'pool'")
+            config.ForwardingMode = "pool"
+        } else {
+            switch config.LoadBalancingStrategy {
+            case "round_robin", "least_pending":
+                // ok
+            case "":
+                config.LoadBalancingStrategy = "round_robin"
+            default:
+                log.Printf("WARN:  unknown load_balancing_strategy=%q, defaulting to 'round_robin'", config.LoadBalancingStrategy)
+                config.LoadBalancingStrategy = "round_robin"
+            }
+            log.Printf("INFO:    otel_collector_load_balance_targets: %d target(s), load_balancing_strategy=%s", len(config.OtelCollectorLoadBalanceTargets), config.LoadBalancingStrategy)
+        }
+    }
+
+    if config.ForwardingMode == "kafka" {
+        if config.KafkaBroker == "" || config.KafkaTopic == "" {
+            log.Printf("WARN:  forwarding_mode=kafka but kafka_broker or kafka_topic is empty - falling back to 'pool'")
+            config.ForwardingMode = "pool"
+        } else {
+            log.Printf("INFO:    kafka_broker: %s, kafka_topic: %s, kafka_client_id: %s", config.KafkaBroker, config.KafkaTopic, config.KafkaClientID)
+        }
+    }
+
+    if config.ForwardingMode == "natsjs" {
+        if config.NATSAddr == "" || config.NATSSubject == "" {
+            log.Printf("WARN:  forwarding_mode=natsjs but nats_addr or nats_subject is empty - falling back to 'pool'")
+            config.ForwardingMode = "pool"
+        } else {
+            log.Printf("INFO:    nats_addr: %s, nats_subject: %s", config.NATSAddr, config.NATSSubject)
+        }
+    }
+
+    // Per-signal forwarder overrides: "pool" is not a valid override value
+    // since it's the zero-value default already; leave logs/traces on the
+    // shared forwarder by setting the mode to "" instead
+    for name, mode := range map[string]*string{"logs_forwarding_mode": &config.LogsForwardingMode, "traces_forwarding_mode": &config.TracesForwardingMode} {
+        switch *mode {
+        case "", "semaphore", "hybrid", "grpc", "failover", "loadbalance", "adaptive", "kafka", "natsjs":
+            // ok
+        default:
+            log.Printf("WARN:  unknown %s=%q, falling back to the shared forwarding_mode", name, *mode)
+            *mode = ""
+        }
+    }
+    if config.LogsForwardingMode != "" {
+        log.Printf("INFO:    logs_forwarding_mode: %s (overrides forwarding_mode for /v1/logs)", config.LogsForwardingMode)
+    }
+    if config.TracesForwardingMode != "" {
+        log.Printf("INFO:    traces_forwarding_mode: %s (overrides forwarding_mode for /v1/traces)", config.TracesForwardingMode)
+    }
+    if config.LogsForwardTimeoutMS < 0 {
+        log.Printf("WARN:  logs_forward_timeout_ms < 0 (%d), disabling the override", config.LogsForwardTimeoutMS)
+        config.LogsForwardTimeoutMS = 0
+    } else if config.LogsForwardTimeoutMS > 0 {
+        log.Printf("INFO:    logs_forward_timeout_ms: %d (pool mode only)", config.LogsForwardTimeoutMS)
+    }
+    if config.TracesForwardTimeoutMS < 0 {
+        log.Printf("WARN:  traces_forward_timeout_ms < 0 (%d), disabling the override", config.TracesForwardTimeoutMS)
+        config.TracesForwardTimeoutMS = 0
+    } else if config.TracesForwardTimeoutMS > 0 {
+        log.Printf("INFO:    traces_forward_timeout_ms: %d (pool mode only)", config.TracesForwardTimeoutMS)
+    }
+
+    switch config.IngestEngine {
+    case "echo", "":
+        config.IngestEngine = "echo"
+    case "nethttp":
+        if config.IngestNetHTTPPort <= 0 {
+            log.Printf("WARN:  ingest_nethttp_port <= 0 (%d), defaulting to 8081", config.IngestNetHTTPPort)
+            config.IngestNetHTTPPort = 8081
+        }
+        log.Printf("INFO:    ingest_engine: nethttp (port=%d) - /v1/logs and /v1/traces additionally served without Echo middleware", config.IngestNetHTTPPort)
+    default:
+        log.Printf("WARN:  unknown ingest_engine=%q, defaulting to 'echo'", config.IngestEngine)
+        config.IngestEngine = "echo"
+    }
+
+    if config.RateLimitStatePersistIntervalSeconds <= 0 {
+        config.RateLimitStatePersistIntervalSeconds = 30
+    }
+
+    if config.RateLimitEnabled {
+        if config.ForwardingMode != "" && config.ForwardingMode != "pool" {
+            log.Printf("WARN:  rate_limit_enabled=true but forwarding_mode=%q is not 'pool' - upstream rate limiting is not applied", config.ForwardingMode)
+        }
+        if config.RateLimitRequestsPerSecond <= 0 {
+            log.Printf("WARN:  rate_limit_requests_per_second <= 0 (%v), defaulting to 1000", config.RateLimitRequestsPerSecond)
+            config.RateLimitRequestsPerSecond = 1000.0
+        }
+        if config.RateLimitBurst <= 0 {
+            log.Printf("WARN:  rate_limit_burst <= 0 (%d), defaulting to 1000", config.RateLimitBurst)
+            config.RateLimitBurst = 1000
+        }
+        log.Printf("INFO:    rate_limit_enabled: true (requests_per_second=%.1f, burst=%d)", config.RateLimitRequestsPerSecond, config.RateLimitBurst)
+        if config.RateLimitStateFile != "" {
+            log.Printf("INFO:    rate_limit_state_file: %s (persisted every %ds)", config.RateLimitStateFile, config.RateLimitStatePersistIntervalSeconds)
+        }
+    }
+
+    if config.WorkerPoolLazySpawn {
+        if config.ForwardingMode != "" && config.ForwardingMode != "pool" {
+            log.Printf("WARN:  worker_pool_lazy_spawn=true but forwarding_mode=%q is not 'pool' - lazy spawning is not applied", config.ForwardingMode)
+        }
+        if config.WorkerPoolIdleTimeoutSeconds <= 0 {
+            log.Printf("WARN:  worker_pool_idle_timeout_seconds <= 0 (%d), defaulting to 60", config.WorkerPoolIdleTimeoutSeconds)
+            config.WorkerPoolIdleTimeoutSeconds = 60
+        }
+        log.Printf("INFO:    worker_pool_lazy_spawn: true (idle_timeout_seconds=%d)", config.WorkerPoolIdleTimeoutSeconds)
+    }
+
+    if config.JobTTLEnabled {
+        if config.ForwardingMode != "" && config.ForwardingMode != "pool" {
+            log.Printf("WARN:  job_ttl_enabled=true but forwarding_mode=%q is not 'pool' - job TTL is not enforced", config.ForwardingMode)
+        }
+        if config.JobTTLSeconds <= 0 {
+            log.Printf("WARN:  job_ttl_seconds <= 0 (%d), defaulting to 30", config.JobTTLSeconds)
+            config.JobTTLSeconds = 30
+        }
+        log.Printf("INFO:    job_ttl_enabled: true (job_ttl_seconds=%d)", config.JobTTLSeconds)
+    }
+
+    if config.WorkerPoolShutdownSpillFile != "" {
+        if config.ForwardingMode != "" && config.ForwardingMode != "pool" {
+            log.Printf("WARN:  worker_pool_shutdown_spill_file=%q but forwarding_mode=%q is not 'pool' - shutdown spilling is not applied", config.WorkerPoolShutdownSpillFile, config.ForwardingMode)
+        }
+        log.Printf("INFO:    worker_pool_shutdown_spill_file: %s", config.WorkerPoolShutdownSpillFile)
+    }
+
+    if config.QueueFullPolicy != "" && config.QueueFullPolicy != "reject" && config.QueueFullPolicy != "drop-oldest" {
+        log.Printf("WARN:  queue_full_policy=%q is not 'reject' or 'drop-oldest', defaulting to 'reject'", config.QueueFullPolicy)
+        config.QueueFullPolicy = "reject"
+    }
+    if config.QueueFullPolicy == "drop-oldest" {
+        if config.ForwardingMode != "" && config.ForwardingMode != "pool" {
+            log.Printf("WARN:  queue_full_policy=\"drop-oldest\" but forwarding_mode=%q is not 'pool' - the setting is not applied", config.ForwardingMode)
+        }
+        log.Printf("INFO:    queue_full_policy: drop-oldest")
+    }
+
+    if config.WorkerPoolMaxQueuedBytes > 0 {
+        if config.ForwardingMode != "" && config.ForwardingMode != "pool" {
+            log.Printf("WARN:  worker_pool_max_queued_bytes=%d but forwarding_mode=%q is not 'pool' - the cap is not applied", config.WorkerPoolMaxQueuedBytes, config.ForwardingMode)
+        }
+        log.Printf("INFO:    worker_pool_max_queued_bytes: %d", config.WorkerPoolMaxQueuedBytes)
+    }
+
+    if config.ConnectionWarmupCount > 0 {
+        if config.ForwardingMode != "" && config.ForwardingMode != "pool" {
+            log.Printf("WARN:  connection_warmup_count=%d but forwarding_mode=%q is not 'pool' - warm-up is not performed", config.ConnectionWarmupCount, config.ForwardingMode)
+        }
+        log.Printf("INFO:    connection_warmup_count: %d", config.ConnectionWarmupCount)
+    }
+
+    if config.ForwarderMaxPendingJobs > 0 {
+        log.Printf("INFO:    forwarder_max_pending_jobs: %d", config.ForwarderMaxPendingJobs)
+    }
+
+    if config.ForwarderSoftMaxPendingJobs > 0 {
+        if config.ForwarderMaxPendingJobs > 0 && config.ForwarderSoftMaxPendingJobs >= config.ForwarderMaxPendingJobs {
+            log.Printf("WARN:  forwarder_soft_max_pending_jobs (%d) >= forwarder_max_pending_jobs (%d) - disabling the soft watermark", config.ForwarderSoftMaxPendingJobs, config.ForwarderMaxPendingJobs)
+            config.ForwarderSoftMaxPendingJobs = 0
+        } else {
+            log.Printf("INFO:    forwarder_soft_max_pending_jobs: %d (retry_after=%ds)", config.ForwarderSoftMaxPendingJobs, config.ForwarderBackpressureRetryAfterSeconds)
+        }
+    }
+
+    if config.OverloadStatusCode != 429 && config.OverloadStatusCode != 503 {
+        log.Printf("WARN:  overload_status_code=%d is neither 429 nor 503, defaulting to 429", config.OverloadStatusCode)
+        config.OverloadStatusCode = 429
+    }
+    if config.OverloadRetryAfterSeconds <= 0 {
+        log.Printf("WARN:  overload_retry_after_seconds <= 0 (%d), defaulting to 5", config.OverloadRetryAfterSeconds)
+        config.OverloadRetryAfterSeconds = 5
+    }
+
+    if config.ForwarderRequestTimeoutSeconds > 0 || config.ForwarderDialTimeoutSeconds > 0 || config.ForwarderTLSHandshakeTimeoutSeconds > 0 || config.ForwarderIdleConnTimeoutSeconds > 0 {
+        log.Printf("INFO:    forwarder HTTP client overrides: request_timeout=%ds, dial_timeout=%ds, tls_handshake_timeout=%ds, idle_conn_timeout=%ds (0 = built-in default)",
+            config.ForwarderRequestTimeoutSeconds, config.ForwarderDialTimeoutSeconds, config.ForwarderTLSHandshakeTimeoutSeconds, config.ForwarderIdleConnTimeoutSeconds)
+    }
+
+    if config.ForwarderMaxIdleConnsPerHost > 0 || config.ForwarderMaxConnsPerHost > 0 || config.ForwarderDisableHTTP2 {
+        log.Printf("INFO:    forwarder connection pool overrides: max_idle_conns_per_host=%d, max_conns_per_host=%d (0 = built-in default), disable_http2=%v",
+            config.ForwarderMaxIdleConnsPerHost, config.ForwarderMaxConnsPerHost, config.ForwarderDisableHTTP2)
+    }
+
+    if config.UpstreamErrorBufferSize > 0 {
+        log.Printf("INFO:    upstream_error_buffer_size: %d", config.UpstreamErrorBufferSize)
+    } else {
+        log.Printf("WARN:  upstream_error_buffer_size=0: GET /admin/debug/upstream-errors will report no entries")
+    }
+
+    if config.LoadSheddingMaxQueueDepth > 0 {
+        log.Printf("INFO:    load_shedding_max_queue_depth: %d", config.LoadSheddingMaxQueueDepth)
+    }
+
+    if config.ProbabilisticSheddingThresholdPercent > 0 {
+        if config.LoadSheddingMaxQueueDepth <= 0 {
+            log.Printf("WARN:  probabilistic_shedding_threshold_percent is set but load_shedding_max_queue_depth is 0 - probabilistic shedding has no queue depth to measure saturation against and will never trigger")
+            config.ProbabilisticSheddingThresholdPercent = 0
+        } else if config.ProbabilisticSheddingDropPercent <= 0 {
+            log.Printf("WARN:  probabilistic_shedding_threshold_percent is set but probabilistic_shedding_drop_percent is 0 - probabilistic shedding will never trigger")
+            config.ProbabilisticSheddingThresholdPercent = 0
+        } else {
+            log.Printf("INFO:    probabilistic_shedding_threshold_percent: %d%%, probabilistic_shedding_drop_percent: %d%%", config.ProbabilisticSheddingThresholdPercent, config.ProbabilisticSheddingDropPercent)
+        }
+    }
+
+    if config.TenantQueueShareMaxPending > 0 {
+        log.Printf("INFO:    tenant_queue_share_max_pending: %d (header: %s)", config.TenantQueueShareMaxPending, config.TenantQueueShareHeader)
+    }
+
+    if len(config.TenantWeights) > 0 {
+        log.Printf("INFO:    tenant_weights: %v", config.TenantWeights)
+    }
+
+    switch config.ForwardCompression {
+    case "", "gzip", "zstd":
+        // ok
+    default:
+        log.Printf("WARN:  unknown forward_compression=%q, disabling forwarding compression", config.ForwardCompression)
+        config.ForwardCompression = ""
+    }
+    if config.ForwardCompression != "" {
+        log.Printf("INFO:    forward_compression: %s", config.ForwardCompression)
+    }
+
+    if config.TenantConcurrencyMaxPerTenant > 0 {
+        log.Printf("INFO:    tenant_concurrency_max_per_tenant: %d (header: %s)", config.TenantConcurrencyMaxPerTenant, config.TenantConcurrencyHeader)
+    }
+
+    if config.IngestByteRateLimitEnabled {
+        if config.IngestByteRateLimitBytesPerSecond <= 0 {
+            log.Printf("WARN:  ingest_byte_rate_limit_bytes_per_second <= 0 (%v), defaulting to 52428800", config.IngestByteRateLimitBytesPerSecond)
+            config.IngestByteRateLimitBytesPerSecond = 52428800.0
+        }
+        if config.IngestByteRateLimitBurstBytes <= 0 {
+            log.Printf("WARN:  ingest_byte_rate_limit_burst_bytes <= 0 (%d), defaulting to 104857600", config.IngestByteRateLimitBurstBytes)
+            config.IngestByteRateLimitBurstBytes = 104857600
+        }
+        log.Printf("INFO:    ingest_byte_rate_limit_enabled: true (bytes_per_second=%.0f, burst_bytes=%d)", config.IngestByteRateLimitBytesPerSecond, config.IngestByteRateLimitBurstBytes)
+        if config.IngestByteRateLimitStateFile != "" {
+            log.Printf("INFO:    ingest_byte_rate_limit_state_file: %s (persisted every %ds)", config.IngestByteRateLimitStateFile, config.RateLimitStatePersistIntervalSeconds)
+        }
+    }
+
+    if config.MaxDecompressedBodyBytes < 0 {
+        log.Printf("WARN:  max_decompressed_body_bytes < 0 (%d), defaulting to 52428800", config.MaxDecompressedBodyBytes)
+        config.MaxDecompressedBodyBytes = 52428800
+    }
+    if config.MaxDecompressedBodyBytes > 0 {
+        log.Printf("INFO:    max_decompressed_body_bytes: %d", config.MaxDecompressedBodyBytes)
+    }
+
+    if config.JobTrackingEnabled {
+        if config.JobTrackingCapacity <= 0 {
+            log.Printf("WARN:  job_tracking_capacity <= 0 (%d), defaulting to 10000", config.JobTrackingCapacity)
+            config.JobTrackingCapacity = 10000
+        }
+        log.Printf("INFO:    job_tracking_enabled: true (capacity=%d)", config.JobTrackingCapacity)
+    }
+
+    switch config.AccessLogFormat {
+    case "", "ecs":
+        // ok
+    default:
+        log.Printf("WARN:  unknown access_log_format=%q, defaulting to '' (echo's combined-log-style line)", config.AccessLogFormat)
+        config.AccessLogFormat = ""
+    }
+
+    if config.DedupEnabled {
+        if config.DedupWindowSeconds <= 0 {
+            log.Printf("WARN:  dedup_window_seconds <= 0 (%d), defaulting to 30", config.DedupWindowSeconds)
+            config.DedupWindowSeconds = 30
+        }
+        log.Printf("INFO:    dedup_enabled: true (window=%ds, idempotency_header=%q)", config.DedupWindowSeconds, config.DedupIdempotencyHeader)
+    }
+
+    if config.IngestTokenIssuanceEnabled {
+        if config.IngestTokenSigningSecret == "" {
+            log.Printf("WARN:  ingest_token_issuance_enabled is true but ingest_token_signing_secret is empty, disabling ingest token issuance")
+            config.IngestTokenIssuanceEnabled = false
+            config.IngestTokenEnforcementEnabled = false
+        } else {
+            if config.IngestTokenTTLSeconds <= 0 {
+                log.Printf("WARN:  ingest_token_ttl_seconds <= 0 (%d), defaulting to 300", config.IngestTokenTTLSeconds)
+                config.IngestTokenTTLSeconds = 300
+            }
+            log.Printf("INFO:    ingest_token_issuance_enabled: true (ttl=%ds)", config.IngestTokenTTLSeconds)
+            if config.IngestTokenEnforcementEnabled {
+                log.Printf("INFO:    ingest_token_enforcement_enabled: true")
+            }
+        }
+    } else if config.IngestTokenEnforcementEnabled {
+        log.Printf("WARN:  ingest_token_enforcement_enabled is true but ingest_token_issuance_enabled is false, disabling enforcement")
+        config.IngestTokenEnforcementEnabled = false
+    }
+
+    if config.PowChallengeEnabled {
+        if config.PowChallengeSigningSecret == "" {
+            log.Printf("WARN:  pow_challenge_enabled is true but pow_challenge_signing_secret is empty, disabling proof-of-work challenges")
+            config.PowChallengeEnabled = false
+        } else {
+            if config.PowChallengeDifficultyBits <= 0 {
+                log.Printf("WARN:  pow_challenge_difficulty_bits <= 0 (%d), defaulting to 16", config.PowChallengeDifficultyBits)
+                config.PowChallengeDifficultyBits = 16
+            }
+            if config.PowChallengeTTLSeconds <= 0 {
+                log.Printf("WARN:  pow_challenge_ttl_seconds <= 0 (%d), defaulting to 60", config.PowChallengeTTLSeconds)
+                config.PowChallengeTTLSeconds = 60
+            }
+            log.Printf("INFO:    pow_challenge_enabled: true (difficulty_bits=%d, ttl=%ds)", config.PowChallengeDifficultyBits, config.PowChallengeTTLSeconds)
+        }
+    }
+
+    if config.GeoIPEnabled {
+        if config.GeoIPDatabasePath == "" {
+            log.Printf("WARN:  geoip_enabled is true but geoip_database_path is empty, disabling GeoIP routing/blocking")
+            config.GeoIPEnabled = false
+        } else {
+            validCountryRoutes := config.GeoIPCountryRoutes[:0]
+            for _, route := range config.GeoIPCountryRoutes {
+                if route.Country == "" || route.TargetURL == "" {
+                    log.Printf("WARN:  geoip_country_routes entry missing country or target_url, skipping rule")
+                    continue
+                }
+                validCountryRoutes = append(validCountryRoutes, route)
+            }
+            config.GeoIPCountryRoutes = validCountryRoutes
+            log.Printf("INFO:    geoip_enabled: true (database=%s, blocked_countries=%v, %d route(s) configured)", config.GeoIPDatabasePath, config.GeoIPBlockedCountries, len(config.GeoIPCountryRoutes))
+        }
+    }
+
+    if config.DataResidencyRegion != "" {
+        if config.DataResidencyAttributeKey == "" {
+            log.Printf("WARN:  data_residency_region is set but data_residency_attribute_key is empty, disabling residency stamping/enforcement")
+            config.DataResidencyRegion = ""
+        } else {
+            log.Printf("INFO:    data_residency_region: %s (attribute=%s, %d target region(s) configured)", config.DataResidencyRegion, config.DataResidencyAttributeKey, len(config.DataResidencyTargetRegions))
+        }
+    }
+
+    if config.HealthSheddingEnabled {
+        if config.HealthSheddingLatencyThresholdMS <= 0 {
+            log.Printf("WARN:  health_shedding_latency_threshold_ms <= 0 (%d), defaulting to 2000", config.HealthSheddingLatencyThresholdMS)
+            config.HealthSheddingLatencyThresholdMS = 2000
+        }
+        if config.HealthSheddingBreakerThreshold > 0 && config.HealthSheddingBreakerCooldownSeconds <= 0 {
+            log.Printf("WARN:  health_shedding_breaker_cooldown_seconds <= 0 (%d), defaulting to 30", config.HealthSheddingBreakerCooldownSeconds)
+            config.HealthSheddingBreakerCooldownSeconds = 30
+        }
+        log.Printf("INFO:    health_shedding_enabled: true (latency_threshold=%dms, breaker_threshold=%d, breaker_cooldown=%ds)", config.HealthSheddingLatencyThresholdMS, config.HealthSheddingBreakerThreshold, config.HealthSheddingBreakerCooldownSeconds)
+    }
+
+    if config.ForwarderReadinessCheckEnabled {
+        log.Printf("INFO:    forwarder_readiness_check_enabled: true")
+    }
+
 	log.Printf("INFO:  Configuration loaded successfully from %s", viper.ConfigFileUsed())
 	log.Printf("INFO:    otel_collector_target_url: %s", config.OtelCollectorTargetURL)
 	log.Printf("INFO:    shutdown_drain_seconds: %d", config.ShutdownDrainSeconds)
@@ -92,7 +903,240 @@ func Load() (*Config, error) {
     if config.ForwardingMode == "semaphore" || config.ForwardingMode == "hybrid" {
         log.Printf("INFO:    semaphore_max_concurrent: %d", config.SemaphoreMaxConcurrent)
     }
+    if config.ForwardingMode == "adaptive" {
+        log.Printf("INFO:    adaptive_min_concurrent: %d, adaptive_max_concurrent: %d, adaptive_latency_threshold_ms: %d", config.AdaptiveMinConcurrent, config.AdaptiveMaxConcurrent, config.AdaptiveLatencyThresholdMS)
+    }
     log.Printf("INFO:    sync_logs_debug: %v", config.SyncLogsDebug)
+    if config.SyncLogsStreamingEnabled && !config.SyncLogsDebug {
+        log.Printf("WARN:  sync_logs_streaming_enabled is true but sync_logs_debug is false, streaming has no effect on the async path")
+    }
+    log.Printf("INFO:    debug_dump_dir: %s", config.DebugDumpDir)
+    if config.WatchdogRSSThresholdMB > 0 || config.WatchdogQueueDepthThreshold > 0 {
+        log.Printf("INFO:    watchdog_rss_threshold_mb: %d", config.WatchdogRSSThresholdMB)
+        log.Printf("INFO:    watchdog_queue_depth_threshold: %d", config.WatchdogQueueDepthThreshold)
+        log.Printf("INFO:    watchdog_check_interval_seconds: %d", config.WatchdogCheckIntervalSeconds)
+        log.Printf("INFO:    watchdog_min_dump_interval_seconds: %d", config.WatchdogMinDumpIntervalSeconds)
+    }
+    if config.LatencyInjectionEnabled {
+        log.Printf("WARN:  latency_injection_enabled=true - artificial latency will be added to %d%% of ingest requests (%d-%dms)", config.LatencyInjectionPercent, config.LatencyInjectionMinMS, config.LatencyInjectionMaxMS)
+    }
+    if config.CanaryEnabled {
+        log.Printf("INFO:    canary_enabled: true (interval=%ds)", config.CanaryIntervalSeconds)
+    }
+    if config.PrivateNetworkAccessEnabled {
+        log.Printf("INFO:    private_network_access_enabled: true")
+    }
+    if config.LargePayloadOffloadEnabled {
+        if config.LargePayloadThresholdBytes <= 0 {
+            log.Printf("WARN:  large_payload_threshold_bytes <= 0 (%d), defaulting to 524288", config.LargePayloadThresholdBytes)
+            config.LargePayloadThresholdBytes = 512 * 1024
+        }
+        log.Printf("INFO:    large_payload_offload_enabled: true (threshold_bytes=%d, temp_dir=%q)", config.LargePayloadThresholdBytes, config.LargePayloadTempDir)
+    }
+    if config.MirrorTargetURL != "" {
+        if config.MirrorMaxConcurrent <= 0 {
+            log.Printf("WARN:  mirror_max_concurrent <= 0 (%d), defaulting to 50", config.MirrorMaxConcurrent)
+            config.MirrorMaxConcurrent = 50
+        }
+        log.Printf("INFO:    mirror_target_url: %s (max_concurrent=%d)", config.MirrorTargetURL, config.MirrorMaxConcurrent)
+    }
+    if config.CircuitBreakerFallbackURL != "" {
+        if config.CircuitBreakerOpenThreshold <= 0 {
+            log.Printf("WARN:  circuit_breaker_open_threshold <= 0 (%d), defaulting to 3", config.CircuitBreakerOpenThreshold)
+            config.CircuitBreakerOpenThreshold = 3
+        }
+        if config.CircuitBreakerCooldownSeconds <= 0 {
+            log.Printf("WARN:  circuit_breaker_cooldown_seconds <= 0 (%d), defaulting to 30", config.CircuitBreakerCooldownSeconds)
+            config.CircuitBreakerCooldownSeconds = 30
+        }
+        log.Printf("INFO:    circuit_breaker_fallback_url: %s (open_threshold=%d, cooldown_seconds=%d)", config.CircuitBreakerFallbackURL, config.CircuitBreakerOpenThreshold, config.CircuitBreakerCooldownSeconds)
+    }
+    if config.SpoolEncryptionEnabled {
+        if _, ok := config.SpoolEncryptionKeys[config.SpoolEncryptionActiveKeyID]; !ok {
+            log.Printf("WARN:  spool_encryption_enabled is true but spool_encryption_active_key_id=%q is not present in spool_encryption_keys, disabling spool encryption", config.SpoolEncryptionActiveKeyID)
+            config.SpoolEncryptionEnabled = false
+        } else {
+            log.Printf("INFO:    spool_encryption_enabled: true (active_key_id=%s, %d key(s) configured)", config.SpoolEncryptionActiveKeyID, len(config.SpoolEncryptionKeys))
+        }
+    }
+    if _, err := tlspolicy.Build(tlspolicy.Config{
+        MinVersion:       config.TLSMinVersion,
+        CipherSuites:     config.TLSCipherSuites,
+        CurvePreferences: config.TLSCurvePreferences,
+    }); err != nil {
+        log.Printf("WARN:  invalid listener TLS policy (%v), falling back to tls_min_version=1.2 with Go's default cipher suites/curves", err)
+        config.TLSMinVersion = "1.2"
+        config.TLSCipherSuites = nil
+        config.TLSCurvePreferences = nil
+    }
+    if config.TLSEnabled {
+        if config.TLSCertFile == "" || config.TLSKeyFile == "" {
+            log.Printf("WARN:  tls_enabled is true but tls_cert_file/tls_key_file are not both set, disabling TLS termination")
+            config.TLSEnabled = false
+        } else {
+            log.Printf("INFO:    tls_enabled: true (min_version=%s, cert_file=%s)", config.TLSMinVersion, config.TLSCertFile)
+        }
+    }
+    if (config.ForwarderTLSClientCertFile == "") != (config.ForwarderTLSClientKeyFile == "") {
+        log.Printf("WARN:  forwarder_tls_client_cert_file and forwarder_tls_client_key_file must both be set for upstream mTLS, disabling")
+        config.ForwarderTLSClientCertFile = ""
+        config.ForwarderTLSClientKeyFile = ""
+    }
+    if _, err := tlspolicy.Build(tlspolicy.Config{
+        MinVersion:       config.ForwarderTLSMinVersion,
+        CipherSuites:     config.ForwarderTLSCipherSuites,
+        CurvePreferences: config.ForwarderTLSCurvePreferences,
+        ClientCertFile:   config.ForwarderTLSClientCertFile,
+        ClientKeyFile:    config.ForwarderTLSClientKeyFile,
+    }); err != nil {
+        log.Printf("WARN:  invalid forwarder TLS policy (%v), falling back to forwarder_tls_min_version=1.2 with Go's default cipher suites/curves and no client certificate", err)
+        config.ForwarderTLSMinVersion = "1.2"
+        config.ForwarderTLSCipherSuites = nil
+        config.ForwarderTLSCurvePreferences = nil
+        config.ForwarderTLSClientCertFile = ""
+        config.ForwarderTLSClientKeyFile = ""
+    } else if config.ForwarderTLSClientCertFile != "" {
+        log.Printf("INFO:    upstream mTLS enabled: client_cert_file=%s (reloaded on change)", config.ForwarderTLSClientCertFile)
+    }
+    if config.ArchiveEnabled {
+        if config.ArchiveOutputDir == "" {
+            log.Printf("WARN:  archive_enabled is true but archive_output_dir is empty, disabling archival")
+            config.ArchiveEnabled = false
+        } else {
+            if config.ArchiveMaxFileSizeMB <= 0 {
+                log.Printf("WARN:  archive_max_file_size_mb <= 0 (%d), defaulting to 100", config.ArchiveMaxFileSizeMB)
+                config.ArchiveMaxFileSizeMB = 100
+            }
+            if config.ArchiveS3Bucket != "" && (config.ArchiveS3Region == "" || config.ArchiveS3AccessKeyID == "" || config.ArchiveS3SecretAccessKey == "") {
+                log.Printf("WARN:  archive_s3_bucket is set but archive_s3_region/archive_s3_access_key_id/archive_s3_secret_access_key is incomplete, archiving locally only")
+                config.ArchiveS3Bucket = ""
+            }
+            log.Printf("INFO:    archive_enabled: true (output_dir=%s, max_file_size_mb=%d, s3_bucket=%q)", config.ArchiveOutputDir, config.ArchiveMaxFileSizeMB, config.ArchiveS3Bucket)
+        }
+    }
+    if config.ReconciliationEnabled && config.ReconciliationCollectorMetricsURL == "" {
+        log.Printf("WARN:  reconciliation_enabled=true but reconciliation_collector_metrics_url is empty - reconciliation will not run")
+        config.ReconciliationEnabled = false
+    }
+    if config.ReconciliationEnabled {
+        log.Printf("INFO:    reconciliation_enabled: true (url=%s, metric=%s, interval=%ds)", config.ReconciliationCollectorMetricsURL, config.ReconciliationMetricName, config.ReconciliationIntervalSeconds)
+    }
+
+    validRewriteRules := config.ResourceAttributeRewriteRules[:0]
+    for _, rule := range config.ResourceAttributeRewriteRules {
+        switch rule.Action {
+        case "rename", "set_default", "delete":
+            validRewriteRules = append(validRewriteRules, rule)
+        default:
+            log.Printf("WARN:  unknown resource_attribute_rewrite_rules action=%q, skipping rule", rule.Action)
+        }
+    }
+    config.ResourceAttributeRewriteRules = validRewriteRules
+    if len(config.ResourceAttributeRewriteRules) > 0 {
+        log.Printf("INFO:    resource_attribute_rewrite_rules: %d rule(s) configured", len(config.ResourceAttributeRewriteRules))
+    }
+
+    if config.WorkerPoolRetryMaxAttempts < 1 {
+        log.Printf("WARN:  worker_pool_retry_max_attempts < 1 (%d), defaulting to 1 (no retries)", config.WorkerPoolRetryMaxAttempts)
+        config.WorkerPoolRetryMaxAttempts = 1
+    }
+    if config.WorkerPoolRetryMaxAttempts > 1 {
+        log.Printf("INFO:    worker_pool_retry_max_attempts: %d (initial_backoff=%dms, max_backoff=%dms, jitter=%.2f)", config.WorkerPoolRetryMaxAttempts, config.WorkerPoolRetryInitialBackoffMS, config.WorkerPoolRetryMaxBackoffMS, config.WorkerPoolRetryJitterFraction)
+    }
+
+    if config.SchemaNormalizationTargetSchemaURL != "" || len(config.SchemaNormalizationAttributeAliases) > 0 {
+        log.Printf("INFO:    schema_normalization: target_schema_url=%q, %d attribute alias(es) configured", config.SchemaNormalizationTargetSchemaURL, len(config.SchemaNormalizationAttributeAliases))
+    }
+
+    if config.DeadLetterDir != "" {
+        log.Printf("INFO:    dead_letter_dir: %s (max_size_mb=%d)", config.DeadLetterDir, config.DeadLetterMaxSizeMB)
+    }
+
+    if config.ForwardingMode == "loopback" {
+        log.Printf("INFO:    forwarding_mode=loopback: payloads are recorded, not sent to a real collector (capacity=%d, disk_dir=%q)", config.LoopbackCapacity, config.LoopbackDiskDir)
+    }
+
+    validRoutingRules := config.ResourceRoutingRules[:0]
+    for _, rule := range config.ResourceRoutingRules {
+        if rule.Key == "" || rule.TargetURL == "" {
+            log.Printf("WARN:  resource_routing_rules entry missing key or target_url, skipping rule")
+            continue
+        }
+        validRoutingRules = append(validRoutingRules, rule)
+    }
+    config.ResourceRoutingRules = validRoutingRules
+    if len(config.ResourceRoutingRules) > 0 {
+        log.Printf("INFO:    resource_routing_rules: %d rule(s) configured", len(config.ResourceRoutingRules))
+    }
+
+    switch config.WorkerPoolQueueMode {
+    case "memory":
+        // default, nothing to log
+    case "disk":
+        if config.WorkerPoolDiskQueueDir == "" {
+            log.Printf("WARN:  worker_pool_queue_mode=disk but worker_pool_disk_queue_dir is empty, falling back to 'memory'")
+            config.WorkerPoolQueueMode = "memory"
+        } else {
+            log.Printf("INFO:    worker_pool_queue_mode: disk (dir=%s)", config.WorkerPoolDiskQueueDir)
+        }
+    default:
+        log.Printf("WARN:  unknown worker_pool_queue_mode=%q, defaulting to 'memory'", config.WorkerPoolQueueMode)
+        config.WorkerPoolQueueMode = "memory"
+    }
+
+    if config.TailSamplingEnabled {
+        if config.TailSamplingWindowSeconds <= 0 {
+            log.Printf("WARN:  tail_sampling_window_seconds <= 0 (%d), defaulting to 10", config.TailSamplingWindowSeconds)
+            config.TailSamplingWindowSeconds = 10
+        }
+        log.Printf("INFO:    tail_sampling_enabled: true (window=%ds, slow_threshold=%dms, error_sample_rate=%.2f, default_sample_rate=%.2f) [experimental]",
+            config.TailSamplingWindowSeconds, config.TailSamplingSlowThresholdMS, config.TailSamplingErrorSampleRate, config.TailSamplingDefaultSampleRate)
+    }
+
+    if config.BatchingEnabled {
+        if config.BatchMaxSize <= 0 {
+            log.Printf("WARN:  batch_max_size <= 0 (%d), defaulting to 20", config.BatchMaxSize)
+            config.BatchMaxSize = 20
+        }
+        if config.BatchMaxAgeMS <= 0 {
+            log.Printf("WARN:  batch_max_age_ms <= 0 (%d), defaulting to 1000", config.BatchMaxAgeMS)
+            config.BatchMaxAgeMS = 1000
+        }
+        log.Printf("INFO:    batching_enabled: true (max_size=%d, max_age_ms=%d)", config.BatchMaxSize, config.BatchMaxAgeMS)
+    }
+
+    if config.UsageAccountingEnabled {
+        if config.UsageAccountingOutputDir == "" && config.UsageAccountingBillingURL == "" {
+            log.Printf("WARN:  usage_accounting_enabled=true but both usage_accounting_output_dir and usage_accounting_billing_url are empty, disabling")
+            config.UsageAccountingEnabled = false
+        } else {
+            if config.UsageAccountingIntervalSeconds <= 0 {
+                log.Printf("WARN:  usage_accounting_interval_seconds <= 0 (%d), defaulting to 60", config.UsageAccountingIntervalSeconds)
+                config.UsageAccountingIntervalSeconds = 60
+            }
+            log.Printf("INFO:    usage_accounting_enabled: true (interval=%ds, output_dir=%q, billing_url=%q, tenant_header=%s)",
+                config.UsageAccountingIntervalSeconds, config.UsageAccountingOutputDir, config.UsageAccountingBillingURL, config.UsageAccountingTenantHeader)
+        }
+    }
 
 	return &config, nil
 }
+
+// Defaults returns a Config populated with the same defaults Load() applies
+// when a key is absent from the environment or config file. It performs no
+// file I/O or validation - it exists for callers that build a Config
+// directly instead of going through Load() (embedding users, tests), so
+// they don't silently run with zero-valued timeouts, pool sizes, and limits
+// where Load() would have filled in a sane default.
+func Defaults() Config {
+	v := viper.New()
+	setDefaults(v)
+
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		// setDefaults only sets literals matching Config's mapstructure
+		// tags, so this can only fail if the two have drifted out of sync.
+		panic(fmt.Sprintf("config: Defaults(): %v", err))
+	}
+	return config
+}
@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestDefaults_MatchesLoadDefaults(t *testing.T) {
+	got := Defaults()
+
+	if got.ServerPort != 8080 {
+		t.Errorf("expected ServerPort default 8080, got %d", got.ServerPort)
+	}
+	if got.JobQueueSize != 10000 {
+		t.Errorf("expected JobQueueSize default 10000, got %d", got.JobQueueSize)
+	}
+	if got.MaxRequestSizeMB != 1 {
+		t.Errorf("expected MaxRequestSizeMB default 1, got %d", got.MaxRequestSizeMB)
+	}
+	if len(got.AllowedOrigins) != 1 || got.AllowedOrigins[0] != "*" {
+		t.Errorf("expected AllowedOrigins default [\"*\"], got %v", got.AllowedOrigins)
+	}
+}
+
+func TestDefaults_ReturnsFreshCopyEachCall(t *testing.T) {
+	first := Defaults()
+	first.AllowedOrigins[0] = "mutated"
+
+	second := Defaults()
+	if second.AllowedOrigins[0] != "*" {
+		t.Errorf("expected second call unaffected by mutation of the first, got %v", second.AllowedOrigins)
+	}
+}
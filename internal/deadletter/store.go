@@ -0,0 +1,132 @@
+package deadletter
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"zep-logger/internal/metrics"
+	"zep-logger/internal/spoolcrypto"
+	"zep-logger/pkg/logger"
+)
+
+// meta is the on-disk sidecar for a dead-lettered job, alongside its raw body.
+// Uses the same "<id>.bin" + "<id>.json" spool layout as the archive package,
+// so a dead-letter directory can be exported/imported with otlpxy-archive.
+type meta struct {
+	TargetURL string      `json:"target_url"`
+	Headers   http.Header `json:"headers"`
+	Reason    string      `json:"reason"`
+}
+
+// Store writes permanently-failed forwarding jobs to disk instead of dropping
+// them, so they can be inspected or replayed later
+type Store struct {
+	dir        string
+	maxSizeMB  int
+	mu         sync.Mutex
+	sizeBytes  int64
+	encryptor  *spoolcrypto.KeySet // Encrypts/decrypts job bodies at rest, nil disables encryption (the original behavior)
+}
+
+// New creates a Store rooted at dir, creating it if necessary
+// maxSizeMB caps the total on-disk size of dead-lettered jobs; once exceeded,
+// further writes are rejected rather than growing the store unbounded.
+// encryptor may be nil to leave bodies on disk in plaintext (the original
+// behavior); when set, every body written by Write is sealed with it.
+func New(dir string, maxSizeMB int, encryptor *spoolcrypto.KeySet) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("deadletter: failed to create dir %s: %w", dir, err)
+	}
+
+	s := &Store{dir: dir, maxSizeMB: maxSizeMB, encryptor: encryptor}
+	if err := s.scanExisting(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write persists a permanently-failed job's body, target URL, and headers to
+// disk, along with reason describing why it was dead-lettered (e.g. "retries
+// exhausted", "4xx from collector")
+func (s *Store) Write(body []byte, targetURL string, headers http.Header, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxSizeMB > 0 && s.sizeBytes+int64(len(body)) > int64(s.maxSizeMB)*1024*1024 {
+		logger.Warn("Dead-letter store full (%dMB limit), dropping job for %s", s.maxSizeMB, targetURL)
+		return fmt.Errorf("deadletter: store size limit reached (%dMB)", s.maxSizeMB)
+	}
+
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+
+	onDiskBody := body
+	if s.encryptor != nil {
+		var err error
+		onDiskBody, err = s.encryptor.Encrypt(body)
+		if err != nil {
+			return fmt.Errorf("deadletter: failed to encrypt body for %s: %w", id, err)
+		}
+	}
+
+	bodyPath := filepath.Join(s.dir, id+".bin")
+	if err := os.WriteFile(bodyPath, onDiskBody, 0o644); err != nil {
+		return fmt.Errorf("deadletter: failed to write %s: %w", bodyPath, err)
+	}
+
+	metaBytes, err := json.Marshal(meta{TargetURL: targetURL, Headers: headers, Reason: reason})
+	if err != nil {
+		return fmt.Errorf("deadletter: failed to encode metadata for %s: %w", id, err)
+	}
+	metaPath := filepath.Join(s.dir, id+".json")
+	if err := os.WriteFile(metaPath, metaBytes, 0o644); err != nil {
+		return fmt.Errorf("deadletter: failed to write %s: %w", metaPath, err)
+	}
+
+	s.sizeBytes += int64(len(onDiskBody))
+	metrics.DeadLetterCountGauge.Inc()
+	metrics.DeadLetterSizeBytesGauge.Add(float64(len(onDiskBody)))
+	return nil
+}
+
+// scanExisting seeds the in-memory size/count from any entries already on
+// disk, so metrics and the size limit are correct across restarts
+func (s *Store) scanExisting() error {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("deadletter: failed to read dir %s: %w", s.dir, err)
+	}
+
+	var count int
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".bin" {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		s.sizeBytes += info.Size()
+		count++
+	}
+
+	metrics.DeadLetterCountGauge.Set(float64(count))
+	metrics.DeadLetterSizeBytesGauge.Set(float64(s.sizeBytes))
+	return nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("deadletter: failed to generate id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
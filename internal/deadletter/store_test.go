@@ -0,0 +1,59 @@
+package deadletter
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_Write_PersistsBodyAndMetadata(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(dir, 0, nil)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	headers := http.Header{"Content-Type": []string{"application/x-protobuf"}}
+	if err := s.Write([]byte("payload"), "http://collector/v1/logs", headers, "retries exhausted"); err != nil {
+		t.Fatalf("failed to write dead-letter entry: %v", err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files (body + metadata), got %d", len(files))
+	}
+}
+
+func TestStore_Write_RejectsWhenSizeLimitExceeded(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(dir, 1, nil)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	s.mu.Lock()
+	s.sizeBytes = 1 * 1024 * 1024
+	s.mu.Unlock()
+
+	if err := s.Write([]byte("more data"), "http://collector/v1/logs", nil, "retries exhausted"); err == nil {
+		t.Fatal("expected write to be rejected once size limit is reached")
+	}
+}
+
+func TestNew_ScansExistingEntriesOnRestart(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "abc123.bin"), []byte("12345"), 0o644); err != nil {
+		t.Fatalf("failed to seed existing entry: %v", err)
+	}
+
+	s, err := New(dir, 0, nil)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	if s.sizeBytes != 5 {
+		t.Errorf("expected sizeBytes to be seeded from existing files, got %d", s.sizeBytes)
+	}
+}
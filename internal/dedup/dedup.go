@@ -0,0 +1,104 @@
+// Package dedup suppresses payloads seen again within a sliding time window,
+// so a browser SDK retrying an OTLP export on a flaky network doesn't produce
+// duplicate telemetry downstream.
+package dedup
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "sync"
+    "time"
+)
+
+// defaultWindow is used when NewDeduper is given a non-positive window
+const defaultWindow = 30 * time.Second
+
+// Deduper tracks recently-seen dedup keys and reports whether a key has
+// already been seen within the configured sliding window
+type Deduper struct {
+    window time.Duration
+
+    mu   sync.Mutex
+    seen map[string]time.Time
+
+    stopOnce sync.Once
+    stopCh   chan struct{}
+    wg       sync.WaitGroup
+}
+
+// NewDeduper creates a Deduper that suppresses a key seen again within
+// window. window <= 0 defaults to 30s.
+func NewDeduper(window time.Duration) *Deduper {
+    if window <= 0 {
+        window = defaultWindow
+    }
+    return &Deduper{
+        window: window,
+        seen:   make(map[string]time.Time),
+        stopCh: make(chan struct{}),
+    }
+}
+
+// Start launches a background goroutine that periodically evicts entries
+// older than window, so long-running processes don't grow seen unbounded
+func (d *Deduper) Start() {
+    d.wg.Add(1)
+    go d.sweep()
+}
+
+// Stop halts the background eviction goroutine, waiting for it to exit
+func (d *Deduper) Stop() {
+    d.stopOnce.Do(func() {
+        close(d.stopCh)
+    })
+    d.wg.Wait()
+}
+
+func (d *Deduper) sweep() {
+    defer d.wg.Done()
+
+    ticker := time.NewTicker(d.window)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ticker.C:
+            d.evictExpired()
+        case <-d.stopCh:
+            return
+        }
+    }
+}
+
+func (d *Deduper) evictExpired() {
+    cutoff := time.Now().Add(-d.window)
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    for key, seenAt := range d.seen {
+        if seenAt.Before(cutoff) {
+            delete(d.seen, key)
+        }
+    }
+}
+
+// Seen reports whether key was already recorded within the sliding window
+// and, if not, records it as seen now
+func (d *Deduper) Seen(key string) bool {
+    now := time.Now()
+
+    d.mu.Lock()
+    defer d.mu.Unlock()
+
+    if lastSeen, ok := d.seen[key]; ok && now.Sub(lastSeen) < d.window {
+        return true
+    }
+    d.seen[key] = now
+    return false
+}
+
+// HashKey returns a stable dedup key derived from a payload body, used when
+// the caller has no client-supplied idempotency key to key off instead
+func HashKey(body []byte) string {
+    sum := sha256.Sum256(body)
+    return hex.EncodeToString(sum[:])
+}
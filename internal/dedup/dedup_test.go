@@ -0,0 +1,85 @@
+package dedup
+
+import (
+    "testing"
+    "time"
+)
+
+// TestDeduper_Seen_FirstTimeIsNotDuplicate verifies a key seen for the first
+// time is reported as new
+func TestDeduper_Seen_FirstTimeIsNotDuplicate(t *testing.T) {
+    d := NewDeduper(time.Minute)
+    if d.Seen("a") {
+        t.Error("expected first occurrence of a key to not be a duplicate")
+    }
+}
+
+// TestDeduper_Seen_WithinWindowIsDuplicate verifies a repeated key within the
+// window is reported as a duplicate
+func TestDeduper_Seen_WithinWindowIsDuplicate(t *testing.T) {
+    d := NewDeduper(time.Minute)
+    d.Seen("a")
+    if !d.Seen("a") {
+        t.Error("expected a repeated key within the window to be a duplicate")
+    }
+}
+
+// TestDeduper_Seen_AfterWindowIsNotDuplicate verifies a key is admitted again
+// once the sliding window has elapsed
+func TestDeduper_Seen_AfterWindowIsNotDuplicate(t *testing.T) {
+    d := NewDeduper(10 * time.Millisecond)
+    d.Seen("a")
+    time.Sleep(30 * time.Millisecond)
+    if d.Seen("a") {
+        t.Error("expected a key to be admitted again once the window has elapsed")
+    }
+}
+
+// TestDeduper_EvictExpired_RemovesStaleEntries verifies the background sweep
+// removes entries older than the window
+func TestDeduper_EvictExpired_RemovesStaleEntries(t *testing.T) {
+    d := NewDeduper(10 * time.Millisecond)
+    d.Seen("a")
+    time.Sleep(20 * time.Millisecond)
+    d.evictExpired()
+
+    d.mu.Lock()
+    _, stillPresent := d.seen["a"]
+    d.mu.Unlock()
+    if stillPresent {
+        t.Error("expected evictExpired to remove a stale entry")
+    }
+}
+
+// TestDeduper_StartStop_SweepsInBackground verifies Start launches a sweep
+// that eventually evicts expired entries without a manual evictExpired call
+func TestDeduper_StartStop_SweepsInBackground(t *testing.T) {
+    d := NewDeduper(10 * time.Millisecond)
+    d.Start()
+    defer d.Stop()
+
+    d.Seen("a")
+    time.Sleep(50 * time.Millisecond)
+
+    d.mu.Lock()
+    _, stillPresent := d.seen["a"]
+    d.mu.Unlock()
+    if stillPresent {
+        t.Error("expected the background sweep to evict a stale entry")
+    }
+}
+
+// TestHashKey_SameBodySameKey verifies HashKey is deterministic
+func TestHashKey_SameBodySameKey(t *testing.T) {
+    if HashKey([]byte("payload")) != HashKey([]byte("payload")) {
+        t.Error("expected HashKey to be deterministic for identical bodies")
+    }
+}
+
+// TestHashKey_DifferentBodyDifferentKey verifies HashKey distinguishes
+// different bodies
+func TestHashKey_DifferentBodyDifferentKey(t *testing.T) {
+    if HashKey([]byte("a")) == HashKey([]byte("b")) {
+        t.Error("expected HashKey to differ for different bodies")
+    }
+}
@@ -0,0 +1,82 @@
+// Package diagnostics records recent non-2xx responses from the upstream
+// collector so an operator can inspect a collector-side rejection (bad
+// request, auth failure, quota) without re-running production traffic to
+// reproduce it.
+package diagnostics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MaxBodySnippetBytes caps how much of a non-2xx response body is retained
+// per entry, so a large error page doesn't balloon memory
+const MaxBodySnippetBytes = 4096
+
+// Entry records a single non-2xx response observed from an upstream collector
+type Entry struct {
+	Time       time.Time
+	TargetURL  string
+	StatusCode int
+	Headers    http.Header
+	Body       string
+}
+
+// Buffer is a fixed-capacity, thread-safe ring buffer of recent upstream
+// error responses, queryable via an admin endpoint for debugging
+// collector-side rejections
+type Buffer struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	size    int
+}
+
+// NewBuffer creates a Buffer holding at most capacity entries; capacity <= 0
+// defaults to 100
+func NewBuffer(capacity int) *Buffer {
+	if capacity <= 0 {
+		capacity = 100
+	}
+	return &Buffer{entries: make([]Entry, capacity)}
+}
+
+// Record appends an entry, overwriting the oldest once the buffer is full.
+// body is truncated to MaxBodySnippetBytes
+func (b *Buffer) Record(targetURL string, statusCode int, headers http.Header, body []byte) {
+	if len(body) > MaxBodySnippetBytes {
+		body = body[:MaxBodySnippetBytes]
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries[b.next] = Entry{
+		Time:       time.Now(),
+		TargetURL:  targetURL,
+		StatusCode: statusCode,
+		Headers:    headers,
+		Body:       string(body),
+	}
+	b.next = (b.next + 1) % len(b.entries)
+	if b.size < len(b.entries) {
+		b.size++
+	}
+}
+
+// Snapshot returns a copy of the currently retained entries, oldest first
+func (b *Buffer) Snapshot() []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make([]Entry, 0, b.size)
+	start := 0
+	if b.size == len(b.entries) {
+		start = b.next
+	}
+	for i := 0; i < b.size; i++ {
+		out = append(out, b.entries[(start+i)%len(b.entries)])
+	}
+	return out
+}
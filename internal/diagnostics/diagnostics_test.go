@@ -0,0 +1,60 @@
+package diagnostics
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestBuffer_Snapshot_ReturnsEntriesOldestFirst verifies ordering for a buffer
+// that hasn't wrapped yet
+func TestBuffer_Snapshot_ReturnsEntriesOldestFirst(t *testing.T) {
+	b := NewBuffer(3)
+	b.Record("http://a", 500, nil, []byte("first"))
+	b.Record("http://b", 502, nil, []byte("second"))
+
+	snapshot := b.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(snapshot))
+	}
+	if snapshot[0].Body != "first" || snapshot[1].Body != "second" {
+		t.Errorf("expected entries oldest-first, got %v", snapshot)
+	}
+}
+
+// TestBuffer_Record_WrapsAroundOverwritingOldest verifies the ring buffer
+// discards the oldest entry once at capacity
+func TestBuffer_Record_WrapsAroundOverwritingOldest(t *testing.T) {
+	b := NewBuffer(2)
+	b.Record("http://a", 500, nil, []byte("one"))
+	b.Record("http://b", 500, nil, []byte("two"))
+	b.Record("http://c", 500, nil, []byte("three"))
+
+	snapshot := b.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("expected 2 entries after wraparound, got %d", len(snapshot))
+	}
+	if snapshot[0].Body != "two" || snapshot[1].Body != "three" {
+		t.Errorf("expected oldest entry evicted, got %v", snapshot)
+	}
+}
+
+// TestBuffer_Record_TruncatesOversizedBody verifies a body larger than
+// MaxBodySnippetBytes is truncated instead of retained in full
+func TestBuffer_Record_TruncatesOversizedBody(t *testing.T) {
+	b := NewBuffer(1)
+	oversized := make([]byte, MaxBodySnippetBytes+100)
+	b.Record("http://a", 500, http.Header{"Content-Type": {"text/plain"}}, oversized)
+
+	snapshot := b.Snapshot()
+	if len(snapshot[0].Body) != MaxBodySnippetBytes {
+		t.Errorf("expected body truncated to %d bytes, got %d", MaxBodySnippetBytes, len(snapshot[0].Body))
+	}
+}
+
+// TestBuffer_Snapshot_EmptyWhenNoEntries verifies a fresh buffer reports no entries
+func TestBuffer_Snapshot_EmptyWhenNoEntries(t *testing.T) {
+	b := NewBuffer(5)
+	if snapshot := b.Snapshot(); len(snapshot) != 0 {
+		t.Errorf("expected empty snapshot, got %d entries", len(snapshot))
+	}
+}
@@ -0,0 +1,158 @@
+// Package diskqueue implements a write-ahead log for the worker pool's job
+// queue, so buffered telemetry survives a pod restart instead of being lost
+// with the in-memory channel in internal/worker.
+package diskqueue
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"zep-logger/internal/spoolcrypto"
+	"zep-logger/pkg/logger"
+)
+
+// meta is the on-disk sidecar for a queued job, alongside its raw body. Uses
+// the same "<id>.bin" + "<id>.json" spool layout as the archive and
+// deadletter packages.
+type meta struct {
+	TargetURL string      `json:"target_url"`
+	Headers   http.Header `json:"headers"`
+}
+
+// Entry is one job replayed from the write-ahead log on startup
+type Entry struct {
+	ID        string
+	Body      []byte
+	TargetURL string
+	Headers   http.Header
+}
+
+// Queue is a directory-backed write-ahead log: Enqueue durably persists a job
+// before it is handed to the in-memory worker queue, and Complete removes it
+// once delivery succeeds. Any entries still on disk at startup are returned
+// by Replay so they can be resubmitted.
+type Queue struct {
+	dir       string
+	encryptor *spoolcrypto.KeySet // Encrypts/decrypts job bodies at rest, nil disables encryption (the original behavior)
+}
+
+// New creates a Queue rooted at dir, creating it if necessary. encryptor
+// may be nil to leave bodies on disk in plaintext (the original behavior);
+// when set, every body written by Enqueue is sealed with it and every body
+// read back by Replay is opened with it.
+func New(dir string, encryptor *spoolcrypto.KeySet) (*Queue, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("diskqueue: failed to create dir %s: %w", dir, err)
+	}
+	return &Queue{dir: dir, encryptor: encryptor}, nil
+}
+
+// Enqueue durably writes body/targetURL/headers to disk and returns the
+// entry's id, to be passed to Complete once the job is delivered
+func (q *Queue) Enqueue(body []byte, targetURL string, headers http.Header) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	onDiskBody := body
+	if q.encryptor != nil {
+		var err error
+		onDiskBody, err = q.encryptor.Encrypt(body)
+		if err != nil {
+			return "", fmt.Errorf("diskqueue: failed to encrypt body for %s: %w", id, err)
+		}
+	}
+
+	bodyPath := filepath.Join(q.dir, id+".bin")
+	if err := os.WriteFile(bodyPath, onDiskBody, 0o644); err != nil {
+		return "", fmt.Errorf("diskqueue: failed to write %s: %w", bodyPath, err)
+	}
+
+	metaBytes, err := json.Marshal(meta{TargetURL: targetURL, Headers: headers})
+	if err != nil {
+		return "", fmt.Errorf("diskqueue: failed to encode metadata for %s: %w", id, err)
+	}
+	metaPath := filepath.Join(q.dir, id+".json")
+	if err := os.WriteFile(metaPath, metaBytes, 0o644); err != nil {
+		return "", fmt.Errorf("diskqueue: failed to write %s: %w", metaPath, err)
+	}
+
+	return id, nil
+}
+
+// Complete removes a previously-enqueued entry once it has been delivered
+// (or permanently given up on, e.g. dead-lettered). A no-op if id is empty.
+func (q *Queue) Complete(id string) error {
+	if id == "" {
+		return nil
+	}
+	if err := os.Remove(filepath.Join(q.dir, id+".bin")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("diskqueue: failed to remove %s.bin: %w", id, err)
+	}
+	if err := os.Remove(filepath.Join(q.dir, id+".json")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("diskqueue: failed to remove %s.json: %w", id, err)
+	}
+	return nil
+}
+
+// Replay returns every entry still on disk, ordered by id so restarts across
+// a crash loop replay in a stable order. Entries with a missing or corrupt
+// sidecar are skipped with a warning rather than failing the whole replay.
+func (q *Queue) Replay() ([]Entry, error) {
+	files, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, fmt.Errorf("diskqueue: failed to read dir %s: %w", q.dir, err)
+	}
+
+	var ids []string
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".bin" {
+			continue
+		}
+		ids = append(ids, f.Name()[:len(f.Name())-len(".bin")])
+	}
+	sort.Strings(ids)
+
+	entries := make([]Entry, 0, len(ids))
+	for _, id := range ids {
+		body, err := os.ReadFile(filepath.Join(q.dir, id+".bin"))
+		if err != nil {
+			logger.Warn("diskqueue: failed to read body for %s during replay, skipping: %v", id, err)
+			continue
+		}
+		if q.encryptor != nil {
+			body, err = q.encryptor.Decrypt(body)
+			if err != nil {
+				logger.Warn("diskqueue: failed to decrypt body for %s during replay, skipping: %v", id, err)
+				continue
+			}
+		}
+		metaBytes, err := os.ReadFile(filepath.Join(q.dir, id+".json"))
+		if err != nil {
+			logger.Warn("diskqueue: failed to read metadata for %s during replay, skipping: %v", id, err)
+			continue
+		}
+		var m meta
+		if err := json.Unmarshal(metaBytes, &m); err != nil {
+			logger.Warn("diskqueue: failed to decode metadata for %s during replay, skipping: %v", id, err)
+			continue
+		}
+		entries = append(entries, Entry{ID: id, Body: body, TargetURL: m.TargetURL, Headers: m.Headers})
+	}
+	return entries, nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("diskqueue: failed to generate id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
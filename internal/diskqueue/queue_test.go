@@ -0,0 +1,81 @@
+package diskqueue
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestQueue_EnqueueThenReplay_ReturnsEntry(t *testing.T) {
+	dir := t.TempDir()
+	q, err := New(dir, nil)
+	if err != nil {
+		t.Fatalf("failed to create queue: %v", err)
+	}
+
+	headers := http.Header{"Content-Type": []string{"application/x-protobuf"}}
+	id, err := q.Enqueue([]byte("payload"), "http://collector/v1/logs", headers)
+	if err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	if id == "" {
+		t.Fatal("expected non-empty id")
+	}
+
+	entries, err := q.Replay()
+	if err != nil {
+		t.Fatalf("failed to replay: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].ID != id || string(entries[0].Body) != "payload" || entries[0].TargetURL != "http://collector/v1/logs" {
+		t.Errorf("unexpected replayed entry: %+v", entries[0])
+	}
+}
+
+func TestQueue_Complete_RemovesEntryFromReplay(t *testing.T) {
+	dir := t.TempDir()
+	q, err := New(dir, nil)
+	if err != nil {
+		t.Fatalf("failed to create queue: %v", err)
+	}
+
+	id, err := q.Enqueue([]byte("payload"), "http://collector/v1/logs", nil)
+	if err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	if err := q.Complete(id); err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+
+	entries, err := q.Replay()
+	if err != nil {
+		t.Fatalf("failed to replay: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries after Complete, got %d", len(entries))
+	}
+}
+
+func TestQueue_Replay_SurvivesAcrossInstances(t *testing.T) {
+	dir := t.TempDir()
+	q1, err := New(dir, nil)
+	if err != nil {
+		t.Fatalf("failed to create queue: %v", err)
+	}
+	if _, err := q1.Enqueue([]byte("payload"), "http://collector/v1/logs", nil); err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+
+	q2, err := New(dir, nil)
+	if err != nil {
+		t.Fatalf("failed to reopen queue: %v", err)
+	}
+	entries, err := q2.Replay()
+	if err != nil {
+		t.Fatalf("failed to replay: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected entry to survive across queue instances, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,237 @@
+package forwarder
+
+import (
+    "bytes"
+    "context"
+    "net/http"
+    "sync"
+    "time"
+
+    "go.uber.org/atomic"
+
+    "zep-logger/internal/httpclient"
+    "zep-logger/internal/metrics"
+    "zep-logger/pkg/logger"
+)
+
+// AdaptiveForwarder implements Forwarder using a self-tuning concurrency
+// limit instead of a fixed semaphore_max_concurrent: it grows the limit by
+// one (additive increase) after a fast, successful request, and halves it
+// (multiplicative decrease) after a slow or failed one, floored at
+// minConcurrent and capped at maxConcurrent. This trades the operational
+// burden of hand-picking a pool size for self-tuning during collector
+// slowdowns, at the cost of a brief overshoot while it converges.
+type AdaptiveForwarder struct {
+    mu               sync.Mutex
+    cond             *sync.Cond
+    inFlight         int64
+    limit            int64
+    minConcurrent    int64
+    maxConcurrent    int64
+    latencyThreshold time.Duration
+    httpClient       httpclient.UpstreamClient
+    wg               sync.WaitGroup
+    waiters          atomic.Int64
+    startOnce        sync.Once
+    stopOnce         sync.Once
+    stopped          atomic.Bool
+}
+
+// NewAdaptiveForwarder creates a new AIMD-based forwarder. minConcurrent is
+// also the starting limit; latencyThreshold is the response time at or above
+// which a request is treated as "slow" for the purposes of shrinking the limit.
+func NewAdaptiveForwarder(minConcurrent int, maxConcurrent int, latencyThreshold time.Duration) *AdaptiveForwarder {
+    if minConcurrent <= 0 {
+        minConcurrent = 10
+    }
+    if maxConcurrent < minConcurrent {
+        maxConcurrent = minConcurrent * 100
+    }
+    if latencyThreshold <= 0 {
+        latencyThreshold = 2 * time.Second
+    }
+
+    transport := &http.Transport{
+        Proxy:                 http.ProxyFromEnvironment,
+        ForceAttemptHTTP2:     true,
+        MaxIdleConns:          maxConcurrent * 2,
+        MaxIdleConnsPerHost:   maxConcurrent,
+        MaxConnsPerHost:       maxConcurrent * 2,
+        IdleConnTimeout:       90 * time.Second,
+        TLSHandshakeTimeout:   10 * time.Second,
+        ExpectContinueTimeout: 1 * time.Second,
+    }
+
+    a := &AdaptiveForwarder{
+        limit:            int64(minConcurrent),
+        minConcurrent:    int64(minConcurrent),
+        maxConcurrent:    int64(maxConcurrent),
+        latencyThreshold: latencyThreshold,
+        httpClient:       &http.Client{Transport: transport, Timeout: 10 * time.Second},
+    }
+    a.cond = sync.NewCond(&a.mu)
+    return a
+}
+
+// Flush blocking until every in-flight Submit
+// goroutine has finished delivery, or ctx is done.
+func (a *AdaptiveForwarder) Flush(ctx context.Context) error {
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        a.wg.Wait()
+    }()
+    select {
+    case <-done:
+        return nil
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
+
+// SetHTTPClient overrides the client used to send requests, so tests can
+// inject a deterministic fake instead of spinning up an httptest server and
+// sleeping for timing. Must be called before Start().
+func (a *AdaptiveForwarder) SetHTTPClient(client httpclient.UpstreamClient) {
+    a.httpClient = client
+}
+
+func (a *AdaptiveForwarder) Start() {
+    a.startOnce.Do(func() {
+        metrics.AdaptiveConcurrencyLimitGauge.Set(float64(a.limit))
+        logger.Info("Adaptive forwarder started (min=%d, max=%d, latencyThreshold=%v)", a.minConcurrent, a.maxConcurrent, a.latencyThreshold)
+    })
+}
+
+func (a *AdaptiveForwarder) Stop(ctx context.Context) error {
+    var err error
+    a.stopOnce.Do(func() {
+        a.stopped.Store(true)
+        logger.Info("Stopping adaptive forwarder: waiting for in-flight goroutines")
+
+        done := make(chan struct{})
+        go func() {
+            defer close(done)
+            a.wg.Wait()
+        }()
+
+        select {
+        case <-done:
+            logger.Info("Adaptive forwarder stopped: all goroutines finished")
+        case <-ctx.Done():
+            logger.Warn("Adaptive forwarder stop timed out: %v", ctx.Err())
+            err = ctx.Err()
+        }
+
+        // Unblock any acquire loops still waiting so they can observe stopped
+        a.cond.Broadcast()
+    })
+    return err
+}
+
+func (a *AdaptiveForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+    if a.stopped.Load() {
+        return nil // during shutdown, readiness will block new traffic
+    }
+
+    a.wg.Add(1)
+    go func() {
+        defer a.wg.Done()
+
+        a.acquire()
+
+        metrics.ActiveWorkersGauge.Inc()
+        start := time.Now()
+
+        req, err := http.NewRequestWithContext(ctx, "POST", targetURL, bytes.NewReader(body))
+        if err != nil {
+            metrics.ActiveWorkersGauge.Dec()
+            logger.Error("Adaptive forwarder: failed to create request: %v", err)
+            metrics.JobsFailedCounter.Inc()
+            a.release(false, 0)
+            return
+        }
+        for k, values := range headers {
+            for _, v := range values {
+                req.Header.Add(k, v)
+            }
+        }
+
+        resp, err := a.httpClient.Do(req)
+        latency := time.Since(start)
+        metrics.ObserveForwardLatency(targetURL, start)
+        metrics.ActiveWorkersGauge.Dec()
+
+        if err != nil {
+            logger.Error("Adaptive forwarder: forwarding to %s failed: %v", targetURL, err)
+            metrics.JobsFailedCounter.Inc()
+            metrics.ForwardErrorsCounter.WithLabelValues(metrics.ClassifyForwardError(err, 0)).Inc()
+            a.release(false, latency)
+            return
+        }
+        resp.Body.Close()
+
+        if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+            logger.Warn("Adaptive forwarder: collector returned %d for %s", resp.StatusCode, targetURL)
+            metrics.JobsFailedCounter.Inc()
+            metrics.ForwardErrorsCounter.WithLabelValues(metrics.ClassifyForwardError(nil, resp.StatusCode)).Inc()
+            a.release(false, latency)
+            return
+        }
+
+        metrics.JobsProcessedCounter.Inc()
+        a.release(true, latency)
+    }()
+
+    return nil
+}
+
+// acquire blocks until fewer than the current limit of requests are in
+// flight, then reserves a slot. Re-checks the limit on every wakeup since it
+// can shrink while a goroutine is waiting.
+func (a *AdaptiveForwarder) acquire() {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+
+    a.waiters.Inc()
+    for a.inFlight >= a.limit && !a.stopped.Load() {
+        a.cond.Wait()
+    }
+    a.waiters.Dec()
+    a.inFlight++
+}
+
+// release frees the calling goroutine's in-flight slot and adjusts the
+// concurrency limit based on the just-completed request's outcome: additive
+// increase on a fast success, multiplicative decrease on a slow or failed one.
+func (a *AdaptiveForwarder) release(success bool, latency time.Duration) {
+    a.mu.Lock()
+    a.inFlight--
+
+    if success && latency < a.latencyThreshold {
+        if a.limit < a.maxConcurrent {
+            a.limit++
+        }
+    } else {
+        newLimit := a.limit / 2
+        if newLimit < a.minConcurrent {
+            newLimit = a.minConcurrent
+        }
+        a.limit = newLimit
+    }
+    metrics.AdaptiveConcurrencyLimitGauge.Set(float64(a.limit))
+    a.mu.Unlock()
+
+    a.cond.Broadcast()
+}
+
+func (a *AdaptiveForwarder) GetQueueDepth() int {
+    v := a.waiters.Load()
+    if v < 0 {
+        return 0
+    }
+    if v > int64(^uint(0)>>1) {
+        return int(^uint(0) >> 1)
+    }
+    return int(v)
+}
@@ -0,0 +1,112 @@
+package forwarder
+
+import (
+	"context"
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestAdaptiveForwarder_GrowsLimitOnFastSuccesses(t *testing.T) {
+    target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer target.Close()
+
+    fwd := NewAdaptiveForwarder(2, 10, time.Second)
+    fwd.Start()
+    defer fwd.Stop(context.Background())
+
+    for i := 0; i < 6; i++ {
+        if err := fwd.Submit(context.Background(), []byte("body"), target.URL, http.Header{}); err != nil {
+            t.Fatalf("submit failed: %v", err)
+        }
+        time.Sleep(20 * time.Millisecond)
+    }
+
+    fwd.mu.Lock()
+    limit := fwd.limit
+    fwd.mu.Unlock()
+    if limit <= 2 {
+        t.Errorf("expected limit to grow above the starting min of 2 after fast successes, got %d", limit)
+    }
+}
+
+func TestAdaptiveForwarder_ShrinksLimitOnSlowResponses(t *testing.T) {
+    var slow int32
+    target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if atomic.LoadInt32(&slow) != 0 {
+            time.Sleep(50 * time.Millisecond)
+        }
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer target.Close()
+
+    fwd := NewAdaptiveForwarder(2, 20, 20*time.Millisecond)
+    fwd.Start()
+
+    // Warm up with fast requests to grow the limit above its starting min
+    for i := 0; i < 8; i++ {
+        if err := fwd.Submit(context.Background(), []byte("body"), target.URL, http.Header{}); err != nil {
+            t.Fatalf("submit failed: %v", err)
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+    fwd.mu.Lock()
+    grownLimit := fwd.limit
+    fwd.mu.Unlock()
+    if grownLimit <= 2 {
+        t.Fatalf("expected limit to grow above the starting min of 2 during warm-up, got %d", grownLimit)
+    }
+
+    // Now every response exceeds latencyThreshold, so the limit should shrink
+    atomic.StoreInt32(&slow, 1)
+    if err := fwd.Submit(context.Background(), []byte("body"), target.URL, http.Header{}); err != nil {
+        t.Fatalf("submit failed: %v", err)
+    }
+    time.Sleep(150 * time.Millisecond)
+    fwd.Stop(context.Background())
+
+    fwd.mu.Lock()
+    shrunkLimit := fwd.limit
+    fwd.mu.Unlock()
+    if shrunkLimit >= grownLimit {
+        t.Errorf("expected limit to shrink below the grown limit of %d after a slow response, got %d", grownLimit, shrunkLimit)
+    }
+}
+
+func TestAdaptiveForwarder_NeverShrinksBelowMinConcurrent(t *testing.T) {
+    target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusInternalServerError)
+    }))
+    defer target.Close()
+
+    fwd := NewAdaptiveForwarder(3, 20, time.Second)
+    fwd.Start()
+
+    var pending int32
+    for i := 0; i < 5; i++ {
+        atomic.AddInt32(&pending, 1)
+        if err := fwd.Submit(context.Background(), []byte("body"), target.URL, http.Header{}); err != nil {
+            t.Fatalf("submit failed: %v", err)
+        }
+        time.Sleep(20 * time.Millisecond)
+    }
+    fwd.Stop(context.Background())
+
+    fwd.mu.Lock()
+    limit := fwd.limit
+    fwd.mu.Unlock()
+    if limit < 3 {
+        t.Errorf("expected limit to stay at or above adaptive_min_concurrent (3), got %d", limit)
+    }
+}
+
+func TestAdaptiveForwarder_GetQueueDepth_NonNegative(t *testing.T) {
+    fwd := NewAdaptiveForwarder(2, 10, time.Second)
+    if depth := fwd.GetQueueDepth(); depth != 0 {
+        t.Errorf("expected queue depth 0 before any submits, got %d", depth)
+    }
+}
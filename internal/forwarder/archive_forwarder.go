@@ -0,0 +1,86 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"zep-logger/internal/archivesink"
+	"zep-logger/internal/metrics"
+	"zep-logger/pkg/logger"
+)
+
+// ArchiveForwarder wraps another Forwarder and, for every Submit, also
+// records the payload to an archive sink (rotating local ndjson files,
+// optionally uploaded to S3) on a best-effort, non-blocking basis for
+// compliance retention and replay. Archive writes never affect the primary
+// path: Submit always delegates to next first, and the archive write
+// happens synchronously afterward - sink.Write is a fast local append, not
+// a network call, so unlike MirrorForwarder's tee this needs no goroutine
+// or its own semaphore.
+type ArchiveForwarder struct {
+	next Forwarder
+	sink *archivesink.Sink
+}
+
+// NewArchiveForwarder wraps next, archiving every Submit to sink in
+// addition to whatever next already does.
+func NewArchiveForwarder(next Forwarder, sink *archivesink.Sink) *ArchiveForwarder {
+	return &ArchiveForwarder{next: next, sink: sink}
+}
+
+func (a *ArchiveForwarder) Start() {
+	a.next.Start()
+	logger.Info("Archive forwarder started")
+}
+
+func (a *ArchiveForwarder) Stop(ctx context.Context) error {
+	err := a.next.Stop(ctx)
+	logger.Info("Stopping archive forwarder: flushing pending archive writes")
+	a.sink.Stop()
+	return err
+}
+
+func (a *ArchiveForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	err := a.next.Submit(ctx, body, targetURL, headers)
+
+	entry := archivesink.Entry{
+		Timestamp:   time.Now(),
+		TargetURL:   targetURL,
+		ContentType: headers.Get("Content-Type"),
+		Headers:     flattenHeaders(headers),
+		BodyBase64:  archivesink.EncodeBody(body),
+	}
+	if writeErr := a.sink.Write(entry); writeErr != nil {
+		logger.Warn("Archive forwarder: failed to write entry for %s: %v", targetURL, writeErr)
+		metrics.ArchiveWritesCounter.WithLabelValues("error").Inc()
+	} else {
+		metrics.ArchiveWritesCounter.WithLabelValues("success").Inc()
+	}
+
+	return err
+}
+
+// flattenHeaders collapses a multi-valued http.Header into a single value
+// per key (the first value), matching how KafkaForwarder shapes headers
+// for the same "one string per key" ndjson-friendly representation.
+func flattenHeaders(headers http.Header) map[string]string {
+	flat := make(map[string]string, len(headers))
+	for k, values := range headers {
+		if len(values) > 0 {
+			flat[k] = values[0]
+		}
+	}
+	return flat
+}
+
+func (a *ArchiveForwarder) GetQueueDepth() int {
+	return a.next.GetQueueDepth()
+}
+
+// Flush delegates straight through to next - the archive write itself is
+// synchronous within Submit, so there's nothing of ArchiveForwarder's own to
+// wait on.
+func (a *ArchiveForwarder) Flush(ctx context.Context) error {
+	return a.next.Flush(ctx)
+}
@@ -0,0 +1,88 @@
+package forwarder
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"zep-logger/internal/metrics"
+	"zep-logger/pkg/logger"
+)
+
+// BackpressureForwarder wraps another Forwarder and rejects Submit once
+// next.GetQueueDepth() reaches a configured hard ceiling, so client-visible
+// behavior under sustained overload is the same regardless of
+// forwarding_mode. Without this, switching modes changes what a client sees
+// under load: pool and hybrid mode already reject once their own queue
+// fills, and semaphore mode once maxConcurrent requests are in flight, but
+// adaptive, failover, load-balance, mirror, gRPC, and batching mode
+// dispatch a goroutine per Submit and never reject on their own, silently
+// piling up unbounded in-flight work instead.
+//
+// Between the soft and hard watermarks, Submit still accepts the request
+// (delegating to next as normal) but reports itself as saturated via
+// IsSaturated, so callers can hint clients to slow down (e.g. a
+// Retry-After header) before backpressure becomes an outright rejection.
+type BackpressureForwarder struct {
+	next               Forwarder
+	softMaxPendingJobs int
+	hardMaxPendingJobs int
+	retryAfterSeconds  int
+}
+
+// NewBackpressureForwarder wraps next, rejecting Submit once
+// next.GetQueueDepth() reaches hardMaxPendingJobs and reporting saturation
+// (see IsSaturated) once it reaches softMaxPendingJobs. Either watermark
+// <= 0 disables that tier; hardMaxPendingJobs <= 0 with softMaxPendingJobs
+// <= 0 disables the forwarder entirely (Submit always delegates straight to
+// next). retryAfterSeconds <= 0 defaults to 5.
+func NewBackpressureForwarder(next Forwarder, softMaxPendingJobs, hardMaxPendingJobs, retryAfterSeconds int) *BackpressureForwarder {
+	if retryAfterSeconds <= 0 {
+		retryAfterSeconds = 5
+	}
+	return &BackpressureForwarder{
+		next:               next,
+		softMaxPendingJobs: softMaxPendingJobs,
+		hardMaxPendingJobs: hardMaxPendingJobs,
+		retryAfterSeconds:  retryAfterSeconds,
+	}
+}
+
+func (b *BackpressureForwarder) Start()                              { b.next.Start() }
+func (b *BackpressureForwarder) Stop(ctx context.Context) error { return b.next.Stop(ctx) }
+
+// Flush delegates straight through to next - BackpressureForwarder holds no
+// in-flight work of its own to wait on.
+func (b *BackpressureForwarder) Flush(ctx context.Context) error {
+	return b.next.Flush(ctx)
+}
+
+func (b *BackpressureForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	depth := b.next.GetQueueDepth()
+
+	if b.hardMaxPendingJobs > 0 && depth >= b.hardMaxPendingJobs {
+		logger.Warn("Backpressure forwarder: rejecting request, pending jobs (%d) at hard limit (%d)", depth, b.hardMaxPendingJobs)
+		return fmt.Errorf("forwarder backpressure limit exceeded (max pending jobs: %d)", b.hardMaxPendingJobs)
+	}
+
+	if b.softMaxPendingJobs > 0 && depth >= b.softMaxPendingJobs {
+		metrics.ForwarderSaturationCounter.Inc()
+		logger.Warn("Backpressure forwarder: soft watermark exceeded (pending jobs %d >= %d), accepting with saturation hint", depth, b.softMaxPendingJobs)
+	}
+
+	return b.next.Submit(ctx, body, targetURL, headers)
+}
+
+func (b *BackpressureForwarder) GetQueueDepth() int {
+	return b.next.GetQueueDepth()
+}
+
+// IsSaturated reports whether next is currently at or above the soft
+// watermark, and the number of seconds a caller should suggest clients wait
+// before retrying. Always false, 0 when no soft watermark is configured.
+func (b *BackpressureForwarder) IsSaturated() (bool, int) {
+	if b.softMaxPendingJobs <= 0 {
+		return false, 0
+	}
+	return b.next.GetQueueDepth() >= b.softMaxPendingJobs, b.retryAfterSeconds
+}
@@ -0,0 +1,96 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+// depthForwarder is a minimal Forwarder whose GetQueueDepth is set directly,
+// for exercising BackpressureForwarder's reject decision independently of
+// any real forwarding mode's own queueing behavior.
+type depthForwarder struct {
+	depth   int32
+	submits int32
+}
+
+func (f *depthForwarder) Start() {}
+func (f *depthForwarder) Stop(ctx context.Context) error { return nil }
+func (f *depthForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	atomic.AddInt32(&f.submits, 1)
+	return nil
+}
+func (f *depthForwarder) GetQueueDepth() int { return int(atomic.LoadInt32(&f.depth)) }
+func (f *depthForwarder) Flush(ctx context.Context) error { return nil }
+
+func TestBackpressureForwarder_RejectsOnceNextQueueDepthReachesHardLimit(t *testing.T) {
+	next := &depthForwarder{depth: 5}
+	fwd := NewBackpressureForwarder(next, 0, 5, 0)
+
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://collector/v1/logs", http.Header{}); err == nil {
+		t.Fatal("expected Submit to be rejected once next's queue depth reaches the configured hard limit")
+	}
+	if got := atomic.LoadInt32(&next.submits); got != 0 {
+		t.Errorf("expected next.Submit not to be called on rejection, got %d calls", got)
+	}
+}
+
+func TestBackpressureForwarder_DelegatesWhenBelowLimit(t *testing.T) {
+	next := &depthForwarder{depth: 2}
+	fwd := NewBackpressureForwarder(next, 0, 5, 0)
+
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://collector/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&next.submits); got != 1 {
+		t.Errorf("expected next.Submit to be called once, got %d calls", got)
+	}
+}
+
+func TestBackpressureForwarder_DisabledWhenLimitIsZero(t *testing.T) {
+	next := &depthForwarder{depth: 1_000_000}
+	fwd := NewBackpressureForwarder(next, 0, 0, 0)
+
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://collector/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("expected disabled limit to always delegate, got error: %v", err)
+	}
+}
+
+func TestBackpressureForwarder_AcceptsAndFlagsSaturatedAboveSoftLimit(t *testing.T) {
+	next := &depthForwarder{depth: 3}
+	fwd := NewBackpressureForwarder(next, 3, 10, 7)
+
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://collector/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("expected soft watermark to still accept, got error: %v", err)
+	}
+	if got := atomic.LoadInt32(&next.submits); got != 1 {
+		t.Errorf("expected next.Submit to be called once, got %d calls", got)
+	}
+	saturated, retryAfter := fwd.IsSaturated()
+	if !saturated {
+		t.Error("expected IsSaturated to report true above the soft watermark")
+	}
+	if retryAfter != 7 {
+		t.Errorf("retryAfter = %d, want 7", retryAfter)
+	}
+}
+
+func TestBackpressureForwarder_NotSaturatedBelowSoftLimit(t *testing.T) {
+	next := &depthForwarder{depth: 1}
+	fwd := NewBackpressureForwarder(next, 3, 10, 7)
+
+	if saturated, _ := fwd.IsSaturated(); saturated {
+		t.Error("expected IsSaturated to report false below the soft watermark")
+	}
+}
+
+func TestBackpressureForwarder_RetryAfterDefaultsWhenUnset(t *testing.T) {
+	next := &depthForwarder{depth: 5}
+	fwd := NewBackpressureForwarder(next, 3, 10, 0)
+
+	_, retryAfter := fwd.IsSaturated()
+	if retryAfter != 5 {
+		t.Errorf("retryAfter = %d, want default 5", retryAfter)
+	}
+}
@@ -0,0 +1,228 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"zep-logger/internal/metrics"
+	"zep-logger/internal/transform"
+	"zep-logger/pkg/logger"
+)
+
+// BatchConfig controls when the batching forwarder flushes a batch upstream
+type BatchConfig struct {
+	MaxSize int           // Flush once a batch holds this many payloads
+	MaxAge  time.Duration // Flush this long after a batch's first payload, even if not full
+}
+
+type batchKey struct {
+	targetURL   string
+	contentType string
+	signal      string
+}
+
+type batchGroup struct {
+	ctx       context.Context // From the Submit call that started the group; used for the eventual flush, including ticker-driven ones no Submit call is around to observe
+	bodies    [][]byte
+	headers   http.Header
+	firstSeen time.Time
+}
+
+// BatchingForwarder wraps another Forwarder and merges Submit calls bound for
+// the same target/content-type into larger upstream requests, so thousands of
+// small logs/traces payloads (e.g. browser beacons) become far fewer POSTs.
+// Payloads for paths it doesn't know how to decode/merge are passed through
+// to the wrapped forwarder unmodified.
+type BatchingForwarder struct {
+	next Forwarder
+	cfg  BatchConfig
+
+	mu     sync.Mutex
+	groups map[batchKey]*batchGroup
+
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// NewBatchingForwarder wraps next with batching. MaxSize <= 0 defaults to 20;
+// MaxAge <= 0 defaults to 1 second.
+func NewBatchingForwarder(next Forwarder, cfg BatchConfig) *BatchingForwarder {
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = 20
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = time.Second
+	}
+	return &BatchingForwarder{
+		next:   next,
+		cfg:    cfg,
+		groups: make(map[batchKey]*batchGroup),
+		stopCh: make(chan struct{}),
+	}
+}
+
+func (b *BatchingForwarder) Start() {
+	b.startOnce.Do(func() {
+		b.next.Start()
+		b.wg.Add(1)
+		go b.run()
+	})
+}
+
+func (b *BatchingForwarder) Stop(ctx context.Context) error {
+	var err error
+	b.stopOnce.Do(func() {
+		close(b.stopCh)
+		b.wg.Wait()
+		b.flushAll()
+		err = b.next.Stop(ctx)
+	})
+	return err
+}
+
+func (b *BatchingForwarder) run() {
+	defer b.wg.Done()
+	ticker := time.NewTicker(b.cfg.MaxAge / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			b.flushDue(now)
+		case <-b.stopCh:
+			return
+		}
+	}
+}
+
+// signalForTarget reports whether targetURL is a signal this forwarder knows
+// how to decode and merge, and which one
+func signalForTarget(targetURL string) (signal string, ok bool) {
+	switch {
+	case strings.HasSuffix(targetURL, "/v1/logs"):
+		return "logs", true
+	case strings.HasSuffix(targetURL, "/v1/traces"):
+		return "traces", true
+	default:
+		return "", false
+	}
+}
+
+func (b *BatchingForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	signal, ok := signalForTarget(targetURL)
+	if !ok {
+		return b.next.Submit(ctx, body, targetURL, headers)
+	}
+
+	contentType := headers.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/x-protobuf"
+	}
+	key := batchKey{targetURL: targetURL, contentType: contentType, signal: signal}
+
+	b.mu.Lock()
+	g, exists := b.groups[key]
+	if !exists {
+		g = &batchGroup{ctx: ctx, headers: headers.Clone(), firstSeen: time.Now()}
+		b.groups[key] = g
+	}
+	g.bodies = append(g.bodies, body)
+	full := len(g.bodies) >= b.cfg.MaxSize
+	if full {
+		delete(b.groups, key)
+	}
+	b.mu.Unlock()
+
+	if full {
+		return b.flushGroup(key, g)
+	}
+	return nil
+}
+
+func (b *BatchingForwarder) flushDue(now time.Time) {
+	var due []struct {
+		key batchKey
+		g   *batchGroup
+	}
+
+	b.mu.Lock()
+	for key, g := range b.groups {
+		if now.Sub(g.firstSeen) >= b.cfg.MaxAge {
+			due = append(due, struct {
+				key batchKey
+				g   *batchGroup
+			}{key, g})
+			delete(b.groups, key)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, d := range due {
+		if err := b.flushGroup(d.key, d.g); err != nil {
+			logger.Warn("Failed to flush batch for %s: %v", d.key.targetURL, err)
+		}
+	}
+}
+
+func (b *BatchingForwarder) flushAll() {
+	b.mu.Lock()
+	groups := b.groups
+	b.groups = make(map[batchKey]*batchGroup)
+	b.mu.Unlock()
+
+	for key, g := range groups {
+		if err := b.flushGroup(key, g); err != nil {
+			logger.Warn("Failed to flush batch for %s: %v", key.targetURL, err)
+		}
+	}
+}
+
+func (b *BatchingForwarder) flushGroup(key batchKey, g *batchGroup) error {
+	if len(g.bodies) == 1 {
+		return b.next.Submit(g.ctx, g.bodies[0], key.targetURL, g.headers)
+	}
+
+	var merged []byte
+	var err error
+	switch key.signal {
+	case "logs":
+		merged, err = transform.MergeLogsPayloads(g.bodies, key.contentType)
+	case "traces":
+		merged, err = transform.MergeTracesPayloads(g.bodies, key.contentType)
+	}
+	if err != nil {
+		logger.Warn("Failed to merge %d batched %s payloads, forwarding individually: %v", len(g.bodies), key.signal, err)
+		var firstErr error
+		for _, body := range g.bodies {
+			if submitErr := b.next.Submit(g.ctx, body, key.targetURL, g.headers); submitErr != nil && firstErr == nil {
+				firstErr = submitErr
+			}
+		}
+		return firstErr
+	}
+
+	metrics.BatchedPayloadsMergedCounter.Add(float64(len(g.bodies)))
+	metrics.BatchFlushesCounter.Inc()
+	return b.next.Submit(g.ctx, merged, key.targetURL, g.headers)
+}
+
+// Flush immediately flushes every buffered batch upstream (rather than
+// waiting for MaxSize/MaxAge) and then waits for next to report idle too.
+func (b *BatchingForwarder) Flush(ctx context.Context) error {
+	b.flushAll()
+	return b.next.Flush(ctx)
+}
+
+func (b *BatchingForwarder) GetQueueDepth() int {
+	b.mu.Lock()
+	buffered := 0
+	for _, g := range b.groups {
+		buffered += len(g.bodies)
+	}
+	b.mu.Unlock()
+	return buffered + b.next.GetQueueDepth()
+}
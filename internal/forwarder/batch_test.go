@@ -0,0 +1,105 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logsv1 "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+type fakeForwarder struct {
+	mu      sync.Mutex
+	submits [][]byte
+}
+
+func (f *fakeForwarder) Start() {}
+func (f *fakeForwarder) Stop(ctx context.Context) error { return nil }
+func (f *fakeForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.submits = append(f.submits, body)
+	return nil
+}
+func (f *fakeForwarder) GetQueueDepth() int { return 0 }
+func (f *fakeForwarder) Flush(ctx context.Context) error { return nil }
+func (f *fakeForwarder) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.submits)
+}
+
+func logsPayload(serviceName string) []byte {
+	req := &logspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logsv1.ResourceLogs{
+			{Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+				{Key: "service.name", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: serviceName}}},
+			}}},
+		},
+	}
+	body, _ := proto.Marshal(req)
+	return body
+}
+
+func TestBatchingForwarder_FlushesOnMaxSize(t *testing.T) {
+	fake := &fakeForwarder{}
+	bf := NewBatchingForwarder(fake, BatchConfig{MaxSize: 2, MaxAge: time.Hour})
+
+	headers := http.Header{"Content-Type": []string{"application/x-protobuf"}}
+	if err := bf.Submit(context.Background(), logsPayload("a"), "http://collector/v1/logs", headers); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	if fake.count() != 0 {
+		t.Fatalf("expected no flush before batch is full, got %d", fake.count())
+	}
+	if err := bf.Submit(context.Background(), logsPayload("b"), "http://collector/v1/logs", headers); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	if fake.count() != 1 {
+		t.Fatalf("expected exactly one merged upstream request, got %d", fake.count())
+	}
+
+	var out logspb.ExportLogsServiceRequest
+	if err := proto.Unmarshal(fake.submits[0], &out); err != nil {
+		t.Fatalf("failed to unmarshal merged batch: %v", err)
+	}
+	if len(out.ResourceLogs) != 2 {
+		t.Errorf("expected merged batch to contain 2 ResourceLogs entries, got %d", len(out.ResourceLogs))
+	}
+}
+
+func TestBatchingForwarder_FlushesOnMaxAge(t *testing.T) {
+	fake := &fakeForwarder{}
+	bf := NewBatchingForwarder(fake, BatchConfig{MaxSize: 100, MaxAge: 40 * time.Millisecond})
+	bf.Start()
+	defer bf.Stop(context.Background())
+
+	headers := http.Header{"Content-Type": []string{"application/x-protobuf"}}
+	if err := bf.Submit(context.Background(), logsPayload("a"), "http://collector/v1/logs", headers); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if fake.count() != 1 {
+		t.Errorf("expected batch to flush after max age elapsed, got %d submits", fake.count())
+	}
+}
+
+func TestBatchingForwarder_PassesThroughUnknownPaths(t *testing.T) {
+	fake := &fakeForwarder{}
+	bf := NewBatchingForwarder(fake, BatchConfig{MaxSize: 100, MaxAge: time.Hour})
+
+	if err := bf.Submit(context.Background(), []byte("ping"), "http://collector/healthz", nil); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	if fake.count() != 1 {
+		t.Errorf("expected unrecognized path to pass through immediately, got %d submits", fake.count())
+	}
+}
@@ -0,0 +1,153 @@
+package forwarder
+
+import (
+    "context"
+    "net/http"
+    "strings"
+    "sync"
+    "time"
+
+    "zep-logger/internal/metrics"
+    "zep-logger/pkg/logger"
+)
+
+// CircuitBreakerForwarder wraps a primary Forwarder and, once its
+// HealthReporter score drops to 0 for openThreshold consecutive checks,
+// diverts Submit to a fallback Forwarder instead of continuing to hand jobs
+// to a primary that's failing them. After cooldown it probes the primary's
+// health once more (half-open); a healthy score closes the circuit and
+// resumes sending to primary, an unhealthy one reopens it for another
+// cooldown.
+//
+// This depends on the primary implementing HealthReporter to know when to
+// trip - most forwarding modes don't track a rolling health score (only
+// forwarding_mode = "pool" with health_shedding_enabled currently does), so
+// wrapping a primary without one leaves the circuit permanently closed and
+// Submit always delegates straight to primary.
+type CircuitBreakerForwarder struct {
+    primary           Forwarder
+    primaryTargetURL  string
+    fallback          Forwarder
+    fallbackTargetURL string
+    openThreshold     int
+    cooldown          time.Duration
+
+    mu             sync.Mutex
+    open           bool
+    consecutiveBad int
+    openedAt       time.Time
+}
+
+// NewCircuitBreakerForwarder wraps primary, diverting Submit to fallback
+// once primary's HealthReporter score reports unhealthy (<= 0) for
+// openThreshold consecutive checks. primaryTargetURL must be the same base
+// URL callers pass to Submit, so a diverted request's path suffix (e.g.
+// /v1/logs) can be re-applied to fallbackTargetURL. cooldown is how long the
+// circuit stays open before probing primary again. openThreshold <= 0
+// defaults to 3, cooldown <= 0 defaults to 30s.
+func NewCircuitBreakerForwarder(primary Forwarder, primaryTargetURL string, fallback Forwarder, fallbackTargetURL string, openThreshold int, cooldown time.Duration) *CircuitBreakerForwarder {
+    if openThreshold <= 0 {
+        openThreshold = 3
+    }
+    if cooldown <= 0 {
+        cooldown = 30 * time.Second
+    }
+    return &CircuitBreakerForwarder{
+        primary:           primary,
+        primaryTargetURL:  primaryTargetURL,
+        fallback:          fallback,
+        fallbackTargetURL: fallbackTargetURL,
+        openThreshold:     openThreshold,
+        cooldown:          cooldown,
+    }
+}
+
+func (c *CircuitBreakerForwarder) Start() {
+    c.primary.Start()
+    c.fallback.Start()
+    if _, ok := c.primary.(HealthReporter); !ok {
+        logger.Warn("Circuit breaker forwarder: primary forwarder does not implement HealthReporter, circuit will never open")
+    }
+}
+
+func (c *CircuitBreakerForwarder) Stop(ctx context.Context) error {
+    err := c.primary.Stop(ctx)
+    if fallbackErr := c.fallback.Stop(ctx); fallbackErr != nil && err == nil {
+        err = fallbackErr
+    }
+    return err
+}
+
+// Flush waits for both primary and fallback to report idle, since a job may
+// have been submitted to either depending on the circuit's state at the time.
+func (c *CircuitBreakerForwarder) Flush(ctx context.Context) error {
+    if err := c.primary.Flush(ctx); err != nil {
+        return err
+    }
+    return c.fallback.Flush(ctx)
+}
+
+func (c *CircuitBreakerForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+    if c.shouldUseFallback() {
+        metrics.CircuitBreakerDivertedCounter.Inc()
+        suffix := strings.TrimPrefix(targetURL, c.primaryTargetURL)
+        return c.fallback.Submit(ctx, body, c.fallbackTargetURL+suffix, headers)
+    }
+    return c.primary.Submit(ctx, body, targetURL, headers)
+}
+
+// shouldUseFallback reports whether Submit should currently route to
+// fallback, re-evaluating primary's health score on each call and
+// transitioning between closed, open, and half-open probe states as needed.
+func (c *CircuitBreakerForwarder) shouldUseFallback() bool {
+    reporter, ok := c.primary.(HealthReporter)
+    if !ok {
+        return false
+    }
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    if c.open {
+        if time.Since(c.openedAt) < c.cooldown {
+            return true
+        }
+        if reporter.HealthScore() > 0 {
+            logger.Info("Circuit breaker forwarder: primary healthy again, closing circuit")
+            c.open = false
+            c.consecutiveBad = 0
+            return false
+        }
+        logger.Warn("Circuit breaker forwarder: primary still unhealthy after cooldown, extending divert to fallback")
+        c.openedAt = time.Now()
+        return true
+    }
+
+    if reporter.HealthScore() > 0 {
+        c.consecutiveBad = 0
+        return false
+    }
+
+    c.consecutiveBad++
+    if c.consecutiveBad < c.openThreshold {
+        return false
+    }
+
+    logger.Warn("Circuit breaker forwarder: primary unhealthy for %d consecutive checks, opening circuit and diverting to fallback", c.consecutiveBad)
+    c.open = true
+    c.openedAt = time.Now()
+    return true
+}
+
+func (c *CircuitBreakerForwarder) GetQueueDepth() int {
+    return c.primary.GetQueueDepth() + c.fallback.GetQueueDepth()
+}
+
+// IsOpen implements BreakerReporter, reporting whether Submit is currently
+// diverted to fallback. This reflects state as of the last shouldUseFallback
+// evaluation (the most recent Submit call), not a fresh probe.
+func (c *CircuitBreakerForwarder) IsOpen() bool {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    return c.open
+}
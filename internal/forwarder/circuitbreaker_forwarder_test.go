@@ -0,0 +1,128 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// healthScoredForwarder is a minimal Forwarder + HealthReporter whose score
+// is set directly, for exercising CircuitBreakerForwarder's open/close
+// decisions independently of any real forwarding mode's own health tracking.
+type healthScoredForwarder struct {
+	score   float64
+	submits int32
+	lastURL string
+}
+
+func (f *healthScoredForwarder) Start() {}
+func (f *healthScoredForwarder) Stop(ctx context.Context) error { return nil }
+func (f *healthScoredForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	atomic.AddInt32(&f.submits, 1)
+	f.lastURL = targetURL
+	return nil
+}
+func (f *healthScoredForwarder) GetQueueDepth() int          { return 0 }
+func (f *healthScoredForwarder) Flush(ctx context.Context) error { return nil }
+func (f *healthScoredForwarder) HealthScore() float64        { return f.score }
+
+func TestCircuitBreakerForwarder_DelegatesToPrimaryWhileHealthy(t *testing.T) {
+	primary := &healthScoredForwarder{score: 1}
+	fallback := &healthScoredForwarder{score: 1}
+	fwd := NewCircuitBreakerForwarder(primary, "http://primary", fallback, "http://fallback", 3, time.Minute)
+
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://primary/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if atomic.LoadInt32(&primary.submits) != 1 {
+		t.Errorf("expected primary to receive the request while healthy, got %d submits", primary.submits)
+	}
+	if atomic.LoadInt32(&fallback.submits) != 0 {
+		t.Errorf("expected fallback to receive nothing while primary is healthy, got %d submits", fallback.submits)
+	}
+}
+
+func TestCircuitBreakerForwarder_OpensAndDivertsAfterConsecutiveUnhealthyChecks(t *testing.T) {
+	primary := &healthScoredForwarder{score: 0}
+	fallback := &healthScoredForwarder{score: 1}
+	fwd := NewCircuitBreakerForwarder(primary, "http://primary", fallback, "http://fallback", 3, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if err := fwd.Submit(context.Background(), []byte("body"), "http://primary/v1/logs", http.Header{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if atomic.LoadInt32(&primary.submits) != 2 {
+		t.Errorf("expected primary to still receive submits before the circuit opens, got %d", primary.submits)
+	}
+
+	// Third consecutive unhealthy check crosses the threshold and opens the circuit
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://primary/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if atomic.LoadInt32(&fallback.submits) != 1 {
+		t.Fatalf("expected the circuit to open and divert to fallback, got %d fallback submits", fallback.submits)
+	}
+	if fallback.lastURL != "http://fallback/v1/logs" {
+		t.Errorf("expected the request path suffix to be re-applied to the fallback URL, got %q", fallback.lastURL)
+	}
+}
+
+func TestCircuitBreakerForwarder_ClosesAfterCooldownOnceHealthy(t *testing.T) {
+	primary := &healthScoredForwarder{score: 0}
+	fallback := &healthScoredForwarder{score: 1}
+	fwd := NewCircuitBreakerForwarder(primary, "http://primary", fallback, "http://fallback", 1, 10*time.Millisecond)
+
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://primary/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if atomic.LoadInt32(&fallback.submits) != 1 {
+		t.Fatalf("expected the circuit to open immediately (threshold=1), got %d fallback submits", fallback.submits)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	primary.score = 1 // primary recovers before the next probe
+
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://primary/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if atomic.LoadInt32(&primary.submits) != 1 {
+		t.Errorf("expected the circuit to close and route back to primary after cooldown, got %d primary submits", primary.submits)
+	}
+}
+
+func TestCircuitBreakerForwarder_NeverOpensWithoutHealthReporter(t *testing.T) {
+	primary := &fakeForwarder{}
+	fallback := &healthScoredForwarder{score: 1}
+	fwd := NewCircuitBreakerForwarder(primary, "http://primary", fallback, "http://fallback", 1, time.Minute)
+
+	for i := 0; i < 5; i++ {
+		if err := fwd.Submit(context.Background(), []byte("body"), "http://primary/v1/logs", http.Header{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if atomic.LoadInt32(&fallback.submits) != 0 {
+		t.Errorf("expected the circuit to never open without a HealthReporter primary, got %d fallback submits", fallback.submits)
+	}
+}
+
+// TestCircuitBreakerForwarder_IsOpen_ReflectsCurrentState verifies IsOpen
+// (used by the admin stats stream/status page) tracks the same open/closed
+// state Submit itself diverts on
+func TestCircuitBreakerForwarder_IsOpen_ReflectsCurrentState(t *testing.T) {
+	primary := &healthScoredForwarder{score: 0}
+	fallback := &healthScoredForwarder{score: 1}
+	fwd := NewCircuitBreakerForwarder(primary, "http://primary", fallback, "http://fallback", 1, time.Minute)
+
+	if fwd.IsOpen() {
+		t.Error("expected the circuit to start closed")
+	}
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://primary/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fwd.IsOpen() {
+		t.Error("expected the circuit to report open after the threshold trips")
+	}
+}
@@ -0,0 +1,213 @@
+package forwarder
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"zep-logger/internal/httpclient"
+	"zep-logger/internal/metrics"
+	"zep-logger/pkg/logger"
+)
+
+// FailoverForwarder implements Forwarder over an ordered list of upstream
+// target base URLs (priority order, index 0 is the primary). Submit tries
+// each target in order, falling through to the next on a request error,
+// timeout, or 5xx response, and tracks per-target health for observability.
+type FailoverForwarder struct {
+	targets         []string
+	httpClient      httpclient.UpstreamClient
+	tokens          chan struct{}
+	wg              sync.WaitGroup
+	waiters         atomic.Int64
+	startOnce       sync.Once
+	stopOnce        sync.Once
+	stopped         atomic.Bool
+}
+
+// NewFailoverForwarder creates a forwarder that fails over across targets in
+// priority order. targets[0] must be the same base URL callers pass to
+// Submit, so the remaining targets can be derived from it.
+func NewFailoverForwarder(targets []string, maxConcurrent int) *FailoverForwarder {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 10000
+	}
+
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxConcurrent * 2,
+		MaxIdleConnsPerHost:   maxConcurrent,
+		MaxConnsPerHost:       maxConcurrent * 2,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	return &FailoverForwarder{
+		targets:    targets,
+		httpClient: &http.Client{Transport: transport, Timeout: 10 * time.Second},
+		tokens:     make(chan struct{}, maxConcurrent),
+	}
+}
+
+// Flush blocking until every in-flight Submit
+// goroutine has finished delivery, or ctx is done.
+func (f *FailoverForwarder) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.wg.Wait()
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetHTTPClient overrides the client used to send requests to every target,
+// so tests can inject a deterministic fake instead of spinning up an
+// httptest server and sleeping for timing. Must be called before Start().
+func (f *FailoverForwarder) SetHTTPClient(client httpclient.UpstreamClient) {
+	f.httpClient = client
+}
+
+func (f *FailoverForwarder) Start() {
+	f.startOnce.Do(func() {
+		logger.Info("Failover forwarder started with %d target(s): %v", len(f.targets), f.targets)
+	})
+}
+
+func (f *FailoverForwarder) Stop(ctx context.Context) error {
+	var err error
+	f.stopOnce.Do(func() {
+		f.stopped.Store(true)
+		logger.Info("Stopping failover forwarder: waiting for in-flight goroutines")
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			f.wg.Wait()
+		}()
+
+		select {
+		case <-done:
+			logger.Info("Failover forwarder stopped: all goroutines finished")
+		case <-ctx.Done():
+			logger.Warn("Failover forwarder stop timed out: %v", ctx.Err())
+			err = ctx.Err()
+		}
+	})
+	return err
+}
+
+func (f *FailoverForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	if f.stopped.Load() {
+		return nil // during shutdown, readiness will block new traffic
+	}
+
+	suffix := strings.TrimPrefix(targetURL, f.targets[0])
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		f.waiters.Inc()
+		f.tokens <- struct{}{} // acquire; blocks when at max concurrency
+		f.waiters.Dec()
+		defer func() { <-f.tokens }() // release
+
+		metrics.ActiveWorkersGauge.Inc()
+		defer metrics.ActiveWorkersGauge.Dec()
+
+		var cause string
+		for i, target := range f.targets {
+			candidateURL := targetURL
+			if i > 0 {
+				candidateURL = target + suffix
+			}
+
+			ok, retry, attemptCause := f.attempt(ctx, candidateURL, target, body, headers)
+			cause = attemptCause
+			if ok {
+				metrics.JobsProcessedCounter.Inc()
+				return
+			}
+			if !retry {
+				metrics.JobsFailedCounter.Inc()
+				metrics.ForwardErrorsCounter.WithLabelValues(cause).Inc()
+				return
+			}
+			logger.Warn("Failover forwarder: target %s failed, trying next target", target)
+		}
+
+		logger.Error("Failover forwarder: all %d target(s) failed for request", len(f.targets))
+		metrics.JobsFailedCounter.Inc()
+		metrics.ForwardErrorsCounter.WithLabelValues(cause).Inc()
+	}()
+
+	return nil
+}
+
+// attempt POSTs body to candidateURL and reports whether it succeeded,
+// whether failure is worth retrying against the next target, and (if it
+// failed) the ForwardErrorsCounter cause. A 5xx or transport error is
+// retryable; a 4xx means the target is healthy but rejected the request
+// itself, so trying another target won't help.
+func (f *FailoverForwarder) attempt(ctx context.Context, candidateURL string, target string, body []byte, headers http.Header) (ok bool, retry bool, cause string) {
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, "POST", candidateURL, bytes.NewReader(body))
+	if err != nil {
+		logger.Error("Failover forwarder: failed to create request for %s: %v", candidateURL, err)
+		metrics.FailoverTargetRequestsCounter.WithLabelValues(target, "error").Inc()
+		metrics.FailoverTargetHealthGauge.WithLabelValues(target).Set(0)
+		return false, false, metrics.ClassifyForwardError(err, 0)
+	}
+	for k, values := range headers {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+
+	resp, err := f.httpClient.Do(req)
+	metrics.ObserveForwardLatency(candidateURL, start)
+	if err != nil {
+		logger.Warn("Failover forwarder: forwarding to %s failed: %v", candidateURL, err)
+		metrics.FailoverTargetRequestsCounter.WithLabelValues(target, "error").Inc()
+		metrics.FailoverTargetHealthGauge.WithLabelValues(target).Set(0)
+		return false, true, metrics.ClassifyForwardError(err, 0)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		logger.Warn("Failover forwarder: target %s returned %d", candidateURL, resp.StatusCode)
+		metrics.FailoverTargetRequestsCounter.WithLabelValues(target, "error").Inc()
+		metrics.FailoverTargetHealthGauge.WithLabelValues(target).Set(0)
+		return false, true, metrics.ClassifyForwardError(nil, resp.StatusCode)
+	}
+
+	metrics.FailoverTargetRequestsCounter.WithLabelValues(target, "success").Inc()
+	metrics.FailoverTargetHealthGauge.WithLabelValues(target).Set(1)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logger.Warn("Failover forwarder: target %s returned %d for request", candidateURL, resp.StatusCode)
+		return false, false, metrics.ClassifyForwardError(nil, resp.StatusCode)
+	}
+
+	return true, false, ""
+}
+
+func (f *FailoverForwarder) GetQueueDepth() int {
+	v := f.waiters.Load()
+	if v < 0 {
+		return 0
+	}
+	return int(v)
+}
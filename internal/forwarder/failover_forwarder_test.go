@@ -0,0 +1,85 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFailoverForwarder_PrimaryHealthy_NeverTriesSecondary(t *testing.T) {
+	var secondaryHits int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&secondaryHits, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer secondary.Close()
+
+	fwd := NewFailoverForwarder([]string{primary.URL, secondary.URL}, 10)
+	fwd.Start()
+	defer fwd.Stop(context.Background())
+
+	if err := fwd.Submit(context.Background(), []byte("body"), primary.URL+"/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	fwd.Stop(context.Background())
+
+	if got := atomic.LoadInt32(&secondaryHits); got != 0 {
+		t.Errorf("expected secondary to never be hit while primary is healthy, got %d hits", got)
+	}
+}
+
+func TestFailoverForwarder_PrimaryDown_FallsThroughToSecondary(t *testing.T) {
+	var secondaryHits int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&secondaryHits, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer secondary.Close()
+
+	fwd := NewFailoverForwarder([]string{primary.URL, secondary.URL}, 10)
+	fwd.Start()
+
+	if err := fwd.Submit(context.Background(), []byte("body"), primary.URL+"/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	fwd.Stop(context.Background())
+
+	if got := atomic.LoadInt32(&secondaryHits); got != 1 {
+		t.Errorf("expected secondary to be hit once after primary 5xx, got %d hits", got)
+	}
+}
+
+func TestFailoverForwarder_PrimaryClientError_DoesNotRetrySecondary(t *testing.T) {
+	var secondaryHits int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&secondaryHits, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer secondary.Close()
+
+	fwd := NewFailoverForwarder([]string{primary.URL, secondary.URL}, 10)
+	fwd.Start()
+
+	if err := fwd.Submit(context.Background(), []byte("body"), primary.URL+"/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	fwd.Stop(context.Background())
+
+	if got := atomic.LoadInt32(&secondaryHits); got != 0 {
+		t.Errorf("expected secondary to never be hit after a primary 4xx, got %d hits", got)
+	}
+}
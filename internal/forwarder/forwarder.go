@@ -1,7 +1,11 @@
 package forwarder
 
 import (
+    "context"
     "net/http"
+    "time"
+
+    "zep-logger/internal/worker"
 )
 
 // Forwarder defines the abstraction for forwarding requests asynchronously
@@ -10,15 +14,121 @@ type Forwarder interface {
     // Start initializes any background workers/resources
     Start()
 
-    // Stop gracefully stops the forwarder, waiting for in-flight tasks up to an internal timeout
-    Stop()
+    // Stop gracefully stops the forwarder, waiting for in-flight tasks to
+    // finish until ctx is done. Returns ctx.Err() if ctx is done before all
+    // in-flight tasks finish (some may still be running when Stop returns),
+    // nil otherwise. Callers coordinating multiple components under one
+    // shutdown deadline (e.g. the app's own shutdown sequence, which also
+    // bounds Echo's own Shutdown) should pass a context.WithTimeout built
+    // from that shared deadline rather than each component tracking its own.
+    Stop(ctx context.Context) error
 
-    // Submit forwards a request body to the target URL with given headers asynchronously
+    // Submit forwards a request body to the target URL with given headers asynchronously.
+    // ctx should be a long-lived context (e.g. the application's run context), not a
+    // per-incoming-request context: several implementations dispatch the actual outbound
+    // request from a background worker/goroutine that outlives the original caller, so a
+    // context canceled when that caller returns would abort delivery before it starts.
+    // Submit honors ctx cancellation by aborting the outbound request; it does not itself
+    // block on ctx.
     // Returns error if the implementation cannot accept more work immediately (e.g., pool queue full)
-    Submit(body []byte, targetURL string, headers http.Header) error
+    Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error
 
     // GetQueueDepth returns current backlog depth (queue in pool mode, waiters in semaphore mode)
     GetQueueDepth() int
+
+    // Flush blocks until every job accepted before the call returns has
+    // finished delivery (success or failure), or ctx is done, whichever
+    // comes first. Jobs accepted concurrently with or after the call are
+    // not guaranteed to be waited on. Implementations with nothing to wait
+    // on (e.g. a synchronous, delivers-then-returns-from-Submit model)
+    // return nil immediately. Used by checkpointing tests (a sync point
+    // instead of time.Sleep), the admin /flush endpoint, and graceful
+    // shutdown to drain in-flight work before stopping.
+    //
+    // This only replaces time.Sleep at the "wait for delivery" boundary. A
+    // fully injectable clock (swapping the time.Sleep/time.After/
+    // time.NewTicker calls used for retry backoff, TTL checks, and
+    // batch-flush timers across worker.Pool, BatchingForwarder, watchdog,
+    // canary, and reconciler) is a larger, separate change and is out of
+    // scope here.
+    Flush(ctx context.Context) error
+}
+
+// PriorityForwarder is implemented by forwarders that can weight jobs by a
+// caller-assigned worker.Priority when their underlying queue is contended.
+// Not every Forwarder implementation supports this (goroutine-per-request
+// models like the semaphore forwarder have nothing to prioritize between);
+// callers should type-assert and fall back to plain Submit when a forwarder
+// doesn't implement this interface.
+type PriorityForwarder interface {
+    // SubmitWithPriority behaves like Submit, but dispatches the job ahead of
+    // any pending worker.PriorityNormal job when priority is worker.PriorityHigh
+    SubmitWithPriority(ctx context.Context, body []byte, targetURL string, headers http.Header, priority worker.Priority) error
+}
+
+// TrackedForwarder is implemented by forwarders that can report a job id for
+// later status lookup (queued/sent/failed) via an admin endpoint. Not every
+// Forwarder implementation tracks jobs (the semaphore forwarder hands each
+// request straight to its own send goroutine, with no queue and no per-job
+// record to report);
+// callers should type-assert and fall back to plain Submit when a forwarder
+// doesn't implement this interface.
+type TrackedForwarder interface {
+    // SubmitTracked behaves like SubmitWithPriority, additionally returning a
+    // job id suitable for a later status lookup and accepting a per-job
+    // timeout (0 = no override, same as SubmitWithPriority). An empty id
+    // means the job wasn't tracked (e.g. no tracker configured), not that it
+    // failed.
+    SubmitTracked(ctx context.Context, body []byte, targetURL string, headers http.Header, priority worker.Priority, timeout time.Duration) (jobID string, err error)
+}
+
+// HealthReporter is implemented by forwarders that track the health (recent
+// latency and error rate) of the collector they deliver to. Not every
+// Forwarder implementation tracks this (the semaphore forwarder has no
+// rolling health signal to report); callers should type-assert and treat a
+// forwarder without this capability as always healthy.
+type HealthReporter interface {
+    // HealthScore reports the forwarder's current delivery health in [0,1],
+    // where 1 is fully healthy (fast, error-free) and 0 is down
+    HealthScore() float64
+}
+
+// SaturationReporter is implemented by forwarders that can report running
+// above a soft capacity watermark while still accepting work (e.g.
+// BackpressureForwarder between its soft and hard limits). Not every
+// Forwarder implementation has a soft watermark to report; callers should
+// type-assert and treat a forwarder without this capability as never
+// saturated.
+type SaturationReporter interface {
+    // IsSaturated reports whether the forwarder is currently above its soft
+    // watermark, and the number of seconds a caller should suggest clients
+    // wait before retrying.
+    IsSaturated() (saturated bool, retryAfterSeconds int)
+}
+
+// TimeoutForwarder is implemented by forwarders that can apply a per-job
+// delivery deadline independent of whatever Timeout is baked into their
+// underlying *http.Client. Not every Forwarder implementation supports this
+// (every mode besides pool reuses one process-wide *http.Client with Timeout
+// fixed at construction, applying identically to every request); callers
+// should type-assert and fall back to plain Submit/SubmitWithPriority when a
+// forwarder doesn't implement this interface.
+type TimeoutForwarder interface {
+    // SubmitWithTimeout behaves like SubmitWithPriority, additionally
+    // bounding this job's delivery attempt(s) to timeout. timeout <= 0
+    // leaves the client's own Timeout as the only bound, same as Submit.
+    SubmitWithTimeout(ctx context.Context, body []byte, targetURL string, headers http.Header, priority worker.Priority, timeout time.Duration) error
+}
+
+// BreakerReporter is implemented by forwarders that can report whether they
+// are currently diverting traffic away from their normal delivery path (e.g.
+// CircuitBreakerForwarder, once open). Not every Forwarder implementation has
+// a breaker to report on; callers should type-assert and treat a forwarder
+// without this capability as never tripped.
+type BreakerReporter interface {
+    // IsOpen reports whether the forwarder is currently diverting Submit
+    // away from its normal path.
+    IsOpen() bool
 }
 
 
@@ -0,0 +1,218 @@
+package forwarder
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	logspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+
+	"zep-logger/internal/metrics"
+	"zep-logger/pkg/logger"
+)
+
+type grpcTarget struct {
+	conn        *grpc.ClientConn
+	logsClient  logspb.LogsServiceClient
+	traceClient tracepb.TraceServiceClient
+}
+
+// GRPCForwarder implements Forwarder by sending OTLP payloads over gRPC
+// (typically port 4317) instead of HTTP, for collector deployments that only
+// expose gRPC internally. Submit is fire-and-forget like SemaphoreForwarder,
+// limited to maxConcurrent in-flight RPCs.
+type GRPCForwarder struct {
+	apiKey          string
+	requestTimeout  time.Duration
+	maxConcurrent   int
+	tokens          chan struct{}
+	wg              sync.WaitGroup
+	startOnce       sync.Once
+	stopOnce        sync.Once
+
+	mu      sync.Mutex
+	targets map[string]*grpcTarget
+}
+
+// NewGRPCForwarder creates a gRPC-based forwarder. apiKey (if non-empty) is
+// sent as the "authorization" gRPC metadata header on every RPC.
+func NewGRPCForwarder(apiKey string, maxConcurrent int, requestTimeout time.Duration) *GRPCForwarder {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 10000
+	}
+	if requestTimeout <= 0 {
+		requestTimeout = 10 * time.Second
+	}
+	return &GRPCForwarder{
+		apiKey:         apiKey,
+		requestTimeout: requestTimeout,
+		maxConcurrent:  maxConcurrent,
+		tokens:         make(chan struct{}, maxConcurrent),
+		targets:        make(map[string]*grpcTarget),
+	}
+}
+
+func (g *GRPCForwarder) Start() {
+	g.startOnce.Do(func() {
+		logger.Info("gRPC forwarder started with maxConcurrent=%d", g.maxConcurrent)
+	})
+}
+
+// Flush blocking until every in-flight send goroutine
+// has finished, or ctx is done.
+func (g *GRPCForwarder) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		g.wg.Wait()
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (g *GRPCForwarder) Stop(ctx context.Context) error {
+	var err error
+	g.stopOnce.Do(func() {
+		logger.Info("Stopping gRPC forwarder: waiting for in-flight RPCs")
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			g.wg.Wait()
+		}()
+
+		select {
+		case <-done:
+			logger.Info("gRPC forwarder stopped: all RPCs finished")
+		case <-ctx.Done():
+			logger.Warn("gRPC forwarder stop timed out: %v", ctx.Err())
+			err = ctx.Err()
+		}
+
+		g.mu.Lock()
+		for host, t := range g.targets {
+			if closeErr := t.conn.Close(); closeErr != nil {
+				logger.Warn("Failed to close gRPC connection to %s: %v", host, closeErr)
+			}
+		}
+		g.targets = make(map[string]*grpcTarget)
+		g.mu.Unlock()
+	})
+	return err
+}
+
+// grpcDialHostAndSignal splits a proxy-handler-style targetURL (e.g.
+// "https://collector:4317/v1/traces") into the gRPC dial target and the
+// signal it's carrying, stripping any http(s) scheme since gRPC dials plain
+// host:port
+func grpcDialHostAndSignal(targetURL string) (host string, signal string, ok bool) {
+	switch {
+	case strings.HasSuffix(targetURL, "/v1/logs"):
+		host, signal, ok = strings.TrimSuffix(targetURL, "/v1/logs"), "logs", true
+	case strings.HasSuffix(targetURL, "/v1/traces"):
+		host, signal, ok = strings.TrimSuffix(targetURL, "/v1/traces"), "traces", true
+	default:
+		return "", "", false
+	}
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	return host, signal, true
+}
+
+func (g *GRPCForwarder) targetFor(host string) (*grpcTarget, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if t, ok := g.targets[host]; ok {
+		return t, nil
+	}
+	conn, err := grpc.NewClient(host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	t := &grpcTarget{
+		conn:        conn,
+		logsClient:  logspb.NewLogsServiceClient(conn),
+		traceClient: tracepb.NewTraceServiceClient(conn),
+	}
+	g.targets[host] = t
+	return t, nil
+}
+
+func (g *GRPCForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	host, signal, ok := grpcDialHostAndSignal(targetURL)
+	if !ok {
+		return fmt.Errorf("forwarder: grpc forwarder cannot determine signal type for target %s", targetURL)
+	}
+
+	target, err := g.targetFor(host)
+	if err != nil {
+		return fmt.Errorf("forwarder: failed to dial grpc target %s: %w", host, err)
+	}
+
+	contentType := headers.Get("Content-Type")
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		g.tokens <- struct{}{} // acquire; blocks when at max concurrency
+		defer func() { <-g.tokens }()
+
+		metrics.ActiveWorkersGauge.Inc()
+		defer metrics.ActiveWorkersGauge.Dec()
+
+		rpcCtx, cancel := context.WithTimeout(ctx, g.requestTimeout)
+		defer cancel()
+		if g.apiKey != "" {
+			rpcCtx = metadata.AppendToOutgoingContext(rpcCtx, "authorization", g.apiKey)
+		}
+
+		var rpcErr error
+		switch signal {
+		case "logs":
+			req := &logspb.ExportLogsServiceRequest{}
+			if rpcErr = grpcUnmarshal(body, contentType, req); rpcErr == nil {
+				_, rpcErr = target.logsClient.Export(rpcCtx, req)
+			}
+		case "traces":
+			req := &tracepb.ExportTraceServiceRequest{}
+			if rpcErr = grpcUnmarshal(body, contentType, req); rpcErr == nil {
+				_, rpcErr = target.traceClient.Export(rpcCtx, req)
+			}
+		}
+
+		if rpcErr != nil {
+			logger.Error("gRPC forwarder: forwarding %s to %s failed: %v", signal, host, rpcErr)
+			metrics.JobsFailedCounter.Inc()
+		} else {
+			metrics.JobsProcessedCounter.Inc()
+		}
+	}()
+
+	return nil
+}
+
+func (g *GRPCForwarder) GetQueueDepth() int {
+	return len(g.tokens)
+}
+
+func grpcUnmarshal(body []byte, contentType string, msg proto.Message) error {
+	if strings.Contains(contentType, "json") {
+		return protojson.Unmarshal(body, msg)
+	}
+	return proto.Unmarshal(body, msg)
+}
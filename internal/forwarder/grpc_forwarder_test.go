@@ -0,0 +1,82 @@
+package forwarder
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	tracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+type fakeTraceServiceServer struct {
+	tracepb.UnimplementedTraceServiceServer
+	mu       sync.Mutex
+	received []*tracepb.ExportTraceServiceRequest
+}
+
+func (s *fakeTraceServiceServer) Export(ctx context.Context, req *tracepb.ExportTraceServiceRequest) (*tracepb.ExportTraceServiceResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.received = append(s.received, req)
+	return &tracepb.ExportTraceServiceResponse{}, nil
+}
+
+func (s *fakeTraceServiceServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.received)
+}
+
+func startFakeCollector(t *testing.T) (addr string, srv *fakeTraceServiceServer, stop func()) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	fake := &fakeTraceServiceServer{}
+	tracepb.RegisterTraceServiceServer(grpcServer, fake)
+	go grpcServer.Serve(lis)
+	return lis.Addr().String(), fake, grpcServer.Stop
+}
+
+func TestGRPCForwarder_Submit_ForwardsTracesOverGRPC(t *testing.T) {
+	addr, fake, stop := startFakeCollector(t)
+	defer stop()
+
+	fwd := NewGRPCForwarder("", 10, time.Second)
+	fwd.Start()
+	defer fwd.Stop(context.Background())
+
+	req := &tracepb.ExportTraceServiceRequest{}
+	body, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+
+	headers := http.Header{"Content-Type": []string{"application/x-protobuf"}}
+	if err := fwd.Submit(context.Background(), body, "http://"+addr+"/v1/traces", headers); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && fake.count() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if fake.count() != 1 {
+		t.Errorf("expected fake collector to receive 1 trace export, got %d", fake.count())
+	}
+}
+
+func TestGRPCForwarder_Submit_UnknownPath_ReturnsError(t *testing.T) {
+	fwd := NewGRPCForwarder("", 10, time.Second)
+	if err := fwd.Submit(context.Background(), []byte("x"), "http://collector:4317/v1/metrics", nil); err == nil {
+		t.Fatal("expected error for unrecognized signal path")
+	}
+}
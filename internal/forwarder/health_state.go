@@ -0,0 +1,55 @@
+package forwarder
+
+// HealthState is a coarse summary of a Forwarder's current delivery health,
+// derived from whichever of HealthReporter and SaturationReporter it
+// implements. Intended for readiness checks and status pages, where an
+// operator wants "is this instance okay" rather than the raw score.
+type HealthState string
+
+const (
+    // HealthStateHealthy means the forwarder is delivering normally: no
+    // HealthReporter score below the degraded threshold, and not saturated.
+    HealthStateHealthy HealthState = "healthy"
+
+    // HealthStateDegraded means the forwarder is still accepting and
+    // delivering work, but its HealthReporter score is below
+    // degradedHealthScoreThreshold or it reports SaturationReporter
+    // saturation.
+    HealthStateDegraded HealthState = "degraded"
+
+    // HealthStateFailing means the forwarder's HealthReporter score has
+    // dropped to 0 - deliveries are effectively not getting through.
+    HealthStateFailing HealthState = "failing"
+)
+
+// degradedHealthScoreThreshold is the HealthScore below which a forwarder is
+// considered degraded rather than healthy. A score of exactly 0 is failing,
+// not merely degraded.
+const degradedHealthScoreThreshold = 0.5
+
+// EvaluateHealth derives f's current HealthState from HealthReporter and
+// SaturationReporter, the capability interfaces most forwarders that track
+// collector health already implement. A forwarder implementing neither
+// (e.g. the semaphore forwarder) always evaluates healthy, matching how
+// callers of those interfaces already treat a missing capability as "can't
+// tell, assume fine".
+func EvaluateHealth(f Forwarder) HealthState {
+    state := HealthStateHealthy
+
+    if hr, ok := f.(HealthReporter); ok {
+        switch score := hr.HealthScore(); {
+        case score <= 0:
+            return HealthStateFailing
+        case score < degradedHealthScoreThreshold:
+            state = HealthStateDegraded
+        }
+    }
+
+    if sr, ok := f.(SaturationReporter); ok {
+        if saturated, _ := sr.IsSaturated(); saturated {
+            state = HealthStateDegraded
+        }
+    }
+
+    return state
+}
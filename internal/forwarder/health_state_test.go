@@ -0,0 +1,70 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// saturatedForwarder is a minimal Forwarder + SaturationReporter whose
+// saturation is set directly, for exercising EvaluateHealth independently of
+// BackpressureForwarder's own watermark tracking.
+type saturatedForwarder struct {
+	saturated bool
+}
+
+func (f *saturatedForwarder) Start()                              {}
+func (f *saturatedForwarder) Stop(ctx context.Context) error      { return nil }
+func (f *saturatedForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	return nil
+}
+func (f *saturatedForwarder) GetQueueDepth() int          { return 0 }
+func (f *saturatedForwarder) Flush(ctx context.Context) error { return nil }
+func (f *saturatedForwarder) IsSaturated() (bool, int)    { return f.saturated, 5 }
+
+func TestEvaluateHealth_NoCapabilities_IsHealthy(t *testing.T) {
+	fwd := &loopbackForwarderStub{}
+	if state := EvaluateHealth(fwd); state != HealthStateHealthy {
+		t.Errorf("expected HealthStateHealthy for a forwarder with no health capability, got %s", state)
+	}
+}
+
+func TestEvaluateHealth_ZeroHealthScore_IsFailing(t *testing.T) {
+	fwd := &healthScoredForwarder{score: 0}
+	if state := EvaluateHealth(fwd); state != HealthStateFailing {
+		t.Errorf("expected HealthStateFailing for a zero health score, got %s", state)
+	}
+}
+
+func TestEvaluateHealth_LowHealthScore_IsDegraded(t *testing.T) {
+	fwd := &healthScoredForwarder{score: 0.3}
+	if state := EvaluateHealth(fwd); state != HealthStateDegraded {
+		t.Errorf("expected HealthStateDegraded for a below-threshold health score, got %s", state)
+	}
+}
+
+func TestEvaluateHealth_HighHealthScore_IsHealthy(t *testing.T) {
+	fwd := &healthScoredForwarder{score: 1}
+	if state := EvaluateHealth(fwd); state != HealthStateHealthy {
+		t.Errorf("expected HealthStateHealthy for a full health score, got %s", state)
+	}
+}
+
+func TestEvaluateHealth_Saturated_IsDegraded(t *testing.T) {
+	fwd := &saturatedForwarder{saturated: true}
+	if state := EvaluateHealth(fwd); state != HealthStateDegraded {
+		t.Errorf("expected HealthStateDegraded for a saturated forwarder, got %s", state)
+	}
+}
+
+// loopbackForwarderStub is a minimal Forwarder implementing neither
+// HealthReporter nor SaturationReporter.
+type loopbackForwarderStub struct{}
+
+func (f *loopbackForwarderStub) Start()                         {}
+func (f *loopbackForwarderStub) Stop(ctx context.Context) error { return nil }
+func (f *loopbackForwarderStub) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	return nil
+}
+func (f *loopbackForwarderStub) GetQueueDepth() int              { return 0 }
+func (f *loopbackForwarderStub) Flush(ctx context.Context) error { return nil }
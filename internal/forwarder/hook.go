@@ -0,0 +1,99 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Hook lets operators compile in custom logic (extra headers, custom
+// metrics, payload mutation) around every Submit call without forking the
+// worker loop. Hooks are Go code, not config - they're wired in via
+// RegisterHook, typically from an init() in a file added at compile time -
+// so this is meant for logic config can't express, not another config
+// surface.
+type Hook interface {
+	// BeforeSend runs before the request is handed to the wrapped
+	// Forwarder. Returning a non-nil body replaces the one that will be
+	// sent (e.g. to inject a field); returning an error aborts the send
+	// entirely and that error is returned from Submit.
+	BeforeSend(ctx context.Context, body []byte, targetURL string, headers http.Header) ([]byte, error)
+
+	// AfterSend runs once the wrapped Forwarder's Submit returns - for an
+	// asynchronous forwarder that is when the job is accepted, not when its
+	// delivery finishes - and receives the same error Submit itself returns
+	// (nil on success).
+	AfterSend(ctx context.Context, targetURL string, headers http.Header, result error)
+}
+
+var (
+	hooksMu         sync.Mutex
+	registeredHooks []Hook
+)
+
+// RegisterHook adds h to the chain applied to every forwarder this process
+// builds. Intended to be called once, from an init() function, before the
+// application constructs its forwarders.
+func RegisterHook(h Hook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	registeredHooks = append(registeredHooks, h)
+}
+
+// RegisteredHooks returns a snapshot of the hooks registered so far, in
+// registration order.
+func RegisteredHooks() []Hook {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	out := make([]Hook, len(registeredHooks))
+	copy(out, registeredHooks)
+	return out
+}
+
+// HookForwarder wraps next, running every configured Hook's BeforeSend
+// before delegating to it and AfterSend once it returns.
+type HookForwarder struct {
+	next  Forwarder
+	hooks []Hook
+}
+
+// NewHookForwarder wraps next with hooks, run in slice order for
+// BeforeSend and the same order for AfterSend.
+func NewHookForwarder(next Forwarder, hooks []Hook) *HookForwarder {
+	return &HookForwarder{next: next, hooks: hooks}
+}
+
+func (h *HookForwarder) Start()                              { h.next.Start() }
+func (h *HookForwarder) Stop(ctx context.Context) error { return h.next.Stop(ctx) }
+
+// Flush delegates straight through to next - HookForwarder holds no
+// in-flight work of its own to wait on.
+func (h *HookForwarder) Flush(ctx context.Context) error {
+	return h.next.Flush(ctx)
+}
+
+// Submit runs every hook's BeforeSend (any hook may replace body or abort
+// the send), delegates to next, then runs every hook's AfterSend with the
+// result.
+func (h *HookForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	for _, hook := range h.hooks {
+		mutated, err := hook.BeforeSend(ctx, body, targetURL, headers)
+		if err != nil {
+			return err
+		}
+		if mutated != nil {
+			body = mutated
+		}
+	}
+
+	err := h.next.Submit(ctx, body, targetURL, headers)
+
+	for _, hook := range h.hooks {
+		hook.AfterSend(ctx, targetURL, headers, err)
+	}
+	return err
+}
+
+func (h *HookForwarder) GetQueueDepth() int {
+	return h.next.GetQueueDepth()
+}
@@ -0,0 +1,79 @@
+package forwarder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+type recordingHook struct {
+	beforeCalls int
+	afterCalls  int
+	afterErr    error
+	mutateBody  []byte
+	rejectErr   error
+}
+
+func (h *recordingHook) BeforeSend(ctx context.Context, body []byte, targetURL string, headers http.Header) ([]byte, error) {
+	h.beforeCalls++
+	if h.rejectErr != nil {
+		return nil, h.rejectErr
+	}
+	return h.mutateBody, nil
+}
+
+func (h *recordingHook) AfterSend(ctx context.Context, targetURL string, headers http.Header, result error) {
+	h.afterCalls++
+	h.afterErr = result
+}
+
+func TestHookForwarder_RunsBeforeAndAfterSend(t *testing.T) {
+	fake := &fakeForwarder{}
+	hook := &recordingHook{}
+	hf := NewHookForwarder(fake, []Hook{hook})
+
+	if err := hf.Submit(context.Background(), []byte("payload"), "http://collector/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("Submit returned unexpected error: %v", err)
+	}
+
+	if hook.beforeCalls != 1 || hook.afterCalls != 1 {
+		t.Errorf("beforeCalls=%d afterCalls=%d, want 1 and 1", hook.beforeCalls, hook.afterCalls)
+	}
+	if fake.count() != 1 {
+		t.Errorf("expected the wrapped forwarder to receive 1 submit, got %d", fake.count())
+	}
+	if hook.afterErr != nil {
+		t.Errorf("AfterSend result = %v, want nil", hook.afterErr)
+	}
+}
+
+func TestHookForwarder_BeforeSendCanMutateBody(t *testing.T) {
+	fake := &fakeForwarder{}
+	hook := &recordingHook{mutateBody: []byte("mutated")}
+	hf := NewHookForwarder(fake, []Hook{hook})
+
+	if err := hf.Submit(context.Background(), []byte("original"), "http://collector/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("Submit returned unexpected error: %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.submits) != 1 || string(fake.submits[0]) != "mutated" {
+		t.Errorf("wrapped forwarder received %q, want %q", fake.submits, "mutated")
+	}
+}
+
+func TestHookForwarder_BeforeSendErrorAbortsSubmit(t *testing.T) {
+	fake := &fakeForwarder{}
+	hook := &recordingHook{rejectErr: errors.New("rejected")}
+	hf := NewHookForwarder(fake, []Hook{hook})
+
+	err := hf.Submit(context.Background(), []byte("payload"), "http://collector/v1/logs", http.Header{})
+	if err == nil {
+		t.Fatal("expected Submit to return the BeforeSend error, got nil")
+	}
+	if fake.count() != 0 {
+		t.Errorf("expected the wrapped forwarder to not receive a submit, got %d", fake.count())
+	}
+}
@@ -2,6 +2,7 @@ package forwarder
 
 import (
     "bytes"
+    "context"
     "fmt"
     "net/http"
     "sync"
@@ -9,11 +10,13 @@ import (
 
     "go.uber.org/atomic"
 
+    "zep-logger/internal/httpclient"
     "zep-logger/internal/metrics"
     "zep-logger/pkg/logger"
 )
 
 type hybridJob struct {
+    ctx       context.Context
     body      []byte
     targetURL string
     headers   http.Header
@@ -26,16 +29,22 @@ type HybridForwarder struct {
     workerCount     int
     jobQueue        chan hybridJob
     tokens          chan struct{}
-    httpClient      *http.Client
+    httpClient      httpclient.UpstreamClient
     wg              sync.WaitGroup
     sendWG          sync.WaitGroup
     startOnce       sync.Once
     stopOnce        sync.Once
     stopped         atomic.Bool
-    shutdownTimeout time.Duration
+    pendingSends    atomic.Int64 // Fire-and-forget sender goroutines currently dispatching, not reflected in jobQueue
+    tenantHeader           string           // Header read from a job's Headers to identify its tenant for concurrency isolation (default: "", isolation disabled)
+    tenantConcurrencyLimit int              // Max sends a single tenant may have in flight at once, 0 disables (default: 0, original behavior - one tenant can consume every token)
+    tenantTokens           *tenantTokenPool // Lazily-created per-tenant token channels, only populated when tenantConcurrencyLimit > 0
 }
 
-func NewHybridForwarder(workerCount int, jobQueueSize int, maxConcurrent int, shutdownTimeout time.Duration) *HybridForwarder {
+// NewHybridForwarder creates a new hybrid dispatcher-pool+semaphore forwarder.
+// httpClientConfig overrides the shared HTTP client's timeouts (zero value:
+// built-in defaults).
+func NewHybridForwarder(workerCount int, jobQueueSize int, maxConcurrent int, httpClientConfig httpclient.Config) *HybridForwarder {
     if workerCount <= 0 {
         workerCount = 1
     }
@@ -46,26 +55,60 @@ func NewHybridForwarder(workerCount int, jobQueueSize int, maxConcurrent int, sh
         maxConcurrent = 10000
     }
 
-    transport := &http.Transport{
-        Proxy:                 http.ProxyFromEnvironment,
-        ForceAttemptHTTP2:     true,
-        MaxIdleConns:          maxConcurrent * 2,
-        MaxIdleConnsPerHost:   maxConcurrent,
-        MaxConnsPerHost:       maxConcurrent * 2,
-        IdleConnTimeout:       90 * time.Second,
-        TLSHandshakeTimeout:   10 * time.Second,
-        ExpectContinueTimeout: 1 * time.Second,
+    return &HybridForwarder{
+        workerCount: workerCount,
+        jobQueue:    make(chan hybridJob, jobQueueSize),
+        tokens:      make(chan struct{}, maxConcurrent),
+        httpClient:  httpclient.New(maxConcurrent, httpClientConfig),
     }
+}
 
-    return &HybridForwarder{
-        workerCount:     workerCount,
-        jobQueue:        make(chan hybridJob, jobQueueSize),
-        tokens:          make(chan struct{}, maxConcurrent),
-        httpClient:      &http.Client{Transport: transport, Timeout: 10 * time.Second},
-        shutdownTimeout: shutdownTimeout,
+// Flush blocking until every job currently queued or
+// in flight has finished delivery, or ctx is done. Unlike Stop, it doesn't
+// close the job queue - the forwarder keeps accepting new Submits once
+// Flush returns.
+func (h *HybridForwarder) Flush(ctx context.Context) error {
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        for len(h.jobQueue) > 0 || h.pendingSends.Load() > 0 {
+            select {
+            case <-ctx.Done():
+                return
+            case <-time.After(10 * time.Millisecond):
+            }
+        }
+        h.sendWG.Wait()
+    }()
+    select {
+    case <-done:
+        return nil
+    case <-ctx.Done():
+        return ctx.Err()
     }
 }
 
+// SetHTTPClient overrides the client used to send requests, so tests can
+// inject a deterministic fake instead of spinning up an httptest server and
+// sleeping for timing. Must be called before Start().
+func (h *HybridForwarder) SetHTTPClient(client httpclient.UpstreamClient) {
+    h.httpClient = client
+}
+
+// SetTenantConcurrencyLimit caps how many sends a single tenant may have in
+// flight at once, in addition to maxConcurrent, so a tenant with a slow
+// dedicated downstream route can't consume every token. tenant is read from
+// each job's Headers via header (a job missing it is bucketed as "unknown").
+// A job whose tenant is already at maxPerTenant is dropped rather than
+// queued to wait, matching this forwarder's fire-and-forget delivery model.
+// Must be called before Start(); if never set (or maxPerTenant <= 0), any
+// tenant may consume up to the full global limit (the original behavior).
+func (h *HybridForwarder) SetTenantConcurrencyLimit(header string, maxPerTenant int) {
+    h.tenantHeader = header
+    h.tenantConcurrencyLimit = maxPerTenant
+    h.tenantTokens = newTenantTokenPool(maxPerTenant)
+}
+
 func (h *HybridForwarder) Start() {
     h.startOnce.Do(func() {
         logger.Info("Hybrid forwarder starting: workers=%d, queueSize=%d, maxConcurrent=%d", h.workerCount, cap(h.jobQueue), cap(h.tokens))
@@ -77,7 +120,8 @@ func (h *HybridForwarder) Start() {
     })
 }
 
-func (h *HybridForwarder) Stop() {
+func (h *HybridForwarder) Stop(ctx context.Context) error {
+    var err error
     h.stopOnce.Do(func() {
         h.stopped.Store(true)
         logger.Info("Stopping hybrid forwarder: closing job queue and waiting for workers")
@@ -93,17 +137,19 @@ func (h *HybridForwarder) Stop() {
         select {
         case <-bothDone:
             logger.Info("Hybrid forwarder stopped: workers and in-flight sends finished")
-        case <-time.After(h.shutdownTimeout):
-            logger.Warn("Hybrid forwarder stop timed out after %v", h.shutdownTimeout)
+        case <-ctx.Done():
+            logger.Warn("Hybrid forwarder stop timed out: %d send(s) still pending: %v", h.pendingSends.Load(), ctx.Err())
+            err = ctx.Err()
         }
     })
+    return err
 }
 
-func (h *HybridForwarder) Submit(body []byte, targetURL string, headers http.Header) error {
+func (h *HybridForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
     if h.stopped.Load() {
         return fmt.Errorf("hybrid forwarder stopped")
     }
-    job := hybridJob{body: body, targetURL: targetURL, headers: headers}
+    job := hybridJob{ctx: ctx, body: body, targetURL: targetURL, headers: headers}
     select {
     case h.jobQueue <- job:
         return nil
@@ -113,8 +159,12 @@ func (h *HybridForwarder) Submit(body []byte, targetURL string, headers http.Hea
     }
 }
 
+// GetQueueDepth returns queued jobs plus sends currently in flight - a
+// fire-and-forget send leaves jobQueue the moment a worker dispatches it, so
+// jobQueue alone understates the real backlog while thousands of sends are
+// still pending against the collector
 func (h *HybridForwarder) GetQueueDepth() int {
-    return len(h.jobQueue)
+    return len(h.jobQueue) + int(h.pendingSends.Load())
 }
 
 func (h *HybridForwarder) worker(id int) {
@@ -126,14 +176,28 @@ func (h *HybridForwarder) worker(id int) {
 
         // Fire-and-forget sender goroutine; worker immediately returns to fetch next job
         h.sendWG.Add(1)
+        h.pendingSends.Inc()
+        metrics.HybridForwarderPendingSendsGauge.Inc()
         go func(j hybridJob) {
             defer h.sendWG.Done()
             defer func() { <-h.tokens }() // release token at end
+            defer h.pendingSends.Dec()
+            defer metrics.HybridForwarderPendingSendsGauge.Dec()
 
             metrics.ActiveWorkersGauge.Inc()
             defer metrics.ActiveWorkersGauge.Dec()
 
-            req, err := http.NewRequest("POST", j.targetURL, bytes.NewReader(j.body))
+            if h.tenantConcurrencyLimit > 0 {
+                tenant := tenantFromHeader(j.headers, h.tenantHeader)
+                if !h.tenantTokens.tryAcquire(tenant) {
+                    logger.Warn("Hybrid send: tenant %q concurrency limit reached (max: %d), dropping request to %s", tenant, h.tenantConcurrencyLimit, j.targetURL)
+                    metrics.JobsFailedCounter.Inc()
+                    return
+                }
+                defer h.tenantTokens.release(tenant)
+            }
+
+            req, err := http.NewRequestWithContext(j.ctx, "POST", j.targetURL, bytes.NewReader(j.body))
             if err != nil {
                 logger.Error("Hybrid send: failed to create request: %v", err)
                 metrics.JobsFailedCounter.Inc()
@@ -145,10 +209,13 @@ func (h *HybridForwarder) worker(id int) {
                 }
             }
 
+            start := time.Now()
             resp, err := client.Do(req)
+            metrics.ObserveForwardLatency(j.targetURL, start)
             if err != nil {
                 logger.Error("Hybrid send: forwarding to %s failed: %v", j.targetURL, err)
                 metrics.JobsFailedCounter.Inc()
+                metrics.ForwardErrorsCounter.WithLabelValues(metrics.ClassifyForwardError(err, 0)).Inc()
                 return
             }
             resp.Body.Close()
@@ -156,6 +223,7 @@ func (h *HybridForwarder) worker(id int) {
             if resp.StatusCode < 200 || resp.StatusCode >= 300 {
                 logger.Warn("Hybrid send: collector returned %d for %s", resp.StatusCode, j.targetURL)
                 metrics.JobsFailedCounter.Inc()
+                metrics.ForwardErrorsCounter.WithLabelValues(metrics.ClassifyForwardError(nil, resp.StatusCode)).Inc()
             } else {
                 metrics.JobsProcessedCounter.Inc()
             }
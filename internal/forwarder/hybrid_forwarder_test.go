@@ -0,0 +1,101 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"zep-logger/internal/httpclient"
+)
+
+// TestHybridForwarder_GetQueueDepth_IncludesPendingSends verifies that a job
+// handed off to a fire-and-forget sender goroutine still counts toward
+// GetQueueDepth until the send completes, even though it has already left
+// jobQueue
+func TestHybridForwarder_GetQueueDepth_IncludesPendingSends(t *testing.T) {
+	release := make(chan struct{})
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	fwd := NewHybridForwarder(1, 10, 5, httpclient.Config{})
+	fwd.Start()
+
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL, http.Header{}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	// Give the worker time to dequeue the job and hand it to a sender
+	// goroutine, which then blocks on the target until released
+	deadline := time.Now().Add(time.Second)
+	for fwd.GetQueueDepth() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if depth := fwd.GetQueueDepth(); depth != 1 {
+		t.Errorf("expected GetQueueDepth to report 1 pending send while jobQueue is empty, got %d", depth)
+	}
+
+	close(release)
+	fwd.Stop(context.Background())
+
+	if depth := fwd.GetQueueDepth(); depth != 0 {
+		t.Errorf("expected GetQueueDepth to be 0 after send completes, got %d", depth)
+	}
+}
+
+// TestHybridForwarder_TenantConcurrencyLimit_DropsOverLimitTenantWithoutAffectingAnother
+// verifies a per-tenant concurrency cap drops a saturated tenant's send
+// (fire-and-forget: there's no caller left to reject) while another
+// tenant's send still goes through
+func TestHybridForwarder_TenantConcurrencyLimit_DropsOverLimitTenantWithoutAffectingAnother(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	receivedTenants := map[string]int{}
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		mu.Lock()
+		receivedTenants[r.Header.Get("X-Tenant-Id")]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	fwd := NewHybridForwarder(4, 10, 10, httpclient.Config{})
+	fwd.SetTenantConcurrencyLimit("X-Tenant-Id", 1)
+	fwd.Start()
+
+	noisyTenant := http.Header{"X-Tenant-Id": {"noisy"}}
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL, noisyTenant); err != nil {
+		t.Fatalf("submit 1 failed: %v", err)
+	}
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL, noisyTenant); err != nil {
+		t.Fatalf("submit 2 failed: %v", err)
+	}
+	quietTenant := http.Header{"X-Tenant-Id": {"quiet"}}
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL, quietTenant); err != nil {
+		t.Fatalf("submit 3 failed: %v", err)
+	}
+
+	// Give workers time to dequeue all three jobs before releasing the target
+	deadline := time.Now().Add(time.Second)
+	for fwd.GetQueueDepth() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(release)
+	fwd.Stop(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	if receivedTenants["noisy"] != 1 {
+		t.Errorf("expected exactly 1 delivered request for the noisy tenant (one dropped past its limit), got %d", receivedTenants["noisy"])
+	}
+	if receivedTenants["quiet"] != 1 {
+		t.Errorf("expected the quiet tenant's request to be delivered, got %d", receivedTenants["quiet"])
+	}
+}
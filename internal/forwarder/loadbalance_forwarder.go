@@ -0,0 +1,225 @@
+package forwarder
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"zep-logger/internal/httpclient"
+	"zep-logger/internal/metrics"
+	"zep-logger/pkg/logger"
+)
+
+// lbTarget is one collector endpoint in a load-balanced pool, with its own
+// HTTP transport so one slow endpoint can't starve connections meant for
+// the others.
+type lbTarget struct {
+	url        string
+	httpClient httpclient.UpstreamClient
+	pending    atomic.Int64
+}
+
+// LoadBalanceForwarder implements Forwarder by fanning requests out across a
+// pool of collector endpoints, each with its own connection pool, using
+// either round-robin or least-pending selection.
+type LoadBalanceForwarder struct {
+	targets         []*lbTarget
+	strategy        string // "round_robin" or "least_pending"
+	nextIndex       atomic.Uint64
+	tokens          chan struct{}
+	wg              sync.WaitGroup
+	waiters         atomic.Int64
+	startOnce       sync.Once
+	stopOnce        sync.Once
+	stopped         atomic.Bool
+}
+
+// NewLoadBalanceForwarder creates a forwarder that fans requests out across
+// targetURLs using strategy ("round_robin" or "least_pending"). targets[0]
+// must be the same base URL callers pass to Submit, so the remaining targets
+// can be derived from it.
+func NewLoadBalanceForwarder(targetURLs []string, strategy string, maxConcurrent int) *LoadBalanceForwarder {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 10000
+	}
+
+	targets := make([]*lbTarget, 0, len(targetURLs))
+	for _, url := range targetURLs {
+		transport := &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          maxConcurrent * 2,
+			MaxIdleConnsPerHost:   maxConcurrent,
+			MaxConnsPerHost:       maxConcurrent * 2,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		}
+		targets = append(targets, &lbTarget{
+			url:        url,
+			httpClient: &http.Client{Transport: transport, Timeout: 10 * time.Second},
+		})
+	}
+
+	return &LoadBalanceForwarder{
+		targets:  targets,
+		strategy: strategy,
+		tokens:   make(chan struct{}, maxConcurrent),
+	}
+}
+
+// Flush blocking until every in-flight Submit
+// goroutine has finished delivery, or ctx is done.
+func (f *LoadBalanceForwarder) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.wg.Wait()
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetHTTPClient overrides the client used to send requests to every target,
+// so tests can inject a deterministic fake instead of spinning up an
+// httptest server and sleeping for timing. Must be called before Start().
+func (f *LoadBalanceForwarder) SetHTTPClient(client httpclient.UpstreamClient) {
+	for _, target := range f.targets {
+		target.httpClient = client
+	}
+}
+
+func (f *LoadBalanceForwarder) Start() {
+	f.startOnce.Do(func() {
+		logger.Info("Load-balance forwarder started with %d target(s), strategy=%s", len(f.targets), f.strategy)
+	})
+}
+
+func (f *LoadBalanceForwarder) Stop(ctx context.Context) error {
+	var err error
+	f.stopOnce.Do(func() {
+		f.stopped.Store(true)
+		logger.Info("Stopping load-balance forwarder: waiting for in-flight goroutines")
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			f.wg.Wait()
+		}()
+
+		select {
+		case <-done:
+			logger.Info("Load-balance forwarder stopped: all goroutines finished")
+		case <-ctx.Done():
+			logger.Warn("Load-balance forwarder stop timed out: %v", ctx.Err())
+			err = ctx.Err()
+		}
+	})
+	return err
+}
+
+// pick selects the next target according to the configured strategy
+func (f *LoadBalanceForwarder) pick() *lbTarget {
+	if f.strategy == "least_pending" {
+		best := f.targets[0]
+		for _, t := range f.targets[1:] {
+			if t.pending.Load() < best.pending.Load() {
+				best = t
+			}
+		}
+		return best
+	}
+
+	idx := f.nextIndex.Inc() - 1
+	return f.targets[idx%uint64(len(f.targets))]
+}
+
+func (f *LoadBalanceForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	if f.stopped.Load() {
+		return nil // during shutdown, readiness will block new traffic
+	}
+
+	suffix := strings.TrimPrefix(targetURL, f.targets[0].url)
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		f.waiters.Inc()
+		f.tokens <- struct{}{} // acquire; blocks when at max concurrency
+		f.waiters.Dec()
+		defer func() { <-f.tokens }() // release
+
+		metrics.ActiveWorkersGauge.Inc()
+		defer metrics.ActiveWorkersGauge.Dec()
+
+		target := f.pick()
+		candidateURL := targetURL
+		if target != f.targets[0] {
+			candidateURL = target.url + suffix
+		}
+
+		target.pending.Inc()
+		metrics.LoadBalancerTargetPendingGauge.WithLabelValues(target.url).Set(float64(target.pending.Load()))
+		defer func() {
+			target.pending.Dec()
+			metrics.LoadBalancerTargetPendingGauge.WithLabelValues(target.url).Set(float64(target.pending.Load()))
+		}()
+
+		req, err := http.NewRequestWithContext(ctx, "POST", candidateURL, bytes.NewReader(body))
+		if err != nil {
+			logger.Error("Load-balance forwarder: failed to create request for %s: %v", candidateURL, err)
+			metrics.LoadBalancerTargetRequestsCounter.WithLabelValues(target.url, "error").Inc()
+			metrics.JobsFailedCounter.Inc()
+			metrics.ForwardErrorsCounter.WithLabelValues(metrics.ClassifyForwardError(err, 0)).Inc()
+			return
+		}
+		for k, values := range headers {
+			for _, v := range values {
+				req.Header.Add(k, v)
+			}
+		}
+
+		start := time.Now()
+		resp, err := target.httpClient.Do(req)
+		metrics.ObserveForwardLatency(candidateURL, start)
+		if err != nil {
+			logger.Error("Load-balance forwarder: forwarding to %s failed: %v", candidateURL, err)
+			metrics.LoadBalancerTargetRequestsCounter.WithLabelValues(target.url, "error").Inc()
+			metrics.JobsFailedCounter.Inc()
+			metrics.ForwardErrorsCounter.WithLabelValues(metrics.ClassifyForwardError(err, 0)).Inc()
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			logger.Warn("Load-balance forwarder: target %s returned %d", candidateURL, resp.StatusCode)
+			metrics.LoadBalancerTargetRequestsCounter.WithLabelValues(target.url, "error").Inc()
+			metrics.JobsFailedCounter.Inc()
+			metrics.ForwardErrorsCounter.WithLabelValues(metrics.ClassifyForwardError(nil, resp.StatusCode)).Inc()
+			return
+		}
+
+		metrics.LoadBalancerTargetRequestsCounter.WithLabelValues(target.url, "success").Inc()
+		metrics.JobsProcessedCounter.Inc()
+	}()
+
+	return nil
+}
+
+func (f *LoadBalanceForwarder) GetQueueDepth() int {
+	v := f.waiters.Load()
+	if v < 0 {
+		return 0
+	}
+	return int(v)
+}
@@ -0,0 +1,67 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLoadBalanceForwarder_RoundRobin_DistributesAcrossTargets(t *testing.T) {
+	var hitsA, hitsB int32
+	targetA := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hitsA, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer targetA.Close()
+	targetB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hitsB, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer targetB.Close()
+
+	fwd := NewLoadBalanceForwarder([]string{targetA.URL, targetB.URL}, "round_robin", 10)
+	fwd.Start()
+
+	for i := 0; i < 4; i++ {
+		if err := fwd.Submit(context.Background(), []byte("body"), targetA.URL+"/v1/logs", http.Header{}); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	fwd.Stop(context.Background())
+
+	if got := atomic.LoadInt32(&hitsA); got != 2 {
+		t.Errorf("expected target A to receive 2 requests, got %d", got)
+	}
+	if got := atomic.LoadInt32(&hitsB); got != 2 {
+		t.Errorf("expected target B to receive 2 requests, got %d", got)
+	}
+}
+
+func TestLoadBalanceForwarder_UnknownStrategy_DefaultsToRoundRobinBehavior(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	fwd := NewLoadBalanceForwarder([]string{target.URL}, "not_a_real_strategy", 10)
+	fwd.Start()
+	defer fwd.Stop(context.Background())
+
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL+"/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+}
+
+func TestLoadBalanceForwarder_GetQueueDepth_NonNegative(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	fwd := NewLoadBalanceForwarder([]string{target.URL}, "round_robin", 10)
+	if depth := fwd.GetQueueDepth(); depth != 0 {
+		t.Errorf("expected queue depth 0 before any submits, got %d", depth)
+	}
+}
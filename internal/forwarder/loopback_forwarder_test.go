@@ -0,0 +1,54 @@
+package forwarder
+
+import (
+	"context"
+	"testing"
+)
+
+// TestLoopbackForwarder_Snapshot_ReturnsEntriesOldestFirst verifies ordering
+// for a forwarder that hasn't wrapped its ring buffer yet
+func TestLoopbackForwarder_Snapshot_ReturnsEntriesOldestFirst(t *testing.T) {
+	l := NewLoopbackForwarder(3, nil)
+	if err := l.Submit(context.Background(), []byte("first"), "http://example.invalid", nil); err != nil {
+		t.Fatalf("Submit returned error: %v", err)
+	}
+	if err := l.Submit(context.Background(), []byte("second"), "http://example.invalid", nil); err != nil {
+		t.Fatalf("Submit returned error: %v", err)
+	}
+
+	snapshot := l.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(snapshot))
+	}
+	if snapshot[0].Body != "first" || snapshot[1].Body != "second" {
+		t.Errorf("expected entries oldest-first, got %v", snapshot)
+	}
+}
+
+// TestLoopbackForwarder_Submit_WrapsAroundEvictingOldest verifies the ring
+// buffer discards the oldest entry once at capacity
+func TestLoopbackForwarder_Submit_WrapsAroundEvictingOldest(t *testing.T) {
+	l := NewLoopbackForwarder(2, nil)
+	l.Submit(context.Background(), []byte("one"), "http://example.invalid", nil)
+	l.Submit(context.Background(), []byte("two"), "http://example.invalid", nil)
+	l.Submit(context.Background(), []byte("three"), "http://example.invalid", nil)
+
+	snapshot := l.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("expected 2 entries after wraparound, got %d", len(snapshot))
+	}
+	if snapshot[0].Body != "two" || snapshot[1].Body != "three" {
+		t.Errorf("expected oldest entry evicted, got %v", snapshot)
+	}
+}
+
+// TestLoopbackForwarder_Submit_NeverErrors verifies loopback always accepts,
+// unlike a real collector-backed forwarder that can reject under pressure
+func TestLoopbackForwarder_Submit_NeverErrors(t *testing.T) {
+	l := NewLoopbackForwarder(1, nil)
+	for i := 0; i < 5; i++ {
+		if err := l.Submit(context.Background(), []byte("payload"), "http://example.invalid", nil); err != nil {
+			t.Errorf("expected Submit to always succeed, got error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package forwarder
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"zep-logger/internal/httpclient"
+	"zep-logger/internal/metrics"
+	"zep-logger/pkg/logger"
+)
+
+// MirrorForwarder wraps another Forwarder and, for every Submit, also tees the
+// payload to a shadow collector on a best-effort, non-blocking basis. Mirror
+// sends never affect the primary path: Submit always delegates to next first
+// and the mirror send happens in its own goroutine, bounded by its own
+// semaphore so a slow or down mirror target can't back up primary traffic.
+type MirrorForwarder struct {
+	next            Forwarder
+	primaryTarget   string
+	mirrorTarget    string
+	httpClient      httpclient.UpstreamClient
+	tokens          chan struct{}
+	wg              sync.WaitGroup
+	startOnce       sync.Once
+	stopOnce        sync.Once
+	stopped         atomic.Bool
+}
+
+// NewMirrorForwarder wraps next, teeing every Submit to mirrorTarget in
+// addition to whatever next already does. primaryTarget must be the same base
+// URL callers pass to Submit, so the mirror URL can be derived from it.
+func NewMirrorForwarder(next Forwarder, primaryTarget string, mirrorTarget string, maxConcurrent int) *MirrorForwarder {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 50
+	}
+
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxConcurrent * 2,
+		MaxIdleConnsPerHost:   maxConcurrent,
+		MaxConnsPerHost:       maxConcurrent * 2,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	return &MirrorForwarder{
+		next:          next,
+		primaryTarget: primaryTarget,
+		mirrorTarget:  mirrorTarget,
+		httpClient:    &http.Client{Transport: transport, Timeout: 10 * time.Second},
+		tokens:        make(chan struct{}, maxConcurrent),
+	}
+}
+
+// Flush blocks until every in-flight mirror send has finished and next
+// reports idle too, or until ctx is done.
+func (m *MirrorForwarder) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return m.next.Flush(ctx)
+}
+
+// SetHTTPClient overrides the client used to send mirror requests, so tests
+// can inject a deterministic fake instead of spinning up an httptest server
+// and sleeping for timing. Must be called before Start().
+func (m *MirrorForwarder) SetHTTPClient(client httpclient.UpstreamClient) {
+	m.httpClient = client
+}
+
+func (m *MirrorForwarder) Start() {
+	m.startOnce.Do(func() {
+		m.next.Start()
+		logger.Info("Mirror forwarder started: mirroring to %s", m.mirrorTarget)
+	})
+}
+
+func (m *MirrorForwarder) Stop(ctx context.Context) error {
+	var err error
+	m.stopOnce.Do(func() {
+		m.stopped.Store(true)
+		logger.Info("Stopping mirror forwarder: waiting for in-flight mirror sends")
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			m.wg.Wait()
+		}()
+
+		select {
+		case <-done:
+			logger.Info("Mirror forwarder stopped: all mirror sends finished")
+		case <-ctx.Done():
+			logger.Warn("Mirror forwarder stop timed out: %v", ctx.Err())
+			err = ctx.Err()
+		}
+
+		if stopErr := m.next.Stop(ctx); stopErr != nil && err == nil {
+			err = stopErr
+		}
+	})
+	return err
+}
+
+func (m *MirrorForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	err := m.next.Submit(ctx, body, targetURL, headers)
+
+	if m.stopped.Load() {
+		return err
+	}
+
+	mirrorURL := m.mirrorTarget + strings.TrimPrefix(targetURL, m.primaryTarget)
+
+	select {
+	case m.tokens <- struct{}{}: // acquire; drop the mirror send if already at max concurrency
+	default:
+		metrics.MirrorRequestsCounter.WithLabelValues("dropped").Inc()
+		return err
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer func() { <-m.tokens }() // release
+
+		req, reqErr := http.NewRequestWithContext(ctx, "POST", mirrorURL, bytes.NewReader(body))
+		if reqErr != nil {
+			logger.Warn("Mirror forwarder: failed to create request for %s: %v", mirrorURL, reqErr)
+			metrics.MirrorRequestsCounter.WithLabelValues("error").Inc()
+			return
+		}
+		for k, values := range headers {
+			for _, v := range values {
+				req.Header.Add(k, v)
+			}
+		}
+
+		resp, sendErr := m.httpClient.Do(req)
+		if sendErr != nil {
+			logger.Warn("Mirror forwarder: sending to %s failed: %v", mirrorURL, sendErr)
+			metrics.MirrorRequestsCounter.WithLabelValues("error").Inc()
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			logger.Warn("Mirror forwarder: mirror target %s returned %d", mirrorURL, resp.StatusCode)
+			metrics.MirrorRequestsCounter.WithLabelValues("error").Inc()
+			return
+		}
+
+		metrics.MirrorRequestsCounter.WithLabelValues("success").Inc()
+	}()
+
+	return err
+}
+
+func (m *MirrorForwarder) GetQueueDepth() int {
+	return m.next.GetQueueDepth()
+}
@@ -0,0 +1,83 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMirrorForwarder_SubmitsToNextRegardlessOfMirrorOutcome(t *testing.T) {
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mirror.Close()
+
+	next := &fakeForwarder{}
+	fwd := NewMirrorForwarder(next, "http://primary.example.com", mirror.URL, 10)
+	fwd.Start()
+
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://primary.example.com/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	fwd.Stop(context.Background())
+
+	if got := next.count(); got != 1 {
+		t.Errorf("expected next.Submit to be called once regardless of mirror outcome, got %d calls", got)
+	}
+}
+
+func TestMirrorForwarder_TeesPayloadToMirrorTarget(t *testing.T) {
+	var mirrorHits int32
+	var gotPath string
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&mirrorHits, 1)
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mirror.Close()
+
+	next := &fakeForwarder{}
+	fwd := NewMirrorForwarder(next, "http://primary.example.com", mirror.URL, 10)
+	fwd.Start()
+
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://primary.example.com/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	fwd.Stop(context.Background())
+
+	if got := atomic.LoadInt32(&mirrorHits); got != 1 {
+		t.Errorf("expected mirror target to be hit once, got %d hits", got)
+	}
+	if gotPath != "/v1/logs" {
+		t.Errorf("expected mirror request path to preserve /v1/logs suffix, got %q", gotPath)
+	}
+}
+
+func TestMirrorForwarder_AtMaxConcurrency_DropsExcessMirrorSends(t *testing.T) {
+	block := make(chan struct{})
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mirror.Close()
+
+	next := &fakeForwarder{}
+	fwd := NewMirrorForwarder(next, "http://primary.example.com", mirror.URL, 1)
+	fwd.Start()
+
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://primary.example.com/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("first submit failed: %v", err)
+	}
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://primary.example.com/v1/logs", http.Header{}); err != nil {
+		t.Fatalf("second submit failed: %v", err)
+	}
+
+	close(block)
+	fwd.Stop(context.Background())
+
+	if got := next.count(); got != 2 {
+		t.Errorf("expected next.Submit to be called for both requests even though mirroring was saturated, got %d calls", got)
+	}
+}
@@ -1,7 +1,9 @@
 package forwarder
 
 import (
+    "context"
     "net/http"
+    "time"
     "zep-logger/internal/worker"
 )
 
@@ -20,20 +22,57 @@ func (p *PoolForwarder) Start() {
     }
 }
 
-func (p *PoolForwarder) Stop() {
+func (p *PoolForwarder) Stop(ctx context.Context) error {
     if p.pool != nil {
         p.pool.Stop()
     }
+    return nil
 }
 
-func (p *PoolForwarder) Submit(body []byte, targetURL string, headers http.Header) error {
+func (p *PoolForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+    _, err := p.submit(ctx, body, targetURL, headers, worker.PriorityNormal, 0)
+    return err
+}
+
+// SubmitWithPriority implements PriorityForwarder, letting callers dispatch a
+// job ahead of any pending normal-priority job when the pool's queue is
+// contended
+func (p *PoolForwarder) SubmitWithPriority(ctx context.Context, body []byte, targetURL string, headers http.Header, priority worker.Priority) error {
+    _, err := p.submit(ctx, body, targetURL, headers, priority, 0)
+    return err
+}
+
+// SubmitTracked implements TrackedForwarder, additionally returning the job's
+// tracking id (empty unless the pool has a jobTracker configured via
+// worker.Pool.SetJobTracker)
+func (p *PoolForwarder) SubmitTracked(ctx context.Context, body []byte, targetURL string, headers http.Header, priority worker.Priority, timeout time.Duration) (string, error) {
+    return p.submit(ctx, body, targetURL, headers, priority, timeout)
+}
+
+// SubmitWithTimeout implements TimeoutForwarder, bounding this job's delivery
+// attempt(s) to timeout independent of the pool's shared *http.Client.Timeout
+func (p *PoolForwarder) SubmitWithTimeout(ctx context.Context, body []byte, targetURL string, headers http.Header, priority worker.Priority, timeout time.Duration) error {
+    _, err := p.submit(ctx, body, targetURL, headers, priority, timeout)
+    return err
+}
+
+func (p *PoolForwarder) submit(ctx context.Context, body []byte, targetURL string, headers http.Header, priority worker.Priority, timeout time.Duration) (string, error) {
     if p.pool == nil {
-        return nil
+        return "", nil
     }
-    job := worker.Job{Body: body, TargetURL: targetURL, Headers: headers}
+    job := worker.Job{Body: body, TargetURL: targetURL, Headers: headers, Priority: priority, Ctx: ctx, Timeout: timeout}
     return p.pool.SubmitJob(job)
 }
 
+// Flush blocking until the pool's queue is drained
+// and no worker is processing a job, or ctx is done.
+func (p *PoolForwarder) Flush(ctx context.Context) error {
+    if p.pool == nil {
+        return nil
+    }
+    return p.pool.Flush(ctx)
+}
+
 func (p *PoolForwarder) GetQueueDepth() int {
     if p.pool == nil {
         return 0
@@ -41,4 +80,14 @@ func (p *PoolForwarder) GetQueueDepth() int {
     return p.pool.GetQueueDepth()
 }
 
+// HealthScore implements HealthReporter, reflecting recent delivery
+// latency/error rate (always 1, full health, unless the pool has a
+// HealthScorer configured via worker.Pool.SetHealthScorer)
+func (p *PoolForwarder) HealthScore() float64 {
+    if p.pool == nil {
+        return 1
+    }
+    return p.pool.HealthScore()
+}
+
 
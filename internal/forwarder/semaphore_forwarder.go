@@ -2,12 +2,15 @@ package forwarder
 
 import (
     "bytes"
+    "context"
+    "fmt"
     "net/http"
     "sync"
     "time"
 
     "go.uber.org/atomic"
 
+    "zep-logger/internal/httpclient"
     "zep-logger/internal/metrics"
     "zep-logger/pkg/logger"
 )
@@ -16,47 +19,74 @@ import (
 type SemaphoreForwarder struct {
     maxConcurrent   int
     tokens          chan struct{}
-    httpClient      *http.Client
+    httpClient      httpclient.UpstreamClient
     wg              sync.WaitGroup
-    waiters         atomic.Int64
     startOnce       sync.Once
     stopOnce        sync.Once
     stopped         atomic.Bool
-    shutdownTimeout time.Duration
+    tenantHeader           string           // Header read from a request's Headers to identify its tenant for concurrency isolation (default: "", isolation disabled)
+    tenantConcurrencyLimit int              // Max requests a single tenant may have in flight at once, 0 disables (default: 0, original behavior - one tenant can consume every token)
+    tenantTokens           *tenantTokenPool // Lazily-created per-tenant token channels, only populated when tenantConcurrencyLimit > 0
 }
 
-// NewSemaphoreForwarder creates a new semaphore-based forwarder
-func NewSemaphoreForwarder(maxConcurrent int, shutdownTimeout time.Duration) *SemaphoreForwarder {
+// NewSemaphoreForwarder creates a new semaphore-based forwarder. httpClientConfig
+// overrides the shared HTTP client's timeouts (zero value: built-in defaults).
+func NewSemaphoreForwarder(maxConcurrent int, httpClientConfig httpclient.Config) *SemaphoreForwarder {
     if maxConcurrent <= 0 {
         maxConcurrent = 10000
     }
 
-    transport := &http.Transport{
-        Proxy:                 http.ProxyFromEnvironment,
-        ForceAttemptHTTP2:     true,
-        MaxIdleConns:          maxConcurrent * 2,
-        MaxIdleConnsPerHost:   maxConcurrent,
-        MaxConnsPerHost:       maxConcurrent * 2,
-        IdleConnTimeout:       90 * time.Second,
-        TLSHandshakeTimeout:   10 * time.Second,
-        ExpectContinueTimeout: 1 * time.Second,
+    return &SemaphoreForwarder{
+        maxConcurrent: maxConcurrent,
+        tokens:        make(chan struct{}, maxConcurrent),
+        httpClient:    httpclient.New(maxConcurrent, httpClientConfig),
     }
+}
 
-    return &SemaphoreForwarder{
-        maxConcurrent:   maxConcurrent,
-        tokens:          make(chan struct{}, maxConcurrent),
-        httpClient:      &http.Client{Transport: transport, Timeout: 10 * time.Second},
-        shutdownTimeout: shutdownTimeout,
+// Flush blocking until every in-flight Submit
+// goroutine has finished delivery, or ctx is done.
+func (s *SemaphoreForwarder) Flush(ctx context.Context) error {
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        s.wg.Wait()
+    }()
+    select {
+    case <-done:
+        return nil
+    case <-ctx.Done():
+        return ctx.Err()
     }
 }
 
+// SetHTTPClient overrides the client used to send requests, so tests can
+// inject a deterministic fake instead of spinning up an httptest server and
+// sleeping for timing. Must be called before Start().
+func (s *SemaphoreForwarder) SetHTTPClient(client httpclient.UpstreamClient) {
+    s.httpClient = client
+}
+
+// SetTenantConcurrencyLimit caps how many requests a single tenant may have
+// in flight at once, in addition to maxConcurrent, so a tenant with a slow
+// dedicated downstream route can't consume every token. tenant is read from
+// each request's Headers via header (a request missing it is bucketed as
+// "unknown"). Must be called before Start(); if never set (or maxPerTenant
+// <= 0), any tenant may consume up to the full global limit (the original
+// behavior).
+func (s *SemaphoreForwarder) SetTenantConcurrencyLimit(header string, maxPerTenant int) {
+    s.tenantHeader = header
+    s.tenantConcurrencyLimit = maxPerTenant
+    s.tenantTokens = newTenantTokenPool(maxPerTenant)
+}
+
 func (s *SemaphoreForwarder) Start() {
     s.startOnce.Do(func() {
         logger.Info("Semaphore forwarder started with maxConcurrent=%d", s.maxConcurrent)
     })
 }
 
-func (s *SemaphoreForwarder) Stop() {
+func (s *SemaphoreForwarder) Stop(ctx context.Context) error {
+    var err error
     s.stopOnce.Do(func() {
         s.stopped.Store(true)
         logger.Info("Stopping semaphore forwarder: waiting for in-flight goroutines")
@@ -70,30 +100,52 @@ func (s *SemaphoreForwarder) Stop() {
         select {
         case <-done:
             logger.Info("Semaphore forwarder stopped: all goroutines finished")
-        case <-time.After(s.shutdownTimeout):
-            logger.Warn("Semaphore forwarder stop timed out after %v", s.shutdownTimeout)
+        case <-ctx.Done():
+            logger.Warn("Semaphore forwarder stop timed out: %v", ctx.Err())
+            err = ctx.Err()
         }
     })
+    return err
 }
 
-func (s *SemaphoreForwarder) Submit(body []byte, targetURL string, headers http.Header) error {
+func (s *SemaphoreForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
     if s.stopped.Load() {
         return nil // during shutdown, readiness will block new traffic
     }
 
+    // Try-acquire a token before spawning a goroutine, so a burst beyond
+    // maxConcurrent is rejected as backpressure instead of spawning a
+    // goroutine per Submit that just blocks waiting for one (a burst could
+    // otherwise pile up hundreds of thousands of goroutines parked on tokens)
+    select {
+    case s.tokens <- struct{}{}:
+    default:
+        logger.Warn("Semaphore forwarder saturated: rejecting request (maxConcurrent: %d)", s.maxConcurrent)
+        return fmt.Errorf("semaphore forwarder saturated (maxConcurrent: %d)", s.maxConcurrent)
+    }
+
+    var tenant string
+    if s.tenantConcurrencyLimit > 0 {
+        tenant = tenantFromHeader(headers, s.tenantHeader)
+        if !s.tenantTokens.tryAcquire(tenant) {
+            <-s.tokens // release the global token acquired above
+            logger.Warn("Semaphore forwarder: tenant %q concurrency limit reached (max: %d), rejecting request", tenant, s.tenantConcurrencyLimit)
+            return fmt.Errorf("tenant %q concurrency limit reached (max: %d)", tenant, s.tenantConcurrencyLimit)
+        }
+    }
+
     s.wg.Add(1)
     go func() {
         defer s.wg.Done()
-
-        s.waiters.Inc()
-        s.tokens <- struct{}{} // acquire; blocks when at max concurrency
-        s.waiters.Dec()
         defer func() { <-s.tokens }() // release
+        if s.tenantConcurrencyLimit > 0 {
+            defer s.tenantTokens.release(tenant)
+        }
 
         metrics.ActiveWorkersGauge.Inc()
         defer metrics.ActiveWorkersGauge.Dec()
 
-        req, err := http.NewRequest("POST", targetURL, bytes.NewReader(body))
+        req, err := http.NewRequestWithContext(ctx, "POST", targetURL, bytes.NewReader(body))
         if err != nil {
             logger.Error("Semaphore forwarder: failed to create request: %v", err)
             metrics.JobsFailedCounter.Inc()
@@ -105,10 +157,13 @@ func (s *SemaphoreForwarder) Submit(body []byte, targetURL string, headers http.
             }
         }
 
+        start := time.Now()
         resp, err := s.httpClient.Do(req)
+        metrics.ObserveForwardLatency(targetURL, start)
         if err != nil {
             logger.Error("Semaphore forwarder: forwarding to %s failed: %v", targetURL, err)
             metrics.JobsFailedCounter.Inc()
+            metrics.ForwardErrorsCounter.WithLabelValues(metrics.ClassifyForwardError(err, 0)).Inc()
             return
         }
         resp.Body.Close()
@@ -116,6 +171,7 @@ func (s *SemaphoreForwarder) Submit(body []byte, targetURL string, headers http.
         if resp.StatusCode < 200 || resp.StatusCode >= 300 {
             logger.Warn("Semaphore forwarder: collector returned %d for %s", resp.StatusCode, targetURL)
             metrics.JobsFailedCounter.Inc()
+            metrics.ForwardErrorsCounter.WithLabelValues(metrics.ClassifyForwardError(nil, resp.StatusCode)).Inc()
         } else {
             metrics.JobsProcessedCounter.Inc()
         }
@@ -124,15 +180,11 @@ func (s *SemaphoreForwarder) Submit(body []byte, targetURL string, headers http.
     return nil
 }
 
+// GetQueueDepth returns the number of requests currently in flight. Submit
+// now rejects anything beyond maxConcurrent instead of queueing it, so
+// there's no separate waiter count to report.
 func (s *SemaphoreForwarder) GetQueueDepth() int {
-    v := s.waiters.Load()
-    if v < 0 {
-        return 0
-    }
-    if v > int64(^uint(0)>>1) { // guard though unrealistic
-        return int(^uint(0) >> 1)
-    }
-    return int(v)
+    return len(s.tokens)
 }
 
 
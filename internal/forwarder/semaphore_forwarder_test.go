@@ -0,0 +1,182 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"zep-logger/internal/httpclient"
+)
+
+// TestSemaphoreForwarder_RejectsSubmitBeyondMaxConcurrent verifies that
+// Submit acquires a token before spawning a goroutine, rejecting a burst
+// beyond maxConcurrent as backpressure instead of spawning a goroutine that
+// blocks waiting for one
+func TestSemaphoreForwarder_RejectsSubmitBeyondMaxConcurrent(t *testing.T) {
+	release := make(chan struct{})
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	fwd := NewSemaphoreForwarder(2, httpclient.Config{})
+	fwd.Start()
+
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL, http.Header{}); err != nil {
+		t.Fatalf("submit 1 failed: %v", err)
+	}
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL, http.Header{}); err != nil {
+		t.Fatalf("submit 2 failed: %v", err)
+	}
+
+	// Both tokens are held by in-flight requests; a third Submit must be
+	// rejected immediately rather than spawning a goroutine to wait
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL, http.Header{}); err == nil {
+		t.Error("expected Submit to reject a request beyond maxConcurrent, got nil error")
+	}
+
+	close(release)
+	fwd.Stop(context.Background())
+}
+
+// TestSemaphoreForwarder_AcceptsNewSubmitAfterInFlightRequestCompletes
+// verifies a released token frees up capacity for a subsequent Submit
+func TestSemaphoreForwarder_AcceptsNewSubmitAfterInFlightRequestCompletes(t *testing.T) {
+	var received int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	fwd := NewSemaphoreForwarder(1, httpclient.Config{})
+	fwd.Start()
+	defer fwd.Stop(context.Background())
+
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL, http.Header{}); err != nil {
+		t.Fatalf("submit 1 failed: %v", err)
+	}
+
+	// Give the first request time to complete and release its token
+	time.Sleep(100 * time.Millisecond)
+
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL, http.Header{}); err != nil {
+		t.Fatalf("expected submit 2 to succeed once capacity freed up: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if got := atomic.LoadInt32(&received); got != 2 {
+		t.Errorf("expected both requests to be delivered, got %d", got)
+	}
+}
+
+// TestSemaphoreForwarder_TenantConcurrencyLimit_RejectsOneTenantWithoutBlockingAnother
+// verifies a per-tenant concurrency cap rejects a saturated tenant's Submit
+// while global capacity is still available for another tenant
+func TestSemaphoreForwarder_TenantConcurrencyLimit_RejectsOneTenantWithoutBlockingAnother(t *testing.T) {
+	release := make(chan struct{})
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	fwd := NewSemaphoreForwarder(10, httpclient.Config{})
+	fwd.SetTenantConcurrencyLimit("X-Tenant-Id", 1)
+	fwd.Start()
+
+	noisyTenant := http.Header{"X-Tenant-Id": {"noisy"}}
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL, noisyTenant); err != nil {
+		t.Fatalf("expected first submit for noisy tenant to be admitted: %v", err)
+	}
+
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL, noisyTenant); err == nil {
+		t.Error("expected second submit for the same tenant to be rejected past its concurrency limit")
+	}
+
+	quietTenant := http.Header{"X-Tenant-Id": {"quiet"}}
+	if err := fwd.Submit(context.Background(), []byte("body"), target.URL, quietTenant); err != nil {
+		t.Errorf("expected another tenant to still be admitted despite the noisy tenant being saturated: %v", err)
+	}
+
+	close(release)
+	fwd.Stop(context.Background())
+}
+
+// fakeUpstreamClient is a deterministic httpclient.UpstreamClient double: it
+// returns respond/err without making any real network call, so tests can
+// exercise error/latency handling without an httptest server or a sleep.
+type fakeUpstreamClient struct {
+	respond func(req *http.Request) (*http.Response, error)
+	calls   int32
+}
+
+func (f *fakeUpstreamClient) Do(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&f.calls, 1)
+	return f.respond(req)
+}
+
+// TestSemaphoreForwarder_SaturationDoesNotSpawnUnboundedGoroutines verifies
+// that a burst of Submits far beyond maxConcurrent, against a stalled
+// collector, does not leave a waiting goroutine parked per rejected request -
+// only the maxConcurrent in-flight goroutines are ever running at once.
+func TestSemaphoreForwarder_SaturationDoesNotSpawnUnboundedGoroutines(t *testing.T) {
+	release := make(chan struct{})
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	const maxConcurrent = 2
+	fwd := NewSemaphoreForwarder(maxConcurrent, httpclient.Config{})
+	fwd.Start()
+
+	before := runtime.NumGoroutine()
+
+	var rejected int
+	for i := 0; i < 500; i++ {
+		if err := fwd.Submit(context.Background(), []byte("body"), target.URL, http.Header{}); err != nil {
+			rejected++
+		}
+	}
+	if rejected != 500-maxConcurrent {
+		t.Errorf("expected %d rejections beyond maxConcurrent, got %d", 500-maxConcurrent, rejected)
+	}
+
+	if after := runtime.NumGoroutine(); after > before+maxConcurrent+10 {
+		t.Errorf("expected goroutine count to stay bounded by maxConcurrent, went from %d to %d", before, after)
+	}
+
+	close(release)
+	fwd.Stop(context.Background())
+}
+
+// TestSemaphoreForwarder_SetHTTPClient_UsesInjectedClient verifies Submit
+// dispatches through a client set via SetHTTPClient instead of the real one
+// built in NewSemaphoreForwarder.
+func TestSemaphoreForwarder_SetHTTPClient_UsesInjectedClient(t *testing.T) {
+	fake := &fakeUpstreamClient{
+		respond: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		},
+	}
+
+	fwd := NewSemaphoreForwarder(2, httpclient.Config{})
+	fwd.SetHTTPClient(fake)
+	fwd.Start()
+
+	if err := fwd.Submit(context.Background(), []byte("body"), "http://collector.invalid", http.Header{}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	fwd.Stop(context.Background())
+
+	if got := atomic.LoadInt32(&fake.calls); got != 1 {
+		t.Errorf("expected the injected client to receive exactly 1 call, got %d", got)
+	}
+}
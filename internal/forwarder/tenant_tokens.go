@@ -0,0 +1,60 @@
+package forwarder
+
+import (
+    "net/http"
+    "sync"
+)
+
+// tenantFromHeader reads the tenant identifier a request is charged against
+// from headers via header, bucketing a missing value as "unknown" rather
+// than letting it bypass per-tenant limiting entirely
+func tenantFromHeader(headers http.Header, header string) string {
+    tenant := headers.Get(header)
+    if tenant == "" {
+        tenant = "unknown"
+    }
+    return tenant
+}
+
+// tenantTokenPool lazily creates a fixed-capacity token channel per tenant,
+// used by SemaphoreForwarder and HybridForwarder to try-acquire a per-tenant
+// concurrency slot in addition to their shared global one
+type tenantTokenPool struct {
+    mu       sync.Mutex
+    tokens   map[string]chan struct{}
+    capacity int
+}
+
+func newTenantTokenPool(capacity int) *tenantTokenPool {
+    return &tenantTokenPool{tokens: make(map[string]chan struct{}), capacity: capacity}
+}
+
+func (p *tenantTokenPool) tokensFor(tenant string) chan struct{} {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    tok, ok := p.tokens[tenant]
+    if !ok {
+        tok = make(chan struct{}, p.capacity)
+        p.tokens[tenant] = tok
+    }
+    return tok
+}
+
+// tryAcquire reserves one of tenant's slots, returning false if tenant is
+// already at capacity
+func (p *tenantTokenPool) tryAcquire(tenant string) bool {
+    select {
+    case p.tokensFor(tenant) <- struct{}{}:
+        return true
+    default:
+        return false
+    }
+}
+
+// release frees a slot reserved by a prior successful tryAcquire
+func (p *tenantTokenPool) release(tenant string) {
+    select {
+    case <-p.tokensFor(tenant):
+    default:
+    }
+}
@@ -0,0 +1,128 @@
+// Package gcpauth fetches Google-signed identity tokens from the GCE/Cloud
+// Run metadata server, for authenticating to a collector fronted by
+// Cloud Run's built-in IAM (which expects a Bearer ID token audienced to the
+// service's own URL, not a static API key). No cloud SDK is vendored; this
+// talks to the metadata server directly over plain HTTP, the same surface
+// google.golang.org/api/idtoken wraps.
+package gcpauth
+
+import (
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "strings"
+    "sync"
+    "time"
+)
+
+// metadataIdentityURL is the well-known metadata server endpoint that mints
+// an ID token for the instance/service's default service account. Only
+// reachable from within GCE/Cloud Run/GKE. A var (not a const) so tests can
+// point it at a fake server.
+var metadataIdentityURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/identity"
+
+// refreshMargin is how long before a cached token's expiry Token fetches a
+// replacement, so a request never races a token expiring mid-flight.
+const refreshMargin = 60 * time.Second
+
+// IDTokenSource fetches and caches a Google-signed ID token for audience,
+// refreshing it shortly before it expires. Safe for concurrent use.
+type IDTokenSource struct {
+    audience   string
+    httpClient *http.Client
+
+    mu        sync.Mutex
+    cached    string
+    expiresAt time.Time
+}
+
+// NewIDTokenSource creates an IDTokenSource that mints tokens audienced to
+// audience (typically the collector's own https:// URL, as Cloud Run IAM
+// expects). timeout bounds each metadata server request; <= 0 defaults to 2s.
+func NewIDTokenSource(audience string, timeout time.Duration) *IDTokenSource {
+    if timeout <= 0 {
+        timeout = 2 * time.Second
+    }
+    return &IDTokenSource{
+        audience:   audience,
+        httpClient: &http.Client{Timeout: timeout},
+    }
+}
+
+// AuthorizationHeader returns a "Bearer <id-token>" value suitable for the
+// outbound Authorization header, serving a cached token until refreshMargin
+// before its expiry and fetching a fresh one from the metadata server
+// otherwise.
+func (s *IDTokenSource) AuthorizationHeader(ctx context.Context) (string, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if s.cached != "" && time.Now().Before(s.expiresAt.Add(-refreshMargin)) {
+        return "Bearer " + s.cached, nil
+    }
+
+    token, expiresAt, err := s.fetch(ctx)
+    if err != nil {
+        return "", err
+    }
+    s.cached = token
+    s.expiresAt = expiresAt
+    return "Bearer " + token, nil
+}
+
+// fetch requests a fresh ID token from the metadata server and parses its
+// exp claim so the caller knows when to refresh next.
+func (s *IDTokenSource) fetch(ctx context.Context) (token string, expiresAt time.Time, err error) {
+    url := metadataIdentityURL + "?audience=" + s.audience + "&format=full"
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+    if err != nil {
+        return "", time.Time{}, fmt.Errorf("building metadata server request: %w", err)
+    }
+    req.Header.Set("Metadata-Flavor", "Google")
+
+    resp, err := s.httpClient.Do(req)
+    if err != nil {
+        return "", time.Time{}, fmt.Errorf("requesting ID token from metadata server: %w", err)
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(io.LimitReader(resp.Body, 16*1024))
+    if err != nil {
+        return "", time.Time{}, fmt.Errorf("reading metadata server response: %w", err)
+    }
+    if resp.StatusCode != http.StatusOK {
+        return "", time.Time{}, fmt.Errorf("metadata server returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+    }
+
+    token = strings.TrimSpace(string(body))
+    expiresAt, err = jwtExpiry(token)
+    if err != nil {
+        return "", time.Time{}, fmt.Errorf("parsing ID token expiry: %w", err)
+    }
+    return token, expiresAt, nil
+}
+
+// jwtExpiry decodes the unverified payload segment of a JWT and returns its
+// exp claim. The metadata server is a trusted local endpoint, so the token's
+// signature isn't (and can't cheaply be) verified here - the collector on
+// the receiving end is responsible for that.
+func jwtExpiry(token string) (time.Time, error) {
+    parts := strings.Split(token, ".")
+    if len(parts) != 3 {
+        return time.Time{}, fmt.Errorf("expected a 3-segment JWT, got %d segments", len(parts))
+    }
+    payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+    if err != nil {
+        return time.Time{}, fmt.Errorf("decoding JWT payload: %w", err)
+    }
+    var claims struct {
+        Exp int64 `json:"exp"`
+    }
+    if err := json.Unmarshal(payload, &claims); err != nil {
+        return time.Time{}, fmt.Errorf("unmarshalling JWT claims: %w", err)
+    }
+    return time.Unix(claims.Exp, 0), nil
+}
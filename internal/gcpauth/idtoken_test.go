@@ -0,0 +1,100 @@
+package gcpauth
+
+import (
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+// fakeIDToken builds an unsigned-but-well-formed JWT (header.payload.sig)
+// carrying exp, matching the shape the metadata server returns.
+func fakeIDToken(t *testing.T, exp time.Time) string {
+    t.Helper()
+    header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+    claims, err := json.Marshal(struct {
+        Exp int64 `json:"exp"`
+    }{Exp: exp.Unix()})
+    if err != nil {
+        t.Fatalf("marshalling claims: %v", err)
+    }
+    payload := base64.RawURLEncoding.EncodeToString(claims)
+    return header + "." + payload + ".fakesignature"
+}
+
+func withFakeMetadataServer(t *testing.T, handler http.HandlerFunc) {
+    t.Helper()
+    server := httptest.NewServer(handler)
+    t.Cleanup(server.Close)
+    original := metadataIdentityURL
+    metadataIdentityURL = server.URL
+    t.Cleanup(func() { metadataIdentityURL = original })
+}
+
+func TestIDTokenSource_AuthorizationHeader_FetchesAndCachesToken(t *testing.T) {
+    var requests int
+    withFakeMetadataServer(t, func(w http.ResponseWriter, r *http.Request) {
+        requests++
+        if r.Header.Get("Metadata-Flavor") != "Google" {
+            t.Errorf("expected Metadata-Flavor: Google header, got %q", r.Header.Get("Metadata-Flavor"))
+        }
+        fmt.Fprint(w, fakeIDToken(t, time.Now().Add(time.Hour)))
+    })
+
+    source := NewIDTokenSource("https://collector.example.com", time.Second)
+
+    header, err := source.AuthorizationHeader(context.Background())
+    if err != nil {
+        t.Fatalf("AuthorizationHeader returned error: %v", err)
+    }
+    if header[:7] != "Bearer " {
+        t.Errorf("expected header to start with %q, got %q", "Bearer ", header)
+    }
+
+    // A second call within the token's lifetime should reuse the cached
+    // token rather than hitting the metadata server again.
+    if _, err := source.AuthorizationHeader(context.Background()); err != nil {
+        t.Fatalf("second AuthorizationHeader call returned error: %v", err)
+    }
+    if requests != 1 {
+        t.Errorf("expected 1 metadata server request (cached on second call), got %d", requests)
+    }
+}
+
+func TestIDTokenSource_AuthorizationHeader_RefreshesNearExpiry(t *testing.T) {
+    var requests int
+    withFakeMetadataServer(t, func(w http.ResponseWriter, r *http.Request) {
+        requests++
+        // Already within refreshMargin of expiry, so every call should refetch.
+        fmt.Fprint(w, fakeIDToken(t, time.Now().Add(30*time.Second)))
+    })
+
+    source := NewIDTokenSource("https://collector.example.com", time.Second)
+
+    if _, err := source.AuthorizationHeader(context.Background()); err != nil {
+        t.Fatalf("first call returned error: %v", err)
+    }
+    if _, err := source.AuthorizationHeader(context.Background()); err != nil {
+        t.Fatalf("second call returned error: %v", err)
+    }
+    if requests != 2 {
+        t.Errorf("expected 2 metadata server requests (token near expiry each time), got %d", requests)
+    }
+}
+
+func TestIDTokenSource_AuthorizationHeader_PropagatesMetadataServerError(t *testing.T) {
+    withFakeMetadataServer(t, func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusForbidden)
+        fmt.Fprint(w, "service account not found")
+    })
+
+    source := NewIDTokenSource("https://collector.example.com", time.Second)
+
+    if _, err := source.AuthorizationHeader(context.Background()); err == nil {
+        t.Fatal("expected an error when the metadata server rejects the request")
+    }
+}
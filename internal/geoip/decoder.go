@@ -0,0 +1,222 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// MaxMind DB data-format type ids, per the public spec at
+// https://maxmind.github.io/MaxMind-DB/. Types 8+ are "extended": the base
+// control byte's type field is 0 and an extra byte carries type-7.
+const (
+	typePointer = 1
+	typeString  = 2
+	typeDouble  = 3
+	typeBytes   = 4
+	typeUint16  = 5
+	typeUint32  = 6
+	typeMap     = 7
+	typeInt32   = 8
+	typeUint64  = 9
+	typeUint128 = 10
+	typeArray   = 11
+	typeBoolean = 14
+	typeFloat   = 15
+)
+
+// decodeValue decodes one MaxMind DB data-format value starting at offset in
+// data, returning the decoded value (string, float64, []byte, uint64, int32,
+// bool, map[string]interface{}, or []interface{}) and the offset immediately
+// following it. dataSectionStart is the absolute offset of the data
+// section's first byte, used to resolve Pointer values; pass 0 when decoding
+// the metadata block, which never contains pointers.
+func decodeValue(data []byte, offset int, dataSectionStart int) (interface{}, int, error) {
+	if offset >= len(data) {
+		return nil, 0, fmt.Errorf("geoip: offset %d past end of data (%d bytes)", offset, len(data))
+	}
+	control := data[offset]
+	offset++
+
+	typ := int(control >> 5)
+	if typ == 0 {
+		if offset >= len(data) {
+			return nil, 0, fmt.Errorf("geoip: truncated extended type at offset %d", offset)
+		}
+		typ = int(data[offset]) + 7
+		offset++
+	}
+
+	if typ == typeBoolean {
+		// Boolean packs its value into the 5 size bits directly - there is
+		// no payload to read.
+		return control&0x1f != 0, offset, nil
+	}
+
+	if typ == typePointer {
+		return decodePointer(data, control, offset, dataSectionStart)
+	}
+
+	size, offset, err := decodeSize(data, control, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch typ {
+	case typeString:
+		if offset+size > len(data) {
+			return nil, 0, fmt.Errorf("geoip: truncated string at offset %d", offset)
+		}
+		return string(data[offset : offset+size]), offset + size, nil
+	case typeBytes:
+		if offset+size > len(data) {
+			return nil, 0, fmt.Errorf("geoip: truncated bytes at offset %d", offset)
+		}
+		return data[offset : offset+size : offset+size], offset + size, nil
+	case typeUint16, typeUint32, typeUint64:
+		v, next, err := decodeUint(data, offset, size)
+		return v, next, err
+	case typeUint128:
+		// Rare in country/city databases (used for wider network records);
+		// returned as raw bytes since we never need to do arithmetic on it.
+		if offset+size > len(data) {
+			return nil, 0, fmt.Errorf("geoip: truncated uint128 at offset %d", offset)
+		}
+		return data[offset : offset+size : offset+size], offset + size, nil
+	case typeInt32:
+		v, next, err := decodeUint(data, offset, size)
+		if err != nil {
+			return nil, 0, err
+		}
+		return int32(v), next, nil
+	case typeDouble:
+		if size != 8 || offset+8 > len(data) {
+			return nil, 0, fmt.Errorf("geoip: malformed double at offset %d", offset)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data[offset : offset+8])), offset + 8, nil
+	case typeFloat:
+		if size != 4 || offset+4 > len(data) {
+			return nil, 0, fmt.Errorf("geoip: malformed float at offset %d", offset)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data[offset : offset+4]))), offset + 4, nil
+	case typeArray:
+		values := make([]interface{}, 0, size)
+		for i := 0; i < size; i++ {
+			var v interface{}
+			var err error
+			v, offset, err = decodeValue(data, offset, dataSectionStart)
+			if err != nil {
+				return nil, 0, err
+			}
+			values = append(values, v)
+		}
+		return values, offset, nil
+	case typeMap:
+		m := make(map[string]interface{}, size)
+		for i := 0; i < size; i++ {
+			var key, value interface{}
+			var err error
+			key, offset, err = decodeValue(data, offset, dataSectionStart)
+			if err != nil {
+				return nil, 0, err
+			}
+			value, offset, err = decodeValue(data, offset, dataSectionStart)
+			if err != nil {
+				return nil, 0, err
+			}
+			keyStr, ok := key.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("geoip: map key at offset %d is not a string", offset)
+			}
+			m[keyStr] = value
+		}
+		return m, offset, nil
+	default:
+		return nil, 0, fmt.Errorf("geoip: unsupported data type %d at offset %d", typ, offset)
+	}
+}
+
+// decodeSize reads a data field's size, which is packed into the control
+// byte's low 5 bits and, for sizes too large to fit there, spills into 1-3
+// following bytes.
+func decodeSize(data []byte, control byte, offset int) (int, int, error) {
+	size := int(control & 0x1f)
+	switch {
+	case size < 29:
+		return size, offset, nil
+	case size == 29:
+		if offset >= len(data) {
+			return 0, 0, fmt.Errorf("geoip: truncated size at offset %d", offset)
+		}
+		return 29 + int(data[offset]), offset + 1, nil
+	case size == 30:
+		if offset+2 > len(data) {
+			return 0, 0, fmt.Errorf("geoip: truncated size at offset %d", offset)
+		}
+		return 285 + int(binary.BigEndian.Uint16(data[offset:offset+2])), offset + 2, nil
+	default:
+		if offset+3 > len(data) {
+			return 0, 0, fmt.Errorf("geoip: truncated size at offset %d", offset)
+		}
+		return 65821 + int(data[offset])<<16 + int(data[offset+1])<<8 + int(data[offset+2]), offset + 3, nil
+	}
+}
+
+// decodeUint reads a big-endian unsigned integer stored in size bytes (which
+// may be fewer than the type's full width - the data format omits leading
+// zero bytes).
+func decodeUint(data []byte, offset int, size int) (uint64, int, error) {
+	if offset+size > len(data) {
+		return 0, 0, fmt.Errorf("geoip: truncated integer at offset %d", offset)
+	}
+	var v uint64
+	for _, b := range data[offset : offset+size] {
+		v = v<<8 | uint64(b)
+	}
+	return v, offset + size, nil
+}
+
+// decodePointer resolves a Pointer value to the value it points at within
+// the data section. Pointer control bytes are laid out as 001SSVVV: SS (2
+// bits) selects one of four size classes, and VVV (3 bits) plus 0-3
+// following bytes carry the pointer value.
+func decodePointer(data []byte, control byte, offset int, dataSectionStart int) (interface{}, int, error) {
+	sizeClass := (control >> 3) & 0x3
+	valueBits := uint64(control & 0x7)
+
+	var pointer uint64
+	var next int
+	switch sizeClass {
+	case 0:
+		if offset+1 > len(data) {
+			return nil, 0, fmt.Errorf("geoip: truncated pointer at offset %d", offset)
+		}
+		pointer = valueBits<<8 | uint64(data[offset])
+		next = offset + 1
+	case 1:
+		if offset+2 > len(data) {
+			return nil, 0, fmt.Errorf("geoip: truncated pointer at offset %d", offset)
+		}
+		pointer = (valueBits<<16 | uint64(binary.BigEndian.Uint16(data[offset:offset+2]))) + 2048
+		next = offset + 2
+	case 2:
+		if offset+3 > len(data) {
+			return nil, 0, fmt.Errorf("geoip: truncated pointer at offset %d", offset)
+		}
+		pointer = (valueBits<<24 | uint64(data[offset])<<16 | uint64(data[offset+1])<<8 | uint64(data[offset+2])) + 526336
+		next = offset + 3
+	default:
+		if offset+4 > len(data) {
+			return nil, 0, fmt.Errorf("geoip: truncated pointer at offset %d", offset)
+		}
+		pointer = uint64(binary.BigEndian.Uint32(data[offset : offset+4]))
+		next = offset + 4
+	}
+
+	target := dataSectionStart + int(pointer)
+	value, _, err := decodeValue(data, target, dataSectionStart)
+	if err != nil {
+		return nil, 0, err
+	}
+	return value, next, nil
+}
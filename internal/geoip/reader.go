@@ -0,0 +1,215 @@
+// Package geoip resolves a client IP to an ISO country code using a local
+// MaxMind DB (MMDB) file, for country-based routing and blocking decisions.
+// The MMDB binary format (https://maxmind.github.io/MaxMind-DB/) is decoded
+// directly against the file bytes, avoiding a dependency on a third-party
+// GeoIP library.
+package geoip
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+)
+
+// metadataMarker precedes the metadata block at the end of every MMDB file
+var metadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// maxMetadataSearch bounds how far from the end of the file we search for
+// metadataMarker, matching the convention used by MaxMind's own readers
+const maxMetadataSearch = 128 * 1024
+
+// dataSectionSeparatorSize is the zero-filled gap between the search tree
+// and the data section
+const dataSectionSeparatorSize = 16
+
+// Reader looks up the country an IP address belongs to using an in-memory
+// MaxMind DB file. A Reader is safe for concurrent use, since Country only
+// reads its underlying byte slice.
+type Reader struct {
+	data             []byte
+	nodeCount        int
+	recordSize       int
+	ipVersion        int
+	searchTreeSize   int
+	dataSectionStart int
+}
+
+// Open reads and parses the MMDB file at path. The entire file is loaded
+// into memory; there is no background refresh, so a change to the file on
+// disk requires the process to restart to pick it up.
+func Open(path string) (*Reader, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("geoip: failed to read database: %w", err)
+	}
+
+	markerIndex := bytes.LastIndex(tail(data, maxMetadataSearch), metadataMarker)
+	if markerIndex == -1 {
+		return nil, fmt.Errorf("geoip: metadata marker not found, not a valid MMDB file")
+	}
+	// Re-anchor markerIndex against the full file if we searched a tail slice
+	markerIndex += len(data) - len(tail(data, maxMetadataSearch))
+
+	metadataStart := markerIndex + len(metadataMarker)
+	rawMetadata, _, err := decodeValue(data, metadataStart, 0)
+	if err != nil {
+		return nil, fmt.Errorf("geoip: failed to decode metadata: %w", err)
+	}
+	metadata, ok := rawMetadata.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("geoip: metadata block is not a map")
+	}
+
+	nodeCount, err := metadataUint(metadata, "node_count")
+	if err != nil {
+		return nil, err
+	}
+	recordSize, err := metadataUint(metadata, "record_size")
+	if err != nil {
+		return nil, err
+	}
+	ipVersion, err := metadataUint(metadata, "ip_version")
+	if err != nil {
+		return nil, err
+	}
+	if recordSize != 24 && recordSize != 28 && recordSize != 32 {
+		return nil, fmt.Errorf("geoip: unsupported record_size %d", recordSize)
+	}
+
+	searchTreeSize := int(recordSize) * 2 / 8 * int(nodeCount)
+	return &Reader{
+		data:             data,
+		nodeCount:        int(nodeCount),
+		recordSize:       int(recordSize),
+		ipVersion:        int(ipVersion),
+		searchTreeSize:   searchTreeSize,
+		dataSectionStart: searchTreeSize + dataSectionSeparatorSize,
+	}, nil
+}
+
+// tail returns the last n bytes of b, or all of b if it's shorter than n
+func tail(b []byte, n int) []byte {
+	if len(b) <= n {
+		return b
+	}
+	return b[len(b)-n:]
+}
+
+func metadataUint(metadata map[string]interface{}, key string) (uint64, error) {
+	switch v := metadata[key].(type) {
+	case uint64:
+		return v, nil
+	case int32:
+		return uint64(v), nil
+	}
+	return 0, fmt.Errorf("geoip: metadata field %q missing or not an integer", key)
+}
+
+// addressBits returns ip's on-disk representation for tree traversal: the
+// database's native width (4 or 16 bytes), with an IPv4 address queried
+// against an IPv6 database zero-padded into the ::/96 range, the de facto
+// convention used by MaxMind's own GeoLite2 builds.
+func (r *Reader) addressBits(ip net.IP) ([]byte, error) {
+	v4 := ip.To4()
+	if r.ipVersion == 4 {
+		if v4 == nil {
+			return nil, fmt.Errorf("geoip: database only supports IPv4, got IPv6 address %s", ip)
+		}
+		return v4, nil
+	}
+	if v4 != nil {
+		padded := make([]byte, 16)
+		copy(padded[12:], v4)
+		return padded, nil
+	}
+	v6 := ip.To16()
+	if v6 == nil {
+		return nil, fmt.Errorf("geoip: invalid IP address %v", ip)
+	}
+	return v6, nil
+}
+
+// readNode returns the search tree's left and right child records for node,
+// each either another node index or (once >= nodeCount) a pointer into the
+// data section.
+func (r *Reader) readNode(node int) (left, right uint, err error) {
+	bytesPerNode := r.recordSize * 2 / 8
+	start := node * bytesPerNode
+	if start+bytesPerNode > len(r.data) {
+		return 0, 0, fmt.Errorf("geoip: node %d out of bounds", node)
+	}
+	rec := r.data[start : start+bytesPerNode]
+
+	switch r.recordSize {
+	case 24:
+		left = uint(rec[0])<<16 | uint(rec[1])<<8 | uint(rec[2])
+		right = uint(rec[3])<<16 | uint(rec[4])<<8 | uint(rec[5])
+	case 28:
+		left = uint(rec[0])<<16 | uint(rec[1])<<8 | uint(rec[2]) | uint(rec[3]&0xf0)<<20
+		right = uint(rec[3]&0x0f)<<24 | uint(rec[4])<<16 | uint(rec[5])<<8 | uint(rec[6])
+	case 32:
+		left = uint(rec[0])<<24 | uint(rec[1])<<16 | uint(rec[2])<<8 | uint(rec[3])
+		right = uint(rec[4])<<24 | uint(rec[5])<<16 | uint(rec[6])<<8 | uint(rec[7])
+	}
+	return left, right, nil
+}
+
+// Lookup traverses the search tree for ip and decodes its associated data
+// record. A nil result (with no error) means ip matched no network in the
+// database.
+func (r *Reader) Lookup(ip net.IP) (interface{}, error) {
+	bits, err := r.addressBits(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	node := 0
+	for _, b := range bits {
+		for i := 7; i >= 0; i-- {
+			if node >= r.nodeCount {
+				break
+			}
+			left, right, err := r.readNode(node)
+			if err != nil {
+				return nil, err
+			}
+			record := left
+			if (b>>uint(i))&1 == 1 {
+				record = right
+			}
+			if int(record) == r.nodeCount {
+				return nil, nil // no match for this address
+			}
+			if int(record) > r.nodeCount {
+				value, _, err := decodeValue(r.data, r.searchTreeSize+int(record)-r.nodeCount, r.dataSectionStart)
+				if err != nil {
+					return nil, err
+				}
+				return value, nil
+			}
+			node = int(record)
+		}
+	}
+	return nil, nil
+}
+
+// Country returns the ISO 3166-1 alpha-2 country code ip resolves to, or ""
+// if the database has no data for it (a private/reserved range, or an
+// address the database's publisher hadn't attributed to a country).
+func (r *Reader) Country(ip net.IP) (string, error) {
+	value, err := r.Lookup(ip)
+	if err != nil || value == nil {
+		return "", err
+	}
+	record, ok := value.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	country, ok := record["country"].(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	iso, _ := country["iso_code"].(string)
+	return iso, nil
+}
@@ -0,0 +1,104 @@
+package geoip
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildTestMMDB assembles a minimal, valid MMDB file by hand: a single
+// search-tree node (record_size=24, ip_version=4) whose left child is "no
+// data" and whose right child points at a {"country": {"iso_code": "US"}}
+// record, followed by the metadata block real MMDB parsers locate by
+// scanning backward for metadataMarker.
+func buildTestMMDB(t *testing.T) string {
+	t.Helper()
+
+	// Data section: a map with 1 pair ("country" -> {"iso_code": "US"})
+	data := []byte{}
+	data = append(data, 0xE1)          // map, 1 pair
+	data = append(data, 0x47)          // string, length 7
+	data = append(data, "country"...)  // key
+	data = append(data, 0xE1)          // map, 1 pair
+	data = append(data, 0x48)          // string, length 8
+	data = append(data, "iso_code"...) // key
+	data = append(data, 0x42)          // string, length 2
+	data = append(data, "US"...)       // value
+
+	// Search tree: 1 node, record_size 24 (3 bytes per child).
+	// Left child = node_count (1): "no data" for addresses with a leading 0 bit.
+	// Right child = node_count + 16 + 0 = 17: pointer to offset 0 in the data section.
+	tree := []byte{0x00, 0x00, 0x01, 0x00, 0x00, 0x11}
+
+	separator := make([]byte, dataSectionSeparatorSize)
+
+	// Metadata: a map with node_count=1, record_size=24, ip_version=4
+	metadata := []byte{}
+	metadata = append(metadata, 0xE3)                                                        // map, 3 pairs
+	metadata = append(metadata, 0x4A, 'n', 'o', 'd', 'e', '_', 'c', 'o', 'u', 'n', 't')      // "node_count" (10)
+	metadata = append(metadata, 0xC1, 0x01)                                                  // uint32(1)
+	metadata = append(metadata, 0x4B, 'r', 'e', 'c', 'o', 'r', 'd', '_', 's', 'i', 'z', 'e') // "record_size" (11)
+	metadata = append(metadata, 0xA1, 0x18)                                                  // uint16(24)
+	metadata = append(metadata, 0x4A, 'i', 'p', '_', 'v', 'e', 'r', 's', 'i', 'o', 'n')      // "ip_version" (10)
+	metadata = append(metadata, 0xA1, 0x04)                                                  // uint16(4)
+
+	var file []byte
+	file = append(file, tree...)
+	file = append(file, separator...)
+	file = append(file, data...)
+	file = append(file, metadataMarker...)
+	file = append(file, metadata...)
+
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	if err := os.WriteFile(path, file, 0o644); err != nil {
+		t.Fatalf("failed to write test MMDB file: %v", err)
+	}
+	return path
+}
+
+func TestReader_Country_ReturnsMatchForRoutedAddress(t *testing.T) {
+	reader, err := Open(buildTestMMDB(t))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	country, err := reader.Country(net.ParseIP("128.0.0.1"))
+	if err != nil {
+		t.Fatalf("Country returned error: %v", err)
+	}
+	if country != "US" {
+		t.Errorf("expected country US, got %q", country)
+	}
+}
+
+func TestReader_Country_ReturnsEmptyForUnmatchedAddress(t *testing.T) {
+	reader, err := Open(buildTestMMDB(t))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	country, err := reader.Country(net.ParseIP("1.0.0.1"))
+	if err != nil {
+		t.Fatalf("Country returned error: %v", err)
+	}
+	if country != "" {
+		t.Errorf("expected no country match, got %q", country)
+	}
+}
+
+func TestReader_Open_RejectsMissingFile(t *testing.T) {
+	if _, err := Open(filepath.Join(t.TempDir(), "does-not-exist.mmdb")); err == nil {
+		t.Error("expected an error opening a nonexistent database")
+	}
+}
+
+func TestReader_Open_RejectsFileWithoutMetadataMarker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.mmdb")
+	if err := os.WriteFile(path, []byte("not an mmdb file"), 0o644); err != nil {
+		t.Fatalf("failed to write invalid file: %v", err)
+	}
+	if _, err := Open(path); err == nil {
+		t.Error("expected an error opening a file without a metadata marker")
+	}
+}
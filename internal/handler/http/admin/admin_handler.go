@@ -0,0 +1,246 @@
+package admin
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "os"
+    "path/filepath"
+    "runtime/pprof"
+    "strconv"
+    "time"
+
+    "github.com/labstack/echo/v4"
+
+    "zep-logger/internal/diagnostics"
+    "zep-logger/internal/forwarder"
+    "zep-logger/internal/ingesttoken"
+    "zep-logger/internal/jobtracking"
+    "zep-logger/internal/metrics"
+    "zep-logger/pkg/logger"
+)
+
+// AdminHandler handles operator-facing debug endpoints
+// These are intended for on-demand diagnostics in production, not for regular traffic
+type AdminHandler struct {
+    dumpDir             string
+    upstreamDiagnostics *diagnostics.Buffer         // Optional; nil if the active forwarding mode doesn't wire one up
+    jobTracker          *jobtracking.Tracker        // Optional; nil unless forwarding_mode = "pool" and job_tracking_enabled
+    tokenIssuer         *ingesttoken.Issuer         // Optional; nil unless ingest_token_issuance_enabled
+    adminAPIKey         string                      // Required in X-Admin-Api-Key on HandleIssueIngestToken when non-empty
+    loopback            *forwarder.LoopbackForwarder // Optional; nil unless forwarding_mode = "loopback"
+    fwd                 forwarder.Forwarder          // Optional; nil disables HandleFlush. The top of the forwarding decorator chain, so Flush drains batching/mirroring/etc. too, not just the innermost forwarder
+}
+
+
+// NewAdminHandler creates a new AdminHandler
+// dumpDir: directory where diagnostic dumps are written (created if missing)
+// upstreamDiagnostics: ring buffer of recent non-2xx collector responses queried by HandleUpstreamErrors (nil disables the endpoint)
+// jobTracker: bounded per-job delivery status queried by HandleJobStatus (nil disables the endpoint)
+// tokenIssuer: mints tenant/origin-bound tokens for HandleIssueIngestToken (nil disables the endpoint)
+// adminAPIKey: value HandleIssueIngestToken requires in X-Admin-Api-Key (empty disables the check)
+// loopback: recorded payloads queried by HandleLoopback (nil disables the endpoint)
+// fwd: the active forwarder, drained by HandleFlush (nil makes the endpoint a no-op)
+func NewAdminHandler(dumpDir string, upstreamDiagnostics *diagnostics.Buffer, jobTracker *jobtracking.Tracker, tokenIssuer *ingesttoken.Issuer, adminAPIKey string, loopback *forwarder.LoopbackForwarder, fwd forwarder.Forwarder) *AdminHandler {
+    return &AdminHandler{dumpDir: dumpDir, upstreamDiagnostics: upstreamDiagnostics, jobTracker: jobTracker, tokenIssuer: tokenIssuer, adminAPIKey: adminAPIKey, loopback: loopback, fwd: fwd}
+}
+
+// HandleGoroutineDump handles POST /admin/debug/goroutines
+// Writes a full goroutine dump (stacks for every goroutine) to a timestamped file
+// so stuck workers can be diagnosed without attaching a debugger in production
+func (h *AdminHandler) HandleGoroutineDump(c echo.Context) error {
+    if err := os.MkdirAll(h.dumpDir, 0o755); err != nil {
+        logger.Error("Goroutine dump: failed to create dump dir %s: %v", h.dumpDir, err)
+        return c.NoContent(http.StatusInternalServerError)
+    }
+
+    path := filepath.Join(h.dumpDir, fmt.Sprintf("goroutines-%s.dump", time.Now().UTC().Format("20060102T150405.000000000Z")))
+    f, err := os.Create(path)
+    if err != nil {
+        logger.Error("Goroutine dump: failed to create file %s: %v", path, err)
+        return c.NoContent(http.StatusInternalServerError)
+    }
+    defer f.Close()
+
+    // debug=2 includes full stack traces for every goroutine, matching a crash dump
+    if err := pprof.Lookup("goroutine").WriteTo(f, 2); err != nil {
+        logger.Error("Goroutine dump: failed to write profile: %v", err)
+        return c.NoContent(http.StatusInternalServerError)
+    }
+
+    logger.Info("Goroutine dump written to %s", path)
+    return c.JSON(http.StatusOK, map[string]string{"path": path})
+}
+
+// HandleUpstreamErrors handles GET /admin/debug/upstream-errors
+// Returns the recent non-2xx responses captured from the collector (status,
+// headers, and a truncated body snippet), oldest first, so a collector-side
+// rejection can be inspected without re-running production traffic
+func (h *AdminHandler) HandleUpstreamErrors(c echo.Context) error {
+    if h.upstreamDiagnostics == nil {
+        return c.JSON(http.StatusOK, map[string]interface{}{"entries": []diagnostics.Entry{}})
+    }
+    return c.JSON(http.StatusOK, map[string]interface{}{"entries": h.upstreamDiagnostics.Snapshot()})
+}
+
+// HandleLoopback handles GET /admin/debug/loopback
+// Returns payloads accepted by forwarding_mode = "loopback", oldest first, so
+// integration tests and demos can assert on what the proxy actually forwarded
+// without standing up a real collector. Empty when loopback mode isn't active.
+func (h *AdminHandler) HandleLoopback(c echo.Context) error {
+    if h.loopback == nil {
+        return c.JSON(http.StatusOK, map[string]interface{}{"entries": []forwarder.LoopbackEntry{}})
+    }
+    return c.JSON(http.StatusOK, map[string]interface{}{"entries": h.loopback.Snapshot()})
+}
+
+// HandleFlush handles POST /admin/debug/flush
+// Blocks until every job the active forwarder has already accepted has
+// finished delivery, so an operator can confirm the queue is drained (e.g.
+// before a maintenance window) without guessing at a sleep duration. No
+// forwarder configured makes this a no-op that returns immediately. Accepts
+// an optional timeout_seconds query param (default 30) bounding how long it
+// waits.
+func (h *AdminHandler) HandleFlush(c echo.Context) error {
+    if h.fwd == nil {
+        return c.JSON(http.StatusOK, map[string]string{"status": "no forwarder configured"})
+    }
+
+    timeoutSeconds := 30
+    if raw := c.QueryParam("timeout_seconds"); raw != "" {
+        if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+            timeoutSeconds = parsed
+        }
+    }
+
+    ctx, cancel := context.WithTimeout(c.Request().Context(), time.Duration(timeoutSeconds)*time.Second)
+    defer cancel()
+
+    if err := h.fwd.Flush(ctx); err != nil {
+        logger.Warn("Flush: forwarder did not report idle within %ds: %v", timeoutSeconds, err)
+        return c.JSON(http.StatusGatewayTimeout, map[string]string{"error": err.Error()})
+    }
+    return c.JSON(http.StatusOK, map[string]string{"status": "flushed"})
+}
+
+// HandleStatsStream handles GET /admin/stats/stream
+// Streams queue depth, delivery throughput (jobs/sec), and error rate over
+// Server-Sent Events, pushed once a second until the client disconnects, so
+// a lightweight live dashboard can watch a load test without waiting out
+// Prometheus's scrape interval. Throughput and error rate are derived from
+// the deltas of the process-wide JobsProcessedCounter/JobsFailedCounter
+// between ticks, not per-connection counters, so concurrent viewers all see
+// the same numbers.
+func (h *AdminHandler) HandleStatsStream(c echo.Context) error {
+    res := c.Response()
+    res.Header().Set("Content-Type", "text/event-stream")
+    res.Header().Set("Cache-Control", "no-cache")
+    res.Header().Set("Connection", "keep-alive")
+    res.WriteHeader(http.StatusOK)
+
+    ticker := time.NewTicker(1 * time.Second)
+    defer ticker.Stop()
+
+    lastProcessed := metrics.CounterValue(metrics.JobsProcessedCounter)
+    lastFailed := metrics.CounterValue(metrics.JobsFailedCounter)
+
+    for {
+        select {
+        case <-c.Request().Context().Done():
+            return nil
+        case <-ticker.C:
+            processed := metrics.CounterValue(metrics.JobsProcessedCounter)
+            failed := metrics.CounterValue(metrics.JobsFailedCounter)
+            deltaProcessed := processed - lastProcessed
+            deltaFailed := failed - lastFailed
+            lastProcessed = processed
+            lastFailed = failed
+
+            var errorRate float64
+            if total := deltaProcessed + deltaFailed; total > 0 {
+                errorRate = deltaFailed / total
+            }
+
+            queueDepth := 0
+            breakerOpen := false
+            if h.fwd != nil {
+                queueDepth = h.fwd.GetQueueDepth()
+                if br, ok := h.fwd.(forwarder.BreakerReporter); ok {
+                    breakerOpen = br.IsOpen()
+                }
+            }
+
+            payload, err := json.Marshal(map[string]interface{}{
+                "queue_depth":  queueDepth,
+                "rps":          deltaProcessed,
+                "error_rate":   errorRate,
+                "breaker_open": breakerOpen,
+            })
+            if err != nil {
+                continue
+            }
+            if _, err := fmt.Fprintf(res, "data: %s\n\n", payload); err != nil {
+                return nil
+            }
+            res.Flush()
+        }
+    }
+}
+
+// HandleJobStatus handles GET /admin/debug/jobs/:id
+// Returns the tracked delivery status (queued/sent/failed) of the job
+// identified by the X-Job-Id value returned from a prior async Submit, so an
+// operator can confirm whether a specific payload actually reached the
+// collector without re-running production traffic
+func (h *AdminHandler) HandleJobStatus(c echo.Context) error {
+    if h.jobTracker == nil {
+        return c.JSON(http.StatusNotFound, map[string]string{"error": "job tracking is not enabled"})
+    }
+    record, ok := h.jobTracker.Get(c.Param("id"))
+    if !ok {
+        return c.JSON(http.StatusNotFound, map[string]string{"error": "job not found"})
+    }
+    return c.JSON(http.StatusOK, record)
+}
+
+// issueIngestTokenRequest is the JSON body of POST /admin/ingest-tokens
+type issueIngestTokenRequest struct {
+    Tenant string `json:"tenant"`
+    Origin string `json:"origin"`
+}
+
+// issueIngestTokenResponse is the JSON response of POST /admin/ingest-tokens
+type issueIngestTokenResponse struct {
+    Token     string    `json:"token"`
+    ExpiresAt time.Time `json:"expires_at"`
+}
+
+// HandleIssueIngestToken handles POST /admin/ingest-tokens
+// Mints a short-lived token bound to the requested tenant and origin, so a
+// browser can present it on /v1/* instead of a long-lived collector key
+// baked into its bundle. Requires the configured admin API key in
+// X-Admin-Api-Key when one is set.
+func (h *AdminHandler) HandleIssueIngestToken(c echo.Context) error {
+    if h.tokenIssuer == nil {
+        return c.JSON(http.StatusNotFound, map[string]string{"error": "ingest token issuance is not enabled"})
+    }
+    if h.adminAPIKey != "" && c.Request().Header.Get("X-Admin-Api-Key") != h.adminAPIKey {
+        return c.NoContent(http.StatusUnauthorized)
+    }
+
+    var req issueIngestTokenRequest
+    if err := c.Bind(&req); err != nil {
+        return c.NoContent(http.StatusBadRequest)
+    }
+    if req.Tenant == "" || req.Origin == "" {
+        return c.JSON(http.StatusBadRequest, map[string]string{"error": "tenant and origin are required"})
+    }
+
+    token, expiresAt, err := h.tokenIssuer.Issue(req.Tenant, req.Origin)
+    if err != nil {
+        logger.Error("Failed to issue ingest token for tenant %s: %v", req.Tenant, err)
+        return c.NoContent(http.StatusInternalServerError)
+    }
+    return c.JSON(http.StatusOK, issueIngestTokenResponse{Token: token, ExpiresAt: expiresAt})
+}
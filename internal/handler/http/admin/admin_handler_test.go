@@ -0,0 +1,472 @@
+package admin
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/labstack/echo/v4"
+
+    "zep-logger/internal/diagnostics"
+    "zep-logger/internal/forwarder"
+    "zep-logger/internal/ingesttoken"
+    "zep-logger/internal/jobtracking"
+)
+
+// TestAdminHandler_GoroutineDump_WritesFile verifies the dump file is created with content
+func TestAdminHandler_GoroutineDump_WritesFile(t *testing.T) {
+    dumpDir := filepath.Join(t.TempDir(), "dumps")
+    handler := NewAdminHandler(dumpDir, nil, nil, nil, "", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodPost, "/admin/debug/goroutines", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleGoroutineDump(c); err != nil {
+        t.Fatalf("HandleGoroutineDump returned error: %v", err)
+    }
+
+    if rec.Code != http.StatusOK {
+        t.Errorf("expected status 200 OK, got %d", rec.Code)
+    }
+
+    entries, err := os.ReadDir(dumpDir)
+    if err != nil {
+        t.Fatalf("failed to read dump dir: %v", err)
+    }
+    if len(entries) != 1 {
+        t.Fatalf("expected exactly 1 dump file, got %d", len(entries))
+    }
+
+    data, err := os.ReadFile(filepath.Join(dumpDir, entries[0].Name()))
+    if err != nil {
+        t.Fatalf("failed to read dump file: %v", err)
+    }
+    if len(data) == 0 {
+        t.Error("expected non-empty goroutine dump")
+    }
+}
+
+// TestAdminHandler_UpstreamErrors_ReturnsEmptyWhenDiagnosticsDisabled verifies
+// the endpoint degrades gracefully when no diagnostics buffer is configured
+func TestAdminHandler_UpstreamErrors_ReturnsEmptyWhenDiagnosticsDisabled(t *testing.T) {
+    handler := NewAdminHandler(t.TempDir(), nil, nil, nil, "", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodGet, "/admin/debug/upstream-errors", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleUpstreamErrors(c); err != nil {
+        t.Fatalf("HandleUpstreamErrors returned error: %v", err)
+    }
+
+    if rec.Code != http.StatusOK {
+        t.Errorf("expected status 200 OK, got %d", rec.Code)
+    }
+    if !strings.Contains(rec.Body.String(), `"entries":[]`) {
+        t.Errorf("expected empty entries list, got %s", rec.Body.String())
+    }
+}
+
+// TestAdminHandler_Loopback_ReturnsEmptyWhenDisabled verifies the endpoint
+// degrades gracefully when the active forwarding mode isn't "loopback"
+func TestAdminHandler_Loopback_ReturnsEmptyWhenDisabled(t *testing.T) {
+    handler := NewAdminHandler(t.TempDir(), nil, nil, nil, "", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodGet, "/admin/debug/loopback", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleLoopback(c); err != nil {
+        t.Fatalf("HandleLoopback returned error: %v", err)
+    }
+    if rec.Code != http.StatusOK {
+        t.Errorf("expected status 200 OK, got %d", rec.Code)
+    }
+    if !strings.Contains(rec.Body.String(), `"entries":[]`) {
+        t.Errorf("expected empty entries list, got %s", rec.Body.String())
+    }
+}
+
+// TestAdminHandler_Loopback_ReturnsRecordedEntries verifies payloads accepted
+// by the loopback forwarder are surfaced through the endpoint
+func TestAdminHandler_Loopback_ReturnsRecordedEntries(t *testing.T) {
+    lb := forwarder.NewLoopbackForwarder(10, nil)
+    lb.Submit(nil, []byte("hello"), "http://loopback/v1/logs", nil)
+    handler := NewAdminHandler(t.TempDir(), nil, nil, nil, "", lb, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodGet, "/admin/debug/loopback", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleLoopback(c); err != nil {
+        t.Fatalf("HandleLoopback returned error: %v", err)
+    }
+    if rec.Code != http.StatusOK {
+        t.Errorf("expected status 200 OK, got %d", rec.Code)
+    }
+    if !strings.Contains(rec.Body.String(), "hello") {
+        t.Errorf("expected recorded payload in response, got %s", rec.Body.String())
+    }
+}
+
+// TestAdminHandler_UpstreamErrors_ReturnsRecordedEntries verifies recorded
+// upstream errors are surfaced through the endpoint
+func TestAdminHandler_UpstreamErrors_ReturnsRecordedEntries(t *testing.T) {
+    buf := diagnostics.NewBuffer(10)
+    buf.Record("http://collector/v1/logs", 502, nil, []byte("bad gateway"))
+    handler := NewAdminHandler(t.TempDir(), buf, nil, nil, "", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodGet, "/admin/debug/upstream-errors", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleUpstreamErrors(c); err != nil {
+        t.Fatalf("HandleUpstreamErrors returned error: %v", err)
+    }
+
+    if rec.Code != http.StatusOK {
+        t.Errorf("expected status 200 OK, got %d", rec.Code)
+    }
+    if !strings.Contains(rec.Body.String(), "bad gateway") {
+        t.Errorf("expected recorded entry body in response, got %s", rec.Body.String())
+    }
+}
+
+// TestAdminHandler_JobStatus_ReturnsNotFoundWhenTrackingDisabled verifies the
+// endpoint degrades gracefully when no job tracker is configured
+func TestAdminHandler_JobStatus_ReturnsNotFoundWhenTrackingDisabled(t *testing.T) {
+    handler := NewAdminHandler(t.TempDir(), nil, nil, nil, "", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodGet, "/admin/debug/jobs/job-1", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+    c.SetParamNames("id")
+    c.SetParamValues("job-1")
+
+    if err := handler.HandleJobStatus(c); err != nil {
+        t.Fatalf("HandleJobStatus returned error: %v", err)
+    }
+    if rec.Code != http.StatusNotFound {
+        t.Errorf("expected status 404 Not Found, got %d", rec.Code)
+    }
+}
+
+// TestAdminHandler_JobStatus_ReturnsTrackedRecord verifies a tracked job's
+// status is surfaced through the endpoint
+func TestAdminHandler_JobStatus_ReturnsTrackedRecord(t *testing.T) {
+    tracker := jobtracking.NewTracker(10)
+    tracker.Track("job-1", "http://collector/v1/logs")
+    tracker.MarkSent("job-1")
+    handler := NewAdminHandler(t.TempDir(), nil, tracker, nil, "", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodGet, "/admin/debug/jobs/job-1", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+    c.SetParamNames("id")
+    c.SetParamValues("job-1")
+
+    if err := handler.HandleJobStatus(c); err != nil {
+        t.Fatalf("HandleJobStatus returned error: %v", err)
+    }
+    if rec.Code != http.StatusOK {
+        t.Errorf("expected status 200 OK, got %d", rec.Code)
+    }
+    if !strings.Contains(rec.Body.String(), `"sent"`) {
+        t.Errorf("expected tracked status in response, got %s", rec.Body.String())
+    }
+}
+
+// TestAdminHandler_JobStatus_ReturnsNotFoundForUnknownID verifies an id that
+// was never tracked (or has since been evicted) reports 404 rather than an
+// empty record
+func TestAdminHandler_JobStatus_ReturnsNotFoundForUnknownID(t *testing.T) {
+    tracker := jobtracking.NewTracker(10)
+    handler := NewAdminHandler(t.TempDir(), nil, tracker, nil, "", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodGet, "/admin/debug/jobs/does-not-exist", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+    c.SetParamNames("id")
+    c.SetParamValues("does-not-exist")
+
+    if err := handler.HandleJobStatus(c); err != nil {
+        t.Fatalf("HandleJobStatus returned error: %v", err)
+    }
+    if rec.Code != http.StatusNotFound {
+        t.Errorf("expected status 404 Not Found, got %d", rec.Code)
+    }
+}
+
+// flushForwarder is a minimal forwarder.Forwarder double with a
+// controllable Flush result and queue depth, for testing HandleFlush and
+// HandleStatsStream without a real forwarding mode.
+type flushForwarder struct {
+    flushErr   error
+    queueDepth int
+}
+
+func (f *flushForwarder) Start()             {}
+func (f *flushForwarder) Stop(ctx context.Context) error { return nil }
+func (f *flushForwarder) GetQueueDepth() int { return f.queueDepth }
+func (f *flushForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+    return nil
+}
+func (f *flushForwarder) Flush(ctx context.Context) error { return f.flushErr }
+
+// TestAdminHandler_Flush_NoOpWhenNoForwarderConfigured verifies the endpoint
+// degrades gracefully instead of panicking when no forwarder is wired up
+func TestAdminHandler_Flush_NoOpWhenNoForwarderConfigured(t *testing.T) {
+    handler := NewAdminHandler(t.TempDir(), nil, nil, nil, "", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodPost, "/admin/debug/flush", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleFlush(c); err != nil {
+        t.Fatalf("HandleFlush returned error: %v", err)
+    }
+    if rec.Code != http.StatusOK {
+        t.Errorf("expected status 200 OK, got %d", rec.Code)
+    }
+}
+
+// TestAdminHandler_Flush_ReturnsOKForForwarderWithNothingInFlight verifies
+// the endpoint returns immediately for a forwarder with no in-flight work
+// to wait on (e.g. LoopbackForwarder)
+func TestAdminHandler_Flush_ReturnsOKForForwarderWithNothingInFlight(t *testing.T) {
+    lb := forwarder.NewLoopbackForwarder(10, nil)
+    handler := NewAdminHandler(t.TempDir(), nil, nil, nil, "", nil, lb)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodPost, "/admin/debug/flush", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleFlush(c); err != nil {
+        t.Fatalf("HandleFlush returned error: %v", err)
+    }
+    if rec.Code != http.StatusOK {
+        t.Errorf("expected status 200 OK, got %d", rec.Code)
+    }
+}
+
+// TestAdminHandler_Flush_ReturnsOKWhenForwarderReportsIdle verifies the
+// success path calls through to the forwarder's Flush and returns 200
+func TestAdminHandler_Flush_ReturnsOKWhenForwarderReportsIdle(t *testing.T) {
+    handler := NewAdminHandler(t.TempDir(), nil, nil, nil, "", nil, &flushForwarder{})
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodPost, "/admin/debug/flush", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleFlush(c); err != nil {
+        t.Fatalf("HandleFlush returned error: %v", err)
+    }
+    if rec.Code != http.StatusOK {
+        t.Errorf("expected status 200 OK, got %d", rec.Code)
+    }
+}
+
+// TestAdminHandler_Flush_ReturnsGatewayTimeoutWhenForwarderNeverIdles
+// verifies a forwarder that never reports idle within the deadline surfaces
+// as 504, not a hang
+func TestAdminHandler_Flush_ReturnsGatewayTimeoutWhenForwarderNeverIdles(t *testing.T) {
+    handler := NewAdminHandler(t.TempDir(), nil, nil, nil, "", nil, &flushForwarder{flushErr: context.DeadlineExceeded})
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodPost, "/admin/debug/flush?timeout_seconds=1", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleFlush(c); err != nil {
+        t.Fatalf("HandleFlush returned error: %v", err)
+    }
+    if rec.Code != http.StatusGatewayTimeout {
+        t.Errorf("expected status 504 Gateway Timeout, got %d", rec.Code)
+    }
+}
+
+// TestAdminHandler_IssueIngestToken_ReturnsNotFoundWhenIssuanceDisabled
+// verifies the endpoint reports 404 rather than issuing a token when no
+// tokenIssuer is configured
+func TestAdminHandler_IssueIngestToken_ReturnsNotFoundWhenIssuanceDisabled(t *testing.T) {
+    handler := NewAdminHandler(t.TempDir(), nil, nil, nil, "", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodPost, "/admin/ingest-tokens", strings.NewReader(`{"tenant":"acme","origin":"https://acme.example.com"}`))
+    req.Header.Set("Content-Type", "application/json")
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleIssueIngestToken(c); err != nil {
+        t.Fatalf("HandleIssueIngestToken returned error: %v", err)
+    }
+    if rec.Code != http.StatusNotFound {
+        t.Errorf("expected status 404 Not Found, got %d", rec.Code)
+    }
+}
+
+// TestAdminHandler_IssueIngestToken_RequiresConfiguredAdminAPIKey verifies a
+// request missing/mismatching X-Admin-Api-Key is rejected when one is configured
+func TestAdminHandler_IssueIngestToken_RequiresConfiguredAdminAPIKey(t *testing.T) {
+    handler := NewAdminHandler(t.TempDir(), nil, nil, ingesttoken.NewIssuer("secret", time.Minute), "correct-key", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodPost, "/admin/ingest-tokens", strings.NewReader(`{"tenant":"acme","origin":"https://acme.example.com"}`))
+    req.Header.Set("Content-Type", "application/json")
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleIssueIngestToken(c); err != nil {
+        t.Fatalf("HandleIssueIngestToken returned error: %v", err)
+    }
+    if rec.Code != http.StatusUnauthorized {
+        t.Errorf("expected status 401 Unauthorized, got %d", rec.Code)
+    }
+}
+
+// TestAdminHandler_IssueIngestToken_IssuesTokenBoundToRequestedTenant
+// verifies a properly authorized request returns a token verifiable against
+// the same issuer, bound to the requested tenant/origin
+func TestAdminHandler_IssueIngestToken_IssuesTokenBoundToRequestedTenant(t *testing.T) {
+    issuer := ingesttoken.NewIssuer("secret", time.Minute)
+    handler := NewAdminHandler(t.TempDir(), nil, nil, issuer, "correct-key", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodPost, "/admin/ingest-tokens", strings.NewReader(`{"tenant":"acme","origin":"https://acme.example.com"}`))
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("X-Admin-Api-Key", "correct-key")
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleIssueIngestToken(c); err != nil {
+        t.Fatalf("HandleIssueIngestToken returned error: %v", err)
+    }
+    if rec.Code != http.StatusOK {
+        t.Fatalf("expected status 200 OK, got %d: %s", rec.Code, rec.Body.String())
+    }
+
+    var resp issueIngestTokenResponse
+    if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+        t.Fatalf("failed to unmarshal response: %v", err)
+    }
+    claims, err := issuer.Verify(resp.Token)
+    if err != nil {
+        t.Fatalf("issued token failed verification: %v", err)
+    }
+    if claims.Tenant != "acme" || claims.Origin != "https://acme.example.com" {
+        t.Errorf("unexpected claims: %+v", claims)
+    }
+}
+
+// TestAdminHandler_IssueIngestToken_RejectsMissingTenant verifies a request
+// missing tenant/origin is rejected before minting a token
+func TestAdminHandler_IssueIngestToken_RejectsMissingTenant(t *testing.T) {
+    handler := NewAdminHandler(t.TempDir(), nil, nil, ingesttoken.NewIssuer("secret", time.Minute), "", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodPost, "/admin/ingest-tokens", strings.NewReader(`{"origin":"https://acme.example.com"}`))
+    req.Header.Set("Content-Type", "application/json")
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleIssueIngestToken(c); err != nil {
+        t.Fatalf("HandleIssueIngestToken returned error: %v", err)
+    }
+    if rec.Code != http.StatusBadRequest {
+        t.Errorf("expected status 400 Bad Request, got %d", rec.Code)
+    }
+}
+
+// TestAdminHandler_StatsStream_ReturnsPromptlyWhenClientDisconnects verifies
+// the handler sets SSE headers and returns as soon as the request context is
+// done, instead of blocking forever waiting for the next tick
+func TestAdminHandler_StatsStream_ReturnsPromptlyWhenClientDisconnects(t *testing.T) {
+    handler := NewAdminHandler(t.TempDir(), nil, nil, nil, "", nil, &flushForwarder{queueDepth: 5})
+
+    e := echo.New()
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel() // already disconnected before the handler even starts its loop
+    req := httptest.NewRequest(http.MethodGet, "/admin/stats/stream", nil).WithContext(ctx)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    done := make(chan error, 1)
+    go func() { done <- handler.HandleStatsStream(c) }()
+
+    select {
+    case err := <-done:
+        if err != nil {
+            t.Fatalf("HandleStatsStream returned error: %v", err)
+        }
+    case <-time.After(2 * time.Second):
+        t.Fatal("expected HandleStatsStream to return promptly once the client context is done")
+    }
+
+    if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+        t.Errorf("expected Content-Type text/event-stream, got %q", got)
+    }
+}
+
+// TestAdminHandler_StatsStream_EmitsQueueDepth verifies at least one SSE tick
+// reports the active forwarder's current queue depth
+func TestAdminHandler_StatsStream_EmitsQueueDepth(t *testing.T) {
+    handler := NewAdminHandler(t.TempDir(), nil, nil, nil, "", nil, &flushForwarder{queueDepth: 5})
+
+    e := echo.New()
+    ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+    defer cancel()
+    req := httptest.NewRequest(http.MethodGet, "/admin/stats/stream", nil).WithContext(ctx)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleStatsStream(c); err != nil {
+        t.Fatalf("HandleStatsStream returned error: %v", err)
+    }
+
+    if !strings.Contains(rec.Body.String(), `"queue_depth":5`) {
+        t.Errorf("expected at least one tick reporting queue_depth 5, got body %q", rec.Body.String())
+    }
+}
+
+// TestAdminHandler_UI_ReturnsHTML verifies GET /admin/ui serves the embedded
+// status page rather than a JSON error, since it has no optional dependency
+// to disable it
+func TestAdminHandler_UI_ReturnsHTML(t *testing.T) {
+    handler := NewAdminHandler(t.TempDir(), nil, nil, nil, "", nil, nil)
+
+    e := echo.New()
+    req := httptest.NewRequest(http.MethodGet, "/admin/ui", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+
+    if err := handler.HandleUI(c); err != nil {
+        t.Fatalf("HandleUI returned error: %v", err)
+    }
+    if rec.Code != http.StatusOK {
+        t.Errorf("expected status 200 OK, got %d", rec.Code)
+    }
+    if ct := rec.Header().Get("Content-Type"); !strings.Contains(ct, "text/html") {
+        t.Errorf("expected Content-Type text/html, got %q", ct)
+    }
+    if !strings.Contains(rec.Body.String(), "admin/stats/stream") {
+        t.Errorf("expected the page to reference the stats stream endpoint, got %s", rec.Body.String())
+    }
+}
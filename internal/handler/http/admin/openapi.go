@@ -0,0 +1,88 @@
+package admin
+
+import (
+    "net/http"
+
+    "github.com/labstack/echo/v4"
+)
+
+// openAPISpecJSON is a hand-maintained OpenAPI 3.0 description of the
+// public-facing endpoints (the OTLP ingest routes and the health/admin
+// endpoints operators script against). It's a plain string constant rather
+// than generated from struct tags, matching statusPageHTML's no-build-step
+// approach - keep it in sync by hand when routes change.
+const openAPISpecJSON = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "otlpxy",
+    "description": "OTLP ingest proxy: forwards logs and traces to an upstream collector with batching, rate limiting, and load shedding.",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/v1/logs": {
+      "post": {
+        "summary": "Ingest OTLP logs",
+        "responses": {
+          "200": { "description": "Accepted (sync mode) or queued (async mode)" },
+          "429": { "description": "Rejected for queue/rate/quota pressure; see Retry-After. Status code is configurable via overload_status_code (default 429)" },
+          "503": { "description": "Rejected for queue/rate/quota pressure when overload_status_code=503, or the proxy is not ready" }
+        }
+      }
+    },
+    "/v1/traces": {
+      "post": {
+        "summary": "Ingest OTLP traces",
+        "responses": {
+          "200": { "description": "Accepted (sync mode) or queued (async mode)" },
+          "429": { "description": "Rejected for queue/rate/quota pressure; see Retry-After. Status code is configurable via overload_status_code (default 429)" },
+          "503": { "description": "Rejected for queue/rate/quota pressure when overload_status_code=503, or the proxy is not ready" }
+        }
+      }
+    },
+    "/v1/challenge": {
+      "get": {
+        "summary": "Issue a proof-of-work challenge for ingest clients",
+        "responses": { "200": { "description": "Challenge issued" } }
+      }
+    },
+    "/healthz": {
+      "get": {
+        "summary": "Liveness probe",
+        "responses": { "200": { "description": "Process is alive" } }
+      }
+    },
+    "/healthz/details": {
+      "get": {
+        "summary": "Per-check readiness details",
+        "responses": { "200": { "description": "JSON breakdown of each registered readiness check" } }
+      }
+    },
+    "/readyz": {
+      "get": {
+        "summary": "Readiness probe",
+        "responses": {
+          "200": { "description": "Ready to accept traffic" },
+          "503": { "description": "Not ready: a registered check failed, or readiness has been explicitly disabled" }
+        }
+      }
+    },
+    "/admin/ui": {
+      "get": {
+        "summary": "Operator status dashboard",
+        "responses": { "200": { "description": "HTML status page" } }
+      }
+    },
+    "/admin/openapi.json": {
+      "get": {
+        "summary": "This document",
+        "responses": { "200": { "description": "OpenAPI 3.0 spec" } }
+      }
+    }
+  }
+}
+`
+
+// HandleOpenAPI serves the OpenAPI spec for this proxy's public endpoints.
+func (h *AdminHandler) HandleOpenAPI(c echo.Context) error {
+    return c.Blob(http.StatusOK, "application/json", []byte(openAPISpecJSON))
+}
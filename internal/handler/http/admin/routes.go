@@ -0,0 +1,19 @@
+package admin
+
+import (
+    "github.com/labstack/echo/v4"
+)
+
+// SetupRoutes registers admin/debug routes with the Echo instance
+// Follows separated routes pattern - route registration separate from handler logic
+func (h *AdminHandler) SetupRoutes(e *echo.Echo) {
+    e.POST("/admin/debug/goroutines", h.HandleGoroutineDump)
+    e.GET("/admin/debug/upstream-errors", h.HandleUpstreamErrors)
+    e.GET("/admin/debug/jobs/:id", h.HandleJobStatus)
+    e.GET("/admin/debug/loopback", h.HandleLoopback)
+    e.POST("/admin/debug/flush", h.HandleFlush)
+    e.GET("/admin/stats/stream", h.HandleStatsStream)
+    e.GET("/admin/ui", h.HandleUI)
+    e.GET("/admin/openapi.json", h.HandleOpenAPI)
+    e.POST("/admin/ingest-tokens", h.HandleIssueIngestToken)
+}
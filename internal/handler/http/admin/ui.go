@@ -0,0 +1,84 @@
+package admin
+
+import (
+    "net/http"
+
+    "github.com/labstack/echo/v4"
+)
+
+// statusPageHTML is a self-contained operator dashboard: no build step, no
+// static assets directory, just a string served as-is. It pulls its data
+// from the JSON/SSE endpoints this same handler already exposes
+// (/admin/stats/stream, /admin/debug/upstream-errors), so it stays accurate
+// without any server-side templating.
+const statusPageHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>otlpxy status</title>
+<style>
+body { font-family: monospace; background: #111; color: #ddd; margin: 2rem; }
+h1 { font-size: 1.2rem; }
+.stats { display: flex; gap: 2rem; margin-bottom: 1.5rem; }
+.stat { background: #1c1c1c; padding: 1rem; border-radius: 4px; min-width: 8rem; }
+.stat .label { color: #888; font-size: 0.8rem; }
+.stat .value { font-size: 1.5rem; }
+.open { color: #f55; }
+.closed { color: #5f5; }
+table { border-collapse: collapse; width: 100%; }
+td, th { border-bottom: 1px solid #333; padding: 0.4rem; text-align: left; font-size: 0.85rem; }
+</style>
+</head>
+<body>
+<h1>otlpxy status</h1>
+<div class="stats">
+  <div class="stat"><div class="label">queue depth</div><div class="value" id="queue_depth">-</div></div>
+  <div class="stat"><div class="label">jobs/sec</div><div class="value" id="rps">-</div></div>
+  <div class="stat"><div class="label">error rate</div><div class="value" id="error_rate">-</div></div>
+  <div class="stat"><div class="label">breaker</div><div class="value" id="breaker_open">-</div></div>
+</div>
+<h2>recent upstream errors</h2>
+<table id="errors"><thead><tr><th>time</th><th>status</th><th>body</th></tr></thead><tbody></tbody></table>
+<script>
+var stream = new EventSource("/admin/stats/stream");
+stream.onmessage = function(evt) {
+  var stats = JSON.parse(evt.data);
+  document.getElementById("queue_depth").textContent = stats.queue_depth;
+  document.getElementById("rps").textContent = stats.rps;
+  document.getElementById("error_rate").textContent = (stats.error_rate * 100).toFixed(1) + "%";
+  var breaker = document.getElementById("breaker_open");
+  breaker.textContent = stats.breaker_open ? "open" : "closed";
+  breaker.className = "value " + (stats.breaker_open ? "open" : "closed");
+};
+
+function refreshErrors() {
+  fetch("/admin/debug/upstream-errors").then(function(res) { return res.json(); }).then(function(data) {
+    var tbody = document.querySelector("#errors tbody");
+    tbody.innerHTML = "";
+    (data.entries || []).forEach(function(entry) {
+      var row = document.createElement("tr");
+      [entry.Time, entry.StatusCode, entry.Body].forEach(function(value) {
+        var td = document.createElement("td");
+        td.textContent = value;
+        row.appendChild(td);
+      });
+      tbody.appendChild(row);
+    });
+  });
+}
+refreshErrors();
+setInterval(refreshErrors, 5000);
+</script>
+</body>
+</html>
+`
+
+// HandleUI handles GET /admin/ui
+// Serves a tiny embedded HTML status page showing live throughput, queue
+// depth, breaker state, and recent upstream errors, for operators at
+// customer sites without Grafana access. The page itself is static; it
+// pulls live data client-side from /admin/stats/stream and
+// /admin/debug/upstream-errors.
+func (h *AdminHandler) HandleUI(c echo.Context) error {
+    return c.HTMLBlob(http.StatusOK, []byte(statusPageHTML))
+}
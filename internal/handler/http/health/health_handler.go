@@ -2,15 +2,22 @@ package health
 
 import (
 	"net/http"
+	"sync"
 
 	"github.com/labstack/echo/v4"
 	"go.uber.org/atomic"
 )
 
+// Checker reports the health of a single dependency (queue depth, spool disk,
+// upstream collector, circuit breaker, ...). A nil return means healthy
+type Checker func() error
+
 // HealthHandler handles health check endpoints for Kubernetes probes
 // Follows constructor injection pattern - no global state
 type HealthHandler struct {
 	readiness *atomic.Bool
+	checksMu  sync.RWMutex
+	checks    map[string]Checker
 }
 
 // NewHealthHandler creates a new HealthHandler with dependency injection
@@ -18,9 +25,33 @@ type HealthHandler struct {
 func NewHealthHandler(readiness *atomic.Bool) *HealthHandler {
 	return &HealthHandler{
 		readiness: readiness,
+		checks:    make(map[string]Checker),
 	}
 }
 
+// RegisterCheck adds a named Checker that HandleReadiness and
+// HandleReadinessDetails consult in addition to the readiness flag. Intended
+// to be called during app startup (e.g. for queue depth, spool disk, upstream
+// collector, or circuit breaker checks), not while serving traffic
+func (h *HealthHandler) RegisterCheck(name string, check Checker) {
+	h.checksMu.Lock()
+	defer h.checksMu.Unlock()
+	h.checks[name] = check
+}
+
+// runChecks evaluates every registered Checker and returns each name's error
+// (nil for healthy)
+func (h *HealthHandler) runChecks() map[string]error {
+	h.checksMu.RLock()
+	defer h.checksMu.RUnlock()
+
+	results := make(map[string]error, len(h.checks))
+	for name, check := range h.checks {
+		results[name] = check()
+	}
+	return results
+}
+
 // HandleLiveness handles GET /healthz - liveness probe
 // Always returns 200 OK to indicate the container is alive
 // Used by Kubernetes to detect if the container needs to be restarted
@@ -29,11 +60,48 @@ func (h *HealthHandler) HandleLiveness(c echo.Context) error {
 }
 
 // HandleReadiness handles GET /readyz - readiness probe
-// Returns 200 OK when ready to accept traffic, 503 when not ready
+// Returns 200 OK when ready to accept traffic and every registered check
+// passes, 503 when not ready or any check is failing
 // Used by Kubernetes to manage traffic routing during deployments
 func (h *HealthHandler) HandleReadiness(c echo.Context) error {
-	if h.readiness.Load() {
-		return c.NoContent(http.StatusOK)
+	if !h.readiness.Load() {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+
+	for _, err := range h.runChecks() {
+		if err != nil {
+			return c.NoContent(http.StatusServiceUnavailable)
+		}
 	}
-	return c.NoContent(http.StatusServiceUnavailable)
+
+	return c.NoContent(http.StatusOK)
+}
+
+// HandleReadinessDetails handles GET /healthz/details - per-check breakdown
+// Reports the readiness flag plus each registered check's individual status,
+// so an operator can see which dependency is failing instead of just an
+// aggregate 503
+func (h *HealthHandler) HandleReadinessDetails(c echo.Context) error {
+	checkResults := h.runChecks()
+
+	details := make(map[string]string, len(checkResults))
+	healthy := h.readiness.Load()
+	for name, err := range checkResults {
+		if err != nil {
+			details[name] = err.Error()
+			healthy = false
+		} else {
+			details[name] = "ok"
+		}
+	}
+
+	status := http.StatusOK
+	if !healthy {
+		status = http.StatusServiceUnavailable
+	}
+
+	return c.JSON(status, map[string]interface{}{
+		"ready":  h.readiness.Load(),
+		"checks": details,
+	})
 }
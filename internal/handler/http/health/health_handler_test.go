@@ -1,8 +1,10 @@
 package health
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
@@ -184,6 +186,80 @@ func TestHealthHandler_ConcurrentReadinessChecks(t *testing.T) {
 	}
 }
 
+// TestHealthHandler_Readiness_FailingCheckReturns503 verifies that a failing
+// registered Checker fails readiness even though the flag itself is true
+func TestHealthHandler_Readiness_FailingCheckReturns503(t *testing.T) {
+	readiness := atomic.NewBool(true)
+	handler := NewHealthHandler(readiness)
+	handler.RegisterCheck("upstream", func() error { return errors.New("upstream unreachable") })
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleReadiness(c); err != nil {
+		t.Fatalf("HandleReadiness returned error: %v", err)
+	}
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 when a check fails, got %d", rec.Code)
+	}
+}
+
+// TestHealthHandler_Readiness_PassingChecksReturn200 verifies that readiness
+// succeeds when the flag is true and every registered Checker passes
+func TestHealthHandler_Readiness_PassingChecksReturn200(t *testing.T) {
+	readiness := atomic.NewBool(true)
+	handler := NewHealthHandler(readiness)
+	handler.RegisterCheck("queue", func() error { return nil })
+	handler.RegisterCheck("spool_disk", func() error { return nil })
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleReadiness(c); err != nil {
+		t.Fatalf("HandleReadiness returned error: %v", err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 when all checks pass, got %d", rec.Code)
+	}
+}
+
+// TestHealthHandler_ReadinessDetails_ReportsPerCheckStatus verifies that
+// /healthz/details surfaces each check's individual status rather than just
+// an aggregate code
+func TestHealthHandler_ReadinessDetails_ReportsPerCheckStatus(t *testing.T) {
+	readiness := atomic.NewBool(true)
+	handler := NewHealthHandler(readiness)
+	handler.RegisterCheck("queue", func() error { return nil })
+	handler.RegisterCheck("upstream", func() error { return errors.New("upstream unreachable") })
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/healthz/details", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleReadinessDetails(c); err != nil {
+		t.Fatalf("HandleReadinessDetails returned error: %v", err)
+	}
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 when a check fails, got %d", rec.Code)
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `"queue":"ok"`) {
+		t.Errorf("expected details body to report queue as ok, got %s", body)
+	}
+	if !strings.Contains(body, `"upstream":"upstream unreachable"`) {
+		t.Errorf("expected details body to report the upstream check's error, got %s", body)
+	}
+}
+
 // TestHealthHandler_SetupRoutes verifies route registration
 func TestHealthHandler_SetupRoutes(t *testing.T) {
 	readiness := atomic.NewBool(true)
@@ -8,5 +8,6 @@ import (
 // Follows separated routes pattern - route registration separate from handler logic
 func (h *HealthHandler) SetupRoutes(e *echo.Echo) {
 	e.GET("/healthz", h.HandleLiveness)
+	e.GET("/healthz/details", h.HandleReadinessDetails)
 	e.GET("/readyz", h.HandleReadiness)
 }
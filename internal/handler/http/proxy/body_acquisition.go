@@ -0,0 +1,193 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/labstack/echo/v4"
+
+	"zep-logger/internal/bufpool"
+	"zep-logger/internal/metrics"
+	"zep-logger/pkg/logger"
+)
+
+// bodyContextKey is the echo.Context key the acquired body is cached under
+const bodyContextKey = "otlpxy_request_body"
+
+// errDecompressedTooLarge is returned by the decompress* helpers once the
+// stream would exceed maxDecompressedBytes, distinguishing a zip-bomb-style
+// rejection from an actually malformed/corrupt stream
+var errDecompressedTooLarge = errors.New("decompressed body exceeds configured limit")
+
+// BodyAcquisitionMiddleware reads and, if gzip-, zstd-, or deflate-encoded,
+// decompresses the request body exactly once for ingest requests, then
+// caches the result on the echo.Context and replaces c.Request().Body with a
+// fresh reader over it. This is the single body-acquisition point for OTLP
+// ingest: validation, resource-attribute rewriting, usage accounting, and
+// forwarding all read the same cached bytes instead of each racing to
+// consume the original stream, which would silently empty it for whichever
+// reader runs second.
+//
+// When largePayloadThresholdBytes > 0 and the declared Content-Length exceeds
+// it, the body is spooled to a temp file under largePayloadTempDir instead of
+// growing an in-memory buffer during the read, capping the peak heap used by
+// the read itself during replay-heavy traffic with many large bodies
+// in flight at once. Everything downstream still operates on the resulting
+// []byte, since decode/rewrite/forwarding are not yet stream-capable.
+//
+// maxDecompressedBytes caps the decompressed size of an encoded body,
+// independent of the declared Content-Length limit enforced upstream by
+// middleware.BodyLimit on the compressed bytes on the wire: a small
+// compressed payload can still decode to a much larger stream (a zip bomb),
+// and BodyLimit alone can't see past the encoding to catch that. 0 disables
+// the cap (bodies decompress to whatever size they decode to, the original
+// behavior).
+func BodyAcquisitionMiddleware(largePayloadThresholdBytes int, largePayloadTempDir string, maxDecompressedBytes int) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Body == nil || req.Method != http.MethodPost {
+				return next(c)
+			}
+
+			var raw []byte
+			if largePayloadThresholdBytes > 0 && req.ContentLength > int64(largePayloadThresholdBytes) {
+				spooled, err := spoolToDisk(req.Body, largePayloadTempDir)
+				if err != nil {
+					logger.Error("Failed to spool large request body to disk: %v", err)
+					return c.NoContent(http.StatusBadRequest)
+				}
+				raw = spooled
+				metrics.LargePayloadsOffloadedCounter.Inc()
+			} else {
+				direct, err := bufpool.ReadAll(req.Body)
+				if err != nil {
+					logger.Error("Failed to read request body: %v", err)
+					return c.NoContent(http.StatusBadRequest)
+				}
+				raw = direct
+			}
+
+			body := raw
+			encoding := req.Header.Get("Content-Encoding")
+			var decompressed []byte
+			var err error
+			switch encoding {
+			case "", "identity":
+				// no decompression needed
+			case "gzip":
+				decompressed, err = gunzip(raw, maxDecompressedBytes)
+			case "zstd":
+				decompressed, err = unzstd(raw, maxDecompressedBytes)
+			case "deflate":
+				decompressed, err = inflate(raw, maxDecompressedBytes)
+			default:
+				logger.Warn("Rejecting request with unsupported Content-Encoding %q", encoding)
+				return c.NoContent(http.StatusUnsupportedMediaType)
+			}
+			if encoding != "" && encoding != "identity" {
+				if errors.Is(err, errDecompressedTooLarge) {
+					logger.Warn("Rejecting %s request body: decompressed size exceeds the configured limit (%d bytes)", encoding, maxDecompressedBytes)
+					return c.NoContent(http.StatusRequestEntityTooLarge)
+				}
+				if err != nil {
+					logger.Error("Failed to decompress %s request body: %v", encoding, err)
+					return c.NoContent(http.StatusBadRequest)
+				}
+				body = decompressed
+				req.Header.Del("Content-Encoding")
+				req.ContentLength = int64(len(body))
+			}
+
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			c.Set(bodyContextKey, body)
+
+			return next(c)
+		}
+	}
+}
+
+// spoolToDisk streams r into a temp file under dir (OS default temp dir if
+// empty), then reads it back and removes the file. The temp file exists only
+// to bound the memory growth of the read itself; callers still get a []byte.
+func spoolToDisk(r io.Reader, dir string) ([]byte, error) {
+	f, err := os.CreateTemp(dir, "otlpxy-body-*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(path)
+}
+
+// gunzip decompresses a gzip-encoded byte slice, capped at maxBytes decompressed (0 = uncapped)
+func gunzip(raw []byte, maxBytes int) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return readAllCapped(r, maxBytes)
+}
+
+// unzstd decompresses a zstd-encoded byte slice, capped at maxBytes decompressed (0 = uncapped)
+func unzstd(raw []byte, maxBytes int) ([]byte, error) {
+	r, err := zstd.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return readAllCapped(r, maxBytes)
+}
+
+// inflate decompresses a raw deflate-encoded (RFC 1951) byte slice, capped at
+// maxBytes decompressed (0 = uncapped)
+func inflate(raw []byte, maxBytes int) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(raw))
+	defer r.Close()
+	return readAllCapped(r, maxBytes)
+}
+
+// readAllCapped reads r fully, failing with errDecompressedTooLarge instead
+// of growing an unbounded buffer once the stream exceeds maxBytes (0 =
+// uncapped); this is what keeps a small, highly-compressible payload (a zip
+// bomb) from ballooning to whatever size it decodes to.
+func readAllCapped(r io.Reader, maxBytes int) ([]byte, error) {
+	if maxBytes <= 0 {
+		return bufpool.ReadAll(r)
+	}
+	limited := io.LimitReader(r, int64(maxBytes)+1)
+	data, err := io.ReadAll(limited)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxBytes {
+		return nil, errDecompressedTooLarge
+	}
+	return data, nil
+}
+
+// acquireBody returns the body cached by BodyAcquisitionMiddleware, falling
+// back to a direct read (e.g. in tests that call handlers without the
+// middleware chain) so callers never need to know which path was taken.
+func acquireBody(c echo.Context) ([]byte, error) {
+	if cached, ok := c.Get(bodyContextKey).([]byte); ok {
+		return cached, nil
+	}
+	return io.ReadAll(c.Request().Body)
+}
@@ -0,0 +1,273 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"zep-logger/internal/metrics"
+)
+
+// TestBodyAcquisitionMiddleware_CachesBodyForDownstreamHandler verifies the
+// handler observes the same bytes the middleware read, via both the cached
+// context value and a fresh c.Request().Body
+func TestBodyAcquisitionMiddleware_CachesBodyForDownstreamHandler(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader([]byte("payload")))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var fromContext, fromBody []byte
+	handler := BodyAcquisitionMiddleware(0, "", 0)(func(c echo.Context) error {
+		var err error
+		fromContext, err = acquireBody(c)
+		if err != nil {
+			return err
+		}
+		fromBody, err = io.ReadAll(c.Request().Body)
+		return err
+	})
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if string(fromContext) != "payload" {
+		t.Errorf("expected cached body %q, got %q", "payload", fromContext)
+	}
+	if string(fromBody) != "payload" {
+		t.Errorf("expected re-readable c.Request().Body %q, got %q", "payload", fromBody)
+	}
+}
+
+// TestBodyAcquisitionMiddleware_DecompressesGzipBody verifies gzip-encoded
+// bodies are decompressed once and Content-Encoding is stripped
+func TestBodyAcquisitionMiddleware_DecompressesGzipBody(t *testing.T) {
+	var compressed bytes.Buffer
+	gw := gzip.NewWriter(&compressed)
+	if _, err := gw.Write([]byte("uncompressed-payload")); err != nil {
+		t.Fatalf("failed to write gzip fixture: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("failed to close gzip writer: %v", err)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader(compressed.Bytes()))
+	req.Header.Set("Content-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var got []byte
+	handler := BodyAcquisitionMiddleware(0, "", 0)(func(c echo.Context) error {
+		var err error
+		got, err = acquireBody(c)
+		return err
+	})
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if string(got) != "uncompressed-payload" {
+		t.Errorf("expected decompressed body %q, got %q", "uncompressed-payload", got)
+	}
+	if enc := c.Request().Header.Get("Content-Encoding"); enc != "" {
+		t.Errorf("expected Content-Encoding to be stripped after decompression, got %q", enc)
+	}
+}
+
+// TestBodyAcquisitionMiddleware_DecompressesZstdBody verifies zstd-encoded
+// bodies are decompressed once and Content-Encoding is stripped
+func TestBodyAcquisitionMiddleware_DecompressesZstdBody(t *testing.T) {
+	zw, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatalf("failed to create zstd writer: %v", err)
+	}
+	compressed := zw.EncodeAll([]byte("uncompressed-payload"), nil)
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zstd writer: %v", err)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader(compressed))
+	req.Header.Set("Content-Encoding", "zstd")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var got []byte
+	handler := BodyAcquisitionMiddleware(0, "", 0)(func(c echo.Context) error {
+		var err error
+		got, err = acquireBody(c)
+		return err
+	})
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if string(got) != "uncompressed-payload" {
+		t.Errorf("expected decompressed body %q, got %q", "uncompressed-payload", got)
+	}
+	if enc := c.Request().Header.Get("Content-Encoding"); enc != "" {
+		t.Errorf("expected Content-Encoding to be stripped after decompression, got %q", enc)
+	}
+}
+
+// TestBodyAcquisitionMiddleware_DecompressesDeflateBody verifies deflate
+// (RFC 1951)-encoded bodies are decompressed once and Content-Encoding is stripped
+func TestBodyAcquisitionMiddleware_DecompressesDeflateBody(t *testing.T) {
+	var compressed bytes.Buffer
+	fw, err := flate.NewWriter(&compressed, flate.DefaultCompression)
+	if err != nil {
+		t.Fatalf("failed to create flate writer: %v", err)
+	}
+	if _, err := fw.Write([]byte("uncompressed-payload")); err != nil {
+		t.Fatalf("failed to write deflate fixture: %v", err)
+	}
+	if err := fw.Close(); err != nil {
+		t.Fatalf("failed to close flate writer: %v", err)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader(compressed.Bytes()))
+	req.Header.Set("Content-Encoding", "deflate")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var got []byte
+	handler := BodyAcquisitionMiddleware(0, "", 0)(func(c echo.Context) error {
+		var err error
+		got, err = acquireBody(c)
+		return err
+	})
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if string(got) != "uncompressed-payload" {
+		t.Errorf("expected decompressed body %q, got %q", "uncompressed-payload", got)
+	}
+	if enc := c.Request().Header.Get("Content-Encoding"); enc != "" {
+		t.Errorf("expected Content-Encoding to be stripped after decompression, got %q", enc)
+	}
+}
+
+// TestBodyAcquisitionMiddleware_RejectsOversizedDecompressedBodyWith413 verifies
+// a small compressed body that decodes past maxDecompressedBytes is rejected
+// with 413 instead of being fully decompressed into memory
+func TestBodyAcquisitionMiddleware_RejectsOversizedDecompressedBodyWith413(t *testing.T) {
+	var compressed bytes.Buffer
+	gw := gzip.NewWriter(&compressed)
+	if _, err := gw.Write(bytes.Repeat([]byte("a"), 10000)); err != nil {
+		t.Fatalf("failed to write gzip fixture: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("failed to close gzip writer: %v", err)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader(compressed.Bytes()))
+	req.Header.Set("Content-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	nextCalled := false
+	handler := BodyAcquisitionMiddleware(0, "", 100)(func(c echo.Context) error {
+		nextCalled = true
+		return c.NoContent(http.StatusOK)
+	})
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if nextCalled {
+		t.Error("expected the request to be rejected before reaching the next handler")
+	}
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413 Request Entity Too Large, got %d", rec.Code)
+	}
+}
+
+// TestBodyAcquisitionMiddleware_RejectsUnsupportedContentEncodingWith415
+// verifies an encoding this middleware doesn't know how to decompress is
+// rejected outright instead of silently forwarding a body that downstream
+// readers can't decode
+func TestBodyAcquisitionMiddleware_RejectsUnsupportedContentEncodingWith415(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader([]byte("payload")))
+	req.Header.Set("Content-Encoding", "br")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	nextCalled := false
+	handler := BodyAcquisitionMiddleware(0, "", 0)(func(c echo.Context) error {
+		nextCalled = true
+		return c.NoContent(http.StatusOK)
+	})
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if nextCalled {
+		t.Error("expected the request to be rejected before reaching the next handler")
+	}
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status 415 Unsupported Media Type, got %d", rec.Code)
+	}
+}
+
+// TestBodyAcquisitionMiddleware_OffloadsLargeBodyToDisk verifies a body whose
+// declared Content-Length exceeds the configured threshold is spooled to disk
+// and read back byte-for-byte, and that the offload counter is incremented
+func TestBodyAcquisitionMiddleware_OffloadsLargeBodyToDisk(t *testing.T) {
+	payload := bytes.Repeat([]byte("x"), 128)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader(payload))
+	req.ContentLength = int64(len(payload))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	before := testutil.ToFloat64(metrics.LargePayloadsOffloadedCounter)
+
+	var got []byte
+	handler := BodyAcquisitionMiddleware(64, "", 0)(func(c echo.Context) error {
+		var err error
+		got, err = acquireBody(c)
+		return err
+	})
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if string(got) != string(payload) {
+		t.Errorf("expected spooled body to round-trip, got %d bytes want %d bytes", len(got), len(payload))
+	}
+	after := testutil.ToFloat64(metrics.LargePayloadsOffloadedCounter)
+	if after != before+1 {
+		t.Errorf("expected LargePayloadsOffloadedCounter to increment by 1, got %v -> %v", before, after)
+	}
+}
+
+// TestAcquireBody_FallsBackWithoutMiddleware verifies handlers invoked without
+// the middleware chain (as in existing handler tests) still read the body
+func TestAcquireBody_FallsBackWithoutMiddleware(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader([]byte("direct-read")))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	got, err := acquireBody(c)
+	if err != nil {
+		t.Fatalf("acquireBody returned error: %v", err)
+	}
+	if string(got) != "direct-read" {
+		t.Errorf("expected fallback body %q, got %q", "direct-read", got)
+	}
+}
@@ -0,0 +1,48 @@
+package proxy
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+
+	"zep-logger/pkg/logger"
+)
+
+// ByteRateLimitMiddleware caps total ingest bytes/sec across every route it's
+// attached to using a token bucket sized on the decompressed request body,
+// rejecting the excess with 429 and a Retry-After hint instead of letting a
+// traffic spike amplify unbounded downstream. limiter is shared across
+// routes since the limit is global rather than per-route; nil disables the
+// middleware entirely (the original behavior). Must run after
+// BodyAcquisitionMiddleware so the body is already decompressed and cached.
+func ByteRateLimitMiddleware(limiter *rate.Limiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if limiter == nil {
+				return next(c)
+			}
+			body, err := acquireBody(c)
+			if err != nil {
+				return err
+			}
+
+			reservation := limiter.ReserveN(time.Now(), len(body))
+			delay := reservation.Delay()
+			if !reservation.OK() || delay > 0 {
+				retryAfter := 1
+				if reservation.OK() {
+					retryAfter = int(delay.Seconds()) + 1
+				}
+				reservation.Cancel()
+				logger.Warn("Ingest byte rate limit exceeded: rejecting %d-byte request, retry after %ds", len(body), retryAfter)
+				c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				return c.NoContent(http.StatusTooManyRequests)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package proxy
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+// TestByteRateLimitMiddleware_AdmitsRequestWithinBudget verifies a request
+// within the token bucket's burst capacity is passed through unmodified
+func TestByteRateLimitMiddleware_AdmitsRequestWithinBudget(t *testing.T) {
+	limiter := rate.NewLimiter(rate.Limit(1000), 1000)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader([]byte("small-payload")))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	nextCalled := false
+	handler := BodyAcquisitionMiddleware(0, "", 0)(ByteRateLimitMiddleware(limiter)(func(c echo.Context) error {
+		nextCalled = true
+		return c.NoContent(http.StatusAccepted)
+	}))
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if !nextCalled {
+		t.Error("expected the request to be admitted and reach the next handler")
+	}
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("expected status 202 Accepted, got %d", rec.Code)
+	}
+}
+
+// TestByteRateLimitMiddleware_RejectsRequestBeyondBurstWith429 verifies a
+// request whose size alone exceeds the bucket's burst capacity is rejected
+// with 429 and a Retry-After header, without reaching the next handler
+func TestByteRateLimitMiddleware_RejectsRequestBeyondBurstWith429(t *testing.T) {
+	limiter := rate.NewLimiter(rate.Limit(10), 10)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader(bytes.Repeat([]byte("x"), 100)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	nextCalled := false
+	handler := BodyAcquisitionMiddleware(0, "", 0)(ByteRateLimitMiddleware(limiter)(func(c echo.Context) error {
+		nextCalled = true
+		return c.NoContent(http.StatusAccepted)
+	}))
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if nextCalled {
+		t.Error("expected the request to be rejected before reaching the next handler")
+	}
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status 429 Too Many Requests, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header on the 429 response")
+	}
+}
+
+// TestByteRateLimitMiddleware_NilLimiterIsNoOp verifies a nil limiter (the
+// default, disabled state) never rejects a request
+func TestByteRateLimitMiddleware_NilLimiterIsNoOp(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader(bytes.Repeat([]byte("x"), 10000)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	nextCalled := false
+	handler := ByteRateLimitMiddleware(nil)(func(c echo.Context) error {
+		nextCalled = true
+		return c.NoContent(http.StatusAccepted)
+	})
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if !nextCalled {
+		t.Error("expected a nil limiter to always admit the request")
+	}
+}
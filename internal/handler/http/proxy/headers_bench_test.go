@@ -0,0 +1,67 @@
+package proxy
+
+import (
+	"context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/labstack/echo/v4"
+
+    "zep-logger/internal/forwarder"
+    "zep-logger/internal/httpclient"
+    "zep-logger/internal/transform"
+    "zep-logger/internal/worker"
+)
+
+func BenchmarkBuildForwardHeaders(b *testing.B) {
+    src := http.Header{
+        "Content-Type":    {"application/x-protobuf"},
+        "User-Agent":      {"otel-sdk/1.0"},
+        "X-Tenant-Id":     {"tenant-a"},
+        "Accept":          {"*/*"},
+        "Accept-Encoding": {"gzip"},
+        "Cookie":          {"session=abc123"},
+    }
+
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        _ = buildForwardHeaders(src, "application/x-protobuf", "test-api-key")
+    }
+}
+
+// BenchmarkProxyHandler_HandleTraces exercises the full async hot path
+// (HandleTraces -> handleAsync -> buildForwardHeaders -> forwarder.Submit)
+// against a worker pool forwarder whose HTTP client talks to a no-op mock
+// collector, so allocations are attributable to proxy code rather than a
+// real network round trip.
+func BenchmarkProxyHandler_HandleTraces(b *testing.B) {
+    mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer mockCollector.Close()
+
+    pool := worker.NewPool(4, 10000, 5*time.Second, httpclient.Config{})
+    pf := forwarder.NewPoolForwarder(pool)
+    pf.Start()
+    defer pf.Stop(context.Background())
+
+    handler := NewProxyHandler(context.Background(), mockCollector.URL, "test-api-key", pf, pf, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+    e := echo.New()
+    body := strings.Repeat("x", 256)
+
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader(body))
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        if err := handler.HandleTraces(c); err != nil {
+            b.Fatalf("HandleTraces returned error: %v", err)
+        }
+    }
+}
@@ -0,0 +1,85 @@
+package proxy
+
+import (
+    "net/http"
+    "testing"
+)
+
+func TestBuildForwardHeaders_DropsHostAndHopByHopHeaders(t *testing.T) {
+    src := http.Header{
+        "Host":              {"example.com"},
+        "Connection":        {"keep-alive"},
+        "X-Custom":          {"a", "b"},
+        "Content-Type":      {"application/json"},
+    }
+
+    got := buildForwardHeaders(src, "application/x-protobuf", "")
+
+    if _, ok := got["Host"]; ok {
+        t.Error("expected Host header to be dropped")
+    }
+    if _, ok := got["Connection"]; ok {
+        t.Error("expected hop-by-hop Connection header to be dropped")
+    }
+    if v := got.Values("X-Custom"); len(v) != 2 || v[0] != "a" || v[1] != "b" {
+        t.Errorf("expected X-Custom to round-trip as [a b], got %v", v)
+    }
+    if ct := got.Get("Content-Type"); ct != "application/json" {
+        t.Errorf("expected existing Content-Type to be preserved, got %q", ct)
+    }
+}
+
+func TestBuildForwardHeaders_DefaultsContentTypeWhenMissing(t *testing.T) {
+    src := http.Header{"X-Custom": {"a"}}
+
+    got := buildForwardHeaders(src, "application/x-protobuf", "")
+
+    if ct := got.Get("Content-Type"); ct != "application/x-protobuf" {
+        t.Errorf("expected default Content-Type, got %q", ct)
+    }
+}
+
+func TestBuildForwardHeaders_OverridesAuthorizationWithAPIKey(t *testing.T) {
+    src := http.Header{"Authorization": {"Bearer client-supplied"}}
+
+    got := buildForwardHeaders(src, "application/x-protobuf", "server-api-key")
+
+    if auth := got.Get("Authorization"); auth != "server-api-key" {
+        t.Errorf("expected Authorization to be overridden with the configured API key, got %q", auth)
+    }
+}
+
+func TestBuildForwardHeaders_LeavesAuthorizationUnsetWhenAPIKeyEmpty(t *testing.T) {
+    src := http.Header{}
+
+    got := buildForwardHeaders(src, "application/x-protobuf", "")
+
+    if got.Get("Authorization") != "" {
+        t.Errorf("expected no Authorization header, got %q", got.Get("Authorization"))
+    }
+}
+
+// TestBuildForwardHeaders_AllocsPerRunBudget enforces an allocs/op budget for
+// the header-cloning hot path, since a plain Benchmark only reports
+// allocations without failing anything. Header value slices are reused
+// directly from src rather than copied, so the remaining allocations are the
+// returned map, its bucket growth, and the Set/Get canonicalization calls for
+// Content-Type/Authorization. Budget is set from the measured baseline plus a
+// small margin, so a regression that adds a new per-header allocation trips it.
+func TestBuildForwardHeaders_AllocsPerRunBudget(t *testing.T) {
+    src := http.Header{
+        "Content-Type":   {"application/x-protobuf"},
+        "User-Agent":     {"otel-sdk/1.0"},
+        "X-Tenant-Id":    {"tenant-a"},
+        "Accept":         {"*/*"},
+        "Accept-Encoding": {"gzip"},
+    }
+
+    const budget = 10.0
+    avg := testing.AllocsPerRun(100, func() {
+        _ = buildForwardHeaders(src, "application/x-protobuf", "test-api-key")
+    })
+    if avg > budget {
+        t.Errorf("buildForwardHeaders allocated %.1f allocs/op, exceeds budget of %.1f", avg, budget)
+    }
+}
@@ -0,0 +1,46 @@
+package proxy
+
+import (
+    "net/http"
+
+    "github.com/labstack/echo/v4"
+)
+
+// nethttpEcho is a route-less, middleware-less *echo.Echo used only to
+// construct a per-request echo.Context via NewContext, which is cheap
+// (no router lookup, no middleware chain). It is never Start()ed and never
+// has routes registered on it.
+var nethttpEcho = echo.New()
+
+// NewNetHTTPHandler adapts HandleLogs/HandleTraces to a plain net/http.Handler
+// for the optional "nethttp" ingest engine (ingest_engine = "nethttp"), which
+// serves /v1/logs and /v1/traces off a stdlib ServeMux instead of through
+// Echo's middleware chain (CORS, logging, body-limit, readiness gating,
+// latency injection, etc.). Trading those cross-cutting features for lower
+// per-request overhead is only appropriate for the pure async ingest path,
+// so this intentionally does not attempt to replicate sync_logs_debug's
+// synchronous forwarding behavior or any of the Echo-side middleware.
+func NewNetHTTPHandler(h *ProxyHandler) http.Handler {
+    mux := http.NewServeMux()
+    mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPost {
+            w.WriteHeader(http.StatusMethodNotAllowed)
+            return
+        }
+        c := nethttpEcho.NewContext(r, w)
+        if err := h.HandleLogs(c); err != nil {
+            w.WriteHeader(http.StatusInternalServerError)
+        }
+    })
+    mux.HandleFunc("/v1/traces", func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPost {
+            w.WriteHeader(http.StatusMethodNotAllowed)
+            return
+        }
+        c := nethttpEcho.NewContext(r, w)
+        if err := h.HandleTraces(c); err != nil {
+            w.WriteHeader(http.StatusInternalServerError)
+        }
+    })
+    return mux
+}
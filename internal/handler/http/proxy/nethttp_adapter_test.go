@@ -0,0 +1,87 @@
+package proxy
+
+import (
+	"context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "zep-logger/internal/forwarder"
+    "zep-logger/internal/httpclient"
+    "zep-logger/internal/transform"
+    "zep-logger/internal/worker"
+)
+
+func TestNetHTTPHandler_HandleLogs_Returns202Accepted(t *testing.T) {
+    mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer mockCollector.Close()
+
+    pool := worker.NewPool(2, 10, 5*time.Second, httpclient.Config{})
+    pf := forwarder.NewPoolForwarder(pool)
+    pf.Start()
+    defer pf.Stop(context.Background())
+
+    handler := NewProxyHandler(context.Background(), mockCollector.URL, "test-api-key", pf, pf, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+    mux := NewNetHTTPHandler(handler)
+
+    req := httptest.NewRequest(http.MethodPost, "/v1/logs", strings.NewReader("test-data"))
+    rec := httptest.NewRecorder()
+    mux.ServeHTTP(rec, req)
+
+    if rec.Code != http.StatusAccepted {
+        t.Errorf("expected status 202 Accepted, got %d", rec.Code)
+    }
+}
+
+func TestNetHTTPHandler_HandleTraces_Returns202Accepted(t *testing.T) {
+    mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer mockCollector.Close()
+
+    pool := worker.NewPool(2, 10, 5*time.Second, httpclient.Config{})
+    pf := forwarder.NewPoolForwarder(pool)
+    pf.Start()
+    defer pf.Stop(context.Background())
+
+    handler := NewProxyHandler(context.Background(), mockCollector.URL, "test-api-key", pf, pf, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+    mux := NewNetHTTPHandler(handler)
+
+    req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("test-trace-data"))
+    rec := httptest.NewRecorder()
+    mux.ServeHTTP(rec, req)
+
+    if rec.Code != http.StatusAccepted {
+        t.Errorf("expected status 202 Accepted, got %d", rec.Code)
+    }
+}
+
+func TestNetHTTPHandler_RejectsNonPostMethods(t *testing.T) {
+    handler := NewProxyHandler(context.Background(), "http://example.com", "", nil, nil, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+    mux := NewNetHTTPHandler(handler)
+
+    req := httptest.NewRequest(http.MethodGet, "/v1/logs", nil)
+    rec := httptest.NewRecorder()
+    mux.ServeHTTP(rec, req)
+
+    if rec.Code != http.StatusMethodNotAllowed {
+        t.Errorf("expected status 405 Method Not Allowed, got %d", rec.Code)
+    }
+}
+
+func TestNetHTTPHandler_UnknownPath_Returns404(t *testing.T) {
+    handler := NewProxyHandler(context.Background(), "http://example.com", "", nil, nil, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+    mux := NewNetHTTPHandler(handler)
+
+    req := httptest.NewRequest(http.MethodPost, "/v1/unknown", nil)
+    rec := httptest.NewRecorder()
+    mux.ServeHTTP(rec, req)
+
+    if rec.Code != http.StatusNotFound {
+        t.Errorf("expected status 404 Not Found, got %d", rec.Code)
+    }
+}
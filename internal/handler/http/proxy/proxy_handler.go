@@ -2,32 +2,137 @@ package proxy
 
 import (
     "bytes"
+    "context"
     "io"
+    "net"
     "net/http"
+    "strconv"
     "strings"
     "time"
 
     "github.com/labstack/echo/v4"
+    "golang.org/x/time/rate"
 
+    "zep-logger/internal/admission"
+    "zep-logger/internal/dedup"
     "zep-logger/internal/forwarder"
+    "zep-logger/internal/geoip"
+    "zep-logger/internal/httpclient"
+    "zep-logger/internal/ingesttoken"
+    "zep-logger/internal/powchallenge"
+    "zep-logger/internal/transform"
+    "zep-logger/internal/worker"
     "zep-logger/pkg/logger"
 )
 
+// TailSampler buffers trace payloads and makes its own keep/drop forwarding
+// decision, so HandleTraces can hand off entirely instead of forwarding directly
+type TailSampler interface {
+    Ingest(body []byte, contentType string) error
+}
+
+// UsageAccountant aggregates per-tenant bytes/records for billing/chargeback
+type UsageAccountant interface {
+    Record(tenant string, signal string, bytes int, records int)
+}
+
+// AuthProvider supplies the outbound Authorization header value for
+// collectors that need something other than a long-lived static api key -
+// e.g. gcpauth.IDTokenSource, for a collector behind Cloud Run IAM. Takes
+// priority over apiKey when configured, falling back to apiKey (if any) on
+// error so a transient auth-server hiccup degrades to whatever static
+// credential is configured rather than dropping the Authorization header.
+type AuthProvider interface {
+    AuthorizationHeader(ctx context.Context) (string, error)
+}
+
 // ProxyHandler handles OTLP proxy endpoints
 // Forwards requests asynchronously to internal OTel collector with API key injection
 type ProxyHandler struct {
+    ctx        context.Context // Application run context, canceled only at shutdown; passed to Submit so forwarders' background delivery aborts on shutdown rather than outliving the process
 	targetURL  string
 	apiKey     string
-    forwarder  forwarder.Forwarder
-    httpClient *http.Client
+    logsForwarder   forwarder.Forwarder
+    tracesForwarder forwarder.Forwarder
+    logsForwardTimeout   time.Duration
+    tracesForwardTimeout time.Duration
+    httpClient httpclient.UpstreamClient
     syncLogs   bool
+    syncLogsStreamingEnabled bool
+    resourceAttributeRewriteRules []transform.RewriteRule
+    schemaNormalization           transform.SchemaNormalization
+    resourceRoutingRules          []transform.ResourceRoute
+    tailSampler                   TailSampler
+    usageAccountant               UsageAccountant
+    usageTenantHeader             string
+    largePayloadThresholdBytes    int
+    largePayloadTempDir           string
+    admissionController           *admission.Controller
+    forwardCompression            string
+    byteRateLimiter               *rate.Limiter
+    maxDecompressedBodyBytes      int
+    deduper                       *dedup.Deduper
+    dedupIdempotencyHeader        string
+    powVerifier                   *powchallenge.Verifier
+    geoReader                     *geoip.Reader
+    geoBlockedCountries           map[string]struct{}
+    geoCountryRoutes              []GeoCountryRoute
+    residencyRegion               string
+    residencyTargetRegions        map[string]string
+    authProvider                  AuthProvider
+    adminAPIKey                   string
+    targetOverrides               map[string]string
+    overloadStatusCode            int
+    overloadRetryAfterSeconds     int
+    ingestTokenIssuer             *ingesttoken.Issuer // Optional; nil disables ingest token enforcement (the original behavior)
+}
+
+// GeoCountryRoute sends payloads from a given client country to a
+// region-specific collector instead of the default target URL, mirroring
+// transform.ResourceRoute but keyed on client country rather than a resource
+// attribute. Rules are evaluated in order; the first match wins.
+type GeoCountryRoute struct {
+    Country   string
+    TargetURL string
 }
 
 // NewProxyHandler creates a new ProxyHandler with the given target URL, API key, and worker pool
+// ctx: application run context, canceled only at shutdown; forwarded to Submit calls so
+// in-flight/queued deliveries abort at shutdown rather than leaking past it
 // targetURL: Internal OTel collector base URL (e.g., "http://localhost:4318")
 // apiKey: Secret API key for Authorization header (can be empty)
-// workerPool: Worker pool for async forwarding
-func NewProxyHandler(targetURL string, apiKey string, forwarder forwarder.Forwarder, syncLogs bool) *ProxyHandler {
+// logsForwarder: forwarder used for /v1/logs (may be the same instance as tracesForwarder if no per-signal override is configured)
+// tracesForwarder: forwarder used for /v1/traces (may be the same instance as logsForwarder if no per-signal override is configured)
+// resourceAttributeRewriteRules: config-driven rewrites applied to resource attributes before forwarding (can be nil)
+// schemaNormalization: config-driven schema_url/semconv alias normalization applied before forwarding
+// resourceRoutingRules: config-driven per-resource target overrides that split one incoming request into several upstream requests (can be nil)
+// tailSampler: experimental tail-based sampling buffer for /v1/traces; if non-nil, HandleTraces hands off to it instead of forwarding directly
+// usageAccountant: per-tenant bytes/records billing aggregator (can be nil to disable)
+// usageTenantHeader: request header read to identify the tenant for usage accounting
+// largePayloadThresholdBytes: bodies with a declared Content-Length above this are spooled to disk during acquisition instead of buffered directly in memory (0 = disabled)
+// largePayloadTempDir: directory spooled bodies are written to (empty = OS default temp dir)
+// admissionController: cost-aware load shedder consulted before queuing an async request (can be nil to disable, the original behavior)
+// forwardCompression: re-encode request bodies with this codec before forwarding to the collector, skipping bodies that arrived already encoded ("gzip", "zstd", or "" to forward bodies as-is, the original behavior)
+// byteRateLimiter: global token bucket bounding total ingest bytes/sec across /v1/logs and /v1/traces, consulted by SetupRoutes' middleware chain (can be nil to disable, the original behavior)
+// maxDecompressedBodyBytes: caps the decompressed size of a gzip/zstd/deflate-encoded body, so a small compressed payload can't decode to an unbounded stream and bypass the wire-level BodyLimit (0 disables the cap, the original behavior)
+// deduper: suppresses a payload seen again within its sliding time window before it's queued (can be nil to disable, the original behavior)
+// dedupIdempotencyHeader: request header read as the dedup key when deduper is non-nil; empty falls back to hashing the request body
+// powVerifier: verifies the proof-of-work solution unauthenticated callers must present (via HandleChallenge) before /v1/logs or /v1/traces accepts their payload (can be nil to disable, the original behavior)
+// geoReader: resolves the client IP to a country for geoBlockedCountries/geoCountryRoutes (can be nil to disable, the original behavior)
+// geoBlockedCountries: ISO 3166-1 alpha-2 country codes rejected with 451, ignored when geoReader is nil
+// geoCountryRoutes: per-country target URL overrides for data-residency requirements, ignored when geoReader is nil; a country matching no rule (or one geoReader can't resolve) uses targetURL
+// residencyRegion: this instance's data residency region, stamped onto every resource and checked against residencyTargetRegions before forwarding (empty disables both, the original behavior)
+// residencyTargetRegions: target URL -> its configured residency region; a resolved target listed here whose region doesn't match residencyRegion is refused instead of forwarded
+// authProvider: supplies the outbound Authorization header dynamically (e.g. a refreshed GCP ID token), taking priority over apiKey when non-nil (can be nil to disable, the original static-apiKey-only behavior)
+// adminAPIKey: value required in X-Admin-Api-Key to use X-Target-Override (empty disables the override feature entirely, regardless of targetOverrides)
+// targetOverrides: name -> target URL a trusted caller may select per-request via X-Target-Override, bypassing the normal geo/residency target resolution for that request (can be nil to disable)
+// syncLogsStreamingEnabled: streams the /v1/logs body straight through to the collector via io.Pipe instead of buffering it, when syncLogs is true and no feature requiring the full body first is configured (see canStreamLogs)
+// logsForwardTimeout: per-job delivery deadline applied to /v1/logs submissions when logsForwarder implements forwarder.TimeoutForwarder, independent of that forwarder's own client Timeout (0 disables the override, the original behavior)
+// tracesForwardTimeout: same as logsForwardTimeout, for /v1/traces submissions
+// overloadStatusCode: status code returned when a request is rejected for queue/rate/quota pressure (load shedding or a full worker queue) rather than true unavailability, which always returns 503 regardless of this setting (e.g. readiness=false, /readyz). Should be 429 (clients back off and retry) or 503 (clients may treat it as retry-forever, the original behavior).
+// overloadRetryAfterSeconds: Retry-After seconds set alongside overloadStatusCode
+// ingestTokenIssuer: verifies a token presented via X-Ingest-Token on /v1/logs and /v1/traces, rejecting the request if it's missing, invalid, expired, or bound to a different Origin (nil disables enforcement entirely, the original behavior)
+func NewProxyHandler(ctx context.Context, targetURL string, apiKey string, logsForwarder forwarder.Forwarder, tracesForwarder forwarder.Forwarder, syncLogs bool, resourceAttributeRewriteRules []transform.RewriteRule, schemaNormalization transform.SchemaNormalization, resourceRoutingRules []transform.ResourceRoute, tailSampler TailSampler, usageAccountant UsageAccountant, usageTenantHeader string, largePayloadThresholdBytes int, largePayloadTempDir string, admissionController *admission.Controller, forwardCompression string, byteRateLimiter *rate.Limiter, maxDecompressedBodyBytes int, deduper *dedup.Deduper, dedupIdempotencyHeader string, powVerifier *powchallenge.Verifier, geoReader *geoip.Reader, geoBlockedCountries []string, geoCountryRoutes []GeoCountryRoute, residencyRegion string, residencyTargetRegions map[string]string, authProvider AuthProvider, adminAPIKey string, targetOverrides map[string]string, syncLogsStreamingEnabled bool, logsForwardTimeout time.Duration, tracesForwardTimeout time.Duration, overloadStatusCode int, overloadRetryAfterSeconds int, ingestTokenIssuer *ingesttoken.Issuer) *ProxyHandler {
     transport := &http.Transport{
         Proxy:                 http.ProxyFromEnvironment,
         ForceAttemptHTTP2:     true,
@@ -39,79 +144,500 @@ func NewProxyHandler(targetURL string, apiKey string, forwarder forwarder.Forwar
         ExpectContinueTimeout: 1 * time.Second,
     }
 	return &ProxyHandler{
+        ctx:        ctx,
 		targetURL:  targetURL,
 		apiKey:     apiKey,
-        forwarder:  forwarder,
+        logsForwarder:   logsForwarder,
+        tracesForwarder: tracesForwarder,
+        logsForwardTimeout:   logsForwardTimeout,
+        tracesForwardTimeout: tracesForwardTimeout,
         httpClient: &http.Client{Transport: transport, Timeout: 10 * time.Second},
         syncLogs:   syncLogs,
+        syncLogsStreamingEnabled: syncLogsStreamingEnabled,
+        resourceAttributeRewriteRules: resourceAttributeRewriteRules,
+        schemaNormalization:           schemaNormalization,
+        resourceRoutingRules:          resourceRoutingRules,
+        tailSampler:                   tailSampler,
+        usageAccountant:               usageAccountant,
+        usageTenantHeader:             usageTenantHeader,
+        largePayloadThresholdBytes:    largePayloadThresholdBytes,
+        largePayloadTempDir:           largePayloadTempDir,
+        admissionController:           admissionController,
+        forwardCompression:            forwardCompression,
+        byteRateLimiter:               byteRateLimiter,
+        maxDecompressedBodyBytes:      maxDecompressedBodyBytes,
+        deduper:                       deduper,
+        dedupIdempotencyHeader:        dedupIdempotencyHeader,
+        powVerifier:                   powVerifier,
+        geoReader:                     geoReader,
+        geoBlockedCountries:           geoCountrySet(geoBlockedCountries),
+        geoCountryRoutes:              geoCountryRoutes,
+        residencyRegion:               residencyRegion,
+        residencyTargetRegions:        residencyTargetRegions,
+        authProvider:                  authProvider,
+        adminAPIKey:                   adminAPIKey,
+        targetOverrides:               targetOverrides,
+        overloadStatusCode:            overloadStatusCode,
+        overloadRetryAfterSeconds:     overloadRetryAfterSeconds,
+        ingestTokenIssuer:             ingestTokenIssuer,
 	}
 }
 
-// HandleLogs handles POST /v1/logs requests
-// Uses synchronous forwarding when syncLogs=true (REQUIRED for session replay)
-// Falls back to async when syncLogs=false (breaks session replay but handles high load)
-func (h *ProxyHandler) HandleLogs(c echo.Context) error {
-    if !h.syncLogs {
-        return h.handleAsync(c, "/v1/logs")
+// SetHTTPClient overrides the client used for the synchronous upstream send
+// path (streamLogsBody, HandleTraces), so tests can inject a deterministic
+// fake instead of spinning up an httptest server and sleeping for timing.
+func (h *ProxyHandler) SetHTTPClient(client httpclient.UpstreamClient) {
+	h.httpClient = client
+}
+
+// geoCountrySet builds a lookup set from a list of ISO country codes, or nil
+// if countries is empty (the common case when geoip_blocked_countries is unset)
+func geoCountrySet(countries []string) map[string]struct{} {
+    if len(countries) == 0 {
+        return nil
+    }
+    set := make(map[string]struct{}, len(countries))
+    for _, c := range countries {
+        set[c] = struct{}{}
     }
+    return set
+}
 
-    // Synchronous forwarding (REQUIRED for session replay to work)
-    body, err := io.ReadAll(c.Request().Body)
+// maybeCompressBody re-encodes body with the configured forwardCompression
+// codec and marks headers with the matching Content-Encoding when
+// forwarding compression is enabled and alreadyEncoded is false, so a body
+// the client already compressed is never double-compressed; returns body
+// unchanged otherwise, or on a (practically unreachable) encoder failure.
+// headers is shared across every payload split from one incoming request, so
+// callers compute alreadyEncoded once from the pre-compression headers
+// rather than re-checking Content-Encoding after this has already set it.
+func (h *ProxyHandler) maybeCompressBody(body []byte, headers http.Header, alreadyEncoded bool) []byte {
+    if alreadyEncoded {
+        return body
+    }
+    var compressed []byte
+    var err error
+    switch h.forwardCompression {
+    case "gzip":
+        compressed, err = gzipBytes(body)
+    case "zstd":
+        compressed, err = zstdBytes(body)
+    default:
+        return body
+    }
     if err != nil {
-        logger.Error("Failed to read request body: %v", err)
-        return c.NoContent(http.StatusBadRequest)
+        logger.Error("Failed to %s-compress request body, forwarding uncompressed: %v", h.forwardCompression, err)
+        return body
+    }
+    headers.Set("Content-Encoding", h.forwardCompression)
+    return compressed
+}
+
+// forwarderFor returns the forwarder configured for path's signal, letting
+// /v1/logs and /v1/traces use independently-configured forwarders
+func (h *ProxyHandler) forwarderFor(path string) forwarder.Forwarder {
+    if path == "/v1/traces" {
+        return h.tracesForwarder
     }
+    return h.logsForwarder
+}
 
-    contentType := c.Request().Header.Get("Content-Type")
-    if contentType == "" {
-        contentType = "application/x-protobuf"
+// priorityFor returns the dispatch priority for path's signal: logs feed
+// session replay, so they're prioritized over traces when a pool-backed
+// forwarder's queue is contended
+func priorityFor(path string) worker.Priority {
+    if path == "/v1/logs" {
+        return worker.PriorityHigh
     }
+    return worker.PriorityNormal
+}
 
-    headers := make(http.Header, len(c.Request().Header)+2)
-    isHopByHop := func(name string) bool {
-        switch strings.ToLower(name) {
-        case "connection", "keep-alive", "proxy-authenticate", "proxy-authorization", "te", "trailer", "transfer-encoding", "upgrade", "proxy-connection":
-            return true
-        default:
-            return false
-        }
+// forwardTimeoutFor returns the configured per-job delivery deadline for
+// path's signal (0 = no override, the original behavior of relying entirely
+// on the forwarder's own client Timeout)
+func (h *ProxyHandler) forwardTimeoutFor(path string) time.Duration {
+    if path == "/v1/traces" {
+        return h.tracesForwardTimeout
     }
-    for k, vals := range c.Request().Header {
-        if len(vals) == 0 {
-            continue
-        }
-        if strings.EqualFold(k, "Host") || isHopByHop(k) {
-            continue
+    return h.logsForwardTimeout
+}
+
+// submit forwards payload to fwd, using fwd's timeout-aware SubmitWithTimeout
+// when timeout > 0 and fwd implements TimeoutForwarder, otherwise falling
+// back to priority-aware SubmitWithPriority when fwd implements
+// PriorityForwarder, and finally to plain Submit (e.g. the semaphore
+// forwarder, which has nothing to prioritize between since it spawns one
+// goroutine per request, and bakes its own timeout into its client)
+func submit(ctx context.Context, fwd forwarder.Forwarder, body []byte, targetURL string, headers http.Header, priority worker.Priority, timeout time.Duration) error {
+    if timeout > 0 {
+        if tf, ok := fwd.(forwarder.TimeoutForwarder); ok {
+            return tf.SubmitWithTimeout(ctx, body, targetURL, headers, priority, timeout)
         }
-        for _, v := range vals {
-            headers.Add(k, v)
+    }
+    if pf, ok := fwd.(forwarder.PriorityForwarder); ok {
+        return pf.SubmitWithPriority(ctx, body, targetURL, headers, priority)
+    }
+    return fwd.Submit(ctx, body, targetURL, headers)
+}
+
+// submitTracked behaves like submit, additionally returning a job id when fwd
+// implements TrackedForwarder (currently the pool forwarder only). An empty
+// id means the job wasn't tracked (e.g. job tracking isn't enabled), not that
+// it failed.
+func submitTracked(ctx context.Context, fwd forwarder.Forwarder, body []byte, targetURL string, headers http.Header, priority worker.Priority, timeout time.Duration) (string, error) {
+    if tf, ok := fwd.(forwarder.TrackedForwarder); ok {
+        return tf.SubmitTracked(ctx, body, targetURL, headers, priority, timeout)
+    }
+    return "", submit(ctx, fwd, body, targetURL, headers, priority, timeout)
+}
+
+// shouldShed decides whether to preemptively reject a request before it
+// reaches fwd's queue: first via the cost-aware admission controller (fwd's
+// current depth and the request's estimated cost, body size weighted by
+// sync vs async), then, for async (fire-and-forget trace) traffic, via the
+// admission controller's coin-flip ShouldProbabilisticallyShed so volume
+// degrades gracefully above a queue watermark rather than only cliff-edging
+// into rejections once the queue is completely full, then, if fwd reports a
+// health score, probabilistically per admission.ShouldShedForHealth so a
+// degrading collector sheds a growing fraction of traffic instead of only
+// shedding once its queue backs up. Always false when neither an admission
+// controller nor a health-reporting fwd is configured, leaving fwd's own
+// queue-full check as the only backpressure (the original behavior).
+func (h *ProxyHandler) shouldShed(fwd forwarder.Forwarder, bodyLen int, sync bool) bool {
+    if h.admissionController != nil && h.admissionController.ShouldShed(fwd.GetQueueDepth(), admission.EstimateCost(bodyLen, sync)) {
+        return true
+    }
+    if h.admissionController != nil && !sync && h.admissionController.ShouldProbabilisticallyShed(fwd.GetQueueDepth()) {
+        return true
+    }
+    if hr, ok := fwd.(forwarder.HealthReporter); ok {
+        return admission.ShouldShedForHealth(hr.HealthScore())
+    }
+    return false
+}
+
+// applySaturationHint sets a Retry-After header when fwd reports itself
+// saturated (above its soft backpressure watermark but still accepting
+// work), so clients can back off before fwd reaches its hard watermark and
+// starts rejecting outright. A no-op for forwarders that don't implement
+// forwarder.SaturationReporter or that report themselves as not saturated.
+func (h *ProxyHandler) applySaturationHint(c echo.Context, fwd forwarder.Forwarder) {
+    sr, ok := fwd.(forwarder.SaturationReporter)
+    if !ok {
+        return
+    }
+    if saturated, retryAfterSeconds := sr.IsSaturated(); saturated {
+        c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+    }
+}
+
+// rejectOverloaded responds to a request rejected for queue/rate/quota
+// pressure (load shedding or a full worker queue) with h.overloadStatusCode
+// and a Retry-After hint, instead of always returning 503 - which SDKs tend
+// to treat as retry-forever rather than back off and retry shortly. True
+// unavailability (e.g. readiness=false) is handled elsewhere and always
+// returns 503 regardless of this setting.
+func (h *ProxyHandler) rejectOverloaded(c echo.Context) error {
+    c.Response().Header().Set("Retry-After", strconv.Itoa(h.overloadRetryAfterSeconds))
+    return c.NoContent(h.overloadStatusCode)
+}
+
+// applyAuth overrides headers' Authorization with a freshly obtained value
+// from h.authProvider, when configured. Leaves headers untouched (including
+// whatever buildForwardHeaders already set from apiKey) if authProvider is
+// nil or fails to produce a token, so a transient auth-server hiccup
+// degrades to the static apiKey instead of dropping Authorization entirely.
+func (h *ProxyHandler) applyAuth(ctx context.Context, headers http.Header) {
+    if h.authProvider == nil {
+        return
+    }
+    value, err := h.authProvider.AuthorizationHeader(ctx)
+    if err != nil {
+        logger.Warn("Failed to obtain Authorization header from auth provider, falling back to static api key: %v", err)
+        return
+    }
+    headers.Set("Authorization", value)
+}
+
+// isDuplicate reports whether body has already been seen within deduper's
+// sliding window, keyed on dedupIdempotencyHeader when the client supplied
+// one, falling back to a hash of body otherwise. Always false when deduper
+// is nil, the original behavior.
+func (h *ProxyHandler) isDuplicate(c echo.Context, body []byte) bool {
+    if h.deduper == nil {
+        return false
+    }
+    key := ""
+    if h.dedupIdempotencyHeader != "" {
+        key = c.Request().Header.Get(h.dedupIdempotencyHeader)
+    }
+    if key == "" {
+        key = dedup.HashKey(body)
+    }
+    return h.deduper.Seen(key)
+}
+
+// resolveGeoTarget looks up c's client country and returns the target URL
+// its payload should be routed to (h.targetURL if no geoCountryRoutes rule
+// matches, or geoReader is nil) and whether the country is on
+// geoBlockedCountries. Always (h.targetURL, false) when geoReader is nil,
+// the original behavior. A country geoReader can't resolve is treated as
+// neither blocked nor routed, so a missing/stale database degrades to the
+// default target rather than rejecting all traffic.
+func (h *ProxyHandler) resolveGeoTarget(c echo.Context) (targetURL string, blocked bool) {
+    if h.geoReader == nil {
+        return h.targetURL, false
+    }
+    ip := net.ParseIP(c.RealIP())
+    if ip == nil {
+        return h.targetURL, false
+    }
+    country, err := h.geoReader.Country(ip)
+    if err != nil {
+        logger.Warn("GeoIP lookup failed for %s, falling back to default target: %v", c.RealIP(), err)
+        return h.targetURL, false
+    }
+    if country == "" {
+        return h.targetURL, false
+    }
+    if _, blocked := h.geoBlockedCountries[country]; blocked {
+        return "", true
+    }
+    for _, route := range h.geoCountryRoutes {
+        if route.Country == country {
+            return route.TargetURL, false
         }
     }
-    if headers.Get("Content-Type") == "" {
-        headers.Set("Content-Type", contentType)
+    return h.targetURL, false
+}
+
+// resolveTargetOverride checks whether c requests a pre-approved named
+// target via X-Target-Override, presenting X-Admin-Api-Key as proof of
+// being a trusted internal caller - the same credential admin.AdminHandler
+// requires, so a caller authorized for one admin operation is authorized
+// for the other; a caller authenticated by mTLS at a reverse proxy in front
+// of this process is expected to have that proxy attach the header too.
+// Returns ok=false (leaving target resolution to the normal geo/residency
+// path) when no override header is present, no targetOverrides are
+// configured, the admin key is missing or doesn't match, or the requested
+// name isn't a configured target.
+func (h *ProxyHandler) resolveTargetOverride(c echo.Context) (targetURL string, ok bool) {
+    name := c.Request().Header.Get("X-Target-Override")
+    if name == "" || len(h.targetOverrides) == 0 {
+        return "", false
+    }
+    if h.adminAPIKey == "" || c.Request().Header.Get("X-Admin-Api-Key") != h.adminAPIKey {
+        logger.Warn("X-Target-Override=%q presented without a valid X-Admin-Api-Key, ignoring", name)
+        return "", false
+    }
+    target, exists := h.targetOverrides[name]
+    if !exists {
+        logger.Warn("X-Target-Override=%q is not a configured target, ignoring", name)
+        return "", false
+    }
+    return target, true
+}
+
+// residencyMismatch reports whether targetURL is configured in
+// residencyTargetRegions with a residency region different from
+// h.residencyRegion, meaning forwarding this request there would move data
+// outside its required residency boundary. Always false when data residency
+// enforcement is disabled (h.residencyRegion == "") or targetURL isn't
+// listed, so a target the operator hasn't opted into enforcement is never
+// blocked.
+func (h *ProxyHandler) residencyMismatch(targetURL string) bool {
+    if h.residencyRegion == "" {
+        return false
     }
-    if h.apiKey != "" {
-        headers.Set("Authorization", h.apiKey)
+    configured, ok := h.residencyTargetRegions[targetURL]
+    if !ok || configured == "" {
+        return false
     }
+    return configured != h.residencyRegion
+}
 
-    req, err := http.NewRequest(http.MethodPost, h.targetURL+"/v1/logs", bytes.NewReader(body))
+// HandleChallenge handles GET /v1/challenge
+// Issues a proof-of-work nonce that must be solved and presented via the
+// X-PoW-Nonce/X-PoW-Solution headers on /v1/logs or /v1/traces, raising the
+// cost of scripted telemetry spam from unauthenticated RUM ingest. 404s when
+// no powVerifier is configured, the original behavior.
+func (h *ProxyHandler) HandleChallenge(c echo.Context) error {
+    if h.powVerifier == nil {
+        return c.NoContent(http.StatusNotFound)
+    }
+    challenge, err := h.powVerifier.Issue()
     if err != nil {
-        logger.Error("Failed to build upstream request: %v", err)
+        logger.Error("Failed to issue proof-of-work challenge: %v", err)
+        return c.NoContent(http.StatusInternalServerError)
+    }
+    return c.JSON(http.StatusOK, challenge)
+}
+
+// verifyProofOfWork reports whether the request's X-PoW-Nonce/X-PoW-Solution
+// headers redeem a valid, unexpired, not-yet-used challenge. Always true
+// when powVerifier is nil, the original behavior.
+func (h *ProxyHandler) verifyProofOfWork(c echo.Context) bool {
+    if h.powVerifier == nil {
+        return true
+    }
+    nonce := c.Request().Header.Get("X-PoW-Nonce")
+    solution := c.Request().Header.Get("X-PoW-Solution")
+    if err := h.powVerifier.Verify(nonce, solution); err != nil {
+        logger.Warn("Rejecting request with invalid proof-of-work challenge: %v", err)
+        return false
+    }
+    return true
+}
+
+// verifyIngestToken reports whether the request's X-Ingest-Token header
+// redeems a valid, unexpired token bound to the request's Origin header, so a
+// browser can present a short-lived token instead of a long-lived collector
+// key baked into its bundle. Always true when ingestTokenIssuer is nil, the
+// original behavior.
+func (h *ProxyHandler) verifyIngestToken(c echo.Context) bool {
+    if h.ingestTokenIssuer == nil {
+        return true
+    }
+    token := c.Request().Header.Get("X-Ingest-Token")
+    claims, err := h.ingestTokenIssuer.Verify(token)
+    if err != nil {
+        logger.Warn("Rejecting request with invalid ingest token: %v", err)
+        return false
+    }
+    if origin := c.Request().Header.Get("Origin"); origin != claims.Origin {
+        logger.Warn("Rejecting request: ingest token is bound to origin %q, request presented %q", claims.Origin, origin)
+        return false
+    }
+    return true
+}
+
+// recordUsage counts records in body (best-effort; a decode failure still
+// records bytes/requests so usage accounting never blocks delivery) and
+// tallies them against the tenant identified by usageTenantHeader
+func (h *ProxyHandler) recordUsage(c echo.Context, body []byte, contentType string, path string) {
+    if h.usageAccountant == nil {
+        return
+    }
+    records, err := transform.CountRecords(body, contentType, path)
+    if err != nil {
+        logger.Warn("Usage accounting: failed to count records in %s payload, recording bytes only: %v", path, err)
+    }
+    tenant := c.Request().Header.Get(h.usageTenantHeader)
+    signal := strings.TrimPrefix(path, "/v1/")
+    h.usageAccountant.Record(tenant, signal, len(body), records)
+}
+
+// splitByResourceRoute groups body by the resourceRoutingRules (if any) into
+// one payload per distinct upstream target. If no rules are configured, or
+// the payload can't be decoded for the given path, it returns a single
+// payload bound for the default target so a request is never dropped.
+// defaultTargetURL is normally h.targetURL, but may be overridden per
+// request by resolveGeoTarget to route a client's country to a
+// region-specific collector.
+func (h *ProxyHandler) splitByResourceRoute(body []byte, contentType string, path string, defaultTargetURL string) []transform.RoutedPayload {
+    if len(h.resourceRoutingRules) == 0 {
+        return []transform.RoutedPayload{{TargetURL: defaultTargetURL + path, Body: body}}
+    }
+
+    var payloads []transform.RoutedPayload
+    var err error
+    switch path {
+    case "/v1/logs":
+        payloads, err = transform.SplitLogsPayloadByResource(body, contentType, h.resourceRoutingRules, defaultTargetURL)
+    case "/v1/traces":
+        payloads, err = transform.SplitTracesPayloadByResource(body, contentType, h.resourceRoutingRules, defaultTargetURL)
+    default:
+        return []transform.RoutedPayload{{TargetURL: defaultTargetURL + path, Body: body}}
+    }
+    if err != nil {
+        logger.Warn("Failed to split %s payload by resource, forwarding as a single request to the default target: %v", path, err)
+        return []transform.RoutedPayload{{TargetURL: defaultTargetURL + path, Body: body}}
+    }
+    for i := range payloads {
+        payloads[i].TargetURL += path
+    }
+    return payloads
+}
+
+// applyResourceAttributeRewrites decodes body (if it's a signal we know how to
+// parse), applies the configured resource attribute rewrite rules and schema
+// normalization, and re-encodes it. On any decode error the original body is
+// forwarded unmodified so a malformed or unrecognized payload never blocks delivery.
+func (h *ProxyHandler) applyResourceAttributeRewrites(body []byte, contentType string, path string) []byte {
+    if len(h.resourceAttributeRewriteRules) == 0 && !h.schemaNormalization.Enabled() {
+        return body
+    }
+
+    var rewritten []byte
+    var err error
+    switch path {
+    case "/v1/logs":
+        rewritten, err = transform.RewriteLogsPayload(body, contentType, h.resourceAttributeRewriteRules, h.schemaNormalization)
+    case "/v1/traces":
+        rewritten, err = transform.RewriteTracesPayload(body, contentType, h.resourceAttributeRewriteRules, h.schemaNormalization)
+    default:
+        return body
+    }
+    if err != nil {
+        logger.Warn("Failed to apply resource attribute rewrite rules to %s payload, forwarding unmodified: %v", path, err)
+        return body
+    }
+    return rewritten
+}
+
+// canStreamLogs reports whether HandleLogs can stream the request body
+// straight through to the collector via io.Pipe instead of buffering it with
+// io.ReadAll. Requires none of the features that need to inspect or
+// transform the full body before it's forwarded.
+func (h *ProxyHandler) canStreamLogs() bool {
+    return h.syncLogsStreamingEnabled &&
+        len(h.resourceAttributeRewriteRules) == 0 &&
+        !h.schemaNormalization.Enabled() &&
+        len(h.resourceRoutingRules) == 0 &&
+        h.usageAccountant == nil &&
+        h.deduper == nil &&
+        h.forwardCompression == ""
+}
+
+// streamLogsBody copies the request body directly into the upstream request
+// via an io.Pipe instead of building a second buffered copy of it, used by
+// HandleLogs when canStreamLogs allows it. Served through NewNetHTTPHandler,
+// the client's body is never held in memory whole. On the Echo route
+// registered by SetupRoutes, BodyAcquisitionMiddleware has already read the
+// full (decompressed) body into memory before HandleLogs runs, so memory per
+// request there still grows with payload size.
+func (h *ProxyHandler) streamLogsBody(c echo.Context, targetURL string, headers http.Header) error {
+    pr, pw := io.Pipe()
+    go func() {
+        _, err := io.Copy(pw, c.Request().Body)
+        pw.CloseWithError(err)
+    }()
+
+    req, err := http.NewRequestWithContext(c.Request().Context(), http.MethodPost, targetURL, pr)
+    if err != nil {
+        logger.Error("Failed to build streaming upstream request: %v", err)
         return c.NoContent(http.StatusBadRequest)
     }
     req.Header = headers
+    req.ContentLength = -1 // unknown length up front; sent as chunked transfer-encoding
 
     resp, err := h.httpClient.Do(req)
     if err != nil {
-        logger.Error("Upstream error (sync logs): %v", err)
+        logger.Error("Upstream error (streaming sync logs): %v", err)
         return c.NoContent(http.StatusBadGateway)
     }
     defer resp.Body.Close()
 
-    // Copy response headers from upstream, but skip problematic headers
+    return relayUpstreamResponse(c, resp)
+}
+
+// relayUpstreamResponse copies resp's status and body back to the client,
+// skipping headers that either conflict with ones Echo sets itself or don't
+// make sense to forward across a hop (CORS, Vary, Content-Length,
+// Transfer-Encoding, Connection).
+func relayUpstreamResponse(c echo.Context, resp *http.Response) error {
     for k, values := range resp.Header {
         lowerKey := strings.ToLower(k)
-        // Skip headers that should not be forwarded or might cause conflicts
         switch {
         case strings.HasPrefix(lowerKey, "access-control-"): // CORS headers (Echo handles these)
             continue
@@ -133,6 +659,101 @@ func (h *ProxyHandler) HandleLogs(c echo.Context) error {
     return nil
 }
 
+// HandleLogs handles POST /v1/logs requests
+// Uses synchronous forwarding when syncLogs=true (REQUIRED for session replay)
+// Falls back to async when syncLogs=false (breaks session replay but handles high load)
+func (h *ProxyHandler) HandleLogs(c echo.Context) error {
+    if !h.syncLogs {
+        return h.handleAsync(c, "/v1/logs")
+    }
+
+    if !h.verifyProofOfWork(c) {
+        return c.NoContent(http.StatusForbidden)
+    }
+    if !h.verifyIngestToken(c) {
+        return c.NoContent(http.StatusUnauthorized)
+    }
+
+    geoTargetURL, geoBlocked := h.resolveGeoTarget(c)
+    if override, ok := h.resolveTargetOverride(c); ok {
+        geoTargetURL, geoBlocked = override, false
+    }
+    if geoBlocked {
+        return c.NoContent(http.StatusUnavailableForLegalReasons)
+    }
+    if h.residencyMismatch(geoTargetURL) {
+        logger.Error("Refusing to forward to %s: configured residency region does not match %s", geoTargetURL, h.residencyRegion)
+        return c.NoContent(http.StatusInternalServerError)
+    }
+
+    contentType := c.Request().Header.Get("Content-Type")
+    if contentType == "" {
+        contentType = "application/x-protobuf"
+    }
+
+    // Streaming path: no feature needs the full body up front, so copy it
+    // straight through to the collector without buffering another copy
+    if h.canStreamLogs() {
+        headers := buildForwardHeaders(c.Request().Header, contentType, h.apiKey)
+        h.applyAuth(c.Request().Context(), headers)
+        return h.streamLogsBody(c, geoTargetURL+"/v1/logs", headers)
+    }
+
+    // Synchronous forwarding (REQUIRED for session replay to work)
+    body, err := acquireBody(c)
+    if err != nil {
+        logger.Error("Failed to read request body: %v", err)
+        return c.NoContent(http.StatusBadRequest)
+    }
+
+    if h.isDuplicate(c, body) {
+        logger.Warn("Dedup: suppressing duplicate logs payload seen within the dedup window")
+        return c.NoContent(http.StatusAccepted)
+    }
+
+    h.recordUsage(c, body, contentType, "/v1/logs")
+    body = h.applyResourceAttributeRewrites(body, contentType, "/v1/logs")
+
+    headers := buildForwardHeaders(c.Request().Header, contentType, h.apiKey)
+    h.applyAuth(c.Request().Context(), headers)
+    alreadyEncoded := headers.Get("Content-Encoding") != ""
+
+    // Routing rules may split this request into several upstream targets. The
+    // primary (first) target is sent synchronously so its response can be
+    // relayed back to the client for session replay; any additional targets
+    // are submitted through the async forwarder since only one response can
+    // be returned here.
+    payloads := h.splitByResourceRoute(body, contentType, "/v1/logs", geoTargetURL)
+    for _, extra := range payloads[1:] {
+        if h.shouldShed(h.logsForwarder, len(extra.Body), true) {
+            logger.Warn("Load shedding: rejecting %d-byte routed logs request to %s (queue depth %d)", len(extra.Body), extra.TargetURL, h.logsForwarder.GetQueueDepth())
+            continue
+        }
+        extraBody := h.maybeCompressBody(extra.Body, headers, alreadyEncoded)
+        if err := submit(h.ctx, h.logsForwarder, extraBody, extra.TargetURL, headers, worker.PriorityHigh, h.logsForwardTimeout); err != nil {
+            logger.Warn("Worker pool queue full: rejecting routed logs request to %s", extra.TargetURL)
+        }
+    }
+    primary := payloads[0]
+    primaryBody := h.maybeCompressBody(primary.Body, headers, alreadyEncoded)
+
+    req, err := http.NewRequest(http.MethodPost, primary.TargetURL, bytes.NewReader(primaryBody))
+    if err != nil {
+        logger.Error("Failed to build upstream request: %v", err)
+        return c.NoContent(http.StatusBadRequest)
+    }
+    req.Header = headers
+
+    resp, err := h.httpClient.Do(req)
+    if err != nil {
+        logger.Error("Upstream error (sync logs): %v", err)
+        return c.NoContent(http.StatusBadGateway)
+    }
+    defer resp.Body.Close()
+
+    return relayUpstreamResponse(c, resp)
+}
+
 // HandleTraces handles POST /v1/traces requests
 // ALWAYS uses async forwarding for better performance (traces are fire-and-forget)
 // Buffers request body, submits async job to worker pool, returns 202 Accepted immediately
@@ -143,8 +764,27 @@ func (h *ProxyHandler) HandleTraces(c echo.Context) error {
 
 // handleAsync implements the async forwarding pattern for all OTLP endpoints
 func (h *ProxyHandler) handleAsync(c echo.Context, path string) error {
+	if !h.verifyProofOfWork(c) {
+		return c.NoContent(http.StatusForbidden)
+	}
+	if !h.verifyIngestToken(c) {
+		return c.NoContent(http.StatusUnauthorized)
+	}
+
+	geoTargetURL, geoBlocked := h.resolveGeoTarget(c)
+	if override, ok := h.resolveTargetOverride(c); ok {
+		geoTargetURL, geoBlocked = override, false
+	}
+	if geoBlocked {
+		return c.NoContent(http.StatusUnavailableForLegalReasons)
+	}
+	if h.residencyMismatch(geoTargetURL) {
+		logger.Error("Refusing to forward to %s: configured residency region does not match %s", geoTargetURL, h.residencyRegion)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
 	// Buffer request body before async submission (prevents race conditions)
-	body, err := io.ReadAll(c.Request().Body)
+	body, err := acquireBody(c)
 	if err != nil {
 		logger.Error("Failed to read request body: %v", err)
 		return c.NoContent(http.StatusBadRequest)
@@ -156,50 +796,53 @@ func (h *ProxyHandler) handleAsync(c echo.Context, path string) error {
 		contentType = "application/x-protobuf" // Default for OTLP
 	}
 
-    // Build headers for forwarding by copying incoming request headers (multi-valued)
-    headers := make(http.Header, len(c.Request().Header)+2)
+    if h.isDuplicate(c, body) {
+        logger.Warn("Dedup: suppressing duplicate %s payload seen within the dedup window", path)
+        return c.NoContent(http.StatusAccepted)
+    }
+
+    h.recordUsage(c, body, contentType, path)
+    body = h.applyResourceAttributeRewrites(body, contentType, path)
 
-    // Helper to detect hop-by-hop headers that must not be forwarded per RFC 7230
-    isHopByHop := func(name string) bool {
-        switch strings.ToLower(name) {
-        case "connection", "keep-alive", "proxy-authenticate", "proxy-authorization", "te", "trailer", "transfer-encoding", "upgrade", "proxy-connection":
-            return true
-        default:
-            return false
+    // Experimental tail-based sampling buffer: hands off entirely for traces,
+    // since the buffer makes its own keep/drop decision and submits later
+    if path == "/v1/traces" && h.tailSampler != nil {
+        if err := h.tailSampler.Ingest(body, contentType); err != nil {
+            logger.Warn("Tail-sampling buffer failed to ingest traces payload, falling back to direct forwarding: %v", err)
+        } else {
+            return c.NoContent(http.StatusAccepted)
         }
     }
 
-    for k, vals := range c.Request().Header {
-        if len(vals) == 0 {
-            continue
+    // Build headers for forwarding by cloning incoming request headers (multi-valued)
+    headers := buildForwardHeaders(c.Request().Header, contentType, h.apiKey)
+    h.applyAuth(c.Request().Context(), headers)
+    alreadyEncoded := headers.Get("Content-Encoding") != ""
+
+    // Routing rules may split this request into several upstream targets;
+    // submit each resulting payload independently to the forwarder configured for path's signal
+    fwd := h.forwarderFor(path)
+    priority := priorityFor(path)
+    timeout := h.forwardTimeoutFor(path)
+    h.applySaturationHint(c, fwd)
+    payloads := h.splitByResourceRoute(body, contentType, path, geoTargetURL)
+    for _, payload := range payloads {
+        if h.shouldShed(fwd, len(payload.Body), false) {
+            logger.Warn("Load shedding: rejecting %d-byte request to %s before queuing (queue depth %d)", len(payload.Body), payload.TargetURL, fwd.GetQueueDepth())
+            return h.rejectOverloaded(c)
         }
-        if strings.EqualFold(k, "Host") || isHopByHop(k) {
-            continue
+        payloadBody := h.maybeCompressBody(payload.Body, headers, alreadyEncoded)
+        jobID, err := submitTracked(h.ctx, fwd, payloadBody, payload.TargetURL, headers, priority, timeout)
+        if err != nil {
+            // Queue is full - backpressure scenario
+            logger.Warn("Worker pool queue full: rejecting request to %s", payload.TargetURL)
+            return h.rejectOverloaded(c)
         }
-        // Preserve Cookie safely and all other header values
-        for _, v := range vals {
-            headers.Add(k, v)
+        if jobID != "" {
+            c.Response().Header().Add("X-Job-Id", jobID)
         }
     }
 
-    // Ensure Content-Type is set
-    if headers.Get("Content-Type") == "" {
-        headers.Set("Content-Type", contentType)
-    }
-
-    // Inject/override Authorization with the configured API key
-    if h.apiKey != "" {
-        headers.Set("Authorization", h.apiKey)
-    }
-
-    // Submit to forwarder (pool or semaphore)
-    err = h.forwarder.Submit(body, h.targetURL+path, headers)
-	if err != nil {
-		// Queue is full - backpressure scenario
-		logger.Warn("Worker pool queue full: rejecting request to %s", path)
-		return c.NoContent(http.StatusServiceUnavailable)
-	}
-
 	// Return 202 Accepted immediately (client doesn't wait for collector)
 	return c.NoContent(http.StatusAccepted)
 }
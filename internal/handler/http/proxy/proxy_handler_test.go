@@ -1,17 +1,30 @@
 package proxy
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/labstack/echo/v4"
 
+	"zep-logger/internal/admission"
+	"zep-logger/internal/dedup"
 	"zep-logger/internal/forwarder"
+	"zep-logger/internal/httpclient"
+	"zep-logger/internal/ingesttoken"
+	"zep-logger/internal/powchallenge"
+	"zep-logger/internal/transform"
 	"zep-logger/internal/worker"
 )
 
@@ -30,12 +43,12 @@ func TestProxyHandler_HandleLogs_InjectsAuthHeader(t *testing.T) {
 	defer mockCollector.Close()
 
 	// Create worker pool and handler
-	pool := worker.NewPool(2, 10, 5*time.Second)
+	pool := worker.NewPool(2, 10, 5*time.Second, httpclient.Config{})
 	pf := forwarder.NewPoolForwarder(pool)
 	pf.Start()
-	defer pf.Stop()
+	defer pf.Stop(context.Background())
 
-    handler := NewProxyHandler(mockCollector.URL, "test-api-key", pf, false)
+    handler := NewProxyHandler(context.Background(), mockCollector.URL, "test-api-key", pf, pf, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
 
 	// Test request
 	e := echo.New()
@@ -72,12 +85,12 @@ func TestProxyHandler_HandleLogs_Returns202Accepted(t *testing.T) {
 	defer mockCollector.Close()
 
 	// Create worker pool and handler
-	pool := worker.NewPool(2, 10, 5*time.Second)
+	pool := worker.NewPool(2, 10, 5*time.Second, httpclient.Config{})
 	pf := forwarder.NewPoolForwarder(pool)
 	pf.Start()
-	defer pf.Stop()
+	defer pf.Stop(context.Background())
 
-	handler := NewProxyHandler(mockCollector.URL, "test-api-key", pf, false)
+	handler := NewProxyHandler(context.Background(), mockCollector.URL, "test-api-key", pf, pf, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
 
 	// Test request
 	e := echo.New()
@@ -112,12 +125,12 @@ func TestProxyHandler_HandleTraces_Returns202Accepted(t *testing.T) {
 	defer mockCollector.Close()
 
 	// Create worker pool and handler
-	pool := worker.NewPool(2, 10, 5*time.Second)
+	pool := worker.NewPool(2, 10, 5*time.Second, httpclient.Config{})
 	pf := forwarder.NewPoolForwarder(pool)
 	pf.Start()
-	defer pf.Stop()
+	defer pf.Stop(context.Background())
 
-    handler := NewProxyHandler(mockCollector.URL, "test-api-key", pf, false)
+    handler := NewProxyHandler(context.Background(), mockCollector.URL, "test-api-key", pf, pf, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
 
 	// Test request
 	e := echo.New()
@@ -152,12 +165,12 @@ func TestProxyHandler_RequestBodyBuffering(t *testing.T) {
 	}))
 	defer mockCollector.Close()
 
-	pool := worker.NewPool(2, 10, 5*time.Second)
+	pool := worker.NewPool(2, 10, 5*time.Second, httpclient.Config{})
 	pf := forwarder.NewPoolForwarder(pool)
 	pf.Start()
-	defer pf.Stop()
+	defer pf.Stop(context.Background())
 
-    handler := NewProxyHandler(mockCollector.URL, "test-api-key", pf, false)
+    handler := NewProxyHandler(context.Background(), mockCollector.URL, "test-api-key", pf, pf, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
 
 	e := echo.New()
 	testData := "buffered-test-data"
@@ -199,13 +212,13 @@ func TestProxyHandler_NoAuthHeader_WhenAPIKeyEmpty(t *testing.T) {
 	}))
 	defer mockCollector.Close()
 
-    pool := worker.NewPool(2, 10, 5*time.Second)
+    pool := worker.NewPool(2, 10, 5*time.Second, httpclient.Config{})
     pf := forwarder.NewPoolForwarder(pool)
     pf.Start()
-    defer pf.Stop()
+    defer pf.Stop(context.Background())
 
     // Create handler with empty API key
-    handler := NewProxyHandler(mockCollector.URL, "", pf, false)
+    handler := NewProxyHandler(context.Background(), mockCollector.URL, "", pf, pf, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodPost, "/v1/logs", strings.NewReader("test-data"))
@@ -229,8 +242,10 @@ func TestProxyHandler_NoAuthHeader_WhenAPIKeyEmpty(t *testing.T) {
 	}
 }
 
-// TestProxyHandler_QueueFull_Returns503 verifies backpressure handling
-func TestProxyHandler_QueueFull_Returns503(t *testing.T) {
+// TestProxyHandler_QueueFull_Returns429 verifies backpressure handling uses
+// the configured overload status code (429 by default) rather than the old
+// hardcoded 503, and sets a Retry-After hint
+func TestProxyHandler_QueueFull_Returns429(t *testing.T) {
 	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Slow response to fill queue
 		time.Sleep(500 * time.Millisecond)
@@ -239,12 +254,12 @@ func TestProxyHandler_QueueFull_Returns503(t *testing.T) {
 	defer mockCollector.Close()
 
 	// Create small pool with tiny queue to test backpressure
-	pool := worker.NewPool(1, 2, 5*time.Second)
+	pool := worker.NewPool(1, 2, 5*time.Second, httpclient.Config{})
 	pf := forwarder.NewPoolForwarder(pool)
 	pf.Start()
-	defer pf.Stop()
+	defer pf.Stop(context.Background())
 
-    handler := NewProxyHandler(mockCollector.URL, "test-api-key", pf, false)
+    handler := NewProxyHandler(context.Background(), mockCollector.URL, "test-api-key", pf, pf, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
 
 	e := echo.New()
 
@@ -256,7 +271,7 @@ func TestProxyHandler_QueueFull_Returns503(t *testing.T) {
 		_ = handler.HandleLogs(c)
 	}
 
-	// This request should fail with 503 (queue full)
+	// This request should fail with 429 (queue full)
 	req := httptest.NewRequest(http.MethodPost, "/v1/logs", strings.NewReader("test-data"))
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
@@ -266,8 +281,682 @@ func TestProxyHandler_QueueFull_Returns503(t *testing.T) {
 		t.Fatalf("HandleLogs returned error: %v", err)
 	}
 
-	// Verify 503 Service Unavailable
-	if rec.Code != http.StatusServiceUnavailable {
-		t.Errorf("expected status 503 Service Unavailable, got %d", rec.Code)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status 429 Too Many Requests, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "5" {
+		t.Errorf("expected Retry-After: 5, got %q", rec.Header().Get("Retry-After"))
+	}
+}
+
+// TestProxyHandler_LoadShedding_RejectsExpensiveRequestWithoutSubmitting
+// verifies an admission controller sheds an oversized async request before it
+// ever reaches the forwarder's queue, using the configured overload status
+// code rather than the old hardcoded 503
+func TestProxyHandler_LoadShedding_RejectsExpensiveRequestWithoutSubmitting(t *testing.T) {
+	fwd := &priorityRecordingForwarder{queueDepth: 900}
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", admission.NewController(1000), "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	oversizedBody := strings.Repeat("x", 5*1024*1024)
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader(oversizedBody))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleTraces(c); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status 429 Too Many Requests when shed, got %d", rec.Code)
+	}
+	if fwd.submitted {
+		t.Error("expected shed request to never reach the forwarder")
+	}
+}
+
+// TestProxyHandler_LoadShedding_AdmitsSmallRequestUnderPressure verifies a
+// small request still gets through even with a near-saturated queue
+func TestProxyHandler_LoadShedding_AdmitsSmallRequestUnderPressure(t *testing.T) {
+	fwd := &priorityRecordingForwarder{queueDepth: 900}
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", admission.NewController(1000), "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("small-payload"))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleTraces(c); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("expected status 202 Accepted for a small request, got %d", rec.Code)
+	}
+	if !fwd.submitted {
+		t.Error("expected small request to reach the forwarder")
+	}
+}
+
+// TestProxyHandler_GzipForwardedBodies_CompressesAndSetsContentEncoding
+// verifies gzip_forwarded_bodies compresses the outgoing body and marks it
+// with Content-Encoding: gzip
+func TestProxyHandler_GzipForwardedBodies_CompressesAndSetsContentEncoding(t *testing.T) {
+	var mu sync.Mutex
+	var receivedEncoding string
+	var receivedBody []byte
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		receivedEncoding = r.Header.Get("Content-Encoding")
+		receivedBody, _ = io.ReadAll(r.Body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	pool := worker.NewPool(2, 10, 5*time.Second, httpclient.Config{})
+	pf := forwarder.NewPoolForwarder(pool)
+	pf.Start()
+	defer pf.Stop(context.Background())
+
+	handler := NewProxyHandler(context.Background(), mockCollector.URL, "", pf, pf, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "gzip", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	testData := "plain-text-payload"
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader(testData))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleTraces(c); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	encoding := receivedEncoding
+	body := receivedBody
+	mu.Unlock()
+
+	if encoding != "gzip" {
+		t.Fatalf("expected Content-Encoding: gzip, got %q", encoding)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("expected a valid gzip body, got error: %v", err)
+	}
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read decompressed body: %v", err)
+	}
+	if string(decompressed) != testData {
+		t.Errorf("expected decompressed body %q, got %q", testData, decompressed)
+	}
+}
+
+// TestProxyHandler_GzipForwardedBodies_SkipsAlreadyEncodedBody verifies a
+// request that arrived already Content-Encoding'd is forwarded unmodified
+// instead of being double-compressed
+func TestProxyHandler_GzipForwardedBodies_SkipsAlreadyEncodedBody(t *testing.T) {
+	var mu sync.Mutex
+	var receivedBody []byte
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		receivedBody, _ = io.ReadAll(r.Body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	pool := worker.NewPool(2, 10, 5*time.Second, httpclient.Config{})
+	pf := forwarder.NewPoolForwarder(pool)
+	pf.Start()
+	defer pf.Stop(context.Background())
+
+	handler := NewProxyHandler(context.Background(), mockCollector.URL, "", pf, pf, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "gzip", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	alreadyCompressed := "client-precompressed-body"
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader(alreadyCompressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleTraces(c); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	body := receivedBody
+	mu.Unlock()
+
+	if string(body) != alreadyCompressed {
+		t.Errorf("expected already-encoded body to be forwarded unmodified, got %q", body)
+	}
+}
+
+// TestProxyHandler_ForwardCompression_Zstd_CompressesAndSetsContentEncoding
+// verifies forward_compression = "zstd" compresses the outgoing body and
+// marks it with Content-Encoding: zstd
+func TestProxyHandler_ForwardCompression_Zstd_CompressesAndSetsContentEncoding(t *testing.T) {
+	var mu sync.Mutex
+	var receivedEncoding string
+	var receivedBody []byte
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		receivedEncoding = r.Header.Get("Content-Encoding")
+		receivedBody, _ = io.ReadAll(r.Body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	pool := worker.NewPool(2, 10, 5*time.Second, httpclient.Config{})
+	pf := forwarder.NewPoolForwarder(pool)
+	pf.Start()
+	defer pf.Stop(context.Background())
+
+	handler := NewProxyHandler(context.Background(), mockCollector.URL, "", pf, pf, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "zstd", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	testData := "plain-text-payload"
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader(testData))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleTraces(c); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	encoding := receivedEncoding
+	body := receivedBody
+	mu.Unlock()
+
+	if encoding != "zstd" {
+		t.Fatalf("expected Content-Encoding: zstd, got %q", encoding)
+	}
+	reader, err := zstd.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("expected a valid zstd body, got error: %v", err)
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read decompressed body: %v", err)
+	}
+	if string(decompressed) != testData {
+		t.Errorf("expected decompressed body %q, got %q", testData, decompressed)
+	}
+}
+
+// priorityRecordingForwarder is a Forwarder that also implements
+// forwarder.PriorityForwarder and forwarder.TimeoutForwarder, recording the
+// priority/timeout it was submitted with so tests can assert on them without
+// spinning up a real worker.Pool
+type priorityRecordingForwarder struct {
+	lastPriority  worker.Priority
+	lastTargetURL string
+	lastTimeout   time.Duration
+	submitted     bool
+	submitCount   int
+	queueDepth    int
+}
+
+func (f *priorityRecordingForwarder) Start() {}
+func (f *priorityRecordingForwarder) Stop(ctx context.Context) error { return nil }
+func (f *priorityRecordingForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	return f.SubmitWithPriority(ctx, body, targetURL, headers, worker.PriorityNormal)
+}
+func (f *priorityRecordingForwarder) SubmitWithPriority(ctx context.Context, body []byte, targetURL string, headers http.Header, priority worker.Priority) error {
+	f.lastPriority = priority
+	f.lastTargetURL = targetURL
+	f.submitted = true
+	f.submitCount++
+	return nil
+}
+func (f *priorityRecordingForwarder) SubmitWithTimeout(ctx context.Context, body []byte, targetURL string, headers http.Header, priority worker.Priority, timeout time.Duration) error {
+	f.lastTimeout = timeout
+	return f.SubmitWithPriority(ctx, body, targetURL, headers, priority)
+}
+func (f *priorityRecordingForwarder) GetQueueDepth() int { return f.queueDepth }
+func (f *priorityRecordingForwarder) Flush(ctx context.Context) error { return nil }
+
+// TestProxyHandler_HandleLogs_SubmitsWithHighPriority verifies logs (which
+// feed session replay) are submitted to a PriorityForwarder as PriorityHigh
+func TestProxyHandler_HandleLogs_SubmitsWithHighPriority(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", strings.NewReader("test-data"))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleLogs(c); err != nil {
+		t.Fatalf("HandleLogs returned error: %v", err)
+	}
+
+	if fwd.lastPriority != worker.PriorityHigh {
+		t.Errorf("expected logs to be submitted with PriorityHigh, got %v", fwd.lastPriority)
+	}
+}
+
+// TestProxyHandler_HandleTraces_SubmitsWithNormalPriority verifies traces are
+// submitted to a PriorityForwarder as PriorityNormal, so they yield to logs
+// when a pool-backed forwarder's queue is contended
+func TestProxyHandler_HandleTraces_SubmitsWithNormalPriority(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("test-data"))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleTraces(c); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+
+	if fwd.lastPriority != worker.PriorityNormal {
+		t.Errorf("expected traces to be submitted with PriorityNormal, got %v", fwd.lastPriority)
+	}
+}
+
+// TestProxyHandler_HandleTraces_SubmitsWithConfiguredTimeout verifies traces
+// are submitted with the configured tracesForwardTimeout when fwd implements
+// TimeoutForwarder
+func TestProxyHandler_HandleTraces_SubmitsWithConfiguredTimeout(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 250*time.Millisecond, 429, 5, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("test-data"))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleTraces(c); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+
+	if fwd.lastTimeout != 250*time.Millisecond {
+		t.Errorf("expected traces to be submitted with a 250ms timeout, got %v", fwd.lastTimeout)
+	}
+}
+
+// TestProxyHandler_HandleTraces_NoTimeoutOverride_LeavesTimeoutZero verifies
+// that leaving tracesForwardTimeout unset (0) doesn't force a deadline onto
+// every submission
+func TestProxyHandler_HandleTraces_NoTimeoutOverride_LeavesTimeoutZero(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("test-data"))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleTraces(c); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+
+	if fwd.lastTimeout != 0 {
+		t.Errorf("expected no timeout override, got %v", fwd.lastTimeout)
+	}
+}
+
+// TestProxyHandler_Dedup_SuppressesRepeatedAsyncPayload verifies a duplicate
+// payload within the dedup window is short-circuited without reaching the forwarder
+func TestProxyHandler_Dedup_SuppressesRepeatedAsyncPayload(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, dedup.NewDeduper(time.Minute), "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+
+	req1 := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("duplicate-data"))
+	rec1 := httptest.NewRecorder()
+	if err := handler.HandleTraces(e.NewContext(req1, rec1)); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+	if rec1.Code != http.StatusAccepted {
+		t.Fatalf("expected first request to be accepted, got %d", rec1.Code)
+	}
+	if fwd.submitCount != 1 {
+		t.Fatalf("expected first request to reach the forwarder, got %d submissions", fwd.submitCount)
+	}
+
+	req2 := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("duplicate-data"))
+	rec2 := httptest.NewRecorder()
+	if err := handler.HandleTraces(e.NewContext(req2, rec2)); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+	if rec2.Code != http.StatusAccepted {
+		t.Errorf("expected the duplicate request to still be accepted, got %d", rec2.Code)
+	}
+	if fwd.submitCount != 1 {
+		t.Errorf("expected the duplicate request to be suppressed before reaching the forwarder, got %d submissions", fwd.submitCount)
+	}
+}
+
+// TestProxyHandler_Dedup_AdmitsDistinctPayloads verifies distinct payloads
+// within the same window are both forwarded
+func TestProxyHandler_Dedup_AdmitsDistinctPayloads(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, dedup.NewDeduper(time.Minute), "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	for _, body := range []string{"payload-one", "payload-two"} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		if err := handler.HandleTraces(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("HandleTraces returned error: %v", err)
+		}
+	}
+	if fwd.submitCount != 2 {
+		t.Errorf("expected both distinct payloads to reach the forwarder, got %d submissions", fwd.submitCount)
+	}
+}
+
+// TestProxyHandler_Dedup_KeysOnIdempotencyHeaderWhenConfigured verifies that,
+// when dedupIdempotencyHeader is set, dedup keys off the header instead of
+// the body hash, so a retried request with a changed body is still suppressed
+func TestProxyHandler_Dedup_KeysOnIdempotencyHeaderWhenConfigured(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, dedup.NewDeduper(time.Minute), "X-Idempotency-Key", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+
+	req1 := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("first-body"))
+	req1.Header.Set("X-Idempotency-Key", "same-key")
+	rec1 := httptest.NewRecorder()
+	if err := handler.HandleTraces(e.NewContext(req1, rec1)); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+
+	req2 := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("second-body"))
+	req2.Header.Set("X-Idempotency-Key", "same-key")
+	rec2 := httptest.NewRecorder()
+	if err := handler.HandleTraces(e.NewContext(req2, rec2)); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+
+	if fwd.submitCount != 1 {
+		t.Errorf("expected the second request to be suppressed by the shared idempotency key, got %d submissions", fwd.submitCount)
+	}
+}
+
+// solvePow brute-forces a proof-of-work solution for challenge, for tests only
+func solvePow(t *testing.T, challenge powchallenge.Challenge) string {
+	t.Helper()
+	nonceID, _, ok := strings.Cut(challenge.Nonce, ":")
+	if !ok {
+		t.Fatalf("nonce %q has no ':' separator", challenge.Nonce)
+	}
+	for i := 0; ; i++ {
+		candidate := strconv.Itoa(i)
+		hash := sha256.Sum256([]byte(nonceID + candidate))
+		if leadingZeroBits(hash) >= challenge.Difficulty {
+			return candidate
+		}
+		if i > 1_000_000 {
+			t.Fatalf("failed to find a proof-of-work solution within 1,000,000 attempts")
+		}
+	}
+}
+
+func leadingZeroBits(hash [32]byte) int {
+	bits := 0
+	for _, b := range hash {
+		for i := 7; i >= 0; i-- {
+			if b&(1<<i) != 0 {
+				return bits
+			}
+			bits++
+		}
+	}
+	return bits
+}
+
+// TestProxyHandler_Challenge_ReturnsNotFoundWhenDisabled verifies GET
+// /v1/challenge 404s when no powVerifier is configured
+func TestProxyHandler_Challenge_ReturnsNotFoundWhenDisabled(t *testing.T) {
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", nil, nil, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/v1/challenge", nil)
+	rec := httptest.NewRecorder()
+
+	if err := handler.HandleChallenge(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("HandleChallenge returned error: %v", err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 Not Found, got %d", rec.Code)
+	}
+}
+
+// TestProxyHandler_PoW_RejectsAsyncRequestMissingSolution verifies traces are
+// rejected before reaching the forwarder when no proof-of-work solution is presented
+func TestProxyHandler_PoW_RejectsAsyncRequestMissingSolution(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	verifier := powchallenge.NewVerifier("secret", time.Minute, 4)
+	verifier.Start()
+	defer verifier.Stop()
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", verifier, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("some-data"))
+	rec := httptest.NewRecorder()
+
+	if err := handler.HandleTraces(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 Forbidden, got %d", rec.Code)
+	}
+	if fwd.submitCount != 0 {
+		t.Errorf("expected the request to be rejected before reaching the forwarder, got %d submissions", fwd.submitCount)
+	}
+}
+
+// TestProxyHandler_PoW_AdmitsRequestWithValidSolution verifies a request
+// presenting a correctly solved challenge is forwarded
+func TestProxyHandler_PoW_AdmitsRequestWithValidSolution(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	verifier := powchallenge.NewVerifier("secret", time.Minute, 4)
+	verifier.Start()
+	defer verifier.Stop()
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", verifier, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	challengeReq := httptest.NewRequest(http.MethodGet, "/v1/challenge", nil)
+	challengeRec := httptest.NewRecorder()
+	if err := handler.HandleChallenge(e.NewContext(challengeReq, challengeRec)); err != nil {
+		t.Fatalf("HandleChallenge returned error: %v", err)
+	}
+	var challenge powchallenge.Challenge
+	if err := json.Unmarshal(challengeRec.Body.Bytes(), &challenge); err != nil {
+		t.Fatalf("failed to unmarshal challenge: %v", err)
+	}
+	solution := solvePow(t, challenge)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("some-data"))
+	req.Header.Set("X-PoW-Nonce", challenge.Nonce)
+	req.Header.Set("X-PoW-Solution", solution)
+	rec := httptest.NewRecorder()
+
+	if err := handler.HandleTraces(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("expected status 202 Accepted, got %d", rec.Code)
+	}
+	if fwd.submitCount != 1 {
+		t.Errorf("expected the request to reach the forwarder, got %d submissions", fwd.submitCount)
+	}
+}
+
+// TestProxyHandler_PoW_RejectsReusedSolution verifies a nonce that's already
+// been redeemed can't be replayed on a second request
+func TestProxyHandler_PoW_RejectsReusedSolution(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	verifier := powchallenge.NewVerifier("secret", time.Minute, 4)
+	verifier.Start()
+	defer verifier.Stop()
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", verifier, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	challengeReq := httptest.NewRequest(http.MethodGet, "/v1/challenge", nil)
+	challengeRec := httptest.NewRecorder()
+	if err := handler.HandleChallenge(e.NewContext(challengeReq, challengeRec)); err != nil {
+		t.Fatalf("HandleChallenge returned error: %v", err)
+	}
+	var challenge powchallenge.Challenge
+	if err := json.Unmarshal(challengeRec.Body.Bytes(), &challenge); err != nil {
+		t.Fatalf("failed to unmarshal challenge: %v", err)
+	}
+	solution := solvePow(t, challenge)
+
+	for i, wantStatus := range []int{http.StatusAccepted, http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("some-data"))
+		req.Header.Set("X-PoW-Nonce", challenge.Nonce)
+		req.Header.Set("X-PoW-Solution", solution)
+		rec := httptest.NewRecorder()
+
+		if err := handler.HandleTraces(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("HandleTraces returned error: %v", err)
+		}
+		if rec.Code != wantStatus {
+			t.Errorf("attempt %d: expected status %d, got %d", i, wantStatus, rec.Code)
+		}
+	}
+}
+
+// TestProxyHandler_IngestToken_RejectsMissingToken verifies a request is
+// rejected before reaching the forwarder when ingest token enforcement is
+// configured and no X-Ingest-Token is presented
+func TestProxyHandler_IngestToken_RejectsMissingToken(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	issuer := ingesttoken.NewIssuer("secret", time.Minute)
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, issuer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("some-data"))
+	rec := httptest.NewRecorder()
+
+	if err := handler.HandleTraces(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 Unauthorized, got %d", rec.Code)
+	}
+	if fwd.submitCount != 0 {
+		t.Errorf("expected the request to be rejected before reaching the forwarder, got %d submissions", fwd.submitCount)
+	}
+}
+
+// TestProxyHandler_IngestToken_RejectsOriginMismatch verifies a token bound
+// to one Origin is rejected when presented from a different Origin
+func TestProxyHandler_IngestToken_RejectsOriginMismatch(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	issuer := ingesttoken.NewIssuer("secret", time.Minute)
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, issuer)
+
+	token, _, err := issuer.Issue("tenant-a", "https://app.example.com")
+	if err != nil {
+		t.Fatalf("failed to issue token: %v", err)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("some-data"))
+	req.Header.Set("X-Ingest-Token", token)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec := httptest.NewRecorder()
+
+	if err := handler.HandleTraces(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 Unauthorized, got %d", rec.Code)
+	}
+	if fwd.submitCount != 0 {
+		t.Errorf("expected the request to be rejected before reaching the forwarder, got %d submissions", fwd.submitCount)
+	}
+}
+
+// TestProxyHandler_IngestToken_AdmitsMatchingOrigin verifies a validly
+// signed, unexpired token presented from its bound Origin is admitted
+func TestProxyHandler_IngestToken_AdmitsMatchingOrigin(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	issuer := ingesttoken.NewIssuer("secret", time.Minute)
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, false, 0, 0, 429, 5, issuer)
+
+	token, _, err := issuer.Issue("tenant-a", "https://app.example.com")
+	if err != nil {
+		t.Fatalf("failed to issue token: %v", err)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("some-data"))
+	req.Header.Set("X-Ingest-Token", token)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+
+	if err := handler.HandleTraces(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("expected status 202 Accepted, got %d", rec.Code)
+	}
+	if fwd.submitCount != 1 {
+		t.Errorf("expected the request to reach the forwarder, got %d submissions", fwd.submitCount)
+	}
+}
+
+// TestProxyHandler_TargetOverride_AdmitsWithValidAdminKey verifies a request
+// presenting a configured X-Target-Override name alongside the correct
+// X-Admin-Api-Key is forwarded to the overridden target instead of the
+// default targetURL
+func TestProxyHandler_TargetOverride_AdmitsWithValidAdminKey(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	targetOverrides := map[string]string{"replica-2": "http://replica-2.invalid"}
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "admin-secret", targetOverrides, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("some-data"))
+	req.Header.Set("X-Target-Override", "replica-2")
+	req.Header.Set("X-Admin-Api-Key", "admin-secret")
+	rec := httptest.NewRecorder()
+
+	if err := handler.HandleTraces(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("expected status 202 Accepted, got %d", rec.Code)
+	}
+	if fwd.lastTargetURL != "http://replica-2.invalid/v1/traces" {
+		t.Errorf("expected the request to be forwarded to the override target, got %q", fwd.lastTargetURL)
+	}
+}
+
+// TestProxyHandler_TargetOverride_IgnoredWithoutValidAdminKey verifies a
+// request presenting X-Target-Override without a matching X-Admin-Api-Key
+// falls back to the default target rather than the override
+func TestProxyHandler_TargetOverride_IgnoredWithoutValidAdminKey(t *testing.T) {
+	fwd := &priorityRecordingForwarder{}
+	targetOverrides := map[string]string{"replica-2": "http://replica-2.invalid"}
+	handler := NewProxyHandler(context.Background(), "http://example.invalid", "", fwd, fwd, false, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "admin-secret", targetOverrides, false, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", strings.NewReader("some-data"))
+	req.Header.Set("X-Target-Override", "replica-2")
+	req.Header.Set("X-Admin-Api-Key", "wrong-key")
+	rec := httptest.NewRecorder()
+
+	if err := handler.HandleTraces(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("HandleTraces returned error: %v", err)
+	}
+	if fwd.lastTargetURL != "http://example.invalid/v1/traces" {
+		t.Errorf("expected the request to be forwarded to the default target, got %q", fwd.lastTargetURL)
 	}
 }
@@ -7,6 +7,9 @@ import (
 // SetupRoutes registers OTLP proxy routes with the Echo instance
 // Follows separated routes pattern - route registration separate from handler logic
 func (h *ProxyHandler) SetupRoutes(e *echo.Echo) {
-	e.POST("/v1/logs", h.HandleLogs)
-	e.POST("/v1/traces", h.HandleTraces)
+	bodyMiddleware := BodyAcquisitionMiddleware(h.largePayloadThresholdBytes, h.largePayloadTempDir, h.maxDecompressedBodyBytes)
+	byteRateLimitMiddleware := ByteRateLimitMiddleware(h.byteRateLimiter)
+	e.POST("/v1/logs", h.HandleLogs, bodyMiddleware, byteRateLimitMiddleware)
+	e.POST("/v1/traces", h.HandleTraces, bodyMiddleware, byteRateLimitMiddleware)
+	e.GET("/v1/challenge", h.HandleChallenge)
 }
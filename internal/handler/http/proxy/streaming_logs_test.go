@@ -0,0 +1,59 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"zep-logger/internal/transform"
+)
+
+// TestProxyHandler_HandleLogs_StreamsBodyWhenEnabled verifies that sync
+// logs streaming forwards the request body and relays the collector's
+// response without going through the buffered acquireBody path
+func TestProxyHandler_HandleLogs_StreamsBodyWhenEnabled(t *testing.T) {
+	var receivedBody []byte
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		receivedBody, err = io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("collector failed to read streamed body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	handler := NewProxyHandler(context.Background(), mockCollector.URL, "", nil, nil, true, nil, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, true, 0, 0, 429, 5, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", strings.NewReader("streamed-payload"))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handler.HandleLogs(c); err != nil {
+		t.Fatalf("HandleLogs returned error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+	if string(receivedBody) != "streamed-payload" {
+		t.Errorf("expected collector to receive %q, got %q", "streamed-payload", receivedBody)
+	}
+}
+
+// TestProxyHandler_CanStreamLogs_FalseWhenFeatureNeedsFullBody verifies that
+// streaming is disabled whenever a feature requiring the full body (resource
+// attribute rewrites here) is configured, even if streaming is enabled
+func TestProxyHandler_CanStreamLogs_FalseWhenFeatureNeedsFullBody(t *testing.T) {
+	rewriteRules := []transform.RewriteRule{{Action: transform.ActionSetDefault, Key: "k", Value: "v"}}
+	handler := NewProxyHandler(context.Background(), "http://example.com", "", nil, nil, true, rewriteRules, transform.SchemaNormalization{}, nil, nil, nil, "", 0, "", nil, "", nil, 0, nil, "", nil, nil, nil, nil, "", nil, nil, "", nil, true, 0, 0, 429, 5, nil)
+
+	if handler.canStreamLogs() {
+		t.Error("expected canStreamLogs to be false when resource attribute rewrite rules are configured")
+	}
+}
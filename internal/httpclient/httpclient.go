@@ -0,0 +1,82 @@
+// Package httpclient builds the shared *http.Client each forwarding mode
+// uses to deliver requests to the OTel collector, so the transport tuning
+// (timeouts, idle connection limits) that used to be hard-coded separately
+// in worker.NewPool, SemaphoreForwarder, and HybridForwarder lives in one
+// place and can be overridden via config.
+package httpclient
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// UpstreamClient is the minimal interface forwarders and the sync handler
+// path depend on to reach the collector. *http.Client satisfies it
+// unmodified, so New's return value needs no adapting; tests can substitute
+// a fake implementation to get deterministic, synchronous responses instead
+// of spinning up an httptest server and sleeping for timing.
+type UpstreamClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Config overrides the defaults New applies when building an *http.Client.
+// A zero-value Config (the zero value of every field) reproduces the exact
+// timeouts every forwarder hard-coded before these became configurable.
+type Config struct {
+	RequestTimeout      time.Duration // Overall per-request deadline (default: 10s)
+	DialTimeout         time.Duration // TCP connect deadline (default: 30s, net.Dialer's own default)
+	TLSHandshakeTimeout time.Duration // Deadline for the TLS handshake after connecting (default: 10s)
+	IdleConnTimeout     time.Duration // How long an idle keep-alive connection is kept in the pool (default: 90s)
+	TLSClientConfig     *tls.Config   // Min version/cipher suite/curve policy for the upstream connection; nil uses Go's default policy
+	MaxIdleConnsPerHost int           // Idle keep-alive connections kept per host (default: concurrency, one per concurrent sender)
+	MaxConnsPerHost     int           // Total connections (idle + in-flight) allowed per host (default: concurrency*2)
+	DisableHTTP2        bool          // Disable HTTP/2 upgrade negotiation, forcing HTTP/1.1 (default: false, HTTP/2 attempted)
+}
+
+// New builds an *http.Client tuned for concurrency concurrent forwarding
+// goroutines/workers, applying cfg's overrides on top of the defaults every
+// forwarder used to hard-code. concurrency sizes MaxIdleConnsPerHost (one
+// idle connection per concurrent sender) and MaxIdleConns/MaxConnsPerHost
+// (2x, to allow a burst of new connections alongside the idle pool).
+func New(concurrency int, cfg Config) *http.Client {
+	requestTimeout := cfg.RequestTimeout
+	if requestTimeout <= 0 {
+		requestTimeout = 10 * time.Second
+	}
+	dialTimeout := cfg.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = 30 * time.Second
+	}
+	tlsHandshakeTimeout := cfg.TLSHandshakeTimeout
+	if tlsHandshakeTimeout <= 0 {
+		tlsHandshakeTimeout = 10 * time.Second
+	}
+	idleConnTimeout := cfg.IdleConnTimeout
+	if idleConnTimeout <= 0 {
+		idleConnTimeout = 90 * time.Second
+	}
+	maxIdleConnsPerHost := cfg.MaxIdleConnsPerHost
+	if maxIdleConnsPerHost <= 0 {
+		maxIdleConnsPerHost = concurrency
+	}
+	maxConnsPerHost := cfg.MaxConnsPerHost
+	if maxConnsPerHost <= 0 {
+		maxConnsPerHost = concurrency * 2
+	}
+
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		ForceAttemptHTTP2:     !cfg.DisableHTTP2,
+		DialContext:           (&net.Dialer{Timeout: dialTimeout}).DialContext,
+		MaxIdleConns:          concurrency * 2,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		MaxConnsPerHost:       maxConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       cfg.TLSClientConfig,
+	}
+	return &http.Client{Transport: transport, Timeout: requestTimeout}
+}
@@ -0,0 +1,62 @@
+package httpclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNew_DefaultsWhenConfigIsZeroValue(t *testing.T) {
+	client := New(10, Config{})
+
+	if client.Timeout != 10*time.Second {
+		t.Errorf("expected default request timeout of 10s, got %v", client.Timeout)
+	}
+}
+
+func TestNew_AppliesConfigOverrides(t *testing.T) {
+	client := New(10, Config{
+		RequestTimeout:      5 * time.Second,
+		DialTimeout:         2 * time.Second,
+		TLSHandshakeTimeout: 3 * time.Second,
+		IdleConnTimeout:     30 * time.Second,
+	})
+
+	if client.Timeout != 5*time.Second {
+		t.Errorf("expected overridden request timeout of 5s, got %v", client.Timeout)
+	}
+}
+
+func TestNew_ConnectionPoolDefaultsFromConcurrency(t *testing.T) {
+	client := New(10, Config{})
+	transport := client.Transport.(*http.Transport)
+
+	if transport.MaxIdleConnsPerHost != 10 {
+		t.Errorf("expected MaxIdleConnsPerHost to default to concurrency (10), got %d", transport.MaxIdleConnsPerHost)
+	}
+	if transport.MaxConnsPerHost != 20 {
+		t.Errorf("expected MaxConnsPerHost to default to 2x concurrency (20), got %d", transport.MaxConnsPerHost)
+	}
+	if !transport.ForceAttemptHTTP2 {
+		t.Error("expected HTTP/2 to be attempted by default")
+	}
+}
+
+func TestNew_ConnectionPoolOverrides(t *testing.T) {
+	client := New(10, Config{
+		MaxIdleConnsPerHost: 50,
+		MaxConnsPerHost:     100,
+		DisableHTTP2:        true,
+	})
+	transport := client.Transport.(*http.Transport)
+
+	if transport.MaxIdleConnsPerHost != 50 {
+		t.Errorf("expected overridden MaxIdleConnsPerHost of 50, got %d", transport.MaxIdleConnsPerHost)
+	}
+	if transport.MaxConnsPerHost != 100 {
+		t.Errorf("expected overridden MaxConnsPerHost of 100, got %d", transport.MaxConnsPerHost)
+	}
+	if transport.ForceAttemptHTTP2 {
+		t.Error("expected HTTP/2 to be disabled")
+	}
+}
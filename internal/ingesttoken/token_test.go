@@ -0,0 +1,80 @@
+package ingesttoken
+
+import (
+    "testing"
+    "time"
+)
+
+// TestIssuer_IssueThenVerify_ReturnsBoundClaims verifies a freshly issued
+// token verifies successfully and carries the tenant/origin it was bound to
+func TestIssuer_IssueThenVerify_ReturnsBoundClaims(t *testing.T) {
+    issuer := NewIssuer("secret", time.Minute)
+    token, expiresAt, err := issuer.Issue("acme", "https://acme.example.com")
+    if err != nil {
+        t.Fatalf("Issue returned error: %v", err)
+    }
+
+    claims, err := issuer.Verify(token)
+    if err != nil {
+        t.Fatalf("Verify returned error: %v", err)
+    }
+    if claims.Tenant != "acme" || claims.Origin != "https://acme.example.com" {
+        t.Errorf("unexpected claims: %+v", claims)
+    }
+    if !claims.ExpiresAt.Equal(expiresAt) {
+        t.Errorf("expected claims.ExpiresAt %v to match issued expiry %v", claims.ExpiresAt, expiresAt)
+    }
+}
+
+// TestIssuer_Verify_RejectsExpiredToken verifies a token past its ttl fails verification
+func TestIssuer_Verify_RejectsExpiredToken(t *testing.T) {
+    issuer := NewIssuer("secret", 10*time.Millisecond)
+    token, _, err := issuer.Issue("acme", "https://acme.example.com")
+    if err != nil {
+        t.Fatalf("Issue returned error: %v", err)
+    }
+
+    time.Sleep(30 * time.Millisecond)
+    if _, err := issuer.Verify(token); err != ErrExpired {
+        t.Errorf("expected ErrExpired, got %v", err)
+    }
+}
+
+// TestIssuer_Verify_RejectsTamperedPayload verifies a token whose payload was
+// altered after issuance fails signature verification
+func TestIssuer_Verify_RejectsTamperedPayload(t *testing.T) {
+    issuer := NewIssuer("secret", time.Minute)
+    token, _, err := issuer.Issue("acme", "https://acme.example.com")
+    if err != nil {
+        t.Fatalf("Issue returned error: %v", err)
+    }
+
+    tampered := token + "AAAA"
+    if _, err := issuer.Verify(tampered); err != ErrInvalidSignature {
+        t.Errorf("expected ErrInvalidSignature, got %v", err)
+    }
+}
+
+// TestIssuer_Verify_RejectsWrongSecret verifies a token signed by one issuer
+// doesn't verify against another issuer with a different secret
+func TestIssuer_Verify_RejectsWrongSecret(t *testing.T) {
+    issuer := NewIssuer("secret-a", time.Minute)
+    other := NewIssuer("secret-b", time.Minute)
+
+    token, _, err := issuer.Issue("acme", "https://acme.example.com")
+    if err != nil {
+        t.Fatalf("Issue returned error: %v", err)
+    }
+    if _, err := other.Verify(token); err != ErrInvalidSignature {
+        t.Errorf("expected ErrInvalidSignature, got %v", err)
+    }
+}
+
+// TestIssuer_Verify_RejectsMalformedToken verifies a token with no
+// payload/signature separator is rejected rather than panicking
+func TestIssuer_Verify_RejectsMalformedToken(t *testing.T) {
+    issuer := NewIssuer("secret", time.Minute)
+    if _, err := issuer.Verify("not-a-real-token"); err != ErrMalformed {
+        t.Errorf("expected ErrMalformed, got %v", err)
+    }
+}
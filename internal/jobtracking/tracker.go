@@ -0,0 +1,108 @@
+// Package jobtracking keeps a bounded in-memory record of recent worker pool
+// job outcomes (queued -> sent -> failed), so an admin/debug endpoint can
+// answer "did my payload actually reach the collector?" during incident
+// investigation without re-running production traffic to reproduce it.
+package jobtracking
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status is the lifecycle state of a tracked job
+type Status string
+
+const (
+	StatusQueued Status = "queued"
+	StatusSent   Status = "sent"
+	StatusFailed Status = "failed"
+)
+
+// Record is a snapshot of a tracked job's current state
+type Record struct {
+	ID        string
+	TargetURL string
+	Status    Status
+	Reason    string // Set once Status is StatusFailed, empty otherwise
+	UpdatedAt time.Time
+}
+
+// Tracker is a fixed-capacity, thread-safe map of job ID to its current
+// status. Once at capacity, the oldest tracked record is evicted to admit a
+// new one, so a long-running process under sustained traffic doesn't grow
+// this without bound.
+type Tracker struct {
+	mu       sync.Mutex
+	capacity int
+	records  map[string]Record
+	order    []string // insertion order, oldest first, for FIFO eviction
+}
+
+// NewTracker creates a Tracker holding at most capacity records; capacity <= 0 defaults to 10000
+func NewTracker(capacity int) *Tracker {
+	if capacity <= 0 {
+		capacity = 10000
+	}
+	return &Tracker{capacity: capacity, records: make(map[string]Record)}
+}
+
+// NewID generates a random job ID suitable for passing to Track
+func NewID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("jobtracking: failed to generate id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Track records a newly-queued job under id, evicting the oldest tracked
+// record first if the Tracker is already at capacity
+func (t *Tracker) Track(id, targetURL string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.records[id]; !exists {
+		if len(t.order) >= t.capacity {
+			oldest := t.order[0]
+			t.order = t.order[1:]
+			delete(t.records, oldest)
+		}
+		t.order = append(t.order, id)
+	}
+	t.records[id] = Record{ID: id, TargetURL: targetURL, Status: StatusQueued, UpdatedAt: time.Now()}
+}
+
+// MarkSent transitions id to StatusSent; a no-op if id isn't tracked (e.g. never tracked, or already evicted)
+func (t *Tracker) MarkSent(id string) {
+	t.update(id, StatusSent, "")
+}
+
+// MarkFailed transitions id to StatusFailed with reason; a no-op if id isn't tracked
+func (t *Tracker) MarkFailed(id, reason string) {
+	t.update(id, StatusFailed, reason)
+}
+
+func (t *Tracker) update(id string, status Status, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rec, ok := t.records[id]
+	if !ok {
+		return
+	}
+	rec.Status = status
+	rec.Reason = reason
+	rec.UpdatedAt = time.Now()
+	t.records[id] = rec
+}
+
+// Get returns the current record for id, and whether it is still tracked
+func (t *Tracker) Get(id string) (Record, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rec, ok := t.records[id]
+	return rec, ok
+}
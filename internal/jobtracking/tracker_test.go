@@ -0,0 +1,113 @@
+package jobtracking
+
+import "testing"
+
+// TestTracker_Track_ThenGet_ReturnsQueuedStatus verifies a freshly tracked
+// job starts out in StatusQueued
+func TestTracker_Track_ThenGet_ReturnsQueuedStatus(t *testing.T) {
+	tr := NewTracker(10)
+	tr.Track("job-1", "http://collector/v1/logs")
+
+	rec, ok := tr.Get("job-1")
+	if !ok {
+		t.Fatal("expected job-1 to be tracked")
+	}
+	if rec.Status != StatusQueued {
+		t.Errorf("expected status %q, got %q", StatusQueued, rec.Status)
+	}
+	if rec.TargetURL != "http://collector/v1/logs" {
+		t.Errorf("expected target URL to be recorded, got %q", rec.TargetURL)
+	}
+}
+
+// TestTracker_MarkSent_TransitionsStatus verifies MarkSent moves a tracked
+// job from queued to sent
+func TestTracker_MarkSent_TransitionsStatus(t *testing.T) {
+	tr := NewTracker(10)
+	tr.Track("job-1", "http://collector/v1/logs")
+	tr.MarkSent("job-1")
+
+	rec, ok := tr.Get("job-1")
+	if !ok {
+		t.Fatal("expected job-1 to be tracked")
+	}
+	if rec.Status != StatusSent {
+		t.Errorf("expected status %q, got %q", StatusSent, rec.Status)
+	}
+}
+
+// TestTracker_MarkFailed_RecordsReason verifies MarkFailed transitions to
+// failed and retains the given reason
+func TestTracker_MarkFailed_RecordsReason(t *testing.T) {
+	tr := NewTracker(10)
+	tr.Track("job-1", "http://collector/v1/logs")
+	tr.MarkFailed("job-1", "retries exhausted")
+
+	rec, ok := tr.Get("job-1")
+	if !ok {
+		t.Fatal("expected job-1 to be tracked")
+	}
+	if rec.Status != StatusFailed {
+		t.Errorf("expected status %q, got %q", StatusFailed, rec.Status)
+	}
+	if rec.Reason != "retries exhausted" {
+		t.Errorf("expected reason %q, got %q", "retries exhausted", rec.Reason)
+	}
+}
+
+// TestTracker_Get_UnknownID_ReturnsFalse verifies a lookup for an id that was
+// never tracked (or has been evicted) reports not found rather than a zero record
+func TestTracker_Get_UnknownID_ReturnsFalse(t *testing.T) {
+	tr := NewTracker(10)
+	if _, ok := tr.Get("does-not-exist"); ok {
+		t.Error("expected unknown id to not be tracked")
+	}
+}
+
+// TestTracker_EvictsOldestOnceAtCapacity verifies the oldest tracked record
+// is dropped once a new one would exceed capacity
+func TestTracker_EvictsOldestOnceAtCapacity(t *testing.T) {
+	tr := NewTracker(2)
+	tr.Track("job-1", "http://collector/v1/logs")
+	tr.Track("job-2", "http://collector/v1/logs")
+	tr.Track("job-3", "http://collector/v1/logs")
+
+	if _, ok := tr.Get("job-1"); ok {
+		t.Error("expected job-1 to be evicted once capacity was exceeded")
+	}
+	if _, ok := tr.Get("job-2"); !ok {
+		t.Error("expected job-2 to still be tracked")
+	}
+	if _, ok := tr.Get("job-3"); !ok {
+		t.Error("expected job-3 to still be tracked")
+	}
+}
+
+// TestTracker_MarkSent_UnknownID_IsNoOp verifies marking an untracked id
+// doesn't panic or create a phantom record
+func TestTracker_MarkSent_UnknownID_IsNoOp(t *testing.T) {
+	tr := NewTracker(10)
+	tr.MarkSent("does-not-exist")
+
+	if _, ok := tr.Get("does-not-exist"); ok {
+		t.Error("expected marking an unknown id not to create a record")
+	}
+}
+
+// TestNewID_ReturnsDistinctIDs verifies NewID doesn't hand out repeats
+func TestNewID_ReturnsDistinctIDs(t *testing.T) {
+	a, err := NewID()
+	if err != nil {
+		t.Fatalf("NewID returned error: %v", err)
+	}
+	b, err := NewID()
+	if err != nil {
+		t.Fatalf("NewID returned error: %v", err)
+	}
+	if a == b {
+		t.Errorf("expected distinct ids, got %q twice", a)
+	}
+	if len(a) == 0 {
+		t.Error("expected a non-empty id")
+	}
+}
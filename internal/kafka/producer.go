@@ -0,0 +1,172 @@
+// Package kafka implements just enough of the Kafka wire protocol to
+// publish records to a single broker/partition, without depending on a
+// third-party client library. It supports the ProduceRequest (API key 0,
+// version 3) and record batch (magic byte 2) formats used by Kafka 0.11+
+// and compatible brokers.
+//
+// Cluster metadata and partition-leader discovery are intentionally out of
+// scope: Produce always targets partition 0 of the configured topic on the
+// configured broker. This is sufficient for the single-broker,
+// single-partition Kafka deployments this client is meant for, but callers
+// fronting a multi-broker cluster with more than one partition should point
+// this client at a partition-0 leader directly rather than a load balancer.
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Producer is a minimal, single-broker Kafka producer. It dials lazily on
+// the first Produce call and reconnects automatically if the connection is
+// lost, mirroring the retry-on-next-call behavior of this package's HTTP
+// forwarders rather than maintaining a background reconnect loop.
+type Producer struct {
+	broker   string
+	clientID string
+	timeout  time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+
+	correlationID int32
+}
+
+// NewProducer creates a Producer that publishes to broker (host:port).
+// clientID is sent with every request for broker-side logging/quotas.
+// timeout bounds both dialing and the produce round trip.
+func NewProducer(broker, clientID string, timeout time.Duration) *Producer {
+	return &Producer{
+		broker:   broker,
+		clientID: clientID,
+		timeout:  timeout,
+	}
+}
+
+// connect returns the current connection, dialing a new one if none is
+// open. Callers must hold p.mu.
+func (p *Producer) connect() (net.Conn, error) {
+	if p.conn != nil {
+		return p.conn, nil
+	}
+	conn, err := net.DialTimeout("tcp", p.broker, p.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: dial %s: %w", p.broker, err)
+	}
+	p.conn = conn
+	return conn, nil
+}
+
+// Produce publishes a single record with the given key, value and headers
+// to topic, waiting for the partition leader's acknowledgement. On any
+// connection-level error the underlying connection is dropped so the next
+// call reconnects rather than reusing a broken socket.
+func (p *Producer) Produce(topic string, key, value []byte, headers map[string]string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conn, err := p.connect()
+	if err != nil {
+		return err
+	}
+
+	correlationID := atomic.AddInt32(&p.correlationID, 1)
+	timeoutMillis := int32(p.timeout / time.Millisecond)
+	req := encodeProduceRequest(correlationID, p.clientID, topic, key, value, headers, timeoutMillis, time.Now().UnixMilli())
+
+	conn.SetDeadline(time.Now().Add(p.timeout))
+	if _, err := conn.Write(req); err != nil {
+		p.closeLocked()
+		return fmt.Errorf("kafka: write produce request: %w", err)
+	}
+
+	errorCode, err := readProduceResponse(conn)
+	if err != nil {
+		p.closeLocked()
+		return fmt.Errorf("kafka: read produce response: %w", err)
+	}
+	if errorCode != 0 {
+		return fmt.Errorf("kafka: broker returned error code %d for topic %s", errorCode, topic)
+	}
+	return nil
+}
+
+// readProduceResponse reads a ProduceResponse (v3) and returns the
+// error_code of its single topic/partition entry. Fields beyond the
+// error_code (base_offset, log_append_time, throttle_time_ms) aren't
+// needed by callers that only care whether the publish succeeded, so they
+// are parsed just enough to be skipped.
+func readProduceResponse(r io.Reader) (int16, error) {
+	var size int32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return 0, err
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, err
+	}
+
+	body := bytes.NewReader(buf)
+	var correlationID int32
+	if err := binary.Read(body, binary.BigEndian, &correlationID); err != nil {
+		return 0, err
+	}
+
+	var topicCount int32
+	if err := binary.Read(body, binary.BigEndian, &topicCount); err != nil {
+		return 0, err
+	}
+	if topicCount < 1 {
+		return 0, fmt.Errorf("kafka: produce response had no topics")
+	}
+
+	var topicNameLen int16
+	if err := binary.Read(body, binary.BigEndian, &topicNameLen); err != nil {
+		return 0, err
+	}
+	if _, err := body.Seek(int64(topicNameLen), io.SeekCurrent); err != nil {
+		return 0, err
+	}
+
+	var partitionCount int32
+	if err := binary.Read(body, binary.BigEndian, &partitionCount); err != nil {
+		return 0, err
+	}
+	if partitionCount < 1 {
+		return 0, fmt.Errorf("kafka: produce response had no partitions")
+	}
+
+	var partition int32
+	var errorCode int16
+	if err := binary.Read(body, binary.BigEndian, &partition); err != nil {
+		return 0, err
+	}
+	if err := binary.Read(body, binary.BigEndian, &errorCode); err != nil {
+		return 0, err
+	}
+	return errorCode, nil
+}
+
+// closeLocked closes and clears the current connection. Callers must hold
+// p.mu.
+func (p *Producer) closeLocked() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+}
+
+// Close closes the underlying connection, if any. Safe to call even if
+// Produce was never called.
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closeLocked()
+	return nil
+}
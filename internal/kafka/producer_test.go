@@ -0,0 +1,141 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeBroker accepts a single connection, reads one ProduceRequest and
+// replies with a ProduceResponse (v3) carrying the given error code. It
+// returns the raw request bytes it received for the caller to assert on.
+func fakeBroker(t *testing.T, errorCode int16) (addr string, requests chan []byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start fake broker: %v", err)
+	}
+	requests = make(chan []byte, 1)
+
+	go func() {
+		defer ln.Close()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var size int32
+		if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+			return
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return
+		}
+		requests <- buf
+
+		correlationID := int32(binary.BigEndian.Uint32(buf[4:8]))
+		resp := encodeFakeProduceResponse(correlationID, "test-topic", errorCode)
+		conn.Write(resp)
+	}()
+
+	return ln.Addr().String(), requests
+}
+
+// encodeFakeProduceResponse builds a ProduceResponse (v3) with a single
+// topic/partition entry, mirroring the shape readProduceResponse expects.
+func encodeFakeProduceResponse(correlationID int32, topic string, errorCode int16) []byte {
+	body := []byte{}
+	appendInt32 := func(v int32) {
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, uint32(v))
+		body = append(body, b...)
+	}
+	appendInt16 := func(v int16) {
+		b := make([]byte, 2)
+		binary.BigEndian.PutUint16(b, uint16(v))
+		body = append(body, b...)
+	}
+	appendInt64 := func(v int64) {
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, uint64(v))
+		body = append(body, b...)
+	}
+
+	appendInt32(correlationID)
+	appendInt32(1) // topic count
+	appendInt16(int16(len(topic)))
+	body = append(body, []byte(topic)...)
+	appendInt32(1) // partition count
+	appendInt32(0) // partition
+	appendInt16(errorCode)
+	appendInt64(0) // base_offset
+	appendInt64(-1) // log_append_time
+	appendInt32(0) // throttle_time_ms
+
+	framed := make([]byte, 4)
+	binary.BigEndian.PutUint32(framed, uint32(len(body)))
+	return append(framed, body...)
+}
+
+func TestProducer_Produce_Success(t *testing.T) {
+	addr, requests := fakeBroker(t, 0)
+	p := NewProducer(addr, "test-client", time.Second)
+	defer p.Close()
+
+	err := p.Produce("test-topic", []byte("key1"), []byte("value1"), map[string]string{"traceparent": "abc"})
+	if err != nil {
+		t.Fatalf("Produce returned unexpected error: %v", err)
+	}
+
+	select {
+	case req := <-requests:
+		if len(req) == 0 {
+			t.Fatal("broker received empty request")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("broker never received a request")
+	}
+}
+
+func TestProducer_Produce_BrokerError(t *testing.T) {
+	addr, _ := fakeBroker(t, 1) // OFFSET_OUT_OF_RANGE, any non-zero code works here
+	p := NewProducer(addr, "test-client", time.Second)
+	defer p.Close()
+
+	err := p.Produce("test-topic", nil, []byte("value1"), nil)
+	if err == nil {
+		t.Fatal("expected error for non-zero broker error code, got nil")
+	}
+}
+
+func TestProducer_Produce_ConnectionRefused(t *testing.T) {
+	p := NewProducer("127.0.0.1:1", "test-client", 200*time.Millisecond)
+	defer p.Close()
+
+	if err := p.Produce("test-topic", nil, []byte("value"), nil); err == nil {
+		t.Fatal("expected error dialing an unreachable broker, got nil")
+	}
+}
+
+func TestEncodeRecordBatch_RoundTripsViaFakeBroker(t *testing.T) {
+	addr, requests := fakeBroker(t, 0)
+	p := NewProducer(addr, "otlpxy", time.Second)
+	defer p.Close()
+
+	if err := p.Produce("otlp-traces", []byte("k"), []byte("v"), map[string]string{"h1": "v1"}); err != nil {
+		t.Fatalf("Produce returned unexpected error: %v", err)
+	}
+
+	req := <-requests
+	// apiKey(2) + apiVersion(2) + correlationId(4) precede the client id string.
+	if apiKey := binary.BigEndian.Uint16(req[0:2]); apiKey != apiKeyProduce {
+		t.Errorf("apiKey = %d, want %d", apiKey, apiKeyProduce)
+	}
+	if apiVersion := binary.BigEndian.Uint16(req[2:4]); apiVersion != produceAPIVersion {
+		t.Errorf("apiVersion = %d, want %d", apiVersion, produceAPIVersion)
+	}
+}
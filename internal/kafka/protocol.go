@@ -0,0 +1,149 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+)
+
+const (
+	apiKeyProduce     = 0
+	produceAPIVersion = 3
+	recordBatchMagic  = 2
+)
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// putUvarint appends v to buf as an unsigned LEB128 varint, the base
+// encoding record batch fields build zigzag varints on top of.
+func putUvarint(buf *bytes.Buffer, v uint64) {
+	for v >= 0x80 {
+		buf.WriteByte(byte(v) | 0x80)
+		v >>= 7
+	}
+	buf.WriteByte(byte(v))
+}
+
+// putVarint appends v to buf as a zigzag-encoded LEB128 varint, the encoding
+// the record batch format (magic byte 2) uses for every signed field.
+func putVarint(buf *bytes.Buffer, v int64) {
+	putUvarint(buf, uint64((v<<1)^(v>>63)))
+}
+
+// putString appends a non-nullable string as an int16 length prefix
+// followed by its bytes.
+func putString(buf *bytes.Buffer, s string) {
+	binary.Write(buf, binary.BigEndian, int16(len(s)))
+	buf.WriteString(s)
+}
+
+// putBytes appends a nullable byte string as an int32 length prefix (-1 for
+// nil) followed by its bytes.
+func putBytes(buf *bytes.Buffer, b []byte) {
+	if b == nil {
+		binary.Write(buf, binary.BigEndian, int32(-1))
+		return
+	}
+	binary.Write(buf, binary.BigEndian, int32(len(b)))
+	buf.Write(b)
+}
+
+// encodeRecord builds a single record (attributes, timestamp/offset deltas,
+// key, value and headers) in the format record batches embed, per
+// https://kafka.apache.org/documentation/#record. offsetDelta is always 0
+// since this client only ever puts one record in a batch.
+func encodeRecord(key, value []byte, headers map[string]string) []byte {
+	var rec bytes.Buffer
+	rec.WriteByte(0) // attributes, unused at the record level
+	putVarint(&rec, 0) // timestampDelta
+	putVarint(&rec, 0) // offsetDelta
+
+	if key == nil {
+		putVarint(&rec, -1)
+	} else {
+		putVarint(&rec, int64(len(key)))
+		rec.Write(key)
+	}
+	if value == nil {
+		putVarint(&rec, -1)
+	} else {
+		putVarint(&rec, int64(len(value)))
+		rec.Write(value)
+	}
+
+	putVarint(&rec, int64(len(headers)))
+	for k, v := range headers {
+		putVarint(&rec, int64(len(k)))
+		rec.WriteString(k)
+		putVarint(&rec, int64(len(v)))
+		rec.WriteString(v)
+	}
+
+	var framed bytes.Buffer
+	putVarint(&framed, int64(rec.Len()))
+	framed.Write(rec.Bytes())
+	return framed.Bytes()
+}
+
+// encodeRecordBatch wraps a single record in a v2 (magic byte 2) record
+// batch, the only record format modern brokers accept from a produce
+// request. producerId/producerEpoch/baseSequence are left at -1 (no
+// idempotent producer support - this client always writes with acks=1 and
+// no dedup guarantees, sufficient for buffering telemetry that tolerates
+// at-least-once delivery).
+func encodeRecordBatch(key, value []byte, headers map[string]string, timestampMillis int64) []byte {
+	record := encodeRecord(key, value, headers)
+
+	var body bytes.Buffer
+	binary.Write(&body, binary.BigEndian, int16(0))               // attributes
+	binary.Write(&body, binary.BigEndian, int32(0))                // lastOffsetDelta
+	binary.Write(&body, binary.BigEndian, timestampMillis)         // firstTimestamp
+	binary.Write(&body, binary.BigEndian, timestampMillis)         // maxTimestamp
+	binary.Write(&body, binary.BigEndian, int64(-1))                // producerId
+	binary.Write(&body, binary.BigEndian, int16(-1))                // producerEpoch
+	binary.Write(&body, binary.BigEndian, int32(-1))                // baseSequence
+	binary.Write(&body, binary.BigEndian, int32(1))                 // records count
+	body.Write(record)
+
+	crc := crc32.Checksum(body.Bytes(), castagnoliTable)
+
+	var batch bytes.Buffer
+	binary.Write(&batch, binary.BigEndian, int64(0)) // baseOffset
+	// batchLength covers everything after this field: partitionLeaderEpoch(4) + magic(1) + crc(4) + body
+	binary.Write(&batch, binary.BigEndian, int32(4+1+4+body.Len()))
+	binary.Write(&batch, binary.BigEndian, int32(-1)) // partitionLeaderEpoch
+	batch.WriteByte(recordBatchMagic)
+	binary.Write(&batch, binary.BigEndian, crc)
+	batch.Write(body.Bytes())
+	return batch.Bytes()
+}
+
+// encodeProduceRequest builds a full ProduceRequest (API key 0, version 3)
+// wire message - size prefix, request header and body - publishing a single
+// record to partition 0 of topic with acks=1 (wait for the partition
+// leader, don't wait on follower replication).
+func encodeProduceRequest(correlationID int32, clientID string, topic string, key, value []byte, headers map[string]string, timeoutMillis int32, timestampMillis int64) []byte {
+	recordBatch := encodeRecordBatch(key, value, headers, timestampMillis)
+
+	var body bytes.Buffer
+	putString(&body, "")                          // transactional_id (nullable in the spec, "" round-trips the same for our purposes)
+	binary.Write(&body, binary.BigEndian, int16(1)) // acks
+	binary.Write(&body, binary.BigEndian, timeoutMillis)
+	binary.Write(&body, binary.BigEndian, int32(1)) // topic_data array length
+	putString(&body, topic)
+	binary.Write(&body, binary.BigEndian, int32(1)) // partition_data array length
+	binary.Write(&body, binary.BigEndian, int32(0)) // partition
+	putBytes(&body, recordBatch)
+
+	var header bytes.Buffer
+	binary.Write(&header, binary.BigEndian, int16(apiKeyProduce))
+	binary.Write(&header, binary.BigEndian, int16(produceAPIVersion))
+	binary.Write(&header, binary.BigEndian, correlationID)
+	putString(&header, clientID)
+
+	var full bytes.Buffer
+	binary.Write(&full, binary.BigEndian, int32(header.Len()+body.Len()))
+	full.Write(header.Bytes())
+	full.Write(body.Bytes())
+	return full.Bytes()
+}
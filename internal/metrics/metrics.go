@@ -1,8 +1,18 @@
 package metrics
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/atomic"
 )
 
 var (
@@ -13,6 +23,25 @@ var (
 		Help:      "Current number of jobs in the worker pool queue",
 	})
 
+	// QueueWaitHistogram measures how long a job sat in the worker pool
+	// queue, from enqueue to a worker picking it up, in seconds. Queue depth
+	// alone doesn't say whether that backlog is seconds or minutes old.
+	QueueWaitHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "zep_logger",
+		Name:      "worker_pool_queue_wait_seconds",
+		Help:      "Time a job spent in the worker pool queue before a worker picked it up, in seconds",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// HybridForwarderPendingSendsGauge tracks fire-and-forget sender
+	// goroutines currently dispatching a request, which HybridForwarder's
+	// jobQueue alone doesn't account for once a worker has handed a job off
+	HybridForwarderPendingSendsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_hybrid_pending_sends",
+		Help:      "Current number of in-flight fire-and-forget sends dispatched by the hybrid forwarder",
+	})
+
 	// JobsProcessedCounter tracks the total number of jobs successfully forwarded
 	JobsProcessedCounter = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: "zep_logger",
@@ -27,10 +56,385 @@ var (
 		Help:      "Total number of jobs that failed to process (request errors, collector errors)",
 	})
 
+	// JobsExpiredCounter tracks the total number of jobs dropped for sitting in
+	// the queue longer than the configured job TTL (see worker.Pool.SetJobTTL)
+	// instead of being forwarded
+	JobsExpiredCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "worker_pool_jobs_expired_total",
+		Help:      "Total number of jobs dropped for exceeding the worker pool's job TTL instead of being forwarded",
+	})
+
+	// JobsEvictedCounter tracks the total number of queued jobs discarded to
+	// make room for a newer submission under queue_full_policy = "drop-oldest"
+	// (see worker.Pool.SetQueueFullPolicy), instead of the new submission
+	// being rejected
+	JobsEvictedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "worker_pool_jobs_evicted_total",
+		Help:      "Total number of queued jobs dropped to admit a newer one under queue_full_policy=drop-oldest",
+	})
+
 	// ActiveWorkersGauge tracks the number of workers currently processing jobs
 	ActiveWorkersGauge = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "zep_logger",
 		Name:      "worker_pool_active_workers",
 		Help:      "Current number of workers actively processing jobs (sending HTTP requests)",
 	})
+
+	// CanarySentCounter tracks synthetic canary requests sent through the proxy's own forwarding path
+	CanarySentCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "canary_requests_sent_total",
+		Help:      "Total number of synthetic soak-test requests sent through the proxy handler path",
+	})
+
+	// CanaryReceivedCounter tracks canary requests observed by the internal mock collector
+	CanaryReceivedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "canary_requests_received_total",
+		Help:      "Total number of synthetic soak-test requests received by the internal mock collector",
+	})
+
+	// CanaryDivergenceGauge tracks sent-minus-received delivery count over the last reconciliation window
+	CanaryDivergenceGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "zep_logger",
+		Name:      "canary_divergence",
+		Help:      "Difference between canary requests sent and received in the last reconciliation window (0 = no loss detected)",
+	})
+
+	// DeliveryDivergenceGauge tracks the difference between requests forwarded by this proxy
+	// and requests the downstream collector reports as accepted, over the last reconciliation window
+	DeliveryDivergenceGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "zep_logger",
+		Name:      "delivery_divergence",
+		Help:      "Difference between forwarded and collector-accepted counts in the last reconciliation window (0 = no loss detected)",
+	})
+
+	// WorkerPoolRetriesCounter tracks the total number of retry attempts made
+	// after a transient transport error or non-2xx response from the collector
+	WorkerPoolRetriesCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "worker_pool_retries_total",
+		Help:      "Total number of retry attempts made by the worker pool after a failed delivery",
+	})
+
+	// DeadLetterCountGauge tracks the number of jobs currently held in the dead-letter store
+	DeadLetterCountGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "zep_logger",
+		Name:      "dead_letter_jobs",
+		Help:      "Current number of permanently-failed jobs held in the dead-letter store",
+	})
+
+	// DeadLetterSizeBytesGauge tracks the total on-disk size of the dead-letter store
+	DeadLetterSizeBytesGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "zep_logger",
+		Name:      "dead_letter_bytes",
+		Help:      "Total on-disk size in bytes of job bodies held in the dead-letter store",
+	})
+
+	// TailSamplingBufferedTracesGauge tracks traces currently held in the tail-sampling buffer awaiting a decision
+	TailSamplingBufferedTracesGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "zep_logger",
+		Name:      "tail_sampling_buffered_traces",
+		Help:      "Current number of traces held in the tail-sampling buffer awaiting a keep/drop decision",
+	})
+
+	// TailSamplingKeptCounter tracks traces the tail-sampling buffer decided to forward
+	TailSamplingKeptCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "tail_sampling_traces_kept_total",
+		Help:      "Total number of traces kept and forwarded by the tail-sampling buffer",
+	})
+
+	// TailSamplingDroppedCounter tracks traces the tail-sampling buffer decided to drop
+	TailSamplingDroppedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "tail_sampling_traces_dropped_total",
+		Help:      "Total number of traces dropped by the tail-sampling buffer's sampling decision",
+	})
+
+	// LogRecordsDecodedCounter tracks the total number of individual log records
+	// seen in decoded /v1/logs payloads, for billing and capacity planning
+	LogRecordsDecodedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "log_records_decoded_total",
+		Help:      "Total number of individual log records seen in decoded logs payloads",
+	})
+
+	// SpansDecodedCounter tracks the total number of individual spans seen in
+	// decoded /v1/traces payloads, for billing and capacity planning
+	SpansDecodedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "spans_decoded_total",
+		Help:      "Total number of individual spans seen in decoded traces payloads",
+	})
+
+	// BatchedPayloadsMergedCounter tracks individual OTLP payloads merged into
+	// batched upstream requests by the batching forwarder
+	BatchedPayloadsMergedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_batched_payloads_merged_total",
+		Help:      "Total number of individual payloads merged into batched upstream requests",
+	})
+
+	// BatchFlushesCounter tracks upstream requests sent by the batching forwarder
+	BatchFlushesCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_batch_flushes_total",
+		Help:      "Total number of upstream requests sent by the batching forwarder",
+	})
+
+	// CORSRejectedCounter tracks preflight/request Origins that don't match
+	// allowed_origins, labeled by the offending origin. Cardinality is bounded
+	// by the caller (see app.corsDiagnosticsMiddleware), which caps the number
+	// of distinct origin labels and buckets the rest under "other"
+	CORSRejectedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "cors_rejected_origin_total",
+		Help:      "Total number of requests whose Origin header did not match allowed_origins, labeled by origin",
+	}, []string{"origin"})
+
+	// FailoverTargetHealthGauge reports 1 if the last request to a failover
+	// target succeeded, 0 otherwise, labeled by target URL
+	FailoverTargetHealthGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_failover_target_healthy",
+		Help:      "1 if the last request to this failover target succeeded, 0 otherwise",
+	}, []string{"target"})
+
+	// FailoverTargetRequestsCounter tracks requests attempted against each
+	// failover target, labeled by target URL and outcome ("success"/"error")
+	FailoverTargetRequestsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_failover_target_requests_total",
+		Help:      "Total number of requests attempted against each failover target, labeled by outcome",
+	}, []string{"target", "outcome"})
+
+	// CircuitBreakerDivertedCounter tracks requests diverted to the configured
+	// fallback collector because the primary forwarder's circuit was open
+	CircuitBreakerDivertedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_circuit_breaker_diverted_total",
+		Help:      "Total number of requests diverted to the fallback collector while the primary's circuit breaker was open",
+	})
+
+	// LoadBalancerTargetPendingGauge tracks in-flight requests per load-balanced
+	// target, used by the "least_pending" strategy and exported for observability
+	LoadBalancerTargetPendingGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_load_balancer_target_pending",
+		Help:      "Current number of in-flight requests to this load-balanced target",
+	}, []string{"target"})
+
+	// LoadBalancerTargetRequestsCounter tracks requests dispatched to each
+	// load-balanced target, labeled by target URL and outcome ("success"/"error")
+	LoadBalancerTargetRequestsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_load_balancer_target_requests_total",
+		Help:      "Total number of requests dispatched to each load-balanced target, labeled by outcome",
+	}, []string{"target", "outcome"})
+
+	// LargePayloadsOffloadedCounter tracks request bodies spooled to disk
+	// instead of being buffered entirely in memory
+	LargePayloadsOffloadedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "large_payloads_offloaded_total",
+		Help:      "Total number of request bodies spooled to a temp file for exceeding large_payload_threshold_bytes",
+	})
+
+	// MirrorRequestsCounter tracks best-effort tee sends to the mirror target,
+	// labeled by outcome. Mirror failures never affect the primary forwarding
+	// path, so this is the only visibility into whether mirroring is healthy
+	MirrorRequestsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_mirror_requests_total",
+		Help:      "Total number of best-effort requests sent to the mirror target, labeled by outcome",
+	}, []string{"outcome"})
+
+	// AdaptiveConcurrencyLimitGauge reports the adaptive forwarder's current
+	// self-tuned concurrency ceiling (forwarding_mode = "adaptive")
+	AdaptiveConcurrencyLimitGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_adaptive_concurrency_limit",
+		Help:      "Current concurrency ceiling of the adaptive (AIMD) forwarder",
+	})
+
+	// WorkerPoolLifecycleEventsCounter tracks worker pool lifecycle
+	// transitions, labeled by event ("started", "stopped", "resized",
+	// "paused", "spool_recovered"), so dashboards can annotate throughput
+	// graphs with lifecycle changes (e.g. via increase(...) over the panel's
+	// time range) without cross-referencing application logs by hand
+	WorkerPoolLifecycleEventsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "worker_pool_lifecycle_events_total",
+		Help:      "Total number of worker pool lifecycle transitions, labeled by event",
+	}, []string{"event"})
+
+	// TenantQueueDepthGauge tracks jobs currently pending in the worker pool
+	// queue per tenant, labeled by the tenant identifier read from
+	// tenant_queue_share_header (see worker.Pool.SetTenantQueueShare).
+	// Cardinality is bounded by the number of distinct tenants seen, plus
+	// "unknown" for jobs missing the header
+	TenantQueueDepthGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "zep_logger",
+		Name:      "worker_pool_tenant_queue_depth",
+		Help:      "Current number of jobs pending in the worker pool queue for this tenant",
+	}, []string{"tenant"})
+
+	// ArchiveWritesCounter tracks best-effort writes to the archive sink,
+	// labeled by outcome. Archive failures never affect the primary
+	// forwarding path, so this is the only visibility into whether
+	// archival is healthy
+	ArchiveWritesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "archive_writes_total",
+		Help:      "Total number of best-effort writes to the archive sink, labeled by outcome",
+	}, []string{"outcome"})
+
+	// ForwarderSaturationCounter tracks how often a request was accepted
+	// above the backpressure forwarder's soft watermark (still accepted,
+	// but with a Retry-After hint), separate from JobsFailedCounter which
+	// only tracks the hard watermark's outright rejections
+	ForwarderSaturationCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_saturation_total",
+		Help:      "Total number of requests accepted above the backpressure forwarder's soft watermark",
+	})
+
+	// ForwardLatencyHistogram tracks collector round-trip time (request sent
+	// to response received, excluding queueing/proxy-side work), labeled by
+	// target host and signal type, so collector-side slowness can be alerted
+	// on separately from overall proxy latency. Populated via ObserveForwardLatency.
+	ForwardLatencyHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_collector_roundtrip_seconds",
+		Help:      "Collector round-trip time in seconds, labeled by target host and signal type",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"target_host", "signal"})
+
+	// ForwardErrorsCounter breaks JobsFailedCounter down by cause, so a
+	// dashboard can tell a collector-side outage (4xx/5xx) apart from a
+	// network problem (dns_error/connect_timeout/tls_error/request_timeout)
+	// without grepping logs. Populated via ClassifyForwardError, in addition
+	// to (not instead of) JobsFailedCounter.
+	ForwardErrorsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "zep_logger",
+		Name:      "forwarder_errors_total",
+		Help:      "Total number of failed forward attempts, labeled by failure cause",
+	}, []string{"cause"})
 )
+
+// signalFromTargetURL derives the OTLP signal type from targetURL's path
+// suffix (as appended by ProxyHandler.splitByResourceRoute), falling back to
+// "unknown" for targets that don't end in a recognized OTLP path (e.g. a
+// resource-routing rule pointing at a bare host)
+func signalFromTargetURL(targetURL string) string {
+	switch {
+	case len(targetURL) >= len("/v1/logs") && targetURL[len(targetURL)-len("/v1/logs"):] == "/v1/logs":
+		return "logs"
+	case len(targetURL) >= len("/v1/traces") && targetURL[len(targetURL)-len("/v1/traces"):] == "/v1/traces":
+		return "traces"
+	default:
+		return "unknown"
+	}
+}
+
+// ClassifyForwardError inspects a failed forward attempt and returns a coarse
+// cause label ("dns_error", "connect_timeout", "tls_error",
+// "request_timeout", "4xx", "5xx", or "other"), suitable for
+// ForwardErrorsCounter. err is the error returned by http.Client.Do (nil if
+// the round trip completed); statusCode is the response status (ignored when
+// err is non-nil).
+func ClassifyForwardError(err error, statusCode int) string {
+	if err == nil {
+		switch {
+		case statusCode >= 500:
+			return "5xx"
+		case statusCode >= 400:
+			return "4xx"
+		default:
+			return "other"
+		}
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return "dns_error"
+	}
+
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &certErr) || errors.As(err, &unknownAuthErr) || errors.As(err, &hostnameErr) {
+		return "tls_error"
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return "connect_timeout"
+		}
+		return "request_timeout"
+	}
+
+	return "other"
+}
+
+// ObserveForwardLatency records the elapsed time since start against
+// ForwardLatencyHistogram, labeled by targetURL's host and signal type.
+// Called by forwarder implementations immediately after their upstream
+// HTTP round trip completes (success or failure)
+func ObserveForwardLatency(targetURL string, start time.Time) {
+	host := targetURL
+	if parsed, err := url.Parse(targetURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+	ForwardLatencyHistogram.WithLabelValues(host, signalFromTargetURL(targetURL)).Observe(time.Since(start).Seconds())
+}
+
+// CounterValue reads the current value of a prometheus.Counter without
+// needing a scrape - the same technique client_golang's own testutil helpers
+// use. Returns 0 if c refuses to report itself (practically unreachable for
+// the counters this package defines).
+func CounterValue(c prometheus.Counter) float64 {
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		return 0
+	}
+	return m.GetCounter().GetValue()
+}
+
+// BatchedCounter accumulates increments in a local, per-instance delta and
+// only touches the wrapped Prometheus counter on Flush, trading a small
+// amount of metric staleness for far fewer atomic operations against a
+// counter that would otherwise be incremented by every request. Intended to
+// be owned by a single long-lived goroutine (e.g. one instance per worker
+// pool worker) rather than shared - sharing one instance across goroutines
+// just moves the contention from the wrapped counter to delta.
+type BatchedCounter struct {
+	counter prometheus.Counter
+	delta   atomic.Int64
+}
+
+// NewBatchedCounter wraps counter with a local delta buffer. Callers must
+// call Flush periodically (e.g. on a time.Ticker) and once more before the
+// owning goroutine exits, or a partial batch is lost.
+func NewBatchedCounter(counter prometheus.Counter) *BatchedCounter {
+	return &BatchedCounter{counter: counter}
+}
+
+// Inc records one occurrence in the local delta without touching the
+// wrapped counter
+func (b *BatchedCounter) Inc() {
+	b.delta.Inc()
+}
+
+// Flush adds the accumulated delta to the wrapped counter and resets it to
+// zero. Safe to call when nothing has been recorded since the last Flush.
+func (b *BatchedCounter) Flush() {
+	if d := b.delta.Swap(0); d != 0 {
+		b.counter.Add(float64(d))
+	}
+}
@@ -1,13 +1,22 @@
 package metrics
 
 import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo-contrib/echoprometheus"
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	"go.uber.org/atomic"
 )
 
@@ -172,3 +181,170 @@ func TestMetrics_HTTPRequestMetrics(t *testing.T) {
 
 // TestApp_QueueDepthMetric_Integration has been moved to the app package
 // This test requires app.NewApp which creates a circular dependency if called from metrics package
+
+// TestBatchedCounter_FlushAddsAccumulatedDelta verifies Inc only touches the
+// local delta and Flush is what actually adds to the wrapped counter
+func TestBatchedCounter_FlushAddsAccumulatedDelta(t *testing.T) {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_batched_counter_flush_total"})
+	b := NewBatchedCounter(counter)
+
+	b.Inc()
+	b.Inc()
+	b.Inc()
+
+	if got := testutil.ToFloat64(counter); got != 0 {
+		t.Fatalf("expected wrapped counter to stay at 0 before Flush, got %v", got)
+	}
+
+	b.Flush()
+
+	if got := testutil.ToFloat64(counter); got != 3 {
+		t.Fatalf("expected wrapped counter to be 3 after Flush, got %v", got)
+	}
+}
+
+// TestBatchedCounter_FlushIsNoOpWithoutNewIncrements verifies a second Flush
+// with no intervening Inc calls doesn't double-count the previous delta
+func TestBatchedCounter_FlushIsNoOpWithoutNewIncrements(t *testing.T) {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_batched_counter_noop_flush_total"})
+	b := NewBatchedCounter(counter)
+
+	b.Inc()
+	b.Flush()
+	b.Flush()
+
+	if got := testutil.ToFloat64(counter); got != 1 {
+		t.Fatalf("expected wrapped counter to stay at 1 after redundant Flush, got %v", got)
+	}
+}
+
+// TestClassifyForwardError_StatusCodes verifies status-only failures (no
+// transport error) are bucketed by status class
+func TestClassifyForwardError_StatusCodes(t *testing.T) {
+	cases := []struct {
+		statusCode int
+		want       string
+	}{
+		{503, "5xx"},
+		{500, "5xx"},
+		{429, "4xx"},
+		{400, "4xx"},
+		{200, "other"},
+	}
+	for _, tc := range cases {
+		if got := ClassifyForwardError(nil, tc.statusCode); got != tc.want {
+			t.Errorf("ClassifyForwardError(nil, %d) = %q, want %q", tc.statusCode, got, tc.want)
+		}
+	}
+}
+
+// TestClassifyForwardError_DNSError verifies a *net.DNSError is classified
+// as dns_error regardless of how deeply it's wrapped
+func TestClassifyForwardError_DNSError(t *testing.T) {
+	err := fmt.Errorf("dial failed: %w", &net.DNSError{Err: "no such host", Name: "collector.invalid", IsNotFound: true})
+	if got := ClassifyForwardError(err, 0); got != "dns_error" {
+		t.Errorf("expected dns_error, got %q", got)
+	}
+}
+
+// TestClassifyForwardError_ConnectTimeout verifies a dial-phase timeout is
+// distinguished from a request-phase timeout
+func TestClassifyForwardError_ConnectTimeout(t *testing.T) {
+	err := &net.OpError{Op: "dial", Net: "tcp", Err: timeoutError{}}
+	if got := ClassifyForwardError(err, 0); got != "connect_timeout" {
+		t.Errorf("expected connect_timeout, got %q", got)
+	}
+}
+
+// TestClassifyForwardError_RequestTimeout verifies a non-dial timeout falls
+// back to the generic request_timeout bucket
+func TestClassifyForwardError_RequestTimeout(t *testing.T) {
+	err := &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}
+	if got := ClassifyForwardError(err, 0); got != "request_timeout" {
+		t.Errorf("expected request_timeout, got %q", got)
+	}
+}
+
+// TestClassifyForwardError_TLSError verifies a certificate verification
+// failure is classified as tls_error
+func TestClassifyForwardError_TLSError(t *testing.T) {
+	err := x509.HostnameError{Certificate: &x509.Certificate{}, Host: "collector.invalid"}
+	if got := ClassifyForwardError(err, 0); got != "tls_error" {
+		t.Errorf("expected tls_error, got %q", got)
+	}
+}
+
+// TestClassifyForwardError_UnrecognizedError falls back to "other"
+func TestClassifyForwardError_UnrecognizedError(t *testing.T) {
+	if got := ClassifyForwardError(errors.New("connection reset by peer"), 0); got != "other" {
+		t.Errorf("expected other, got %q", got)
+	}
+}
+
+// timeoutError is a minimal net.Error implementation for constructing
+// synthetic timeout errors in tests
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// histogramSampleCount returns the number of observations recorded against a
+// single label combination of ForwardLatencyHistogram
+func histogramSampleCount(host, signal string) uint64 {
+	var m dto.Metric
+	ForwardLatencyHistogram.WithLabelValues(host, signal).(prometheus.Histogram).Write(&m)
+	return m.GetHistogram().GetSampleCount()
+}
+
+// TestObserveForwardLatency_LabelsByHostAndSignal verifies the histogram is
+// recorded under the target's host and the signal derived from its path
+func TestObserveForwardLatency_LabelsByHostAndSignal(t *testing.T) {
+	before := histogramSampleCount("collector.example.com:4318", "traces")
+	ObserveForwardLatency("http://collector.example.com:4318/v1/traces", time.Now())
+
+	if after := histogramSampleCount("collector.example.com:4318", "traces"); after != before+1 {
+		t.Fatalf("expected sample count to increase by 1, got %d -> %d", before, after)
+	}
+}
+
+// TestObserveForwardLatency_UnknownPathFallsBackToUnknownSignal verifies a
+// target URL that isn't a recognized OTLP path (e.g. a resource-routing rule
+// pointing at a bare host) doesn't panic and is labeled "unknown"
+func TestObserveForwardLatency_UnknownPathFallsBackToUnknownSignal(t *testing.T) {
+	before := histogramSampleCount("billing-collector.example.com", "unknown")
+	ObserveForwardLatency("http://billing-collector.example.com", time.Now())
+
+	if after := histogramSampleCount("billing-collector.example.com", "unknown"); after != before+1 {
+		t.Fatalf("expected sample count to increase by 1, got %d -> %d", before, after)
+	}
+}
+
+// TestBatchedCounter_ConcurrentIncMatchesFlushedTotal verifies Inc is safe
+// for concurrent use by many goroutines sharing one BatchedCounter, even
+// though normal usage is one instance per goroutine
+func TestBatchedCounter_ConcurrentIncMatchesFlushedTotal(t *testing.T) {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_batched_counter_concurrent_total"})
+	b := NewBatchedCounter(counter)
+
+	const goroutines = 50
+	const incsEach = 100
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < incsEach; j++ {
+				b.Inc()
+			}
+		}()
+	}
+	wg.Wait()
+	b.Flush()
+
+	want := float64(goroutines * incsEach)
+	if got := testutil.ToFloat64(counter); got != want {
+		t.Fatalf("expected wrapped counter to be %v, got %v", want, got)
+	}
+}
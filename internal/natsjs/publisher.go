@@ -0,0 +1,252 @@
+// Package natsjs implements just enough of the NATS client protocol to
+// publish a message to a JetStream subject and wait for its publish
+// acknowledgement, without depending on a third-party client library. It
+// speaks the plain-text NATS protocol (INFO/CONNECT/PUB/SUB/MSG) directly
+// over TCP; TLS-terminated or authenticated NATS deployments and full
+// subscription/consumer support are out of scope, since this package only
+// ever needs a synchronous "publish and wait for the stream to ack" round
+// trip.
+package natsjs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Ack is a JetStream publish acknowledgement, returned by the stream on the
+// reply subject of a JetStream publish.
+type Ack struct {
+	Stream string `json:"stream"`
+	Seq    uint64 `json:"seq"`
+	Error  *struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"error,omitempty"`
+}
+
+// Publisher is a minimal, single-server NATS JetStream publisher. It dials
+// lazily on the first Publish call and reconnects automatically if the
+// connection is lost, mirroring the retry-on-next-call behavior of this
+// package's Kafka counterpart rather than maintaining a background
+// reconnect loop.
+type Publisher struct {
+	addr    string
+	timeout time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+
+	inboxCounter int64
+}
+
+// NewPublisher creates a Publisher connecting to addr (host:port). timeout
+// bounds both dialing and the publish/ack round trip.
+func NewPublisher(addr string, timeout time.Duration) *Publisher {
+	return &Publisher{
+		addr:    addr,
+		timeout: timeout,
+	}
+}
+
+// connect returns the current connection, dialing and handshaking a new one
+// if none is open. Callers must hold p.mu.
+func (p *Publisher) connect() (*bufio.Reader, error) {
+	if p.conn != nil {
+		return p.reader, nil
+	}
+
+	conn, err := net.DialTimeout("tcp", p.addr, p.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsjs: dial %s: %w", p.addr, err)
+	}
+	conn.SetDeadline(time.Now().Add(p.timeout))
+
+	reader := bufio.NewReader(conn)
+	// The server greets every new connection with an INFO line before
+	// anything else is sent.
+	if _, err := readLine(reader); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("natsjs: read INFO: %w", err)
+	}
+
+	if _, err := conn.Write([]byte("CONNECT {\"verbose\":false,\"pedantic\":false}\r\nPING\r\n")); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("natsjs: write CONNECT: %w", err)
+	}
+	if err := waitForPong(reader); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("natsjs: handshake: %w", err)
+	}
+
+	p.conn = conn
+	p.reader = reader
+	return reader, nil
+}
+
+// Publish sends payload to subject as a JetStream publish (a request with a
+// reply-to inbox subject), and waits for the stream's publish
+// acknowledgement on that inbox. It returns an error if the connection
+// fails, the ack can't be parsed, or the ack itself carries a JetStream
+// error (e.g. the subject has no stream bound to it).
+func (p *Publisher) Publish(subject string, payload []byte) (*Ack, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	reader, err := p.connect()
+	if err != nil {
+		return nil, err
+	}
+	p.conn.SetDeadline(time.Now().Add(p.timeout))
+
+	sid := atomic.AddInt64(&p.inboxCounter, 1)
+	inbox := fmt.Sprintf("_INBOX.otlpxy.%d", sid)
+	sidStr := strconv.FormatInt(sid, 10)
+
+	frame := fmt.Sprintf("SUB %s %s\r\nPUB %s %s %d\r\n", inbox, sidStr, subject, inbox, len(payload))
+	if _, err := p.conn.Write([]byte(frame)); err != nil {
+		p.closeLocked()
+		return nil, fmt.Errorf("natsjs: write SUB/PUB: %w", err)
+	}
+	if _, err := p.conn.Write(payload); err != nil {
+		p.closeLocked()
+		return nil, fmt.Errorf("natsjs: write payload: %w", err)
+	}
+	if _, err := p.conn.Write([]byte("\r\nUNSUB " + sidStr + "\r\n")); err != nil {
+		p.closeLocked()
+		return nil, fmt.Errorf("natsjs: write UNSUB: %w", err)
+	}
+
+	ackBody, err := readMsgBody(reader, sidStr)
+	if err != nil {
+		p.closeLocked()
+		return nil, fmt.Errorf("natsjs: read ack: %w", err)
+	}
+
+	var ack Ack
+	if err := json.Unmarshal(ackBody, &ack); err != nil {
+		return nil, fmt.Errorf("natsjs: decode ack: %w", err)
+	}
+	if ack.Error != nil {
+		return &ack, fmt.Errorf("natsjs: jetstream rejected publish to %s: %s (code %d)", subject, ack.Error.Description, ack.Error.Code)
+	}
+	return &ack, nil
+}
+
+// readLine reads a single CRLF-terminated protocol line, with the
+// terminator stripped.
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// waitForPong reads protocol lines until it sees the PONG that answers the
+// PING sent during CONNECT, returning an error if the server reports -ERR
+// first.
+func waitForPong(reader *bufio.Reader) error {
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			return err
+		}
+		switch {
+		case strings.HasPrefix(line, "PONG"):
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("server rejected connect: %s", line)
+		}
+	}
+}
+
+// readMsgBody reads protocol lines until it finds a MSG frame addressed to
+// sid, then reads and returns its payload. Any -ERR seen along the way is
+// surfaced as an error.
+func readMsgBody(reader *bufio.Reader, sid string) ([]byte, error) {
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			return nil, fmt.Errorf("server error: %s", line)
+		}
+		if !strings.HasPrefix(line, "MSG ") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		// MSG <subject> <sid> [reply-to] <#bytes>
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("malformed MSG frame: %q", line)
+		}
+		if fields[2] != sid {
+			// Not ours; drain and skip it.
+			n, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return nil, fmt.Errorf("malformed MSG frame: %q", line)
+			}
+			if _, err := discard(reader, n+2); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		n, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil {
+			return nil, fmt.Errorf("malformed MSG frame: %q", line)
+		}
+		body := make([]byte, n)
+		if _, err := discardInto(reader, body); err != nil {
+			return nil, err
+		}
+		if _, err := discard(reader, 2); err != nil { // trailing CRLF
+			return nil, err
+		}
+		return body, nil
+	}
+}
+
+func discard(reader *bufio.Reader, n int) (int, error) {
+	return reader.Discard(n)
+}
+
+func discardInto(reader *bufio.Reader, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := reader.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// closeLocked closes and clears the current connection. Callers must hold
+// p.mu.
+func (p *Publisher) closeLocked() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+		p.reader = nil
+	}
+}
+
+// Close closes the underlying connection, if any. Safe to call even if
+// Publish was never called.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closeLocked()
+	return nil
+}
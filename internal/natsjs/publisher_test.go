@@ -0,0 +1,120 @@
+package natsjs
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeServer accepts a single connection, performs the INFO/CONNECT/PING
+// handshake, then waits for a SUB+PUB and replies on the inbox with ackBody
+// (or, if ackBody is empty, doesn't reply at all - useful for exercising a
+// timeout). It returns the published payload it received for the caller to
+// assert on.
+func fakeServer(t *testing.T, ackBody string) (addr string, published chan []byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start fake server: %v", err)
+	}
+	published = make(chan []byte, 1)
+
+	go func() {
+		defer ln.Close()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+
+		reader := bufio.NewReader(conn)
+		var inbox string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			switch {
+			case strings.HasPrefix(line, "CONNECT"):
+				// no reply expected
+			case line == "PING":
+				conn.Write([]byte("PONG\r\n"))
+			case strings.HasPrefix(line, "SUB "):
+				fields := strings.Fields(line)
+				inbox = fields[1]
+			case strings.HasPrefix(line, "PUB "):
+				fields := strings.Fields(line)
+				n := 0
+				fmt.Sscanf(fields[len(fields)-1], "%d", &n)
+				body := make([]byte, n)
+				readN := 0
+				for readN < n {
+					m, err := reader.Read(body[readN:])
+					readN += m
+					if err != nil {
+						return
+					}
+				}
+				reader.Discard(2) // trailing CRLF
+				published <- body
+
+				if ackBody != "" {
+					msg := fmt.Sprintf("MSG %s 1 %d\r\n%s\r\n", inbox, len(ackBody), ackBody)
+					conn.Write([]byte(msg))
+				}
+			case strings.HasPrefix(line, "UNSUB"):
+				// no-op
+			}
+		}
+	}()
+
+	return ln.Addr().String(), published
+}
+
+func TestPublisher_Publish_Success(t *testing.T) {
+	addr, published := fakeServer(t, `{"stream":"otlp","seq":42}`)
+	p := NewPublisher(addr, time.Second)
+	defer p.Close()
+
+	ack, err := p.Publish("otlp.logs", []byte("payload"))
+	if err != nil {
+		t.Fatalf("Publish returned unexpected error: %v", err)
+	}
+	if ack.Stream != "otlp" || ack.Seq != 42 {
+		t.Errorf("ack = %+v, want stream=otlp seq=42", ack)
+	}
+
+	select {
+	case body := <-published:
+		if string(body) != "payload" {
+			t.Errorf("published body = %q, want %q", body, "payload")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("server never received a publish")
+	}
+}
+
+func TestPublisher_Publish_JetStreamError(t *testing.T) {
+	addr, _ := fakeServer(t, `{"error":{"code":503,"description":"no responders"}}`)
+	p := NewPublisher(addr, time.Second)
+	defer p.Close()
+
+	if _, err := p.Publish("otlp.logs", []byte("payload")); err == nil {
+		t.Fatal("expected error for a JetStream error ack, got nil")
+	}
+}
+
+func TestPublisher_Publish_ConnectionRefused(t *testing.T) {
+	p := NewPublisher("127.0.0.1:1", 200*time.Millisecond)
+	defer p.Close()
+
+	if _, err := p.Publish("otlp.logs", []byte("payload")); err == nil {
+		t.Fatal("expected error dialing an unreachable server, got nil")
+	}
+}
@@ -0,0 +1,137 @@
+// Package powchallenge issues and verifies lightweight proof-of-work
+// challenges for unauthenticated ingest, raising the cost of scripted
+// telemetry spam without requiring a login or API key.
+package powchallenge
+
+import (
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+
+    "zep-logger/internal/dedup"
+)
+
+// defaultTTL is used when NewVerifier is given a non-positive ttl
+const defaultTTL = 60 * time.Second
+
+// ErrMalformed indicates a nonce isn't in the expected "id:difficulty:expiry:signature" form
+var ErrMalformed = errors.New("challenge nonce is malformed")
+
+// ErrInvalidSignature indicates a nonce's signature doesn't match its fields
+var ErrInvalidSignature = errors.New("challenge nonce has an invalid signature")
+
+// ErrExpired indicates a nonce's expiry has passed
+var ErrExpired = errors.New("challenge nonce expired")
+
+// ErrAlreadyUsed indicates a nonce was already redeemed once (rotating-nonce, single use)
+var ErrAlreadyUsed = errors.New("challenge nonce already used")
+
+// ErrInvalidSolution indicates the solution's hash doesn't meet the required difficulty
+var ErrInvalidSolution = errors.New("challenge solution does not meet required difficulty")
+
+// Challenge is what's handed back to the client from the issuance endpoint
+type Challenge struct {
+    Nonce      string `json:"nonce"`
+    Difficulty int    `json:"difficulty"`
+}
+
+// Verifier mints HMAC-signed, single-use proof-of-work nonces and verifies
+// client-submitted solutions against them
+type Verifier struct {
+    secret     []byte
+    ttl        time.Duration
+    difficulty int
+    seen       *dedup.Deduper // guards against a nonce being redeemed more than once within ttl
+}
+
+// NewVerifier creates a Verifier that signs nonces with secret, valid for
+// ttl, requiring difficulty leading zero bits in a solution's hash. ttl <= 0
+// defaults to 60s.
+func NewVerifier(secret string, ttl time.Duration, difficulty int) *Verifier {
+    if ttl <= 0 {
+        ttl = defaultTTL
+    }
+    return &Verifier{secret: []byte(secret), ttl: ttl, difficulty: difficulty, seen: dedup.NewDeduper(ttl)}
+}
+
+// Start launches the background goroutine that evicts expired, redeemed nonces
+func (v *Verifier) Start() {
+    v.seen.Start()
+}
+
+// Stop halts the background nonce-replay eviction goroutine
+func (v *Verifier) Stop() {
+    v.seen.Stop()
+}
+
+// Issue mints a new challenge nonce good for ttl
+func (v *Verifier) Issue() (Challenge, error) {
+    var raw [16]byte
+    if _, err := rand.Read(raw[:]); err != nil {
+        return Challenge{}, err
+    }
+    id := hex.EncodeToString(raw[:])
+    payload := fmt.Sprintf("%s:%d:%d", id, v.difficulty, time.Now().Add(v.ttl).Unix())
+    return Challenge{Nonce: payload + ":" + v.sign(payload), Difficulty: v.difficulty}, nil
+}
+
+// Verify checks that nonce is authentic, unexpired, and not already
+// redeemed, and that solution's hash meets nonce's required difficulty. A
+// nonce is consumed (marked used) as soon as it passes signature/expiry
+// checks, regardless of whether the solution itself is valid, so a single
+// nonce can't be brute-forced with repeated solution attempts.
+func (v *Verifier) Verify(nonce string, solution string) error {
+    parts := strings.Split(nonce, ":")
+    if len(parts) != 4 {
+        return ErrMalformed
+    }
+    id, difficultyStr, expiryStr, signature := parts[0], parts[1], parts[2], parts[3]
+    payload := id + ":" + difficultyStr + ":" + expiryStr
+    if !hmac.Equal([]byte(signature), []byte(v.sign(payload))) {
+        return ErrInvalidSignature
+    }
+    expiryUnix, err := strconv.ParseInt(expiryStr, 10, 64)
+    if err != nil {
+        return ErrMalformed
+    }
+    if time.Now().After(time.Unix(expiryUnix, 0)) {
+        return ErrExpired
+    }
+    difficulty, err := strconv.Atoi(difficultyStr)
+    if err != nil {
+        return ErrMalformed
+    }
+    if v.seen.Seen(id) {
+        return ErrAlreadyUsed
+    }
+    if !meetsDifficulty(sha256.Sum256([]byte(id+solution)), difficulty) {
+        return ErrInvalidSolution
+    }
+    return nil
+}
+
+func (v *Verifier) sign(payload string) string {
+    mac := hmac.New(sha256.New, v.secret)
+    mac.Write([]byte(payload))
+    return hex.EncodeToString(mac.Sum(nil))
+}
+
+// meetsDifficulty reports whether hash has at least bits leading zero bits
+func meetsDifficulty(hash [32]byte, bits int) bool {
+    for i := 0; i < bits; i++ {
+        byteIdx, bitIdx := i/8, 7-i%8
+        if byteIdx >= len(hash) {
+            return false
+        }
+        if hash[byteIdx]&(1<<bitIdx) != 0 {
+            return false
+        }
+    }
+    return true
+}
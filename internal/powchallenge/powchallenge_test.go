@@ -0,0 +1,151 @@
+package powchallenge
+
+import (
+    "crypto/sha256"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+)
+
+// solve brute-forces a valid solution for id at the given difficulty, for tests only
+func solve(t *testing.T, id string, difficulty int) string {
+    t.Helper()
+    for i := 0; ; i++ {
+        candidate := strconv.Itoa(i)
+        if meetsDifficulty(sha256.Sum256([]byte(id+candidate)), difficulty) {
+            return candidate
+        }
+        if i > 1_000_000 {
+            t.Fatalf("failed to find a solution for id %s at difficulty %d within 1,000,000 attempts", id, difficulty)
+        }
+    }
+}
+
+func nonceID(t *testing.T, nonce string) string {
+    t.Helper()
+    for i, c := range nonce {
+        if c == ':' {
+            return nonce[:i]
+        }
+    }
+    t.Fatalf("nonce %q has no ':' separator", nonce)
+    return ""
+}
+
+// TestVerifier_IssueThenVerify_AcceptsValidSolution verifies a correctly
+// solved, freshly issued nonce is accepted
+func TestVerifier_IssueThenVerify_AcceptsValidSolution(t *testing.T) {
+    v := NewVerifier("secret", time.Minute, 4)
+    v.Start()
+    defer v.Stop()
+
+    challenge, err := v.Issue()
+    if err != nil {
+        t.Fatalf("Issue returned error: %v", err)
+    }
+    solution := solve(t, nonceID(t, challenge.Nonce), challenge.Difficulty)
+
+    if err := v.Verify(challenge.Nonce, solution); err != nil {
+        t.Errorf("expected a correctly solved nonce to verify, got %v", err)
+    }
+}
+
+// TestVerifier_Verify_RejectsWrongSolution verifies a solution that doesn't
+// meet the required difficulty is rejected
+func TestVerifier_Verify_RejectsWrongSolution(t *testing.T) {
+    v := NewVerifier("secret", time.Minute, 32)
+    v.Start()
+    defer v.Stop()
+
+    challenge, err := v.Issue()
+    if err != nil {
+        t.Fatalf("Issue returned error: %v", err)
+    }
+    if err := v.Verify(challenge.Nonce, "not-a-real-solution"); err != ErrInvalidSolution {
+        t.Errorf("expected ErrInvalidSolution, got %v", err)
+    }
+}
+
+// TestVerifier_Verify_RejectsReuseOfSameNonce verifies a nonce can only be
+// redeemed once, so a solved challenge can't be replayed
+func TestVerifier_Verify_RejectsReuseOfSameNonce(t *testing.T) {
+    v := NewVerifier("secret", time.Minute, 4)
+    v.Start()
+    defer v.Stop()
+
+    challenge, err := v.Issue()
+    if err != nil {
+        t.Fatalf("Issue returned error: %v", err)
+    }
+    solution := solve(t, nonceID(t, challenge.Nonce), challenge.Difficulty)
+
+    if err := v.Verify(challenge.Nonce, solution); err != nil {
+        t.Fatalf("expected first redemption to succeed, got %v", err)
+    }
+    if err := v.Verify(challenge.Nonce, solution); err != ErrAlreadyUsed {
+        t.Errorf("expected ErrAlreadyUsed on replay, got %v", err)
+    }
+}
+
+// TestVerifier_Verify_RejectsExpiredNonce verifies a nonce past its ttl is rejected
+func TestVerifier_Verify_RejectsExpiredNonce(t *testing.T) {
+    v := NewVerifier("secret", 10*time.Millisecond, 4)
+    v.Start()
+    defer v.Stop()
+
+    challenge, err := v.Issue()
+    if err != nil {
+        t.Fatalf("Issue returned error: %v", err)
+    }
+    solution := solve(t, nonceID(t, challenge.Nonce), challenge.Difficulty)
+
+    time.Sleep(30 * time.Millisecond)
+    if err := v.Verify(challenge.Nonce, solution); err != ErrExpired {
+        t.Errorf("expected ErrExpired, got %v", err)
+    }
+}
+
+// TestVerifier_Verify_RejectsTamperedNonce verifies a nonce with an altered
+// difficulty or expiry fails signature verification
+func TestVerifier_Verify_RejectsTamperedNonce(t *testing.T) {
+    v := NewVerifier("secret", time.Minute, 4)
+    v.Start()
+    defer v.Stop()
+
+    challenge, err := v.Issue()
+    if err != nil {
+        t.Fatalf("Issue returned error: %v", err)
+    }
+
+    // Bump the signed difficulty field by one instead of flipping the last
+    // character of the whole nonce - that character is the tail of the
+    // hex-encoded signature, and flipping it to a digit it already is (~1/16
+    // of the time) is a no-op, leaving the signature valid.
+    parts := strings.Split(challenge.Nonce, ":")
+    if len(parts) != 4 {
+        t.Fatalf("expected a 4-field nonce, got %q", challenge.Nonce)
+    }
+    difficulty, err := strconv.Atoi(parts[1])
+    if err != nil {
+        t.Fatalf("failed to parse difficulty field: %v", err)
+    }
+    parts[1] = strconv.Itoa(difficulty + 1)
+    tampered := strings.Join(parts, ":")
+
+    if err := v.Verify(tampered, "0"); err != ErrInvalidSignature {
+        t.Errorf("expected ErrInvalidSignature, got %v", err)
+    }
+}
+
+// TestVerifier_Verify_RejectsMalformedNonce verifies a nonce with the wrong
+// number of fields is rejected rather than panicking
+func TestVerifier_Verify_RejectsMalformedNonce(t *testing.T) {
+    v := NewVerifier("secret", time.Minute, 4)
+    v.Start()
+    defer v.Stop()
+
+    if err := v.Verify("not-a-real-nonce", "solution"); err != ErrMalformed {
+        t.Errorf("expected ErrMalformed, got %v", err)
+    }
+}
@@ -0,0 +1,129 @@
+// Package ratelimitpersist periodically snapshots a golang.org/x/time/rate.Limiter's
+// remaining tokens to disk and restores that snapshot into a freshly built
+// Limiter's initial full bucket, so a process restart doesn't hand every
+// caller a fresh burst allowance - which customers otherwise exploit by
+// timing uploads around deploys.
+package ratelimitpersist
+
+import (
+    "encoding/json"
+    "os"
+    "sync"
+    "time"
+
+    "golang.org/x/time/rate"
+
+    "zep-logger/pkg/logger"
+)
+
+// snapshot is the on-disk representation of a Limiter's remaining tokens as
+// of SavedAt. SavedAt is currently unused by Restore (see its doc comment)
+// but kept so a future version can account for elapsed downtime.
+type snapshot struct {
+    Tokens  float64   `json:"tokens"`
+    SavedAt time.Time `json:"saved_at"`
+}
+
+// Restore consumes tokens from limiter's initial-full bucket down to
+// whatever count was persisted at path, so it starts back where the previous
+// process left off instead of full. A missing or unreadable file is treated
+// as "no prior state" (limiter is left full, the original behavior) rather
+// than an error, since a lost snapshot should degrade gracefully rather than
+// block startup. Downtime between the last save and this call is not
+// credited back as refill - the bucket resumes exactly as empty/full as it
+// was last persisted, then refills at its configured rate from now.
+func Restore(path string, limiter *rate.Limiter) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return
+    }
+
+    var snap snapshot
+    if err := json.Unmarshal(data, &snap); err != nil {
+        logger.Warn("Rate limiter state: failed to parse %s, starting with a full bucket: %v", path, err)
+        return
+    }
+
+    burst := float64(limiter.Burst())
+    consume := burst - snap.Tokens
+    if consume <= 0 {
+        return
+    }
+    if consume > burst {
+        consume = burst
+    }
+    limiter.ReserveN(time.Now(), int(consume))
+}
+
+// Persister periodically snapshots a Limiter's current token count to path,
+// so Restore can rebuild it across a restart.
+type Persister struct {
+    path     string
+    limiter  *rate.Limiter
+    interval time.Duration
+
+    stopCh    chan struct{}
+    wg        sync.WaitGroup
+    startOnce sync.Once
+    stopOnce  sync.Once
+}
+
+// NewPersister creates a Persister that snapshots limiter to path every
+// interval (defaults to 30s if <= 0).
+func NewPersister(path string, limiter *rate.Limiter, interval time.Duration) *Persister {
+    if interval <= 0 {
+        interval = 30 * time.Second
+    }
+    return &Persister{
+        path:     path,
+        limiter:  limiter,
+        interval: interval,
+        stopCh:   make(chan struct{}),
+    }
+}
+
+func (p *Persister) Start() {
+    p.startOnce.Do(func() {
+        p.wg.Add(1)
+        go p.run()
+    })
+}
+
+// Stop stops the periodic snapshot loop and saves one final snapshot so the
+// most recent state isn't lost to the last unsaved interval.
+func (p *Persister) Stop() {
+    p.stopOnce.Do(func() {
+        close(p.stopCh)
+        p.wg.Wait()
+        p.save()
+    })
+}
+
+func (p *Persister) run() {
+    defer p.wg.Done()
+    ticker := time.NewTicker(p.interval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ticker.C:
+            p.save()
+        case <-p.stopCh:
+            return
+        }
+    }
+}
+
+func (p *Persister) save() {
+    snap := snapshot{
+        Tokens:  p.limiter.Tokens(),
+        SavedAt: time.Now(),
+    }
+    data, err := json.Marshal(snap)
+    if err != nil {
+        logger.Error("Rate limiter state: failed to marshal snapshot for %s: %v", p.path, err)
+        return
+    }
+    if err := os.WriteFile(p.path, data, 0644); err != nil {
+        logger.Error("Rate limiter state: failed to write %s: %v", p.path, err)
+    }
+}
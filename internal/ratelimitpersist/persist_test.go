@@ -0,0 +1,51 @@
+package ratelimitpersist
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "golang.org/x/time/rate"
+)
+
+func TestRestore_NoFile_LeavesLimiterFull(t *testing.T) {
+    limiter := rate.NewLimiter(rate.Limit(10), 100)
+    Restore(filepath.Join(t.TempDir(), "missing.json"), limiter)
+
+    if tokens := limiter.Tokens(); tokens != 100 {
+        t.Errorf("expected a full bucket when no state file exists, got %f tokens", tokens)
+    }
+}
+
+func TestPersisterThenRestore_RoundTripsRemainingTokens(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "state.json")
+
+    limiter := rate.NewLimiter(rate.Limit(10), 100)
+    limiter.ReserveN(time.Now(), 60) // leaves 40 tokens
+
+    p := NewPersister(path, limiter, time.Hour)
+    p.Start()
+    p.Stop() // Stop always saves once, regardless of the interval
+
+    restored := rate.NewLimiter(rate.Limit(10), 100)
+    Restore(path, restored)
+
+    if tokens := restored.Tokens(); tokens > 45 || tokens < 39 {
+        t.Errorf("expected restored limiter to have ~40 tokens, got %f", tokens)
+    }
+}
+
+func TestRestore_CorruptFile_LeavesLimiterFull(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "state.json")
+    if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+        t.Fatalf("failed to write corrupt state file: %v", err)
+    }
+
+    limiter := rate.NewLimiter(rate.Limit(10), 100)
+    Restore(path, limiter)
+
+    if tokens := limiter.Tokens(); tokens != 100 {
+        t.Errorf("expected a full bucket when the state file is corrupt, got %f tokens", tokens)
+    }
+}
@@ -0,0 +1,155 @@
+package reconciler
+
+import (
+    "fmt"
+    "net/http"
+    "sync"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus"
+    dto "github.com/prometheus/client_model/go"
+    "github.com/prometheus/common/expfmt"
+
+    "zep-logger/internal/metrics"
+    "zep-logger/pkg/logger"
+)
+
+// Reconciler periodically compares the number of requests this proxy has
+// forwarded against the number the downstream collector reports as accepted,
+// scraping the collector's own metrics endpoint. Divergence between the two
+// over a time window is a signal of silent data loss between proxy and collector.
+type Reconciler struct {
+    collectorMetricsURL string
+    metricName          string
+    interval            time.Duration
+    httpClient          *http.Client
+
+    lastForwarded float64
+    lastAccepted  float64
+
+    stopCh    chan struct{}
+    wg        sync.WaitGroup
+    startOnce sync.Once
+    stopOnce  sync.Once
+}
+
+// New creates a Reconciler
+// collectorMetricsURL: the collector's own Prometheus metrics endpoint (e.g. http://collector:8888/metrics)
+// metricName: the collector-side counter representing accepted requests
+func New(collectorMetricsURL string, metricName string, interval time.Duration) *Reconciler {
+    if interval <= 0 {
+        interval = 60 * time.Second
+    }
+    return &Reconciler{
+        collectorMetricsURL: collectorMetricsURL,
+        metricName:          metricName,
+        interval:            interval,
+        httpClient:          &http.Client{Timeout: 10 * time.Second},
+        stopCh:              make(chan struct{}),
+    }
+}
+
+// Start begins the reconciliation loop in a background goroutine
+func (r *Reconciler) Start() {
+    r.startOnce.Do(func() {
+        logger.Info("Delivery reconciler started: collectorMetricsURL=%s, metricName=%s, interval=%v", r.collectorMetricsURL, r.metricName, r.interval)
+        r.wg.Add(1)
+        go r.run()
+    })
+}
+
+// Stop halts the reconciliation loop
+func (r *Reconciler) Stop() {
+    r.stopOnce.Do(func() {
+        close(r.stopCh)
+        r.wg.Wait()
+    })
+}
+
+func (r *Reconciler) run() {
+    defer r.wg.Done()
+
+    ticker := time.NewTicker(r.interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-r.stopCh:
+            return
+        case <-ticker.C:
+            r.reconcile()
+        }
+    }
+}
+
+// reconcile compares the delta in forwarded vs accepted counts since the last
+// window and emits the divergence metric
+func (r *Reconciler) reconcile() {
+    accepted, err := r.fetchCollectorAccepted()
+    if err != nil {
+        logger.Warn("Delivery reconciler: failed to scrape collector metrics: %v", err)
+        return
+    }
+
+    forwarded := counterValue(metrics.JobsProcessedCounter)
+
+    deltaForwarded := forwarded - r.lastForwarded
+    deltaAccepted := accepted - r.lastAccepted
+    r.lastForwarded = forwarded
+    r.lastAccepted = accepted
+
+    divergence := deltaForwarded - deltaAccepted
+    metrics.DeliveryDivergenceGauge.Set(divergence)
+
+    if divergence != 0 {
+        logger.Warn("Delivery reconciler: divergence detected in this window, forwarded=%.0f accepted=%.0f divergence=%.0f", deltaForwarded, deltaAccepted, divergence)
+    }
+}
+
+// fetchCollectorAccepted scrapes the collector's metrics endpoint and sums the
+// configured counter across all label combinations
+func (r *Reconciler) fetchCollectorAccepted() (float64, error) {
+    resp, err := r.httpClient.Get(r.collectorMetricsURL)
+    if err != nil {
+        return 0, fmt.Errorf("failed to fetch %s: %w", r.collectorMetricsURL, err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return 0, fmt.Errorf("collector metrics endpoint returned status %d", resp.StatusCode)
+    }
+
+    var parser expfmt.TextParser
+    families, err := parser.TextToMetricFamilies(resp.Body)
+    if err != nil {
+        return 0, fmt.Errorf("failed to parse collector metrics: %w", err)
+    }
+
+    family, ok := families[r.metricName]
+    if !ok {
+        return 0, fmt.Errorf("metric %q not found in collector metrics output", r.metricName)
+    }
+
+    var total float64
+    for _, m := range family.GetMetric() {
+        switch {
+        case m.GetCounter() != nil:
+            total += m.GetCounter().GetValue()
+        case m.GetGauge() != nil:
+            total += m.GetGauge().GetValue()
+        case m.GetUntyped() != nil:
+            total += m.GetUntyped().GetValue()
+        }
+    }
+    return total, nil
+}
+
+// counterValue reads the current value of a prometheus.Counter without needing
+// a scrape - the same technique client_golang's own testutil helpers use
+func counterValue(c prometheus.Counter) float64 {
+    var m dto.Metric
+    if err := c.Write(&m); err != nil {
+        return 0
+    }
+    return m.GetCounter().GetValue()
+}
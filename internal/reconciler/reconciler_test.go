@@ -0,0 +1,50 @@
+package reconciler
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus/testutil"
+
+    "zep-logger/internal/metrics"
+)
+
+// TestReconciler_DetectsDivergence verifies the divergence gauge reflects the
+// gap between forwarded and collector-accepted counts over a window
+func TestReconciler_DetectsDivergence(t *testing.T) {
+    mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+        _, _ = w.Write([]byte("otelcol_receiver_accepted_log_records_total 5\n"))
+    }))
+    defer mockCollector.Close()
+
+    before := testutil.ToFloat64(metrics.JobsProcessedCounter)
+    metrics.JobsProcessedCounter.Add(10)
+
+    r := New(mockCollector.URL, "otelcol_receiver_accepted_log_records_total", time.Hour)
+    r.reconcile()
+
+    wantDivergence := (before + 10) - 5
+    if v := testutil.ToFloat64(metrics.DeliveryDivergenceGauge); v != wantDivergence {
+        t.Errorf("expected divergence %v, got %v", wantDivergence, v)
+    }
+}
+
+// TestReconciler_MetricNotFound_LeavesStatePrevious verifies a scrape failure
+// does not update the last-seen counters
+func TestReconciler_MetricNotFound_LeavesStatePrevious(t *testing.T) {
+    mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+        _, _ = w.Write([]byte("some_other_metric 1\n"))
+    }))
+    defer mockCollector.Close()
+
+    r := New(mockCollector.URL, "otelcol_receiver_accepted_log_records_total", time.Hour)
+    r.reconcile()
+
+    if r.lastAccepted != 0 {
+        t.Errorf("expected lastAccepted to remain 0 on scrape failure, got %v", r.lastAccepted)
+    }
+}
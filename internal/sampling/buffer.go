@@ -0,0 +1,219 @@
+// Package sampling implements an experimental tail-based sampling buffer for
+// deployments without a sampling-capable collector tier: spans are held per
+// trace ID for a short window, then a simple tail rule decides whether the
+// whole trace is forwarded (errored or slow traces, plus a sample of the
+// rest) or dropped.
+package sampling
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	tracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracedata "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"zep-logger/internal/forwarder"
+	"zep-logger/internal/metrics"
+	"zep-logger/pkg/logger"
+)
+
+// Rules configures the tail decision applied to each buffered trace once its window elapses
+type Rules struct {
+	Window            time.Duration // How long spans for a trace are buffered before a decision is made
+	SlowThreshold     time.Duration // A trace is "slow" if any span's duration meets or exceeds this
+	ErrorSampleRate   float64       // Fraction (0.0-1.0) of errored/slow traces kept; 1.0 keeps all of them
+	DefaultSampleRate float64       // Fraction (0.0-1.0) of otherwise-uninteresting traces kept
+}
+
+type traceRecord struct {
+	firstSeen     time.Time
+	resourceSpans []*tracedata.ResourceSpans
+	hasError      bool
+	maxDuration   time.Duration
+}
+
+// Buffer accumulates spans per trace ID and flushes each trace as a keep/drop
+// decision once its buffering window elapses
+type Buffer struct {
+	rules     Rules
+	fwd       forwarder.Forwarder
+	targetURL string
+	headers   http.Header
+
+	mu     sync.Mutex
+	traces map[string]*traceRecord
+	rng    *rand.Rand
+
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// New creates a Buffer that flushes decisions through fwd to targetURL+"/v1/traces"
+// with the given headers (typically just Content-Type and Authorization)
+func New(rules Rules, fwd forwarder.Forwarder, targetURL string, headers http.Header) *Buffer {
+	if rules.Window <= 0 {
+		rules.Window = 10 * time.Second
+	}
+	return &Buffer{
+		rules:     rules,
+		fwd:       fwd,
+		targetURL: targetURL,
+		headers:   headers,
+		traces:    make(map[string]*traceRecord),
+		rng:       rand.New(rand.NewSource(1)),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start begins the background flush loop that evaluates buffered traces once their window elapses
+func (b *Buffer) Start() {
+	b.startOnce.Do(func() {
+		logger.Info("Tail-sampling buffer started: window=%v, slowThreshold=%v, errorSampleRate=%.2f, defaultSampleRate=%.2f",
+			b.rules.Window, b.rules.SlowThreshold, b.rules.ErrorSampleRate, b.rules.DefaultSampleRate)
+		b.wg.Add(1)
+		go b.run()
+	})
+}
+
+// Stop halts the flush loop and flushes every trace still buffered, regardless of window
+func (b *Buffer) Stop() {
+	b.stopOnce.Do(func() {
+		close(b.stopCh)
+		b.wg.Wait()
+		b.flushDue(time.Time{}) // zero cutoff: everything is "due"
+	})
+}
+
+// Ingest decodes an ExportTraceServiceRequest and buffers its spans by trace ID.
+// Returns an error (and buffers nothing) if body can't be decoded, so callers
+// can fall back to forwarding the raw body unmodified.
+func (b *Buffer) Ingest(body []byte, contentType string) error {
+	req := &tracepb.ExportTraceServiceRequest{}
+	if err := unmarshal(body, contentType, req); err != nil {
+		return fmt.Errorf("sampling: failed to decode traces payload: %w", err)
+	}
+
+	now := time.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				b.bufferSpan(now, rs, ss, span)
+			}
+		}
+	}
+	metrics.TailSamplingBufferedTracesGauge.Set(float64(len(b.traces)))
+	return nil
+}
+
+func (b *Buffer) bufferSpan(now time.Time, rs *tracedata.ResourceSpans, ss *tracedata.ScopeSpans, span *tracedata.Span) {
+	traceID := hex.EncodeToString(span.TraceId)
+	rec, ok := b.traces[traceID]
+	if !ok {
+		rec = &traceRecord{firstSeen: now}
+		b.traces[traceID] = rec
+	}
+
+	rec.resourceSpans = append(rec.resourceSpans, &tracedata.ResourceSpans{
+		Resource:  rs.Resource,
+		SchemaUrl: rs.SchemaUrl,
+		ScopeSpans: []*tracedata.ScopeSpans{{
+			Scope:     ss.Scope,
+			SchemaUrl: ss.SchemaUrl,
+			Spans:     []*tracedata.Span{span},
+		}},
+	})
+
+	if span.Status != nil && span.Status.Code == tracedata.Status_STATUS_CODE_ERROR {
+		rec.hasError = true
+	}
+	if span.EndTimeUnixNano > span.StartTimeUnixNano {
+		if d := time.Duration(span.EndTimeUnixNano - span.StartTimeUnixNano); d > rec.maxDuration {
+			rec.maxDuration = d
+		}
+	}
+}
+
+func (b *Buffer) run() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.rules.Window / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopCh:
+			return
+		case <-ticker.C:
+			b.flushDue(time.Now().Add(-b.rules.Window))
+		}
+	}
+}
+
+// flushDue evaluates and removes every trace whose firstSeen is at or before cutoff
+func (b *Buffer) flushDue(cutoff time.Time) {
+	b.mu.Lock()
+	var due []*traceRecord
+	for id, rec := range b.traces {
+		if !rec.firstSeen.After(cutoff) {
+			due = append(due, rec)
+			delete(b.traces, id)
+		}
+	}
+	metrics.TailSamplingBufferedTracesGauge.Set(float64(len(b.traces)))
+	b.mu.Unlock()
+
+	for _, rec := range due {
+		b.decide(rec)
+	}
+}
+
+func (b *Buffer) decide(rec *traceRecord) {
+	interesting := rec.hasError || (b.rules.SlowThreshold > 0 && rec.maxDuration >= b.rules.SlowThreshold)
+
+	sampleRate := b.rules.DefaultSampleRate
+	if interesting {
+		sampleRate = b.rules.ErrorSampleRate
+	}
+
+	b.mu.Lock()
+	roll := b.rng.Float64()
+	b.mu.Unlock()
+
+	if roll >= sampleRate {
+		metrics.TailSamplingDroppedCounter.Inc()
+		return
+	}
+
+	req := &tracepb.ExportTraceServiceRequest{ResourceSpans: rec.resourceSpans}
+	body, err := proto.Marshal(req)
+	if err != nil {
+		logger.Error("Tail-sampling buffer: failed to encode kept trace: %v", err)
+		return
+	}
+	if err := b.fwd.Submit(context.Background(), body, b.targetURL, b.headers); err != nil {
+		logger.Warn("Tail-sampling buffer: failed to submit kept trace, dropping: %v", err)
+		return
+	}
+	metrics.TailSamplingKeptCounter.Inc()
+}
+
+func unmarshal(body []byte, contentType string, msg proto.Message) error {
+	if strings.Contains(contentType, "json") {
+		return protojson.Unmarshal(body, msg)
+	}
+	return proto.Unmarshal(body, msg)
+}
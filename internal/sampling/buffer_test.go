@@ -0,0 +1,125 @@
+package sampling
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	tracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracedata "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+type fakeForwarder struct {
+	mu       sync.Mutex
+	submits  int
+	lastBody []byte
+}
+
+func (f *fakeForwarder) Start() {}
+func (f *fakeForwarder) Stop(ctx context.Context) error { return nil }
+func (f *fakeForwarder) Submit(ctx context.Context, body []byte, targetURL string, headers http.Header) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.submits++
+	f.lastBody = body
+	return nil
+}
+func (f *fakeForwarder) GetQueueDepth() int { return 0 }
+func (f *fakeForwarder) Flush(ctx context.Context) error { return nil }
+func (f *fakeForwarder) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.submits
+}
+
+func spanWithTraceID(traceID byte, status tracedata.Status_StatusCode, start, end uint64) *tracedata.Span {
+	return &tracedata.Span{
+		TraceId:           []byte{traceID, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		SpanId:            []byte{1, 2, 3, 4, 5, 6, 7, 8},
+		Status:            &tracedata.Status{Code: status},
+		StartTimeUnixNano: start,
+		EndTimeUnixNano:   end,
+	}
+}
+
+func requestWithSpans(spans ...*tracedata.Span) []byte {
+	req := &tracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracedata.ResourceSpans{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{}},
+			ScopeSpans: []*tracedata.ScopeSpans{{
+				Spans: spans,
+			}},
+		}},
+	}
+	body, _ := proto.Marshal(req)
+	return body
+}
+
+func TestBuffer_KeepsErroredTraces(t *testing.T) {
+	fwd := &fakeForwarder{}
+	buf := New(Rules{Window: 50 * time.Millisecond, ErrorSampleRate: 1.0, DefaultSampleRate: 0.0}, fwd, "http://collector/v1/traces", nil)
+	buf.Start()
+	defer buf.Stop()
+
+	body := requestWithSpans(spanWithTraceID(1, tracedata.Status_STATUS_CODE_ERROR, 0, 100))
+	if err := buf.Ingest(body, "application/x-protobuf"); err != nil {
+		t.Fatalf("failed to ingest: %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if fwd.count() != 1 {
+		t.Errorf("expected errored trace to be kept and forwarded, got %d submits", fwd.count())
+	}
+}
+
+func TestBuffer_DropsUninterestingTracesAtZeroDefaultRate(t *testing.T) {
+	fwd := &fakeForwarder{}
+	buf := New(Rules{Window: 50 * time.Millisecond, ErrorSampleRate: 1.0, DefaultSampleRate: 0.0}, fwd, "http://collector/v1/traces", nil)
+	buf.Start()
+	defer buf.Stop()
+
+	body := requestWithSpans(spanWithTraceID(2, tracedata.Status_STATUS_CODE_OK, 0, 100))
+	if err := buf.Ingest(body, "application/x-protobuf"); err != nil {
+		t.Fatalf("failed to ingest: %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if fwd.count() != 0 {
+		t.Errorf("expected uninteresting trace to be dropped at default_sample_rate=0, got %d submits", fwd.count())
+	}
+}
+
+func TestBuffer_KeepsSlowTraces(t *testing.T) {
+	fwd := &fakeForwarder{}
+	buf := New(Rules{Window: 50 * time.Millisecond, SlowThreshold: 500 * time.Millisecond, ErrorSampleRate: 1.0, DefaultSampleRate: 0.0}, fwd, "http://collector/v1/traces", nil)
+	buf.Start()
+	defer buf.Stop()
+
+	slowSpan := spanWithTraceID(3, tracedata.Status_STATUS_CODE_OK, 0, uint64(time.Second))
+	body := requestWithSpans(slowSpan)
+	if err := buf.Ingest(body, "application/x-protobuf"); err != nil {
+		t.Fatalf("failed to ingest: %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if fwd.count() != 1 {
+		t.Errorf("expected slow trace to be kept and forwarded, got %d submits", fwd.count())
+	}
+}
+
+func TestBuffer_Ingest_MalformedBody_ReturnsError(t *testing.T) {
+	fwd := &fakeForwarder{}
+	buf := New(Rules{Window: time.Second}, fwd, "http://collector/v1/traces", nil)
+
+	if err := buf.Ingest([]byte{0xff, 0xff, 0xff}, "application/x-protobuf"); err == nil {
+		t.Fatal("expected error decoding malformed traces payload")
+	}
+}
@@ -0,0 +1,83 @@
+package spoolcrypto
+
+import "testing"
+
+const (
+	testKeyV1 = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	testKeyV2 = "1f1e1d1c1b1a191817161514131211100f0e0d0c0b0a09080706050403020100"
+)
+
+func TestKeySet_EncryptDecrypt_RoundTrips(t *testing.T) {
+	ks, err := NewKeySet("v1", map[string]string{"v1": testKeyV1})
+	if err != nil {
+		t.Fatalf("NewKeySet returned unexpected error: %v", err)
+	}
+
+	plaintext := []byte("raw RUM payload with user content")
+	ciphertext, err := ks.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt returned unexpected error: %v", err)
+	}
+
+	decrypted, err := ks.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt returned unexpected error: %v", err)
+	}
+	if string(decrypted) != string(plaintext) {
+		t.Errorf("decrypted = %q, want %q", decrypted, plaintext)
+	}
+}
+
+func TestKeySet_Decrypt_SurvivesKeyRotation(t *testing.T) {
+	ksBeforeRotation, err := NewKeySet("v1", map[string]string{"v1": testKeyV1})
+	if err != nil {
+		t.Fatalf("NewKeySet returned unexpected error: %v", err)
+	}
+	ciphertext, err := ksBeforeRotation.Encrypt([]byte("written under v1"))
+	if err != nil {
+		t.Fatalf("Encrypt returned unexpected error: %v", err)
+	}
+
+	ksAfterRotation, err := NewKeySet("v2", map[string]string{"v1": testKeyV1, "v2": testKeyV2})
+	if err != nil {
+		t.Fatalf("NewKeySet returned unexpected error: %v", err)
+	}
+
+	decrypted, err := ksAfterRotation.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt of a file written under the pre-rotation key returned unexpected error: %v", err)
+	}
+	if string(decrypted) != "written under v1" {
+		t.Errorf("decrypted = %q, want %q", decrypted, "written under v1")
+	}
+
+	newCiphertext, err := ksAfterRotation.Encrypt([]byte("written under v2"))
+	if err != nil {
+		t.Fatalf("Encrypt returned unexpected error: %v", err)
+	}
+	if newCiphertext[1] != 'v' || newCiphertext[2] != '2' {
+		t.Errorf("expected new ciphertext to embed key id v2")
+	}
+}
+
+func TestNewKeySet_UnknownActiveKeyID(t *testing.T) {
+	if _, err := NewKeySet("missing", map[string]string{"v1": testKeyV1}); err == nil {
+		t.Fatal("expected error for an active key id absent from keys, got nil")
+	}
+}
+
+func TestNewKeySet_InvalidKeyLength(t *testing.T) {
+	if _, err := NewKeySet("v1", map[string]string{"v1": "deadbeef"}); err == nil {
+		t.Fatal("expected error for a key that isn't 32 bytes, got nil")
+	}
+}
+
+func TestKeySet_Decrypt_UnknownKeyID(t *testing.T) {
+	ks, err := NewKeySet("v1", map[string]string{"v1": testKeyV1})
+	if err != nil {
+		t.Fatalf("NewKeySet returned unexpected error: %v", err)
+	}
+	if _, err := ks.Decrypt([]byte{2, 'v', '9', 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}); err == nil {
+		t.Fatal("expected error decrypting with an unrecognized key id, got nil")
+	}
+}
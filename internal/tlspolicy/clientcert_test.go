@@ -0,0 +1,117 @@
+package tlspolicy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCertKeyPair generates a self-signed ECDSA cert/key pair and
+// writes them as PEM files under dir, returning their paths.
+func writeTestCertKeyPair(t *testing.T, dir string, serial int64) (certFile, keyFile string) {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test-workload"},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write cert file: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestBuild_ClientCertRequiresBothCertAndKey(t *testing.T) {
+	if _, err := Build(Config{ClientCertFile: "cert.pem"}); err == nil {
+		t.Fatal("expected error when client_key_file is missing, got nil")
+	}
+	if _, err := Build(Config{ClientKeyFile: "key.pem"}); err == nil {
+		t.Fatal("expected error when client_cert_file is missing, got nil")
+	}
+}
+
+func TestBuild_ClientCertLoadsSuccessfully(t *testing.T) {
+	certFile, keyFile := writeTestCertKeyPair(t, t.TempDir(), 1)
+
+	cfg, err := Build(Config{ClientCertFile: certFile, ClientKeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("Build returned unexpected error: %v", err)
+	}
+	if cfg.GetClientCertificate == nil {
+		t.Fatal("expected GetClientCertificate to be set")
+	}
+	cert, err := cfg.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetClientCertificate returned unexpected error: %v", err)
+	}
+	if cert == nil {
+		t.Fatal("expected a non-nil certificate")
+	}
+}
+
+func TestClientCertReloader_ReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertKeyPair(t, dir, 1)
+
+	reloader := newClientCertReloader(certFile, keyFile)
+	first, err := reloader.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetClientCertificate returned unexpected error: %v", err)
+	}
+
+	// Re-fetching without touching the files should return the cached cert
+	second, err := reloader.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetClientCertificate returned unexpected error: %v", err)
+	}
+	if first != second {
+		t.Error("expected the cached certificate to be reused when files are unchanged")
+	}
+
+	// Rewrite with a newer mtime and a different serial number
+	time.Sleep(10 * time.Millisecond)
+	writeTestCertKeyPair(t, dir, 2)
+
+	third, err := reloader.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetClientCertificate returned unexpected error: %v", err)
+	}
+	if third == first {
+		t.Error("expected a rotated certificate file to be reloaded")
+	}
+}
+
+func TestClientCertReloader_MissingFile(t *testing.T) {
+	reloader := newClientCertReloader("/nonexistent/cert.pem", "/nonexistent/key.pem")
+	if _, err := reloader.GetClientCertificate(nil); err == nil {
+		t.Fatal("expected an error for a missing certificate file, got nil")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !fips
+
+package tlspolicy
+
+import "crypto/tls"
+
+// enforceFIPS is a no-op in ordinary builds; it only restricts the policy
+// when compiled with `-tags fips` (see fips.go).
+func enforceFIPS(version uint16, suites []uint16, curves []tls.CurveID) error {
+	return nil
+}
@@ -0,0 +1,46 @@
+//go:build fips
+
+package tlspolicy
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// fipsApprovedSuites is the FIPS 140-2 approved subset: AES-GCM with
+// ECDHE key exchange only, no ChaCha20 (not a FIPS-approved algorithm) and
+// no static RSA key exchange (no forward secrecy).
+var fipsApprovedSuites = map[uint16]bool{
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256:   true,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384:   true,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256: true,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384: true,
+}
+
+// fipsApprovedCurves excludes X25519, which isn't part of the FIPS 140-2
+// approved curve set (P-256/P-384/P-521 only).
+var fipsApprovedCurves = map[tls.CurveID]bool{
+	tls.CurveP256: true,
+	tls.CurveP384: true,
+	tls.CurveP521: true,
+}
+
+// enforceFIPS rejects anything outside the FIPS-approved policy. Built into
+// the binary only when compiled with `-tags fips`, normally alongside
+// GOEXPERIMENT=boringcrypto, for government-adjacent deployments.
+func enforceFIPS(version uint16, suites []uint16, curves []tls.CurveID) error {
+	if version < tls.VersionTLS12 {
+		return fmt.Errorf("tlspolicy: fips build requires tls_min_version >= 1.2")
+	}
+	for _, id := range suites {
+		if !fipsApprovedSuites[id] {
+			return fmt.Errorf("tlspolicy: cipher suite %q is not FIPS 140-2 approved", tls.CipherSuiteName(id))
+		}
+	}
+	for _, curve := range curves {
+		if !fipsApprovedCurves[curve] {
+			return fmt.Errorf("tlspolicy: curve preference is not FIPS 140-2 approved")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Package tlspolicy turns operator-facing TLS settings (minimum version,
+// cipher suite names, curve names) into a *tls.Config, shared by both the
+// inbound Echo listener and the outbound collector client so the same
+// config surface controls both directions of the proxy. Building with the
+// "fips" build tag additionally rejects anything outside the FIPS 140-2
+// approved cipher suite/curve set, for government-adjacent deployments that
+// need to run against a BoringCrypto-enabled Go toolchain.
+package tlspolicy
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// Config describes the TLS policy in operator-facing names, as they appear
+// in config.toml, rather than tls package constants.
+type Config struct {
+	MinVersion       string   // "1.2" or "1.3"; empty defaults to "1.2"
+	CipherSuites     []string // Names from tls.CipherSuiteName; empty means "use Go's default policy"
+	CurvePreferences []string // "X25519", "P256", "P384", "P521"; empty means "use Go's default policy"
+	ClientCertFile   string   // PEM client certificate to present for mTLS; empty means no client certificate. Must be set together with ClientKeyFile.
+	ClientKeyFile    string   // PEM private key matching ClientCertFile
+}
+
+var versionByName = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var curveByName = map[string]tls.CurveID{
+	"X25519": tls.X25519,
+	"P256":   tls.CurveP256,
+	"P384":   tls.CurveP384,
+	"P521":   tls.CurveP521,
+}
+
+// cipherSuiteByName is built from the standard library's own suite list, so
+// it always matches the suites actually implemented by the running
+// toolchain (including BoringCrypto builds, which trim the list).
+var cipherSuiteByName = func() map[string]uint16 {
+	byName := make(map[string]uint16)
+	for _, s := range tls.CipherSuites() {
+		byName[s.Name] = s.ID
+	}
+	return byName
+}()
+
+// Build resolves cfg into a *tls.Config. It fails if MinVersion, a cipher
+// suite name, or a curve name isn't recognized, or (in a "fips"-tagged
+// build) if cfg asks for anything outside the FIPS-approved policy.
+func Build(cfg Config) (*tls.Config, error) {
+	minVersion := cfg.MinVersion
+	if minVersion == "" {
+		minVersion = "1.2"
+	}
+	version, ok := versionByName[minVersion]
+	if !ok {
+		return nil, fmt.Errorf("tlspolicy: unknown tls_min_version %q (want \"1.2\" or \"1.3\")", minVersion)
+	}
+
+	var suites []uint16
+	for _, name := range cfg.CipherSuites {
+		id, ok := cipherSuiteByName[name]
+		if !ok {
+			return nil, fmt.Errorf("tlspolicy: unknown cipher suite %q", name)
+		}
+		suites = append(suites, id)
+	}
+
+	var curves []tls.CurveID
+	for _, name := range cfg.CurvePreferences {
+		curve, ok := curveByName[name]
+		if !ok {
+			return nil, fmt.Errorf("tlspolicy: unknown curve %q", name)
+		}
+		curves = append(curves, curve)
+	}
+
+	if err := enforceFIPS(version, suites, curves); err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:       version,
+		CipherSuites:     suites,
+		CurvePreferences: curves,
+	}
+
+	if cfg.ClientCertFile != "" || cfg.ClientKeyFile != "" {
+		if cfg.ClientCertFile == "" || cfg.ClientKeyFile == "" {
+			return nil, fmt.Errorf("tlspolicy: client_cert_file and client_key_file must both be set")
+		}
+		reloader := newClientCertReloader(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if _, err := reloader.GetClientCertificate(nil); err != nil {
+			return nil, err
+		}
+		tlsConfig.GetClientCertificate = reloader.GetClientCertificate
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,54 @@
+package tlspolicy
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestBuild_DefaultsToTLS12WhenMinVersionEmpty(t *testing.T) {
+	cfg, err := Build(Config{})
+	if err != nil {
+		t.Fatalf("Build returned unexpected error: %v", err)
+	}
+	if cfg.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %v, want TLS 1.2", cfg.MinVersion)
+	}
+}
+
+func TestBuild_ResolvesCipherSuitesAndCurves(t *testing.T) {
+	cfg, err := Build(Config{
+		MinVersion:       "1.3",
+		CipherSuites:     []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		CurvePreferences: []string{"X25519", "P256"},
+	})
+	if err != nil {
+		t.Fatalf("Build returned unexpected error: %v", err)
+	}
+	if cfg.MinVersion != tls.VersionTLS13 {
+		t.Errorf("MinVersion = %v, want TLS 1.3", cfg.MinVersion)
+	}
+	if len(cfg.CipherSuites) != 1 || cfg.CipherSuites[0] != tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 {
+		t.Errorf("CipherSuites = %v, want [TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256]", cfg.CipherSuites)
+	}
+	if len(cfg.CurvePreferences) != 2 || cfg.CurvePreferences[0] != tls.X25519 || cfg.CurvePreferences[1] != tls.CurveP256 {
+		t.Errorf("CurvePreferences = %v, want [X25519 P256]", cfg.CurvePreferences)
+	}
+}
+
+func TestBuild_UnknownMinVersion(t *testing.T) {
+	if _, err := Build(Config{MinVersion: "1.0"}); err == nil {
+		t.Fatal("expected error for an unsupported tls_min_version, got nil")
+	}
+}
+
+func TestBuild_UnknownCipherSuite(t *testing.T) {
+	if _, err := Build(Config{CipherSuites: []string{"NOT_A_REAL_SUITE"}}); err == nil {
+		t.Fatal("expected error for an unrecognized cipher suite name, got nil")
+	}
+}
+
+func TestBuild_UnknownCurve(t *testing.T) {
+	if _, err := Build(Config{CurvePreferences: []string{"NOT_A_REAL_CURVE"}}); err == nil {
+		t.Fatal("expected error for an unrecognized curve name, got nil")
+	}
+}
@@ -0,0 +1,38 @@
+package transform
+
+import (
+	"fmt"
+
+	logspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+// MergeLogsPayloads decodes each body as an ExportLogsServiceRequest and
+// concatenates their ResourceLogs entries into a single re-encoded payload,
+// for the batching forwarder to coalesce many small requests into one
+func MergeLogsPayloads(bodies [][]byte, contentType string) ([]byte, error) {
+	merged := &logspb.ExportLogsServiceRequest{}
+	for _, body := range bodies {
+		req := &logspb.ExportLogsServiceRequest{}
+		if err := unmarshal(body, contentType, req); err != nil {
+			return nil, fmt.Errorf("transform: failed to decode logs payload for batching: %w", err)
+		}
+		merged.ResourceLogs = append(merged.ResourceLogs, req.ResourceLogs...)
+	}
+	return marshal(merged, contentType)
+}
+
+// MergeTracesPayloads decodes each body as an ExportTraceServiceRequest and
+// concatenates their ResourceSpans entries into a single re-encoded payload,
+// for the batching forwarder to coalesce many small requests into one
+func MergeTracesPayloads(bodies [][]byte, contentType string) ([]byte, error) {
+	merged := &tracepb.ExportTraceServiceRequest{}
+	for _, body := range bodies {
+		req := &tracepb.ExportTraceServiceRequest{}
+		if err := unmarshal(body, contentType, req); err != nil {
+			return nil, fmt.Errorf("transform: failed to decode traces payload for batching: %w", err)
+		}
+		merged.ResourceSpans = append(merged.ResourceSpans, req.ResourceSpans...)
+	}
+	return marshal(merged, contentType)
+}
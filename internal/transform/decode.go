@@ -0,0 +1,118 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	logspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+
+	"zep-logger/internal/metrics"
+)
+
+// RewriteLogsPayload decodes an OTLP ExportLogsServiceRequest, applies rewrite
+// rules and schema normalization to every ResourceLogs entry, and re-encodes
+// it in the same wire format
+func RewriteLogsPayload(body []byte, contentType string, rules []RewriteRule, schemaNorm SchemaNormalization) ([]byte, error) {
+	req := &logspb.ExportLogsServiceRequest{}
+	if err := unmarshal(body, contentType, req); err != nil {
+		return nil, fmt.Errorf("transform: failed to decode logs payload: %w", err)
+	}
+	metrics.LogRecordsDecodedCounter.Add(float64(countLogRecords(req)))
+
+	for _, rl := range req.ResourceLogs {
+		ApplyRules(rl.Resource, rules)
+	}
+	if schemaNorm.Enabled() {
+		NormalizeLogsSchema(req, schemaNorm)
+	}
+
+	return marshal(req, contentType)
+}
+
+// RewriteTracesPayload decodes an OTLP ExportTraceServiceRequest, applies
+// rewrite rules and schema normalization to every ResourceSpans entry, and
+// re-encodes it in the same wire format
+func RewriteTracesPayload(body []byte, contentType string, rules []RewriteRule, schemaNorm SchemaNormalization) ([]byte, error) {
+	req := &tracepb.ExportTraceServiceRequest{}
+	if err := unmarshal(body, contentType, req); err != nil {
+		return nil, fmt.Errorf("transform: failed to decode traces payload: %w", err)
+	}
+	metrics.SpansDecodedCounter.Add(float64(countSpans(req)))
+
+	for _, rs := range req.ResourceSpans {
+		ApplyRules(rs.Resource, rules)
+	}
+	if schemaNorm.Enabled() {
+		NormalizeTracesSchema(req, schemaNorm)
+	}
+
+	return marshal(req, contentType)
+}
+
+// countLogRecords returns the total number of individual log records across
+// every ResourceLogs/ScopeLogs entry in req
+func countLogRecords(req *logspb.ExportLogsServiceRequest) int {
+	count := 0
+	for _, rl := range req.ResourceLogs {
+		for _, sl := range rl.ScopeLogs {
+			count += len(sl.LogRecords)
+		}
+	}
+	return count
+}
+
+// countSpans returns the total number of individual spans across every
+// ResourceSpans/ScopeSpans entry in req
+func countSpans(req *tracepb.ExportTraceServiceRequest) int {
+	count := 0
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			count += len(ss.Spans)
+		}
+	}
+	return count
+}
+
+// CountRecords decodes body as the OTLP request type for path and returns the
+// number of individual log records (/v1/logs) or spans (/v1/traces) it
+// contains. Unrecognized paths return 0 with no error.
+func CountRecords(body []byte, contentType string, path string) (int, error) {
+	switch path {
+	case "/v1/logs":
+		req := &logspb.ExportLogsServiceRequest{}
+		if err := unmarshal(body, contentType, req); err != nil {
+			return 0, fmt.Errorf("transform: failed to decode logs payload: %w", err)
+		}
+		return countLogRecords(req), nil
+	case "/v1/traces":
+		req := &tracepb.ExportTraceServiceRequest{}
+		if err := unmarshal(body, contentType, req); err != nil {
+			return 0, fmt.Errorf("transform: failed to decode traces payload: %w", err)
+		}
+		return countSpans(req), nil
+	default:
+		return 0, nil
+	}
+}
+
+func isJSONContentType(contentType string) bool {
+	return strings.Contains(contentType, "json")
+}
+
+func unmarshal(body []byte, contentType string, msg proto.Message) error {
+	if isJSONContentType(contentType) {
+		return protojson.Unmarshal(body, msg)
+	}
+	return proto.Unmarshal(body, msg)
+}
+
+func marshal(msg proto.Message, contentType string) ([]byte, error) {
+	if isJSONContentType(contentType) {
+		return protojson.Marshal(msg)
+	}
+	return proto.Marshal(msg)
+}
@@ -0,0 +1,93 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/protobuf/proto"
+
+	tracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	logsv1 "go.opentelemetry.io/proto/otlp/logs/v1"
+	tracedata "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"zep-logger/internal/metrics"
+)
+
+func TestRewriteLogsPayload_ProtobufRoundTrip(t *testing.T) {
+	req := &logspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logsv1.ResourceLogs{
+			{Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{stringAttr("service.name.legacy", "checkout")}}},
+		},
+	}
+	body, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+
+	rewritten, err := RewriteLogsPayload(body, "application/x-protobuf", []RewriteRule{{Action: ActionRename, FromKey: "service.name.legacy", ToKey: "service.name"}}, SchemaNormalization{})
+	if err != nil {
+		t.Fatalf("failed to rewrite logs payload: %v", err)
+	}
+
+	var out logspb.ExportLogsServiceRequest
+	if err := proto.Unmarshal(rewritten, &out); err != nil {
+		t.Fatalf("failed to unmarshal rewritten payload: %v", err)
+	}
+	if findAttribute(out.ResourceLogs[0].Resource, "service.name") == -1 {
+		t.Error("expected renamed attribute to survive round trip")
+	}
+}
+
+func TestRewriteLogsPayload_MalformedBody_ReturnsError(t *testing.T) {
+	_, err := RewriteLogsPayload([]byte{0xff, 0xff, 0xff}, "application/x-protobuf", []RewriteRule{{Action: ActionDelete, Key: "x"}}, SchemaNormalization{})
+	if err == nil {
+		t.Fatal("expected error decoding malformed payload")
+	}
+}
+
+func TestRewriteLogsPayload_CountsLogRecords(t *testing.T) {
+	before := testutil.ToFloat64(metrics.LogRecordsDecodedCounter)
+
+	req := &logspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logsv1.ResourceLogs{
+			{ScopeLogs: []*logsv1.ScopeLogs{{LogRecords: []*logsv1.LogRecord{{}, {}, {}}}}},
+		},
+	}
+	body, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+
+	if _, err := RewriteLogsPayload(body, "application/x-protobuf", nil, SchemaNormalization{}); err != nil {
+		t.Fatalf("failed to rewrite logs payload: %v", err)
+	}
+
+	if got := testutil.ToFloat64(metrics.LogRecordsDecodedCounter) - before; got != 3 {
+		t.Errorf("expected LogRecordsDecodedCounter to increase by 3, got %v", got)
+	}
+}
+
+func TestRewriteTracesPayload_CountsSpans(t *testing.T) {
+	before := testutil.ToFloat64(metrics.SpansDecodedCounter)
+
+	req := &tracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracedata.ResourceSpans{
+			{ScopeSpans: []*tracedata.ScopeSpans{{Spans: []*tracedata.Span{{}, {}}}}},
+		},
+	}
+	body, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+
+	if _, err := RewriteTracesPayload(body, "application/x-protobuf", nil, SchemaNormalization{}); err != nil {
+		t.Fatalf("failed to rewrite traces payload: %v", err)
+	}
+
+	if got := testutil.ToFloat64(metrics.SpansDecodedCounter) - before; got != 2 {
+		t.Errorf("expected SpansDecodedCounter to increase by 2, got %v", got)
+	}
+}
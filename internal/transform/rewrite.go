@@ -0,0 +1,91 @@
+package transform
+
+import (
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// RuleAction identifies what a RewriteRule does to a resource attribute
+type RuleAction string
+
+const (
+	// ActionRename copies the value at FromKey to ToKey and removes FromKey
+	ActionRename RuleAction = "rename"
+	// ActionSetDefault sets Key to Value only if the attribute is not already present
+	ActionSetDefault RuleAction = "set_default"
+	// ActionDelete removes Key from the resource attributes entirely
+	ActionDelete RuleAction = "delete"
+)
+
+// RewriteRule is one config-driven transformation applied to resource attributes
+// Only the fields relevant to Action are populated by config
+type RewriteRule struct {
+	Action  RuleAction
+	FromKey string
+	ToKey   string
+	Key     string
+	Value   string
+}
+
+// ApplyRules rewrites the resource attributes of a decoded resource in place,
+// centralizing conventions (e.g. service.namespace) that differ across the
+// many SDK versions producing telemetry for this proxy
+func ApplyRules(resource *resourcepb.Resource, rules []RewriteRule) {
+	if resource == nil {
+		return
+	}
+
+	for _, rule := range rules {
+		switch rule.Action {
+		case ActionRename:
+			renameAttribute(resource, rule.FromKey, rule.ToKey)
+		case ActionSetDefault:
+			setDefaultAttribute(resource, rule.Key, rule.Value)
+		case ActionDelete:
+			deleteAttribute(resource, rule.Key)
+		}
+	}
+}
+
+func findAttribute(resource *resourcepb.Resource, key string) int {
+	for i, kv := range resource.Attributes {
+		if kv.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func renameAttribute(resource *resourcepb.Resource, fromKey string, toKey string) {
+	idx := findAttribute(resource, fromKey)
+	if idx == -1 {
+		return
+	}
+
+	value := resource.Attributes[idx].Value
+	resource.Attributes = append(resource.Attributes[:idx], resource.Attributes[idx+1:]...)
+
+	if toIdx := findAttribute(resource, toKey); toIdx != -1 {
+		resource.Attributes[toIdx].Value = value
+		return
+	}
+	resource.Attributes = append(resource.Attributes, &commonpb.KeyValue{Key: toKey, Value: value})
+}
+
+func setDefaultAttribute(resource *resourcepb.Resource, key string, value string) {
+	if findAttribute(resource, key) != -1 {
+		return
+	}
+	resource.Attributes = append(resource.Attributes, &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	})
+}
+
+func deleteAttribute(resource *resourcepb.Resource, key string) {
+	idx := findAttribute(resource, key)
+	if idx == -1 {
+		return
+	}
+	resource.Attributes = append(resource.Attributes[:idx], resource.Attributes[idx+1:]...)
+}
@@ -0,0 +1,65 @@
+package transform
+
+import (
+	"testing"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+func stringAttr(key string, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func TestApplyRules_Rename(t *testing.T) {
+	resource := &resourcepb.Resource{Attributes: []*commonpb.KeyValue{stringAttr("service.name.legacy", "checkout")}}
+
+	ApplyRules(resource, []RewriteRule{{Action: ActionRename, FromKey: "service.name.legacy", ToKey: "service.name"}})
+
+	if findAttribute(resource, "service.name.legacy") != -1 {
+		t.Error("expected old key to be removed")
+	}
+	idx := findAttribute(resource, "service.name")
+	if idx == -1 {
+		t.Fatal("expected new key to be present")
+	}
+	if resource.Attributes[idx].Value.GetStringValue() != "checkout" {
+		t.Errorf("expected renamed value to be preserved, got %q", resource.Attributes[idx].Value.GetStringValue())
+	}
+}
+
+func TestApplyRules_SetDefault_DoesNotOverrideExisting(t *testing.T) {
+	resource := &resourcepb.Resource{Attributes: []*commonpb.KeyValue{stringAttr("service.namespace", "explicit")}}
+
+	ApplyRules(resource, []RewriteRule{{Action: ActionSetDefault, Key: "service.namespace", Value: "default"}})
+
+	idx := findAttribute(resource, "service.namespace")
+	if idx == -1 || resource.Attributes[idx].Value.GetStringValue() != "explicit" {
+		t.Error("expected existing value to be left untouched")
+	}
+}
+
+func TestApplyRules_SetDefault_AddsMissing(t *testing.T) {
+	resource := &resourcepb.Resource{}
+
+	ApplyRules(resource, []RewriteRule{{Action: ActionSetDefault, Key: "service.namespace", Value: "default"}})
+
+	idx := findAttribute(resource, "service.namespace")
+	if idx == -1 || resource.Attributes[idx].Value.GetStringValue() != "default" {
+		t.Error("expected default value to be added")
+	}
+}
+
+func TestApplyRules_Delete(t *testing.T) {
+	resource := &resourcepb.Resource{Attributes: []*commonpb.KeyValue{stringAttr("debug.internal_only", "true")}}
+
+	ApplyRules(resource, []RewriteRule{{Action: ActionDelete, Key: "debug.internal_only"}})
+
+	if findAttribute(resource, "debug.internal_only") != -1 {
+		t.Error("expected key to be deleted")
+	}
+}
+
+func TestApplyRules_NilResource_NoPanic(t *testing.T) {
+	ApplyRules(nil, []RewriteRule{{Action: ActionDelete, Key: "x"}})
+}
@@ -0,0 +1,73 @@
+package transform
+
+import (
+	logspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	logsv1 "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// SchemaNormalization rewrites schema_url and known-renamed resource attribute
+// keys emitted by a mixed fleet of SDK versions to a single canonical spelling,
+// so downstream queries don't have to union every semconv version's attribute names
+type SchemaNormalization struct {
+	// TargetSchemaURL overwrites every resource/scope schema_url found in the
+	// payload. Empty means schema_url is left untouched.
+	TargetSchemaURL string
+
+	// AttributeAliases maps a semconv attribute key that has been renamed across
+	// versions (e.g. "net.host.name") to the canonical key this proxy standardizes
+	// on (e.g. "server.address"). Applied to resource attributes only.
+	AttributeAliases map[string]string
+}
+
+// Enabled reports whether the normalizer has anything configured to do
+func (n SchemaNormalization) Enabled() bool {
+	return n.TargetSchemaURL != "" || len(n.AttributeAliases) > 0
+}
+
+func (n SchemaNormalization) normalizeResource(resource *resourcepb.Resource) {
+	if resource == nil {
+		return
+	}
+	for oldKey, canonicalKey := range n.AttributeAliases {
+		renameAttribute(resource, oldKey, canonicalKey)
+	}
+}
+
+// NormalizeLogsSchema applies the configured normalization to every
+// ResourceLogs/ScopeLogs entry in a decoded ExportLogsServiceRequest
+func NormalizeLogsSchema(req *logspb.ExportLogsServiceRequest, n SchemaNormalization) {
+	for _, rl := range req.ResourceLogs {
+		n.normalizeResource(rl.Resource)
+		if n.TargetSchemaURL != "" {
+			rl.SchemaUrl = n.TargetSchemaURL
+			normalizeScopeLogsSchemaURL(rl.ScopeLogs, n.TargetSchemaURL)
+		}
+	}
+}
+
+// NormalizeTracesSchema applies the configured normalization to every
+// ResourceSpans/ScopeSpans entry in a decoded ExportTraceServiceRequest
+func NormalizeTracesSchema(req *tracepb.ExportTraceServiceRequest, n SchemaNormalization) {
+	for _, rs := range req.ResourceSpans {
+		n.normalizeResource(rs.Resource)
+		if n.TargetSchemaURL != "" {
+			rs.SchemaUrl = n.TargetSchemaURL
+			normalizeScopeSpansSchemaURL(rs.ScopeSpans, n.TargetSchemaURL)
+		}
+	}
+}
+
+func normalizeScopeLogsSchemaURL(scopeLogs []*logsv1.ScopeLogs, schemaURL string) {
+	for _, sl := range scopeLogs {
+		sl.SchemaUrl = schemaURL
+	}
+}
+
+func normalizeScopeSpansSchemaURL(scopeSpans []*tracev1.ScopeSpans, schemaURL string) {
+	for _, ss := range scopeSpans {
+		ss.SchemaUrl = schemaURL
+	}
+}
@@ -0,0 +1,69 @@
+package transform
+
+import (
+	"testing"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logsv1 "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+func TestSchemaNormalization_Enabled(t *testing.T) {
+	if (SchemaNormalization{}).Enabled() {
+		t.Error("expected empty normalization to be disabled")
+	}
+	if !(SchemaNormalization{TargetSchemaURL: "https://opentelemetry.io/schemas/1.21.0"}).Enabled() {
+		t.Error("expected normalization with target schema url to be enabled")
+	}
+	if !(SchemaNormalization{AttributeAliases: map[string]string{"a": "b"}}).Enabled() {
+		t.Error("expected normalization with aliases to be enabled")
+	}
+}
+
+func TestNormalizeLogsSchema_RewritesSchemaURLAndAliases(t *testing.T) {
+	req := &logspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logsv1.ResourceLogs{
+			{
+				Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{stringAttr("net.host.name", "checkout-1")}},
+				ScopeLogs: []*logsv1.ScopeLogs{
+					{SchemaUrl: "https://opentelemetry.io/schemas/1.4.0"},
+				},
+				SchemaUrl: "https://opentelemetry.io/schemas/1.4.0",
+			},
+		},
+	}
+
+	NormalizeLogsSchema(req, SchemaNormalization{
+		TargetSchemaURL:  "https://opentelemetry.io/schemas/1.21.0",
+		AttributeAliases: map[string]string{"net.host.name": "server.address"},
+	})
+
+	rl := req.ResourceLogs[0]
+	if rl.SchemaUrl != "https://opentelemetry.io/schemas/1.21.0" {
+		t.Errorf("expected resource schema_url to be normalized, got %q", rl.SchemaUrl)
+	}
+	if rl.ScopeLogs[0].SchemaUrl != "https://opentelemetry.io/schemas/1.21.0" {
+		t.Errorf("expected scope schema_url to be normalized, got %q", rl.ScopeLogs[0].SchemaUrl)
+	}
+	if findAttribute(rl.Resource, "net.host.name") != -1 {
+		t.Error("expected old attribute key to be removed")
+	}
+	if findAttribute(rl.Resource, "server.address") == -1 {
+		t.Error("expected canonical attribute key to be present")
+	}
+}
+
+func TestNormalizeLogsSchema_NoTargetURL_LeavesSchemaURLUntouched(t *testing.T) {
+	req := &logspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logsv1.ResourceLogs{
+			{Resource: &resourcepb.Resource{}, SchemaUrl: "https://opentelemetry.io/schemas/1.4.0"},
+		},
+	}
+
+	NormalizeLogsSchema(req, SchemaNormalization{AttributeAliases: map[string]string{"a": "b"}})
+
+	if req.ResourceLogs[0].SchemaUrl != "https://opentelemetry.io/schemas/1.4.0" {
+		t.Errorf("expected schema_url to be left untouched, got %q", req.ResourceLogs[0].SchemaUrl)
+	}
+}
@@ -0,0 +1,124 @@
+package transform
+
+import (
+	"fmt"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// ResourceRoute maps resources whose attribute Key equals Value to TargetURL.
+// Rules are evaluated in order; the first match wins. Resources matching no
+// rule are routed to the default target URL passed to the split functions.
+type ResourceRoute struct {
+	Key       string
+	Value     string
+	TargetURL string
+}
+
+// RoutedPayload is one group of resources bound for a single upstream target,
+// re-encoded in the same wire format as the original request
+type RoutedPayload struct {
+	TargetURL string
+	Body      []byte
+}
+
+// SplitLogsPayloadByResource decodes an OTLP ExportLogsServiceRequest and
+// groups its ResourceLogs entries by the ResourceRoute (if any) matching each
+// resource's attributes, re-encoding one payload per distinct target URL.
+// Resources matching no route are grouped under defaultTargetURL. If routes
+// is empty, the original body is returned unchanged as a single payload.
+func SplitLogsPayloadByResource(body []byte, contentType string, routes []ResourceRoute, defaultTargetURL string) ([]RoutedPayload, error) {
+	if len(routes) == 0 {
+		return []RoutedPayload{{TargetURL: defaultTargetURL, Body: body}}, nil
+	}
+
+	req := &logspb.ExportLogsServiceRequest{}
+	if err := unmarshal(body, contentType, req); err != nil {
+		return nil, fmt.Errorf("transform: failed to decode logs payload: %w", err)
+	}
+
+	groups := make(map[string]*logspb.ExportLogsServiceRequest)
+	var order []string
+	for _, rl := range req.ResourceLogs {
+		target := resolveRoute(rl.Resource, routes, defaultTargetURL)
+		g, ok := groups[target]
+		if !ok {
+			g = &logspb.ExportLogsServiceRequest{}
+			groups[target] = g
+			order = append(order, target)
+		}
+		g.ResourceLogs = append(g.ResourceLogs, rl)
+	}
+
+	payloads := make([]RoutedPayload, 0, len(order))
+	for _, target := range order {
+		encoded, err := marshal(groups[target], contentType)
+		if err != nil {
+			return nil, fmt.Errorf("transform: failed to encode routed logs payload for %s: %w", target, err)
+		}
+		payloads = append(payloads, RoutedPayload{TargetURL: target, Body: encoded})
+	}
+	return payloads, nil
+}
+
+// SplitTracesPayloadByResource is the ExportTraceServiceRequest counterpart of
+// SplitLogsPayloadByResource - see its doc comment for behavior
+func SplitTracesPayloadByResource(body []byte, contentType string, routes []ResourceRoute, defaultTargetURL string) ([]RoutedPayload, error) {
+	if len(routes) == 0 {
+		return []RoutedPayload{{TargetURL: defaultTargetURL, Body: body}}, nil
+	}
+
+	req := &tracepb.ExportTraceServiceRequest{}
+	if err := unmarshal(body, contentType, req); err != nil {
+		return nil, fmt.Errorf("transform: failed to decode traces payload: %w", err)
+	}
+
+	groups := make(map[string]*tracepb.ExportTraceServiceRequest)
+	var order []string
+	for _, rs := range req.ResourceSpans {
+		target := resolveRoute(rs.Resource, routes, defaultTargetURL)
+		g, ok := groups[target]
+		if !ok {
+			g = &tracepb.ExportTraceServiceRequest{}
+			groups[target] = g
+			order = append(order, target)
+		}
+		g.ResourceSpans = append(g.ResourceSpans, rs)
+	}
+
+	payloads := make([]RoutedPayload, 0, len(order))
+	for _, target := range order {
+		encoded, err := marshal(groups[target], contentType)
+		if err != nil {
+			return nil, fmt.Errorf("transform: failed to encode routed traces payload for %s: %w", target, err)
+		}
+		payloads = append(payloads, RoutedPayload{TargetURL: target, Body: encoded})
+	}
+	return payloads, nil
+}
+
+func resolveRoute(resource *resourcepb.Resource, routes []ResourceRoute, defaultTargetURL string) string {
+	if resource == nil {
+		return defaultTargetURL
+	}
+	for _, route := range routes {
+		if attributeStringValue(resource, route.Key) == route.Value {
+			return route.TargetURL
+		}
+	}
+	return defaultTargetURL
+}
+
+func attributeStringValue(resource *resourcepb.Resource, key string) string {
+	idx := findAttribute(resource, key)
+	if idx == -1 {
+		return ""
+	}
+	if sv, ok := resource.Attributes[idx].Value.GetValue().(*commonpb.AnyValue_StringValue); ok {
+		return sv.StringValue
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package transform
+
+import (
+	"testing"
+
+	logspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspbdata "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func resourceLogsFor(serviceName string) *logspbdata.ResourceLogs {
+	return &logspbdata.ResourceLogs{
+		Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{stringAttr("service.name", serviceName)}},
+	}
+}
+
+func TestSplitLogsPayloadByResource_NoRoutes_ReturnsSinglePayload(t *testing.T) {
+	req := &logspb.ExportLogsServiceRequest{ResourceLogs: []*logspbdata.ResourceLogs{resourceLogsFor("checkout")}}
+	body, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+
+	payloads, err := SplitLogsPayloadByResource(body, "application/x-protobuf", nil, "http://default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(payloads) != 1 || payloads[0].TargetURL != "http://default" {
+		t.Fatalf("expected single payload bound for default target, got %+v", payloads)
+	}
+}
+
+func TestSplitLogsPayloadByResource_GroupsByMatchingRoute(t *testing.T) {
+	req := &logspb.ExportLogsServiceRequest{ResourceLogs: []*logspbdata.ResourceLogs{
+		resourceLogsFor("checkout"),
+		resourceLogsFor("billing"),
+		resourceLogsFor("checkout"),
+	}}
+	body, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+
+	routes := []ResourceRoute{{Key: "service.name", Value: "billing", TargetURL: "http://billing-collector"}}
+	payloads, err := SplitLogsPayloadByResource(body, "application/x-protobuf", routes, "http://default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(payloads) != 2 {
+		t.Fatalf("expected 2 payloads (default + billing), got %d", len(payloads))
+	}
+
+	byTarget := make(map[string]*logspb.ExportLogsServiceRequest)
+	for _, p := range payloads {
+		decoded := &logspb.ExportLogsServiceRequest{}
+		if err := proto.Unmarshal(p.Body, decoded); err != nil {
+			t.Fatalf("failed to decode routed payload for %s: %v", p.TargetURL, err)
+		}
+		byTarget[p.TargetURL] = decoded
+	}
+
+	if len(byTarget["http://default"].ResourceLogs) != 2 {
+		t.Errorf("expected 2 resource logs routed to default target, got %d", len(byTarget["http://default"].ResourceLogs))
+	}
+	if len(byTarget["http://billing-collector"].ResourceLogs) != 1 {
+		t.Errorf("expected 1 resource log routed to billing target, got %d", len(byTarget["http://billing-collector"].ResourceLogs))
+	}
+}
+
+func TestSplitLogsPayloadByResource_MalformedBody_ReturnsError(t *testing.T) {
+	routes := []ResourceRoute{{Key: "service.name", Value: "billing", TargetURL: "http://billing-collector"}}
+	if _, err := SplitLogsPayloadByResource([]byte{0xff, 0xff, 0xff}, "application/x-protobuf", routes, "http://default"); err == nil {
+		t.Fatal("expected error decoding malformed body")
+	}
+}
@@ -0,0 +1,193 @@
+// Package usage aggregates per-tenant bytes/records forwarded through the
+// proxy and periodically flushes usage reports (to disk and/or a billing
+// endpoint), so chargeback doesn't depend on Prometheus retention.
+package usage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"zep-logger/pkg/logger"
+)
+
+// Report is one tenant/signal's aggregated usage over an interval
+type Report struct {
+	Tenant      string    `json:"tenant"`
+	Signal      string    `json:"signal"`
+	Requests    int64     `json:"requests"`
+	Bytes       int64     `json:"bytes"`
+	Records     int64     `json:"records"`
+	IntervalEnd time.Time `json:"interval_end"`
+}
+
+type tenantSignalKey struct {
+	tenant string
+	signal string
+}
+
+type accumulator struct {
+	requests int64
+	bytes    int64
+	records  int64
+}
+
+// Accountant aggregates usage in memory and flushes it on a fixed interval
+type Accountant struct {
+	interval   time.Duration
+	outputDir  string
+	billingURL string
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	counts map[tenantSignalKey]*accumulator
+
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// New creates an Accountant that flushes every interval (defaults to 1 minute
+// if <= 0). outputDir writes a JSON report file per flush when non-empty;
+// billingURL POSTs the same reports as a JSON array when non-empty. At least
+// one of the two should be set or flushed usage is simply discarded.
+func New(interval time.Duration, outputDir string, billingURL string) *Accountant {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Accountant{
+		interval:   interval,
+		outputDir:  outputDir,
+		billingURL: billingURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		counts:     make(map[tenantSignalKey]*accumulator),
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Record adds one request's usage to the current interval's tally for tenant/signal.
+// An empty tenant is bucketed as "unknown" rather than dropped.
+func (a *Accountant) Record(tenant string, signal string, bytes int, records int) {
+	if tenant == "" {
+		tenant = "unknown"
+	}
+	key := tenantSignalKey{tenant: tenant, signal: signal}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	acc, ok := a.counts[key]
+	if !ok {
+		acc = &accumulator{}
+		a.counts[key] = acc
+	}
+	acc.requests++
+	acc.bytes += int64(bytes)
+	acc.records += int64(records)
+}
+
+func (a *Accountant) Start() {
+	a.startOnce.Do(func() {
+		a.wg.Add(1)
+		go a.run()
+	})
+}
+
+func (a *Accountant) Stop() {
+	a.stopOnce.Do(func() {
+		close(a.stopCh)
+		a.wg.Wait()
+		a.flush()
+	})
+}
+
+func (a *Accountant) run() {
+	defer a.wg.Done()
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.flush()
+		case <-a.stopCh:
+			return
+		}
+	}
+}
+
+func (a *Accountant) flush() {
+	reports := a.drain()
+	if len(reports) == 0 {
+		return
+	}
+
+	if a.outputDir != "" {
+		if err := a.writeToDisk(reports); err != nil {
+			logger.Error("Usage accounting: failed to write report to disk: %v", err)
+		}
+	}
+	if a.billingURL != "" {
+		if err := a.postToBillingEndpoint(reports); err != nil {
+			logger.Error("Usage accounting: failed to POST report to billing endpoint: %v", err)
+		}
+	}
+}
+
+func (a *Accountant) drain() []Report {
+	now := time.Now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.counts) == 0 {
+		return nil
+	}
+	reports := make([]Report, 0, len(a.counts))
+	for key, acc := range a.counts {
+		reports = append(reports, Report{
+			Tenant:      key.tenant,
+			Signal:      key.signal,
+			Requests:    acc.requests,
+			Bytes:       acc.bytes,
+			Records:     acc.records,
+			IntervalEnd: now,
+		})
+	}
+	a.counts = make(map[tenantSignalKey]*accumulator)
+	return reports
+}
+
+func (a *Accountant) writeToDisk(reports []Report) error {
+	if err := os.MkdirAll(a.outputDir, 0o755); err != nil {
+		return fmt.Errorf("usage: failed to create output dir: %w", err)
+	}
+	data, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return fmt.Errorf("usage: failed to marshal report: %w", err)
+	}
+	name := fmt.Sprintf("usage-%d.json", reports[0].IntervalEnd.UnixNano())
+	if err := os.WriteFile(filepath.Join(a.outputDir, name), data, 0o644); err != nil {
+		return fmt.Errorf("usage: failed to write report file: %w", err)
+	}
+	return nil
+}
+
+func (a *Accountant) postToBillingEndpoint(reports []Report) error {
+	data, err := json.Marshal(reports)
+	if err != nil {
+		return fmt.Errorf("usage: failed to marshal report: %w", err)
+	}
+	resp, err := a.httpClient.Post(a.billingURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("usage: failed to POST report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("usage: billing endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
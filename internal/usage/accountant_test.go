@@ -0,0 +1,90 @@
+package usage
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAccountant_RecordThenFlush_WritesReportToDisk(t *testing.T) {
+	dir := t.TempDir()
+	a := New(20*time.Millisecond, dir, "")
+	a.Start()
+	defer a.Stop()
+
+	a.Record("tenant-a", "logs", 100, 5)
+	a.Record("tenant-a", "logs", 50, 2)
+	a.Record("tenant-b", "traces", 10, 1)
+
+	time.Sleep(200 * time.Millisecond)
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read output dir: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatal("expected at least one usage report file to be written")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		t.Fatalf("failed to read report file: %v", err)
+	}
+	var reports []Report
+	if err := json.Unmarshal(data, &reports); err != nil {
+		t.Fatalf("failed to unmarshal report: %v", err)
+	}
+
+	var tenantABytes, tenantARecords int64
+	for _, r := range reports {
+		if r.Tenant == "tenant-a" && r.Signal == "logs" {
+			tenantABytes = r.Bytes
+			tenantARecords = r.Records
+		}
+	}
+	if tenantABytes != 150 || tenantARecords != 7 {
+		t.Errorf("expected tenant-a logs usage to aggregate to bytes=150 records=7, got bytes=%d records=%d", tenantABytes, tenantARecords)
+	}
+}
+
+func TestAccountant_RecordEmptyTenant_BucketsAsUnknown(t *testing.T) {
+	a := New(time.Hour, "", "")
+	a.Record("", "logs", 10, 1)
+
+	reports := a.drain()
+	if len(reports) != 1 || reports[0].Tenant != "unknown" {
+		t.Errorf("expected empty tenant to be bucketed as 'unknown', got %+v", reports)
+	}
+}
+
+func TestAccountant_Flush_PostsToBillingEndpoint(t *testing.T) {
+	received := make(chan []Report, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reports []Report
+		if err := json.NewDecoder(r.Body).Decode(&reports); err != nil {
+			t.Errorf("failed to decode posted reports: %v", err)
+		}
+		received <- reports
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	a := New(20*time.Millisecond, "", server.URL)
+	a.Start()
+	defer a.Stop()
+
+	a.Record("tenant-a", "logs", 100, 5)
+
+	select {
+	case reports := <-received:
+		if len(reports) != 1 || reports[0].Tenant != "tenant-a" {
+			t.Errorf("expected tenant-a usage report to be posted, got %+v", reports)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for usage report to be posted")
+	}
+}
@@ -0,0 +1,191 @@
+package watchdog
+
+import (
+    "bufio"
+    "fmt"
+    "os"
+    "path/filepath"
+    "runtime/pprof"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "zep-logger/pkg/logger"
+)
+
+// QueueDepthProvider is the minimal dependency needed to read current backlog depth
+// Satisfied by forwarder.Forwarder without importing the forwarder package directly
+type QueueDepthProvider interface {
+    GetQueueDepth() int
+}
+
+// Config holds the thresholds and timing for the memory watchdog
+type Config struct {
+    RSSThresholdMB          int           // Capture a heap profile once RSS reaches this (0 = disabled)
+    QueueDepthThreshold     int           // Capture a heap profile once queue depth reaches this (0 = disabled)
+    CheckInterval           time.Duration // How often to sample RSS/queue depth
+    MinDumpInterval         time.Duration // Minimum time between captured profiles (rate limit)
+    DumpDir                 string        // Directory heap profiles are written to
+}
+
+// Watchdog periodically samples process RSS and queue depth, capturing a heap
+// profile to disk when either crosses its configured threshold. Captures are
+// rate-limited so a sustained breach doesn't flood disk with profiles.
+type Watchdog struct {
+    cfg          Config
+    depthProvider QueueDepthProvider
+    lastDump     time.Time
+    mu           sync.Mutex
+    stopCh       chan struct{}
+    wg           sync.WaitGroup
+    startOnce    sync.Once
+    stopOnce     sync.Once
+}
+
+// New creates a new memory/queue-depth watchdog
+// depthProvider: source of current queue depth (typically the active forwarder)
+func New(cfg Config, depthProvider QueueDepthProvider) *Watchdog {
+    if cfg.CheckInterval <= 0 {
+        cfg.CheckInterval = 10 * time.Second
+    }
+    if cfg.MinDumpInterval <= 0 {
+        cfg.MinDumpInterval = 60 * time.Second
+    }
+    if cfg.DumpDir == "" {
+        cfg.DumpDir = "/tmp/zep-logger-debug"
+    }
+
+    return &Watchdog{
+        cfg:           cfg,
+        depthProvider: depthProvider,
+        stopCh:        make(chan struct{}),
+    }
+}
+
+// Enabled reports whether the watchdog has any threshold configured
+func (w *Watchdog) Enabled() bool {
+    return w.cfg.RSSThresholdMB > 0 || w.cfg.QueueDepthThreshold > 0
+}
+
+// Start begins the sampling loop in a background goroutine
+// No-op if no threshold is configured
+func (w *Watchdog) Start() {
+    if !w.Enabled() {
+        return
+    }
+    w.startOnce.Do(func() {
+        logger.Info("Memory watchdog started: rssThresholdMB=%d, queueDepthThreshold=%d, checkInterval=%v, minDumpInterval=%v",
+            w.cfg.RSSThresholdMB, w.cfg.QueueDepthThreshold, w.cfg.CheckInterval, w.cfg.MinDumpInterval)
+        w.wg.Add(1)
+        go w.run()
+    })
+}
+
+// Stop halts the sampling loop and waits for it to exit
+func (w *Watchdog) Stop() {
+    w.stopOnce.Do(func() {
+        close(w.stopCh)
+        w.wg.Wait()
+    })
+}
+
+func (w *Watchdog) run() {
+    defer w.wg.Done()
+
+    ticker := time.NewTicker(w.cfg.CheckInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-w.stopCh:
+            return
+        case <-ticker.C:
+            w.check()
+        }
+    }
+}
+
+func (w *Watchdog) check() {
+    var reasons []string
+
+    if w.cfg.RSSThresholdMB > 0 {
+        if rssBytes, err := readRSSBytes(); err == nil {
+            if rssMB := rssBytes / (1024 * 1024); rssMB >= int64(w.cfg.RSSThresholdMB) {
+                reasons = append(reasons, fmt.Sprintf("rss=%dMB", rssMB))
+            }
+        } else {
+            logger.Warn("Memory watchdog: failed to read RSS: %v", err)
+        }
+    }
+
+    if w.cfg.QueueDepthThreshold > 0 && w.depthProvider != nil {
+        if depth := w.depthProvider.GetQueueDepth(); depth >= w.cfg.QueueDepthThreshold {
+            reasons = append(reasons, fmt.Sprintf("queueDepth=%d", depth))
+        }
+    }
+
+    if len(reasons) == 0 {
+        return
+    }
+
+    w.captureHeapProfile(strings.Join(reasons, ","))
+}
+
+// captureHeapProfile writes a heap profile to disk, respecting MinDumpInterval
+func (w *Watchdog) captureHeapProfile(reason string) {
+    w.mu.Lock()
+    if !w.lastDump.IsZero() && time.Since(w.lastDump) < w.cfg.MinDumpInterval {
+        w.mu.Unlock()
+        return
+    }
+    w.lastDump = time.Now()
+    w.mu.Unlock()
+
+    if err := os.MkdirAll(w.cfg.DumpDir, 0o755); err != nil {
+        logger.Error("Memory watchdog: failed to create dump dir %s: %v", w.cfg.DumpDir, err)
+        return
+    }
+
+    path := filepath.Join(w.cfg.DumpDir, fmt.Sprintf("heap-%s.pprof", time.Now().UTC().Format("20060102T150405.000000000Z")))
+    f, err := os.Create(path)
+    if err != nil {
+        logger.Error("Memory watchdog: failed to create heap profile file %s: %v", path, err)
+        return
+    }
+    defer f.Close()
+
+    if err := pprof.WriteHeapProfile(f); err != nil {
+        logger.Error("Memory watchdog: failed to write heap profile: %v", err)
+        return
+    }
+
+    logger.Warn("Memory watchdog: threshold breached (%s), heap profile written to %s", reason, path)
+}
+
+// readRSSBytes reads the resident set size of the current process from /proc/self/status
+func readRSSBytes() (int64, error) {
+    f, err := os.Open("/proc/self/status")
+    if err != nil {
+        return 0, err
+    }
+    defer f.Close()
+
+    scanner := bufio.NewScanner(f)
+    for scanner.Scan() {
+        line := scanner.Text()
+        if !strings.HasPrefix(line, "VmRSS:") {
+            continue
+        }
+        fields := strings.Fields(line)
+        if len(fields) < 2 {
+            return 0, fmt.Errorf("unexpected VmRSS line format: %q", line)
+        }
+        kb, err := strconv.ParseInt(fields[1], 10, 64)
+        if err != nil {
+            return 0, fmt.Errorf("failed to parse VmRSS value: %w", err)
+        }
+        return kb * 1024, nil
+    }
+    return 0, fmt.Errorf("VmRSS not found in /proc/self/status")
+}
@@ -0,0 +1,79 @@
+package watchdog
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+type fakeDepthProvider struct {
+    depth int
+}
+
+func (f *fakeDepthProvider) GetQueueDepth() int {
+    return f.depth
+}
+
+// TestWatchdog_QueueDepthBreach_CapturesHeapProfile verifies a profile is written
+// once queue depth crosses the configured threshold
+func TestWatchdog_QueueDepthBreach_CapturesHeapProfile(t *testing.T) {
+    dumpDir := filepath.Join(t.TempDir(), "dumps")
+    depthProvider := &fakeDepthProvider{depth: 100}
+
+    w := New(Config{
+        QueueDepthThreshold: 50,
+        CheckInterval:       10 * time.Millisecond,
+        MinDumpInterval:     time.Hour,
+        DumpDir:             dumpDir,
+    }, depthProvider)
+
+    w.Start()
+    defer w.Stop()
+
+    deadline := time.Now().Add(2 * time.Second)
+    for time.Now().Before(deadline) {
+        entries, err := os.ReadDir(dumpDir)
+        if err == nil && len(entries) > 0 {
+            return
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+    t.Fatal("expected a heap profile to be written after queue depth breach, none found")
+}
+
+// TestWatchdog_Disabled_NeverStarts verifies a watchdog with no thresholds does nothing
+func TestWatchdog_Disabled_NeverStarts(t *testing.T) {
+    w := New(Config{}, &fakeDepthProvider{})
+    if w.Enabled() {
+        t.Error("expected watchdog with no thresholds to be disabled")
+    }
+    w.Start()
+    w.Stop()
+}
+
+// TestWatchdog_MinDumpInterval_RateLimits verifies repeated breaches within the
+// rate-limit window only produce a single profile
+func TestWatchdog_MinDumpInterval_RateLimits(t *testing.T) {
+    dumpDir := filepath.Join(t.TempDir(), "dumps")
+    depthProvider := &fakeDepthProvider{depth: 100}
+
+    w := New(Config{
+        QueueDepthThreshold: 50,
+        CheckInterval:       5 * time.Millisecond,
+        MinDumpInterval:     time.Hour,
+        DumpDir:             dumpDir,
+    }, depthProvider)
+
+    w.Start()
+    time.Sleep(200 * time.Millisecond)
+    w.Stop()
+
+    entries, err := os.ReadDir(dumpDir)
+    if err != nil {
+        t.Fatalf("failed to read dump dir: %v", err)
+    }
+    if len(entries) != 1 {
+        t.Errorf("expected exactly 1 profile within rate-limit window, got %d", len(entries))
+    }
+}
@@ -1,35 +1,135 @@
 package worker
 
 import (
-    "bytes"
-    "fmt"
-    "net/http"
-    "runtime"
-    "sync"
-    "time"
-    "zep-logger/internal/metrics"
-    "zep-logger/pkg/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+	"zep-logger/internal/admission"
+	"zep-logger/internal/bufpool"
+	"zep-logger/internal/diagnostics"
+	"zep-logger/internal/diskqueue"
+	"zep-logger/internal/httpclient"
+	"zep-logger/internal/jobtracking"
+	"zep-logger/internal/metrics"
+	"zep-logger/pkg/logger"
+
+	"go.uber.org/atomic"
+	"golang.org/x/time/rate"
+)
+
+// Priority indicates how urgently a job should be dispatched when the queue
+// is contended. A worker always prefers a pending PriorityHigh job over a
+// pending PriorityNormal one; jobs at the same priority are served FIFO.
+type Priority int
+
+const (
+	PriorityNormal Priority = iota
+	PriorityHigh
 )
 
 // Job represents a forwarding task to be processed by the worker pool
 // Contains all necessary information to forward a request to the OTel collector
 type Job struct {
-	Body      []byte            // Buffered request body
-	TargetURL string            // Target OTel collector URL
-    Headers   http.Header       // Headers to include in forwarded request (multi-valued)
+	Body       []byte          // Buffered request body
+	TargetURL  string          // Target OTel collector URL
+	Headers    http.Header     // Headers to include in forwarded request (multi-valued)
+	Priority   Priority        // Dispatch priority under queue contention (default: PriorityNormal)
+	EnqueuedAt time.Time       // When the job was accepted into the pool, set by enqueue; used to enforce SetJobTTL
+	Ctx        context.Context // Governs delivery; a nil Ctx (e.g. a job replayed from disk or a spill file) falls back to context.Background() via context()
+	walID      string          // Disk queue entry id, set when a DiskQueue is configured (empty otherwise)
+	JobID      string          // Tracking id, set by SubmitJob when a jobTracker is configured (empty otherwise)
+	Timeout    time.Duration   // Per-job delivery deadline, overriding the client's own baked-in Timeout when > 0 (0 = use the client's timeout, unchanged)
+}
+
+// context returns job.Ctx, or context.Background() if it wasn't set - jobs
+// built outside of Submit (replayed from a DiskQueue or spill file) never
+// have one, since neither format persists a context across a restart
+func (j Job) context() context.Context {
+	if j.Ctx != nil {
+		return j.Ctx
+	}
+	return context.Background()
+}
+
+// DiskQueue durably persists jobs before they enter the in-memory job queue,
+// so buffered telemetry survives a process restart. Complete removes an entry
+// once its job has been delivered or permanently given up on.
+type DiskQueue interface {
+	Enqueue(body []byte, targetURL string, headers http.Header) (id string, err error)
+	Complete(id string) error
+	Replay() ([]diskqueue.Entry, error)
+}
+
+// DeadLetterWriter persists a job that has permanently failed to forward
+// (retries exhausted, or a non-retryable 4xx from the collector) so it isn't
+// silently dropped. reason describes why the job was dead-lettered.
+type DeadLetterWriter interface {
+	Write(body []byte, targetURL string, headers http.Header, reason string) error
 }
 
 // Pool represents a bounded goroutine worker pool for async request forwarding
 // Implements a fixed-size pool of workers processing jobs from a buffered channel
 type Pool struct {
-	workerCount     int           // Number of worker goroutines
-	jobQueue        chan Job      // Buffered channel for queuing forwarding jobs
-	wg              sync.WaitGroup
-	stopOnce        sync.Once     // Ensures Stop() is called only once
-	startOnce       sync.Once     // Ensures Start() is called only once
-	shutdownTimeout time.Duration // Maximum time to wait for workers to finish during shutdown
-    httpClient      *http.Client  // Shared HTTP client with connection pooling
-    permits         chan struct{} // Counts in-flight + queued jobs for deterministic backpressure
+	workerCount       atomic.Int64 // Current target number of worker goroutines; changed at runtime by Resize
+	jobQueue          chan Job     // Buffered channel for queuing normal-priority forwarding jobs
+	highPriorityQueue chan Job     // Buffered channel for jobs submitted with PriorityHigh; workers drain this first
+	wg                sync.WaitGroup
+	stopOnce          sync.Once               // Ensures Stop() is called only once
+	startOnce         sync.Once               // Ensures Start() is called only once
+	shutdownTimeout   time.Duration           // Maximum time to wait for workers to finish during shutdown
+	httpClient        *http.Client            // Shared HTTP client with connection pooling
+	permits           chan struct{}           // Counts in-flight + queued jobs for deterministic backpressure
+	retryPolicy       RetryPolicy             // Retry behavior on transport error or non-2xx (default: no retries)
+	deadLetter        DeadLetterWriter        // Optional sink for permanently-failed jobs (default: none, jobs are just dropped)
+	diagnostics       *diagnostics.Buffer     // Optional ring buffer of non-2xx collector responses, for admin inspection (default: none, disabled)
+	diskQueue         DiskQueue               // Optional write-ahead log so queued jobs survive a restart (default: none, queue is purely in-memory)
+	rateLimiter       *rate.Limiter           // Optional cap on requests/sec sent to the collector (default: nil, unlimited)
+	lazySpawn         bool                    // If true, workers are spawned on demand (up to workerCount) instead of all at Start() (default: false, original behavior)
+	idleTimeout       time.Duration           // How long a lazily-spawned worker waits for a job before retiring, ignored unless lazySpawn
+	jobTTL            time.Duration           // Max time a job may wait in the queue before a worker drops it instead of forwarding it (default: 0, disabled)
+	queueFullPolicy   string                  // "reject" (default) rejects a new job when the pool is full; "drop-oldest" evicts the oldest normal-priority queued job to admit it instead
+	liveWorkers       atomic.Int64            // Current number of running worker goroutines (only tracked/enforced in lazySpawn mode)
+	nextWorkerID      atomic.Int64            // Monotonically increasing id handed to lazily-spawned workers, for log lines
+	spillFilePath     string                  // Where Stop spills still-queued jobs if shutdownTimeout is hit, replayed by the next Start (default: "", disabled)
+	stopping          atomic.Bool             // Set once Stop() begins, so a worker exiting as part of shutdown doesn't also report a Resize-style "resized"/"paused" lifecycle event
+	resizeMu          sync.Mutex              // Guards replacing resizeCh
+	resizeCh          chan struct{}           // Closed and replaced by Resize on every target change, waking any worker blocked in its select so it re-checks tryRetire immediately instead of waiting for its next job or idle timeout
+	tenantHeader      string                  // Header read from a job's Headers to identify its tenant for fair queuing (default: "", fair queuing disabled)
+	tenantShareLimit  int                     // Max jobs a single tenant may have pending in the queue at once, 0 disables (default: 0, original behavior - one tenant can fill the whole queue)
+	tenantMu          sync.Mutex              // Guards tenantPending
+	tenantPending     map[string]int          // Count of currently-queued (not yet dequeued) jobs per tenant, only populated when tenantShareLimit > 0
+	tenantWeights     map[string]int          // Tenant -> relative dequeue weight, nil disables weighted fair scheduling (default: nil, plain FIFO jobQueue). A tenant missing from this map is treated as weight 1.
+	tenantSchedMu     sync.Mutex              // Guards tenantSchedQueues, tenantSchedOrder and tenantSchedClosed
+	tenantSchedCond   *sync.Cond              // Wakes tenantScheduler when a sub-queue receives a job or Stop closes scheduling
+	tenantSchedQueues map[string][]Job        // Per-tenant FIFO sub-queue, populated by scheduleTenantJob and drained by tenantScheduler in weighted round-robin order
+	tenantSchedOrder  []string                // Tenants in order of first appearance, iterated by tenantScheduler each round
+	tenantSchedClosed bool                    // Set by Stop once no more jobs will be scheduled; tenantScheduler exits once every sub-queue has drained
+	tenantSchedDone   chan struct{}           // Closed when tenantScheduler returns, so Stop can wait for it to drain before closing jobQueue
+	jobTracker        *jobtracking.Tracker    // Optional bounded status record (queued/sent/failed) per job, for admin lookup by job id (default: none, disabled)
+	healthScorer      *admission.HealthScorer // Optional latency/error/breaker health score fed by delivery outcomes, consulted by the forwarder's HealthScore() for probabilistic admission (default: none, disabled)
+	cooldownMu        sync.Mutex              // Guards cooldownUntil
+	cooldownUntil     map[string]time.Time    // Per-target-URL time before which no worker should dispatch to it, set from a collector's Retry-After on 429/503 so every worker backs off that target, not just the one that got throttled
+	maxQueuedBytes    int64                   // Max total size of Job.Body across all currently-queued jobs, 0 disables (default: 0, original behavior - only job count is bounded, not their combined size)
+	queuedBytes       atomic.Int64            // Current total size of Job.Body across all currently-queued jobs, only tracked/enforced when maxQueuedBytes > 0
+	inFlight          atomic.Int64            // Jobs a worker has dequeued and is currently processing (dispatched, retrying, or awaiting a permit), used by Flush to know when the pool is fully idle
+}
+
+// spillEntry is one job written to the shutdown spill file, in the same
+// shape as Job but without the fields (EnqueuedAt, walID) that only make
+// sense within a single process's lifetime
+type spillEntry struct {
+	Body      []byte      `json:"body"`
+	TargetURL string      `json:"target_url"`
+	Headers   http.Header `json:"headers"`
+	Priority  Priority    `json:"priority"`
 }
 
 // NewPool creates a new worker pool with the specified configuration
@@ -39,16 +139,17 @@ type Pool struct {
 //   - workerCount: Number of worker goroutines (default: 2×NumCPU per ADR-001)
 //   - jobQueueSize: Buffer capacity for job queue (default: 10000)
 //   - shutdownTimeout: Maximum time to wait for workers during shutdown (e.g., 10s)
+//   - httpClientConfig: Overrides for the shared HTTP client's timeouts (zero value: built-in defaults)
 //
 // Returns configured Pool instance ready to be started
-func NewPool(workerCount int, jobQueueSize int, shutdownTimeout time.Duration) *Pool {
-    // Use 50×NumCPU as default if workerCount is 0 or negative (IO-bound workload)
-    // For HTTP forwarding, workers spend most time waiting on network I/O,
-    // so we can use many more workers than CPU cores to maximize throughput
-    if workerCount <= 0 {
-        workerCount = 50 * runtime.NumCPU()
-        logger.Info("Worker pool size not configured, using default: %d (50×NumCPU for I/O-bound workload)", workerCount)
-    }
+func NewPool(workerCount int, jobQueueSize int, shutdownTimeout time.Duration, httpClientConfig httpclient.Config) *Pool {
+	// Use 50×NumCPU as default if workerCount is 0 or negative (IO-bound workload)
+	// For HTTP forwarding, workers spend most time waiting on network I/O,
+	// so we can use many more workers than CPU cores to maximize throughput
+	if workerCount <= 0 {
+		workerCount = 50 * runtime.NumCPU()
+		logger.Info("Worker pool size not configured, using default: %d (50×NumCPU for I/O-bound workload)", workerCount)
+	}
 
 	// Use 10000 as default job queue size
 	if jobQueueSize <= 0 {
@@ -58,26 +159,362 @@ func NewPool(workerCount int, jobQueueSize int, shutdownTimeout time.Duration) *
 
 	logger.Info("Creating worker pool: workers=%d, queueSize=%d, shutdownTimeout=%v", workerCount, jobQueueSize, shutdownTimeout)
 
-    // Create a shared HTTP client with aggressive connection pooling
-    // Tuned for high-concurrency I/O-bound workloads
-    transport := &http.Transport{
-        Proxy:                 http.ProxyFromEnvironment,
-        ForceAttemptHTTP2:     true,
-        MaxIdleConns:          workerCount * 2,        // Higher connection reuse
-        MaxIdleConnsPerHost:   workerCount,            // One connection per worker
-        MaxConnsPerHost:       workerCount * 2,        // Allow connection bursts
-        IdleConnTimeout:       90 * time.Second,
-        TLSHandshakeTimeout:   10 * time.Second,
-        ExpectContinueTimeout: 1 * time.Second,
-    }
-
-    return &Pool{
-        workerCount:     workerCount,
-        jobQueue:        make(chan Job, jobQueueSize),
-        shutdownTimeout: shutdownTimeout,
-        httpClient:      &http.Client{Transport: transport, Timeout: 10 * time.Second},
-        permits:         make(chan struct{}, workerCount+jobQueueSize),
-    }
+	// Shared HTTP client with aggressive connection pooling, tuned for
+	// high-concurrency I/O-bound workloads
+	httpClient := httpclient.New(workerCount, httpClientConfig)
+
+	pool := &Pool{
+		jobQueue:          make(chan Job, jobQueueSize),
+		highPriorityQueue: make(chan Job, jobQueueSize),
+		shutdownTimeout:   shutdownTimeout,
+		httpClient:        httpClient,
+		permits:           make(chan struct{}, workerCount+jobQueueSize),
+		retryPolicy:       DefaultRetryPolicy(),
+		resizeCh:          make(chan struct{}),
+		cooldownUntil:     make(map[string]time.Time),
+	}
+	pool.workerCount.Store(int64(workerCount))
+	return pool
+}
+
+// SetRetryPolicy configures the retry behavior applied when a job fails with a
+// transport error or non-2xx response from the collector. Must be called
+// before Start(); the default policy performs no retries.
+func (p *Pool) SetRetryPolicy(policy RetryPolicy) {
+	p.retryPolicy = policy
+}
+
+// SetDeadLetterWriter configures where permanently-failed jobs are persisted.
+// Must be called before Start(); if never set, failed jobs are simply dropped
+// (the original behavior).
+func (p *Pool) SetDeadLetterWriter(w DeadLetterWriter) {
+	p.deadLetter = w
+}
+
+// SetDiagnostics configures a ring buffer that captures the status, headers,
+// and a truncated body snippet of every non-2xx collector response, so an
+// admin endpoint can inspect a collector-side rejection after the fact. Must
+// be called before Start(); if never set, only the status code is logged
+// (the original behavior).
+func (p *Pool) SetDiagnostics(d *diagnostics.Buffer) {
+	p.diagnostics = d
+}
+
+// SetDiskQueue configures a write-ahead log that jobs are durably persisted
+// to before entering the in-memory queue, and removed from once delivered.
+// Must be called before Start(); Start replays any entries left over from a
+// previous restart before spawning workers. If never set, the queue is
+// purely in-memory (the original behavior).
+func (p *Pool) SetDiskQueue(q DiskQueue) {
+	p.diskQueue = q
+}
+
+// SetRateLimiter caps how many requests/sec workers send to the collector.
+// A burst of client traffic still queues up in the (already-bounded)
+// jobQueue instead of hitting the collector all at once. Must be called
+// before Start(); if never set, workers send requests as fast as they can
+// (the original behavior).
+func (p *Pool) SetRateLimiter(limiter *rate.Limiter) {
+	p.rateLimiter = limiter
+}
+
+// SetLazySpawn switches the pool from spawning all workerCount workers up
+// front (the default) to spawning them on demand as the queue backs up, up
+// to workerCount, retiring a worker once it has waited idleTimeout without a
+// job. Reduces idle goroutine count on nodes provisioned for peak load that
+// rarely see it, at the cost of a cold-start delay the first time load
+// ramps up. Must be called before Start().
+func (p *Pool) SetLazySpawn(idleTimeout time.Duration) {
+	p.lazySpawn = true
+	p.idleTimeout = idleTimeout
+}
+
+// SetJobTTL configures the maximum time a job may sit in the queue before a
+// worker drops it (counted in metrics.JobsExpiredCounter) instead of
+// forwarding it, so a long collector outage doesn't leave workers spending
+// the recovery window delivering stale telemetry instead of fresh. Must be
+// called before Start(); if never set (or set to zero), jobs are forwarded
+// regardless of how long they waited in the queue (the original behavior).
+func (p *Pool) SetJobTTL(ttl time.Duration) {
+	p.jobTTL = ttl
+}
+
+// SetQueueFullPolicy configures what happens when the pool has no room for a
+// newly submitted job. "reject" (the default, also used for any unrecognized
+// value) rejects the new job, leaving already-queued jobs untouched.
+// "drop-oldest" instead evicts the oldest normal-priority queued job to make
+// room, admitting the new one - for RUM traffic the freshest data is often
+// more valuable than data that's already been waiting. High-priority jobs
+// are never evicted. Must be called before Start().
+func (p *Pool) SetQueueFullPolicy(policy string) {
+	p.queueFullPolicy = policy
+}
+
+// SetMaxQueuedBytes caps the combined size of Job.Body across every
+// currently-queued job (in-flight jobs already dequeued by a worker don't
+// count) at n bytes; a submission that would exceed it is rejected, the same
+// as one that finds the job queue full. Job count alone doesn't bound
+// memory - 10,000 jobs at 1MB each is 10GB, regardless of how small
+// job_queue_size is set. 0 (the default) disables the cap. Must be called
+// before Start().
+func (p *Pool) SetMaxQueuedBytes(n int64) {
+	p.maxQueuedBytes = n
+}
+
+// WarmUp fires count concurrent HEAD requests at targetURL using the pool's
+// own httpClient, so the resulting connections (including completed TLS
+// handshakes) land in the same keep-alive pool real jobs are later delivered
+// through. It blocks until every request has completed or ctx is done.
+// Responses and errors are ignored - the goal is only to establish the
+// underlying connection before the first real traffic arrives, not to
+// exercise the collector's HEAD handling. Safe to call before Start().
+func (p *Pool) WarmUp(ctx context.Context, targetURL string, count int) {
+	if count <= 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(ctx, http.MethodHead, targetURL, nil)
+			if err != nil {
+				return
+			}
+			resp, err := p.httpClient.Do(req)
+			if err != nil {
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+}
+
+// SetSpillFile configures a file path that Stop writes any jobs still
+// sitting in the queues to if shutdownTimeout is exceeded, instead of
+// silently abandoning them; the next Start replays and removes the file.
+// This only recovers jobs that hadn't yet been picked up by a worker - one
+// already in flight past the timeout isn't recoverable, since delivery
+// isn't cancellable. Must be called before Start(); if never set, jobs
+// still queued at a shutdown timeout are lost (the original behavior).
+func (p *Pool) SetSpillFile(path string) {
+	p.spillFilePath = path
+}
+
+// SetTenantQueueShare bounds how many jobs a single tenant may have pending
+// in the queue at once, so one tenant's burst can't fill the shared queue
+// and cause 503s for everyone else. tenant is read from each job's Headers
+// via header (a job whose Headers lack it is bucketed as "unknown"). Each
+// tenant's pending count is reported on metrics.TenantQueueDepthGauge. Must
+// be called before Start(); if never set (or maxPerTenant <= 0), any tenant
+// may fill the whole queue (the original behavior).
+func (p *Pool) SetTenantQueueShare(header string, maxPerTenant int) {
+	p.tenantHeader = header
+	p.tenantShareLimit = maxPerTenant
+	p.tenantPending = make(map[string]int)
+}
+
+// SetTenantWeights enables weighted fair scheduling on top of
+// SetTenantQueueShare's per-tenant partitions: instead of dequeuing
+// normal-priority jobs in plain arrival order, tenantScheduler drains up to
+// each tenant's configured weight in jobs per round before moving to the
+// next tenant, so a higher-weight tenant is guaranteed proportionally more
+// forwarding bandwidth under contention. weights is relative, not a
+// percentage (e.g. {"a": 3, "b": 1} gives "a" 3x "b"'s share); a tenant
+// absent from weights (or with a non-positive weight) defaults to 1. Uses
+// the same tenant identifier as SetTenantQueueShare (tenantHeader/tenantFor).
+// Must be called before Start(); if never set, jobs dequeue in plain FIFO
+// order (the original behavior).
+func (p *Pool) SetTenantWeights(weights map[string]int) {
+	p.tenantWeights = weights
+	p.tenantSchedCond = sync.NewCond(&p.tenantSchedMu)
+	p.tenantSchedQueues = make(map[string][]Job)
+	p.tenantSchedDone = make(chan struct{})
+}
+
+// SetJobTracker configures a bounded status record that SubmitJob populates
+// with a generated job id and every worker updates as that job reaches a
+// terminal state (sent or failed), so an admin endpoint can answer "did my
+// payload actually reach the collector?" by id. Must be called before
+// Start(); if never set, SubmitJob returns an empty job id and jobs aren't
+// tracked (the original behavior).
+func (p *Pool) SetJobTracker(t *jobtracking.Tracker) {
+	p.jobTracker = t
+}
+
+// SetHealthScorer configures a HealthScorer that every delivery attempt
+// reports its latency/success into, and that HealthScore() reads back for
+// callers deciding whether to shed traffic before it reaches this pool. Must
+// be called before Start(); if never set, HealthScore() reports full health
+// unconditionally (the original behavior).
+func (p *Pool) SetHealthScorer(s *admission.HealthScorer) {
+	p.healthScorer = s
+}
+
+// HealthScore reports this pool's current delivery health in [0,1] (1 = fully
+// healthy), or 1 unconditionally if no HealthScorer is configured
+func (p *Pool) HealthScore() float64 {
+	if p.healthScorer == nil {
+		return 1
+	}
+	return p.healthScorer.Score()
+}
+
+// tenantFor returns the tenant identifier a job is charged against for
+// SetTenantQueueShare accounting, bucketing a missing header as "unknown"
+// rather than letting it bypass the per-tenant limit entirely
+func (p *Pool) tenantFor(job Job) string {
+	tenant := job.Headers.Get(p.tenantHeader)
+	if tenant == "" {
+		tenant = "unknown"
+	}
+	return tenant
+}
+
+// admitTenant reserves this job's slot against its tenant's share of the
+// queue, returning false if the tenant is already at tenantShareLimit. A
+// no-op (always admits) unless SetTenantQueueShare has been called.
+func (p *Pool) admitTenant(job Job) bool {
+	if p.tenantShareLimit <= 0 {
+		return true
+	}
+	tenant := p.tenantFor(job)
+	p.tenantMu.Lock()
+	defer p.tenantMu.Unlock()
+	if p.tenantPending[tenant] >= p.tenantShareLimit {
+		return false
+	}
+	p.tenantPending[tenant]++
+	metrics.TenantQueueDepthGauge.WithLabelValues(tenant).Set(float64(p.tenantPending[tenant]))
+	return true
+}
+
+// releaseTenant frees the queue slot a job reserved via admitTenant, once
+// the job has been dequeued for processing. A no-op unless
+// SetTenantQueueShare has been called.
+func (p *Pool) releaseTenant(job Job) {
+	if p.tenantShareLimit <= 0 {
+		return
+	}
+	tenant := p.tenantFor(job)
+	p.tenantMu.Lock()
+	defer p.tenantMu.Unlock()
+	if p.tenantPending[tenant] > 0 {
+		p.tenantPending[tenant]--
+	}
+	metrics.TenantQueueDepthGauge.WithLabelValues(tenant).Set(float64(p.tenantPending[tenant]))
+}
+
+// admitBytes reserves job.Body's share of maxQueuedBytes, returning false if
+// admitting it would exceed the cap. A no-op (always admits) unless
+// SetMaxQueuedBytes has been called.
+func (p *Pool) admitBytes(job Job) bool {
+	if p.maxQueuedBytes <= 0 {
+		return true
+	}
+	size := int64(len(job.Body))
+	if p.queuedBytes.Add(size) > p.maxQueuedBytes {
+		p.queuedBytes.Sub(size)
+		return false
+	}
+	return true
+}
+
+// releaseBytes frees the share of maxQueuedBytes a job reserved via
+// admitBytes, once the job has been dequeued for processing. A no-op unless
+// SetMaxQueuedBytes has been called.
+func (p *Pool) releaseBytes(job Job) {
+	if p.maxQueuedBytes <= 0 {
+		return
+	}
+	p.queuedBytes.Sub(int64(len(job.Body)))
+}
+
+// scheduleTenantJob appends job to its tenant's weighted-scheduling
+// sub-queue instead of handing it to jobQueue directly, and wakes
+// tenantScheduler so it can dequeue jobs in weighted round-robin order.
+// Only called by enqueue when SetTenantWeights has been configured.
+func (p *Pool) scheduleTenantJob(job Job) {
+	tenant := p.tenantFor(job)
+	p.tenantSchedMu.Lock()
+	if _, ok := p.tenantSchedQueues[tenant]; !ok {
+		p.tenantSchedOrder = append(p.tenantSchedOrder, tenant)
+	}
+	p.tenantSchedQueues[tenant] = append(p.tenantSchedQueues[tenant], job)
+	p.tenantSchedMu.Unlock()
+	p.tenantSchedCond.Signal()
+}
+
+// tenantSchedEmpty reports whether every tenant sub-queue has been fully
+// drained. Callers must hold tenantSchedMu.
+func (p *Pool) tenantSchedEmpty() bool {
+	for _, q := range p.tenantSchedQueues {
+		if len(q) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// tenantScheduler feeds jobs from the per-tenant sub-queues built by
+// scheduleTenantJob into the shared jobQueue in weighted round-robin order:
+// each round it walks tenantSchedOrder and drains up to that tenant's
+// configured weight in jobs (see SetTenantWeights) before moving to the
+// next, blocking on tenantSchedCond whenever a full round drains nothing.
+// Runs for the lifetime of the pool once SetTenantWeights is configured,
+// exiting once Stop marks scheduling closed and every sub-queue is empty.
+func (p *Pool) tenantScheduler() {
+	defer close(p.tenantSchedDone)
+	for {
+		p.tenantSchedMu.Lock()
+		if p.tenantSchedClosed && p.tenantSchedEmpty() {
+			p.tenantSchedMu.Unlock()
+			return
+		}
+		order := append([]string(nil), p.tenantSchedOrder...)
+		p.tenantSchedMu.Unlock()
+
+		sent := 0
+		for _, tenant := range order {
+			weight := p.tenantWeights[tenant]
+			if weight <= 0 {
+				weight = 1
+			}
+			for i := 0; i < weight; i++ {
+				p.tenantSchedMu.Lock()
+				queue := p.tenantSchedQueues[tenant]
+				if len(queue) == 0 {
+					p.tenantSchedMu.Unlock()
+					break
+				}
+				job := queue[0]
+				p.tenantSchedQueues[tenant] = queue[1:]
+				p.tenantSchedMu.Unlock()
+				p.jobQueue <- job
+				sent++
+			}
+		}
+
+		if sent == 0 {
+			p.tenantSchedMu.Lock()
+			if !p.tenantSchedClosed {
+				p.tenantSchedCond.Wait()
+			}
+			p.tenantSchedMu.Unlock()
+		}
+	}
+}
+
+// recordLifecycleEvent logs a worker pool lifecycle transition and
+// increments metrics.WorkerPoolLifecycleEventsCounter for the given event, so
+// dashboards can annotate throughput graphs with lifecycle changes (started,
+// stopped, resized, paused, spool-recovered) automatically instead of
+// cross-referencing application logs by hand.
+func (p *Pool) recordLifecycleEvent(event string, format string, args ...interface{}) {
+	logger.Info(format, args...)
+	metrics.WorkerPoolLifecycleEventsCounter.WithLabelValues(event).Inc()
 }
 
 // Start spawns all worker goroutines to begin processing jobs
@@ -86,17 +523,103 @@ func NewPool(workerCount int, jobQueueSize int, shutdownTimeout time.Duration) *
 // It is safe to call multiple times - workers will only be started once
 func (p *Pool) Start() {
 	p.startOnce.Do(func() {
-		logger.Info("Starting worker pool with %d workers", p.workerCount)
+		p.replayDiskQueue()
+		p.replaySpillFile()
 
-		for i := 0; i < p.workerCount; i++ {
-			p.wg.Add(1)
-			go p.worker(i)
+		if p.tenantWeights != nil {
+			go p.tenantScheduler()
+		}
+
+		if p.lazySpawn {
+			logger.Info("Starting worker pool in lazy-spawn mode: workers spawn on demand up to %d, retiring after %v idle", p.workerCount.Load(), p.idleTimeout)
+		} else {
+			logger.Info("Starting worker pool with %d workers", p.workerCount.Load())
+
+			for i := int64(0); i < p.workerCount.Load(); i++ {
+				p.liveWorkers.Inc()
+				id := int(p.nextWorkerID.Inc())
+				p.wg.Add(1)
+				go p.worker(id)
+			}
 		}
 
-		logger.Info("Worker pool started successfully")
+		p.recordLifecycleEvent("started", "Worker pool started successfully")
 	})
 }
 
+// replayDiskQueue resubmits any jobs left over on disk from a previous
+// restart, so buffered telemetry isn't lost across a pod restart. A no-op if
+// no DiskQueue is configured.
+func (p *Pool) replayDiskQueue() {
+	if p.diskQueue == nil {
+		return
+	}
+
+	entries, err := p.diskQueue.Replay()
+	if err != nil {
+		logger.Error("Failed to replay disk-backed job queue: %v", err)
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	p.recordLifecycleEvent("spool_recovered", "Replaying %d job(s) from disk-backed queue", len(entries))
+	for _, entry := range entries {
+		job := Job{Body: entry.Body, TargetURL: entry.TargetURL, Headers: entry.Headers, walID: entry.ID}
+		if err := p.enqueue(job); err != nil {
+			logger.Warn("Worker pool queue full while replaying disk-backed job %s, will retry on next restart: %v", entry.ID, err)
+		}
+	}
+}
+
+// replaySpillFile resubmits any jobs left over in the shutdown spill file
+// from a previous run whose shutdownTimeout was exceeded, then removes the
+// file. A no-op if no spill file is configured or none exists.
+func (p *Pool) replaySpillFile() {
+	if p.spillFilePath == "" {
+		return
+	}
+
+	f, err := os.Open(p.spillFilePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Error("Failed to open shutdown spill file %s: %v", p.spillFilePath, err)
+		}
+		return
+	}
+
+	var entries []spillEntry
+	dec := json.NewDecoder(f)
+	for {
+		var entry spillEntry
+		if err := dec.Decode(&entry); err != nil {
+			if err != io.EOF {
+				logger.Error("Failed to decode shutdown spill file %s: %v", p.spillFilePath, err)
+			}
+			break
+		}
+		entries = append(entries, entry)
+	}
+	f.Close()
+
+	if err := os.Remove(p.spillFilePath); err != nil {
+		logger.Error("Failed to remove shutdown spill file %s after replay: %v", p.spillFilePath, err)
+	}
+
+	if len(entries) == 0 {
+		return
+	}
+
+	p.recordLifecycleEvent("spool_recovered", "Replaying %d job(s) from shutdown spill file %s", len(entries), p.spillFilePath)
+	for _, entry := range entries {
+		job := Job{Body: entry.Body, TargetURL: entry.TargetURL, Headers: entry.Headers, Priority: entry.Priority}
+		if err := p.enqueue(job); err != nil {
+			logger.Warn("Worker pool queue full while replaying shutdown spill job for %s, dropping: %v", entry.TargetURL, err)
+		}
+	}
+}
+
 // Stop gracefully shuts down the worker pool
 // Closes the job queue channel and waits for all workers to finish processing
 // In-flight jobs will complete before shutdown finishes, up to shutdownTimeout
@@ -104,10 +627,24 @@ func (p *Pool) Start() {
 // This method is safe to call multiple times (only executes once)
 func (p *Pool) Stop() {
 	p.stopOnce.Do(func() {
-		logger.Info("Stopping worker pool: closing job queue and waiting for workers to finish")
+		p.stopping.Store(true)
+		p.recordLifecycleEvent("stopped", "Stopping worker pool: closing job queue and waiting for workers to finish")
 
-		// Close the job queue to signal workers to exit
+		// Stop feeding jobQueue from the weighted scheduler before closing
+		// it below - closing jobQueue while tenantScheduler could still send
+		// to it would panic. tenantScheduler drains any jobs still sitting
+		// in tenant sub-queues into jobQueue before it returns.
+		if p.tenantWeights != nil {
+			p.tenantSchedMu.Lock()
+			p.tenantSchedClosed = true
+			p.tenantSchedMu.Unlock()
+			p.tenantSchedCond.Broadcast()
+			<-p.tenantSchedDone
+		}
+
+		// Close both job queues to signal workers to exit
 		close(p.jobQueue)
+		close(p.highPriorityQueue)
 
 		// Wait for all workers to finish, with timeout protection
 		done := make(chan struct{})
@@ -121,14 +658,106 @@ func (p *Pool) Stop() {
 			logger.Info("Worker pool stopped: all workers finished gracefully")
 		case <-time.After(p.shutdownTimeout):
 			logger.Warn("Worker pool stop timed out after %v: some workers may not have finished", p.shutdownTimeout)
+			p.spillRemainingJobs()
 		}
 	})
 }
 
-// GetQueueDepth returns the current number of jobs in the queue
-// This is useful for monitoring and metrics collection
+// spillRemainingJobs drains any jobs still sitting in the queues (not yet
+// picked up by a worker) to p.spillFilePath, so they survive a shutdown
+// timeout instead of being silently dropped; the next Start replays them.
+// Races with any worker still draining the same queues past the timeout are
+// harmless - whichever side gets a given job handles it, so this is a
+// best-effort capture, not an exact snapshot. A no-op if no spill file is
+// configured.
+func (p *Pool) spillRemainingJobs() {
+	if p.spillFilePath == "" {
+		return
+	}
+
+	// Same nil-once-drained approach as worker(): a channel found closed and
+	// empty is set to nil so it's never selected again, instead of aborting
+	// the whole drain the moment either queue (even one that was never used)
+	// reports !ok before the other has been checked
+	var remaining []Job
+	highQueue := p.highPriorityQueue
+	lowQueue := p.jobQueue
+	for highQueue != nil || lowQueue != nil {
+		select {
+		case job, ok := <-highQueue:
+			if !ok {
+				highQueue = nil
+				continue
+			}
+			remaining = append(remaining, job)
+		case job, ok := <-lowQueue:
+			if !ok {
+				lowQueue = nil
+				continue
+			}
+			remaining = append(remaining, job)
+		default:
+			highQueue = nil
+			lowQueue = nil
+		}
+	}
+	if len(remaining) == 0 {
+		return
+	}
+
+	f, err := os.Create(p.spillFilePath)
+	if err != nil {
+		logger.Error("Failed to create shutdown spill file %s: %v", p.spillFilePath, err)
+		return
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	written := 0
+	for _, job := range remaining {
+		entry := spillEntry{Body: job.Body, TargetURL: job.TargetURL, Headers: job.Headers, Priority: job.Priority}
+		if err := enc.Encode(entry); err != nil {
+			logger.Error("Failed to spill job for %s to %s: %v", job.TargetURL, p.spillFilePath, err)
+			continue
+		}
+		written++
+	}
+	logger.Info("Spilled %d still-queued job(s) to %s for replay on next start", written, p.spillFilePath)
+}
+
+// GetQueueDepth returns the current number of jobs in the queue, across both
+// the normal and high-priority queues. This is useful for monitoring and
+// metrics collection
 func (p *Pool) GetQueueDepth() int {
-	return len(p.jobQueue)
+	depth := len(p.jobQueue) + len(p.highPriorityQueue)
+	if p.tenantWeights != nil {
+		p.tenantSchedMu.Lock()
+		for _, q := range p.tenantSchedQueues {
+			depth += len(q)
+		}
+		p.tenantSchedMu.Unlock()
+	}
+	return depth
+}
+
+// Flush blocks until the queue is empty
+// and no worker is currently processing a job, or ctx is done. It polls
+// rather than tracking a precise completion signal, since jobs move through
+// several queues (tenant sub-queues, priority queues) before a worker picks
+// one up.
+func (p *Pool) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if p.GetQueueDepth() == 0 && p.inFlight.Load() == 0 {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 }
 
 // SubmitJob submits a new forwarding job to the worker pool
@@ -137,84 +766,623 @@ func (p *Pool) GetQueueDepth() int {
 // Parameters:
 //   - job: Job containing request body, target URL, and headers
 //
-// Returns error if queue is full, nil on success
-func (p *Pool) SubmitJob(job Job) error {
-    // First, check system-wide capacity: in-flight (workers) + queued (buffer)
-    select {
-    case p.permits <- struct{}{}:
-        // There is capacity in the system; deliver the job. Use a blocking send
-        // because a worker may be ready to receive immediately or buffer has space.
-        p.jobQueue <- job
-        return nil
-    default:
-        // No capacity available -> backpressure
-        logger.Warn("Job queue full: rejecting new job (queue size: %d)", cap(p.jobQueue))
-        return fmt.Errorf("worker pool queue full (capacity: %d)", cap(p.jobQueue))
-    }
+// Returns the job's tracking id (empty unless a jobTracker is configured via
+// SetJobTracker) and an error if the queue is full
+func (p *Pool) SubmitJob(job Job) (string, error) {
+	if p.diskQueue != nil {
+		id, err := p.diskQueue.Enqueue(job.Body, job.TargetURL, job.Headers)
+		if err != nil {
+			logger.Error("Failed to persist job to disk-backed queue, submitting in-memory only: %v", err)
+		} else {
+			job.walID = id
+		}
+	}
+
+	var jobID string
+	if p.jobTracker != nil {
+		id, err := jobtracking.NewID()
+		if err != nil {
+			logger.Error("Failed to generate job tracking id, proceeding without tracking: %v", err)
+		} else {
+			jobID = id
+			job.JobID = id
+			p.jobTracker.Track(id, job.TargetURL)
+		}
+	}
+
+	if err := p.enqueue(job); err != nil {
+		if jobID != "" {
+			p.jobTracker.MarkFailed(jobID, err.Error())
+		}
+		return jobID, err
+	}
+	return jobID, nil
+}
+
+// enqueue applies backpressure and hands job to the in-memory job queue,
+// without touching the disk-backed queue (used both by SubmitJob and by
+// replayDiskQueue, which has already persisted or found the job on disk)
+func (p *Pool) enqueue(job Job) error {
+	if !p.admitTenant(job) {
+		logger.Warn("Tenant queue share exceeded: rejecting new job for tenant %q (limit: %d)", p.tenantFor(job), p.tenantShareLimit)
+		return fmt.Errorf("tenant queue share exceeded (limit: %d)", p.tenantShareLimit)
+	}
+
+	if !p.admitBytes(job) {
+		p.releaseTenant(job)
+		logger.Warn("Queued byte cap exceeded: rejecting new job (cap: %d bytes)", p.maxQueuedBytes)
+		return fmt.Errorf("worker pool queued bytes cap exceeded (cap: %d bytes)", p.maxQueuedBytes)
+	}
+
+	// First, check system-wide capacity: in-flight (workers) + queued (buffer)
+	select {
+	case p.permits <- struct{}{}:
+		// There is capacity in the system; deliver the job. Use a blocking send
+		// because a worker may be ready to receive immediately or buffer has space.
+		job.EnqueuedAt = time.Now()
+		if job.Priority == PriorityHigh {
+			p.highPriorityQueue <- job
+		} else if p.tenantWeights != nil {
+			p.scheduleTenantJob(job)
+		} else {
+			p.jobQueue <- job
+		}
+		// Grow the pool on every accepted job until it reaches workerCount;
+		// maybeSpawnWorker is a no-op past that point. Workers spawned that
+		// turn out not to be needed simply retire after sitting idle.
+		if p.lazySpawn {
+			p.maybeSpawnWorker()
+		}
+		return nil
+	default:
+		// No capacity available -> backpressure
+		if p.queueFullPolicy == "drop-oldest" && p.evictOldest() {
+			job.EnqueuedAt = time.Now()
+			if job.Priority == PriorityHigh {
+				p.highPriorityQueue <- job
+			} else if p.tenantWeights != nil {
+				p.scheduleTenantJob(job)
+			} else {
+				p.jobQueue <- job
+			}
+			if p.lazySpawn {
+				p.maybeSpawnWorker()
+			}
+			return nil
+		}
+		p.releaseTenant(job)
+		p.releaseBytes(job)
+		logger.Warn("Job queue full: rejecting new job (queue size: %d)", cap(p.jobQueue))
+		return fmt.Errorf("worker pool queue full (capacity: %d)", cap(p.jobQueue))
+	}
+}
+
+// evictOldest discards the oldest job waiting in the normal-priority queue
+// and releases the permit it held, making room for a newer submission under
+// queue_full_policy = "drop-oldest". Returns false (leaving admission to the
+// caller, which falls back to rejecting) when there's nothing evictable -
+// the normal queue is empty (all backlog is in-flight or high-priority) or
+// weighted tenant scheduling is enabled, whose per-tenant sub-queues aren't
+// safe to reach into from here.
+func (p *Pool) evictOldest() bool {
+	if p.tenantWeights != nil {
+		return false
+	}
+	select {
+	case dropped := <-p.jobQueue:
+		<-p.permits // free the slot the evicted job was holding
+		p.releaseTenant(dropped)
+		p.releaseBytes(dropped)
+		metrics.JobsEvictedCounter.Inc()
+		if dropped.JobID != "" && p.jobTracker != nil {
+			p.jobTracker.MarkFailed(dropped.JobID, "evicted: queue full, dropped in favor of a newer job")
+		}
+		logger.Warn("Job queue full: evicted oldest queued job for %s to admit a newer one (queue_full_policy=drop-oldest)", dropped.TargetURL)
+		select {
+		case p.permits <- struct{}{}:
+			return true
+		default:
+			// Shouldn't happen immediately after freeing a slot above, but
+			// guard against a concurrent enqueue racing to claim it first.
+			return false
+		}
+	default:
+		return false
+	}
+}
+
+// maybeSpawnWorker spawns one additional worker if the pool hasn't yet
+// reached its current target worker count (p.workerCount). Called by
+// enqueue in lazy-spawn mode to grow on demand as traffic arrives, and by
+// Resize to fill a raised target immediately regardless of mode. Safe to
+// call concurrently: the CAS loop ensures at most p.workerCount workers are
+// ever live even if many callers race to grow the pool at once.
+func (p *Pool) maybeSpawnWorker() {
+	for {
+		cur := p.liveWorkers.Load()
+		target := p.workerCount.Load()
+		if cur >= target {
+			return
+		}
+		if p.liveWorkers.CompareAndSwap(cur, cur+1) {
+			id := int(p.nextWorkerID.Inc())
+			p.recordLifecycleEvent("resized", "Spawning worker %d (%d/%d live)", id, cur+1, target)
+			p.wg.Add(1)
+			go p.worker(id)
+			return
+		}
+	}
+}
+
+// Resize grows or shrinks the pool's target worker count to n at runtime,
+// without restarting the process - e.g. from an admin endpoint or
+// autoscaling logic reacting to queue depth. Growing spawns the shortfall
+// immediately, regardless of SetLazySpawn. Shrinking doesn't kill any
+// worker mid-job: each excess worker notices at its next loop iteration and
+// retires itself, so a shrink takes effect gradually rather than
+// instantaneously. Safe to call at any point after NewPool, including
+// before or after Start(). n <= 0 is a no-op; Resize never reduces the
+// pool below one worker some other way (the pool simply won't grow back
+// down further than n itself).
+//
+// Resize only changes how many goroutines are available to drain the
+// pool's fixed admission capacity (p.permits, sized from the workerCount
+// and jobQueueSize passed to NewPool) - it doesn't change that capacity
+// itself, so a shrink can slow draining but never causes Submit to reject
+// jobs it wouldn't have rejected before the resize.
+func (p *Pool) Resize(n int) {
+	if n <= 0 {
+		return
+	}
+
+	prev := p.workerCount.Swap(int64(n))
+	if int64(n) == prev {
+		return
+	}
+	p.recordLifecycleEvent("resized", "Resizing worker pool: %d -> %d target workers", prev, n)
+
+	// Wake any worker currently blocked in its select (no job traffic, no
+	// idle timeout) so a shrink is noticed immediately rather than on its
+	// next job or idle retirement
+	p.resizeMu.Lock()
+	close(p.resizeCh)
+	p.resizeCh = make(chan struct{})
+	p.resizeMu.Unlock()
+
+	// Growing spawns the shortfall now; shrinking is handled entirely by
+	// each excess worker's own tryRetire check, so there's nothing further
+	// to do here.
+	for p.liveWorkers.Load() < int64(n) {
+		p.maybeSpawnWorker()
+	}
 }
 
+// tryRetire exits the calling worker if the pool currently has more live
+// workers than its target (e.g. after a Resize shrink), CAS-claiming the
+// retirement so concurrently-checking workers can't retire more than the
+// excess. Returns true if this worker claimed the retirement (the caller
+// must return immediately without the usual liveWorkers.Dec() in its
+// exit defer - tryRetire already accounts for the decrement).
+func (p *Pool) tryRetire(id int) bool {
+	for {
+		cur := p.liveWorkers.Load()
+		target := p.workerCount.Load()
+		if cur <= target {
+			return false
+		}
+		if p.liveWorkers.CompareAndSwap(cur, cur-1) {
+			p.recordLifecycleEvent("resized", "Worker %d retiring (pool resized down to %d)", id, target)
+			if cur-1 == 0 {
+				p.recordLifecycleEvent("paused", "Worker pool paused: no live workers remain")
+			}
+			return true
+		}
+	}
+}
+
+// batchedCounterFlushInterval bounds how stale a worker's locally-batched
+// JobsProcessedCounter/JobsFailedCounter deltas can get before being added to
+// the real Prometheus counters. Kept well under reconciliation's default 60s
+// polling interval (see reconciler.Reconciler) so a busy worker's unflushed
+// batch never looks like data loss.
+const batchedCounterFlushInterval = 2 * time.Second
+
 // worker is the main worker goroutine loop
-// Processes jobs from the queue until the channel is closed
+// Processes jobs from the queue until both the normal and high-priority
+// channels are closed and drained. A pending high-priority job (see
+// Priority) is always dispatched before a pending normal one.
 // Each worker runs independently and concurrently with other workers
 func (p *Pool) worker(id int) {
 	defer p.wg.Done()
 
+	// retiredEarly is set once tryRetire successfully claims this worker's
+	// exit (Resize shrink); tryRetire already decrements liveWorkers and
+	// emits its own lifecycle event, so the fallback decrement below must
+	// not double-count it.
+	retiredEarly := false
+	if p.lazySpawn {
+		defer func() {
+			if retiredEarly {
+				return
+			}
+			remaining := p.liveWorkers.Dec()
+			if p.stopping.Load() {
+				return
+			}
+			p.recordLifecycleEvent("resized", "Worker %d exited (%d/%d live)", id, remaining, p.workerCount.Load())
+			if remaining == 0 {
+				p.recordLifecycleEvent("paused", "Worker pool paused: no live workers remain in lazy-spawn mode")
+			}
+		}()
+	} else {
+		defer func() {
+			if !retiredEarly {
+				p.liveWorkers.Dec()
+			}
+		}()
+	}
+
 	logger.Info("Worker %d started", id)
 
-    // Reuse shared HTTP client with connection pooling
-    client := p.httpClient
+	// Reuse shared HTTP client with connection pooling
+	client := p.httpClient
 
-    for job := range p.jobQueue {
-		// Increment active workers gauge
-		metrics.ActiveWorkersGauge.Inc()
+	// Each worker owns its own BatchedCounter so increments on the hot path
+	// only touch a private delta instead of contending on the shared
+	// Prometheus counter with every other worker
+	processed := metrics.NewBatchedCounter(metrics.JobsProcessedCounter)
+	failed := metrics.NewBatchedCounter(metrics.JobsFailedCounter)
+	expired := metrics.NewBatchedCounter(metrics.JobsExpiredCounter)
+	flushTicker := time.NewTicker(batchedCounterFlushInterval)
+	defer flushTicker.Stop()
+	defer processed.Flush()
+	defer failed.Flush()
+	defer expired.Flush()
 
-		// Build HTTP POST request from Job struct
-		req, err := http.NewRequest("POST", job.TargetURL, bytes.NewReader(job.Body))
-		if err != nil {
-			logger.Error("Worker %d: failed to create request: %v", id, err)
-			metrics.JobsFailedCounter.Inc()
-			metrics.ActiveWorkersGauge.Dec()
-			// Release permit for this job
+	// Only lazily-spawned workers retire on idle; eager workers (the
+	// default) run for the lifetime of the pool, so idleTimer stays nil and
+	// the case below is simply never selected
+	var idleTimer *time.Timer
+	var idleTimeoutC <-chan time.Time
+	if p.lazySpawn {
+		idleTimer = time.NewTimer(p.idleTimeout)
+		defer idleTimer.Stop()
+		idleTimeoutC = idleTimer.C
+	}
+	resetIdleTimer := func() {
+		if idleTimer == nil {
+			return
+		}
+		if !idleTimer.Stop() {
+			select {
+			case <-idleTimer.C:
+			default:
+			}
+		}
+		idleTimer.Reset(p.idleTimeout)
+	}
+
+	// Set to nil once observed closed-and-drained so a select never spins on
+	// it again; a nil channel is simply never selected
+	highQueue := p.highPriorityQueue
+	lowQueue := p.jobQueue
+
+	for highQueue != nil || lowQueue != nil {
+		// Checked once per loop iteration (idle or busy) so a Resize shrink
+		// takes effect promptly: once this worker is counted as excess, it
+		// retires between jobs rather than picking up more work. The
+		// resizeCh case in the select below wakes a worker that's currently
+		// blocked waiting on job traffic so it reaches this check without
+		// needing a job to arrive first.
+		if p.tryRetire(id) {
+			retiredEarly = true
+			return
+		}
+		p.resizeMu.Lock()
+		resizeCh := p.resizeCh
+		p.resizeMu.Unlock()
+
+		// Opportunistically drain a pending high-priority job before
+		// considering anything else, so a backlog of normal-priority jobs
+		// never delays one submitted as high-priority
+		if highQueue != nil {
+			select {
+			case job, ok := <-highQueue:
+				if !ok {
+					highQueue = nil
+					continue
+				}
+				resetIdleTimer()
+				p.processJob(id, job, client, processed, failed, expired)
+				continue
+			default:
+			}
+		}
+
+		select {
+		case job, ok := <-highQueue:
+			if !ok {
+				highQueue = nil
+				continue
+			}
+			resetIdleTimer()
+			p.processJob(id, job, client, processed, failed, expired)
+		case job, ok := <-lowQueue:
+			if !ok {
+				lowQueue = nil
+				continue
+			}
+			resetIdleTimer()
+			p.processJob(id, job, client, processed, failed, expired)
+		case <-flushTicker.C:
+			processed.Flush()
+			failed.Flush()
+		case <-resizeCh:
+			// Just wakes the select so the tryRetire check at the top of the
+			// loop runs again; continue back there instead of processing a
+			// job here
+		case <-idleTimeoutC:
+			logger.Info("Worker %d retiring after %v idle", id, p.idleTimeout)
+			return
+		}
+	}
+	logger.Info("Worker %d stopped", id)
+}
+
+// processJob delivers job to the collector, retrying per p.retryPolicy and
+// dead-lettering it on permanent failure, then releases its resources (disk
+// queue entry, active-worker gauge, backpressure permit). processed, failed
+// and expired are the calling worker's own BatchedCounters.
+func (p *Pool) processJob(id int, job Job, client *http.Client, processed, failed, expired *metrics.BatchedCounter) {
+	p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+
+	// Job has left the queue - free its tenant's share and its reservation
+	// against maxQueuedBytes so new jobs can be admitted
+	p.releaseTenant(job)
+	p.releaseBytes(job)
+
+	if !job.EnqueuedAt.IsZero() {
+		metrics.QueueWaitHistogram.Observe(time.Since(job.EnqueuedAt).Seconds())
+	}
+
+	// Drop jobs that sat in the queue past the configured TTL rather than
+	// forwarding stale telemetry - useful after a long collector outage,
+	// where the recovery window is better spent delivering fresh data than
+	// working through a backlog nobody wants anymore
+	if p.jobTTL > 0 && !job.EnqueuedAt.IsZero() {
+		if age := time.Since(job.EnqueuedAt); age > p.jobTTL {
+			logger.Warn("Worker %d: dropping job for %s, queued %v exceeds TTL %v", id, job.TargetURL, age, p.jobTTL)
+			expired.Inc()
+			if p.jobTracker != nil && job.JobID != "" {
+				p.jobTracker.MarkFailed(job.JobID, "dropped: exceeded job TTL")
+			}
+			p.completeDiskEntry(id, job)
 			<-p.permits
-			continue
+			return
 		}
+	}
+
+	// A job whose context was already canceled before we got to it (e.g. the
+	// app is shutting down) isn't worth dispatching at all
+	if err := job.context().Err(); err != nil {
+		logger.Warn("Worker %d: dropping job for %s, context done: %v", id, job.TargetURL, err)
+		failed.Inc()
+		p.deadLetterJob(id, job, fmt.Sprintf("context done before dispatch: %v", err))
+		p.completeDiskEntry(id, job)
+		<-p.permits
+		return
+	}
+
+	// Wait for a token before sending anything to the collector, so a
+	// burst of client traffic waits in the (already-dequeued-but-not-
+	// yet-sent) job rather than hitting the collector all at once
+	if p.rateLimiter != nil {
+		_ = p.rateLimiter.Wait(job.context())
+	}
 
-        // Set headers from job (Content-Type, Authorization, etc.) with full values
-        for key, values := range job.Headers {
-            for _, v := range values {
-                req.Header.Add(key, v)
-            }
-        }
+	// Increment active workers gauge
+	metrics.ActiveWorkersGauge.Inc()
+
+	for attempt := 1; ; attempt++ {
+		p.waitForCooldown(job.context(), job.TargetURL)
+
+		attemptStart := time.Now()
+		statusCode, retryAfter, err := p.deliver(client, job)
+		latency := time.Since(attemptStart)
+		success := err == nil && statusCode >= 200 && statusCode < 300
+		if p.healthScorer != nil {
+			if success {
+				p.healthScorer.RecordSuccess(latency)
+			} else {
+				p.healthScorer.RecordFailure()
+			}
+		}
+		if success {
+			processed.Inc()
+			if p.jobTracker != nil && job.JobID != "" {
+				p.jobTracker.MarkSent(job.JobID)
+			}
+			break
+		}
 
-		// Execute HTTP request with timeout
-		resp, err := client.Do(req)
 		if err != nil {
-			// Log forwarding errors to stderr (don't propagate to client)
-			logger.Error("Worker %d: forwarding to %s failed: %v", id, job.TargetURL, err)
-			metrics.JobsFailedCounter.Inc()
-			metrics.ActiveWorkersGauge.Dec()
-			// Release permit for this job
-			<-p.permits
+			logger.Error("Worker %d: forwarding to %s failed (attempt %d/%d): %v", id, job.TargetURL, attempt, p.retryPolicy.MaxAttempts, err)
+		} else {
+			logger.Warn("Worker %d: collector returned %d for %s (attempt %d/%d)", id, statusCode, job.TargetURL, attempt, p.retryPolicy.MaxAttempts)
+		}
+
+		if retryAfter > 0 {
+			logger.Info("Worker %d: honoring Retry-After %v from %s, pausing dispatch to this target", id, retryAfter, job.TargetURL)
+			p.setCooldown(job.TargetURL, retryAfter)
+		}
+
+		// A 4xx (other than 429 Too Many Requests) means the request itself
+		// is malformed or rejected - retrying won't help, so dead-letter it now
+		nonRetryableClientError := statusCode >= 400 && statusCode < 500 && statusCode != http.StatusTooManyRequests
+		if nonRetryableClientError {
+			failed.Inc()
+			metrics.ForwardErrorsCounter.WithLabelValues(metrics.ClassifyForwardError(err, statusCode)).Inc()
+			p.deadLetterJob(id, job, fmt.Sprintf("collector returned non-retryable status %d", statusCode))
+			break
+		}
+
+		if !p.retryPolicy.shouldRetry(attempt) {
+			failed.Inc()
+			metrics.ForwardErrorsCounter.WithLabelValues(metrics.ClassifyForwardError(err, statusCode)).Inc()
+			p.deadLetterJob(id, job, "retries exhausted")
+			break
+		}
+
+		metrics.WorkerPoolRetriesCounter.Inc()
+		if retryAfter > 0 {
+			// waitForCooldown at the top of the next iteration already covers
+			// this; nothing more to sleep here
 			continue
 		}
+		time.Sleep(p.retryPolicy.backoffFor(attempt))
+	}
 
-		// Close response body to reuse connection
-		resp.Body.Close()
+	p.completeDiskEntry(id, job)
 
-		// Log non-2xx responses as warnings (operational visibility)
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			logger.Warn("Worker %d: collector returned %d for %s", id, resp.StatusCode, job.TargetURL)
-			metrics.JobsFailedCounter.Inc()
-		} else {
-			// Job processed successfully
-			metrics.JobsProcessedCounter.Inc()
+	// Decrement active workers gauge
+	metrics.ActiveWorkersGauge.Dec()
+	// Release permit after finishing this job
+	<-p.permits
+}
+
+// completeDiskEntry removes job's write-ahead log entry, if any, once it has
+// reached a terminal state (delivered, dead-lettered, or dropped)
+func (p *Pool) completeDiskEntry(id int, job Job) {
+	if p.diskQueue == nil || job.walID == "" {
+		return
+	}
+	if err := p.diskQueue.Complete(job.walID); err != nil {
+		logger.Error("Worker %d: failed to remove disk-backed queue entry for %s: %v", id, job.TargetURL, err)
+	}
+}
+
+// deadLetterJob records job's failure with the jobTracker (if configured) and
+// persists it if a DeadLetterWriter is configured
+func (p *Pool) deadLetterJob(id int, job Job, reason string) {
+	if p.jobTracker != nil && job.JobID != "" {
+		p.jobTracker.MarkFailed(job.JobID, reason)
+	}
+	if p.deadLetter == nil {
+		return
+	}
+	if err := p.deadLetter.Write(job.Body, job.TargetURL, job.Headers, reason); err != nil {
+		logger.Error("Worker %d: failed to dead-letter job for %s: %v", id, job.TargetURL, err)
+	}
+}
+
+// waitForCooldown blocks until targetURL's cooldown set by a prior 429/503's
+// Retry-After has elapsed (a no-op if none is set or it's already passed), or
+// until ctx is done
+func (p *Pool) waitForCooldown(ctx context.Context, targetURL string) {
+	p.cooldownMu.Lock()
+	until, ok := p.cooldownUntil[targetURL]
+	p.cooldownMu.Unlock()
+	if !ok {
+		return
+	}
+
+	remaining := time.Until(until)
+	if remaining <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// setCooldown records that no worker should dispatch to targetURL again until
+// after retryAfter elapses, so a collector's Retry-After on one job's
+// response throttles every subsequent job to that target, not just the job
+// that triggered it. A cooldown already further out than until is left alone.
+func (p *Pool) setCooldown(targetURL string, retryAfter time.Duration) {
+	until := time.Now().Add(retryAfter)
+	p.cooldownMu.Lock()
+	defer p.cooldownMu.Unlock()
+	if existing, ok := p.cooldownUntil[targetURL]; ok && existing.After(until) {
+		return
+	}
+	p.cooldownUntil[targetURL] = until
+}
+
+// deliver sends a single attempt at forwarding job to the collector, returning
+// the response status code (0 if the request never reached the collector) and
+// the Retry-After duration parsed from a 429/503 response, if any (0 if
+// absent or the status doesn't warrant honoring it)
+func (p *Pool) deliver(client *http.Client, job Job) (int, time.Duration, error) {
+	ctx := job.context()
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", job.TargetURL, bytes.NewReader(job.Body))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers from job (Content-Type, Authorization, etc.) with full values
+	for key, values := range job.Headers {
+		for _, v := range values {
+			req.Header.Add(key, v)
 		}
+	}
 
-		// Decrement active workers gauge
-		metrics.ActiveWorkersGauge.Dec()
-        // Release permit after finishing this job
-        <-p.permits
+	// Jobs backed by a DiskQueue carry a walID that's stable across a crash:
+	// a job replayed after a restart has the same walID it was enqueued
+	// with. Stamp it so the collector can dedup a crash-redelivered job
+	// against the one it may have already received before the crash.
+	if job.walID != "" {
+		req.Header.Set("X-Idempotency-Key", job.walID)
 	}
 
-	logger.Info("Worker %d stopped", id)
+	start := time.Now()
+	resp, err := client.Do(req)
+	metrics.ObserveForwardLatency(job.TargetURL, start)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+
+	if p.diagnostics != nil && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+		body, _ := bufpool.ReadAll(io.LimitReader(resp.Body, diagnostics.MaxBodySnippetBytes))
+		p.diagnostics.Record(job.TargetURL, resp.StatusCode, resp.Header, body)
+	}
+
+	var retryAfter time.Duration
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		retryAfter, _ = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+
+	return resp.StatusCode, retryAfter, nil
+}
+
+// parseRetryAfter parses an HTTP Retry-After header value, which per RFC 9110
+// is either a number of delta-seconds or an HTTP-date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
 }
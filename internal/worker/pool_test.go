@@ -1,12 +1,27 @@
 package worker
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"zep-logger/internal/diskqueue"
+	"zep-logger/internal/httpclient"
+	"zep-logger/internal/jobtracking"
+	"zep-logger/internal/metrics"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"golang.org/x/time/rate"
 )
 
 // TestWorkerPool_BoundedConcurrency verifies max workers respected
@@ -35,7 +50,7 @@ func TestWorkerPool_BoundedConcurrency(t *testing.T) {
 	defer mockCollector.Close()
 
 	// Create pool with 2 workers
-	pool := NewPool(2, 100, 5*time.Second)
+	pool := NewPool(2, 100, 5*time.Second, httpclient.Config{})
 	pool.Start()
 	defer pool.Stop()
 
@@ -46,7 +61,7 @@ func TestWorkerPool_BoundedConcurrency(t *testing.T) {
             Body:      []byte("test"),
             Headers:   http.Header{},
         }
-		err := pool.SubmitJob(job)
+		_, err := pool.SubmitJob(job)
 		if err != nil {
 			t.Fatalf("failed to submit job %d: %v", i, err)
 		}
@@ -65,7 +80,7 @@ func TestWorkerPool_BoundedConcurrency(t *testing.T) {
 // AC3: Worker pool test verifies job queue buffer size enforced
 func TestWorkerPool_JobQueueBufferSize(t *testing.T) {
 	// Create pool with small queue (5 jobs)
-	pool := NewPool(1, 5, 5*time.Second)
+	pool := NewPool(1, 5, 5*time.Second, httpclient.Config{})
 	pool.Start()
 	defer pool.Stop()
 
@@ -84,7 +99,7 @@ func TestWorkerPool_JobQueueBufferSize(t *testing.T) {
             Body:      []byte("test"),
             Headers:   http.Header{},
         }
-		err := pool.SubmitJob(job)
+		_, err := pool.SubmitJob(job)
 		if err == nil {
 			successCount++
 		}
@@ -100,7 +115,7 @@ func TestWorkerPool_JobQueueBufferSize(t *testing.T) {
 // AC3: Worker pool test verifies backpressure (queue full returns error)
 func TestWorkerPool_Backpressure(t *testing.T) {
 	// Create pool with tiny queue (1 job)
-	pool := NewPool(1, 1, 5*time.Second)
+	pool := NewPool(1, 1, 5*time.Second, httpclient.Config{})
 	pool.Start()
 	defer pool.Stop()
 
@@ -118,16 +133,291 @@ func TestWorkerPool_Backpressure(t *testing.T) {
     }
 
 	// Fill queue (1 in-flight + 1 queued)
-	_ = pool.SubmitJob(job)
-	_ = pool.SubmitJob(job)
+	_, _ = pool.SubmitJob(job)
+	_, _ = pool.SubmitJob(job)
 
 	// This should fail with backpressure error
-	err := pool.SubmitJob(job)
+	_, err := pool.SubmitJob(job)
 	if err == nil {
 		t.Error("expected backpressure error when queue is full, got nil")
 	}
 }
 
+// TestWorkerPool_QueueFullPolicy_DropOldestAdmitsNewerJob verifies that with
+// SetQueueFullPolicy("drop-oldest"), a submission that would otherwise be
+// rejected under backpressure is instead admitted by evicting the oldest
+// queued job.
+func TestWorkerPool_QueueFullPolicy_DropOldestAdmitsNewerJob(t *testing.T) {
+	pool := NewPool(1, 1, 5*time.Second, httpclient.Config{})
+	pool.SetQueueFullPolicy("drop-oldest")
+	pool.Start()
+	defer pool.Stop()
+
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: http.Header{}}
+
+	// First submit is picked up by the sole worker and blocks on release,
+	// keeping it in-flight rather than queued.
+	if _, err := pool.SubmitJob(job); err != nil {
+		t.Fatalf("first submit failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// Second submit fills the one-job queue.
+	if _, err := pool.SubmitJob(job); err != nil {
+		t.Fatalf("second submit failed: %v", err)
+	}
+
+	// Third submit would be rejected under the default policy; drop-oldest
+	// should instead evict the second job and admit this one.
+	if _, err := pool.SubmitJob(job); err != nil {
+		t.Errorf("expected drop-oldest to admit the newer job instead of rejecting, got error: %v", err)
+	}
+}
+
+// TestWorkerPool_MaxQueuedBytes_RejectsOnceCapExceeded verifies that
+// SetMaxQueuedBytes rejects a submission that would push the combined size
+// of queued job bodies past the configured cap, even though the job count
+// itself is still within job_queue_size.
+func TestWorkerPool_MaxQueuedBytes_RejectsOnceCapExceeded(t *testing.T) {
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.SetMaxQueuedBytes(15)
+	pool.Start()
+	defer pool.Stop()
+
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	// First submit is picked up by the sole worker and blocks in flight,
+	// so it doesn't count against the queued-bytes cap.
+	inFlight := Job{TargetURL: mockCollector.URL, Body: []byte("in-flight"), Headers: http.Header{}}
+	if _, err := pool.SubmitJob(inFlight); err != nil {
+		t.Fatalf("first submit failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// Second submit (10 bytes) fits within the 15-byte cap and queues.
+	if _, err := pool.SubmitJob(Job{TargetURL: mockCollector.URL, Body: []byte("0123456789"), Headers: http.Header{}}); err != nil {
+		t.Fatalf("second submit failed: %v", err)
+	}
+
+	// Third submit (10 more bytes) would bring the queued total to 20,
+	// exceeding the 15-byte cap - it should be rejected even though the
+	// queue (capacity 10) has plenty of room by job count alone.
+	if _, err := pool.SubmitJob(Job{TargetURL: mockCollector.URL, Body: []byte("0123456789"), Headers: http.Header{}}); err == nil {
+		t.Error("expected submission to be rejected once queued bytes cap is exceeded, got nil error")
+	}
+}
+
+// TestWorkerPool_MaxQueuedBytes_Disabled_AllowsUnboundedBodySizes verifies
+// that leaving SetMaxQueuedBytes unset (the default) doesn't reject jobs
+// based on body size, only ever on job count/backpressure as before.
+func TestWorkerPool_MaxQueuedBytes_Disabled_AllowsUnboundedBodySizes(t *testing.T) {
+	pool := NewPool(2, 10, 5*time.Second, httpclient.Config{})
+	pool.Start()
+	defer pool.Stop()
+
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	bigBody := make([]byte, 1024*1024)
+	if _, err := pool.SubmitJob(Job{TargetURL: mockCollector.URL, Body: bigBody, Headers: http.Header{}}); err != nil {
+		t.Errorf("expected large job to be admitted with no byte cap configured, got error: %v", err)
+	}
+}
+
+// TestWorkerPool_WarmUp_FiresRequestedRequestCount verifies WarmUp issues
+// exactly count requests against targetURL and blocks until they've all
+// completed.
+func TestWorkerPool_WarmUp_FiresRequestedRequestCount(t *testing.T) {
+	pool := NewPool(2, 10, 5*time.Second, httpclient.Config{})
+
+	var received int64
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&received, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	pool.WarmUp(context.Background(), mockCollector.URL, 5)
+
+	if got := atomic.LoadInt64(&received); got != 5 {
+		t.Errorf("expected 5 warm-up requests to reach the collector, got %d", got)
+	}
+}
+
+// TestWorkerPool_WarmUp_ZeroCountIsNoOp verifies a count of 0 (the default,
+// warm-up disabled) issues no requests at all.
+func TestWorkerPool_WarmUp_ZeroCountIsNoOp(t *testing.T) {
+	pool := NewPool(2, 10, 5*time.Second, httpclient.Config{})
+
+	var received int64
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&received, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	pool.WarmUp(context.Background(), mockCollector.URL, 0)
+
+	if got := atomic.LoadInt64(&received); got != 0 {
+		t.Errorf("expected no warm-up requests with count=0, got %d", got)
+	}
+}
+
+// TestWorkerPool_RateLimiter_CapsRequestsPerSecond verifies that a
+// configured rate limiter throttles how fast jobs reach the collector,
+// rather than letting every worker fire immediately
+func TestWorkerPool_RateLimiter_CapsRequestsPerSecond(t *testing.T) {
+	var received int64
+
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&received, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	pool := NewPool(10, 100, 5*time.Second, httpclient.Config{})
+	pool.SetRateLimiter(rate.NewLimiter(rate.Limit(5), 1))
+	pool.Start()
+	defer pool.Stop()
+
+	for i := 0; i < 20; i++ {
+		job := Job{
+			TargetURL: mockCollector.URL,
+			Body:      []byte("test"),
+			Headers:   http.Header{},
+		}
+		_, _ = pool.SubmitJob(job)
+	}
+
+	// At 5 req/s with a burst of 1, well under a second in shouldn't be
+	// enough time for the collector to see anywhere near all 20 jobs
+	time.Sleep(200 * time.Millisecond)
+	if got := atomic.LoadInt64(&received); got >= 20 {
+		t.Errorf("expected rate limiter to hold back most of the 20 jobs within 200ms, but collector already received %d", got)
+	}
+
+	// Give the limiter enough time to drain the rest so Stop() doesn't hang
+	time.Sleep(4 * time.Second)
+	if got := atomic.LoadInt64(&received); got != 20 {
+		t.Errorf("expected all 20 jobs to eventually be delivered, got %d", got)
+	}
+}
+
+// TestWorkerPool_PriorityJobs_DispatchedBeforeQueuedNormalJobs verifies that
+// a PriorityHigh job is delivered before a backlog of already-queued
+// PriorityNormal jobs, even though it was submitted last
+func TestWorkerPool_PriorityJobs_DispatchedBeforeQueuedNormalJobs(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	release := make(chan struct{})
+
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		label := string(body)
+		if label == "blocker" {
+			<-release
+		} else {
+			mu.Lock()
+			order = append(order, label)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	// A single worker means everything submitted after the blocker job piles
+	// up in the queue(s) instead of being dispatched concurrently
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.Start()
+	defer pool.Stop()
+
+	_, _ = pool.SubmitJob(Job{TargetURL: mockCollector.URL, Body: []byte("blocker"), Headers: http.Header{}})
+	time.Sleep(50 * time.Millisecond) // let the worker pick up the blocker before queuing more
+
+	for i := 0; i < 3; i++ {
+		_, _ = pool.SubmitJob(Job{
+			TargetURL: mockCollector.URL,
+			Body:      []byte(fmt.Sprintf("normal-%d", i)),
+			Headers:   http.Header{},
+			Priority:  PriorityNormal,
+		})
+	}
+	_, _ = pool.SubmitJob(Job{
+		TargetURL: mockCollector.URL,
+		Body:      []byte("high"),
+		Headers:   http.Header{},
+		Priority:  PriorityHigh,
+	})
+
+	close(release)
+	time.Sleep(200 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) == 0 || order[0] != "high" {
+		t.Fatalf("expected the high-priority job to be dispatched first, got order: %v", order)
+	}
+}
+
+// TestWorkerPool_JobTTL_DropsStaleJobsAndCountsExpiredMetric verifies that a
+// job which sat in the queue longer than SetJobTTL is dropped instead of
+// forwarded, and counted in metrics.JobsExpiredCounter
+func TestWorkerPool_JobTTL_DropsStaleJobsAndCountsExpiredMetric(t *testing.T) {
+	var delivered int64
+	release := make(chan struct{})
+
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) == "blocker" {
+			<-release
+		} else {
+			atomic.AddInt64(&delivered, 1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.SetJobTTL(50 * time.Millisecond)
+	pool.Start()
+
+	before := testutil.ToFloat64(metrics.JobsExpiredCounter)
+
+	_, _ = pool.SubmitJob(Job{TargetURL: mockCollector.URL, Body: []byte("blocker"), Headers: http.Header{}})
+	time.Sleep(20 * time.Millisecond) // let the worker pick up the blocker first
+
+	_, _ = pool.SubmitJob(Job{TargetURL: mockCollector.URL, Body: []byte("stale"), Headers: http.Header{}})
+
+	// Hold the blocker well past the TTL so the queued job is already stale
+	// by the time the worker reaches it
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	time.Sleep(100 * time.Millisecond)
+
+	// Stop flushes the worker's BatchedCounter instead of waiting for the
+	// (up to 2s) flush ticker, so the metric assertion below isn't flaky
+	pool.Stop()
+
+	if got := atomic.LoadInt64(&delivered); got != 0 {
+		t.Errorf("expected the stale job to be dropped rather than delivered, but collector received %d", got)
+	}
+	if after := testutil.ToFloat64(metrics.JobsExpiredCounter); after-before < 1 {
+		t.Errorf("expected worker_pool_jobs_expired_total to increase by at least 1, got delta %v", after-before)
+	}
+}
+
 // TestWorkerPool_GracefulShutdown verifies in-flight jobs complete
 // AC3: Worker pool test verifies graceful shutdown (in-flight jobs complete)
 func TestWorkerPool_GracefulShutdown(t *testing.T) {
@@ -141,7 +431,7 @@ func TestWorkerPool_GracefulShutdown(t *testing.T) {
 	}))
 	defer mockCollector.Close()
 
-	pool := NewPool(2, 10, 5*time.Second)
+	pool := NewPool(2, 10, 5*time.Second, httpclient.Config{})
 	pool.Start()
 
 	// Submit 5 jobs
@@ -151,7 +441,7 @@ func TestWorkerPool_GracefulShutdown(t *testing.T) {
             Body:      []byte("test"),
             Headers:   http.Header{},
         }
-		_ = pool.SubmitJob(job)
+		_, _ = pool.SubmitJob(job)
 	}
 
 	// Stop pool (should wait for in-flight jobs)
@@ -163,10 +453,172 @@ func TestWorkerPool_GracefulShutdown(t *testing.T) {
 	}
 }
 
+// TestWorkerPool_Stop_FlushesBatchedJobsProcessedCounter verifies that a
+// worker's locally-batched JobsProcessedCounter delta is not lost on
+// shutdown, even if it hasn't hit a flush tick yet
+func TestWorkerPool_Stop_FlushesBatchedJobsProcessedCounter(t *testing.T) {
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	before := testutil.ToFloat64(metrics.JobsProcessedCounter)
+
+	pool := NewPool(2, 10, 5*time.Second, httpclient.Config{})
+	pool.Start()
+
+	for i := 0; i < 5; i++ {
+		job := Job{
+			TargetURL: mockCollector.URL,
+			Body:      []byte("test"),
+			Headers:   http.Header{},
+		}
+		_, _ = pool.SubmitJob(job)
+	}
+
+	// Stop immediately, well before batchedCounterFlushInterval elapses, so
+	// the only way the delta reaches the counter is via Stop's drain flush
+	pool.Stop()
+
+	after := testutil.ToFloat64(metrics.JobsProcessedCounter)
+	if got := after - before; got != 5 {
+		t.Errorf("expected JobsProcessedCounter to increase by 5 after Stop, got %v", got)
+	}
+}
+
+// TestWorkerPool_ProcessJob_ObservesQueueWaitHistogram verifies that a job's
+// time-in-queue (enqueue to a worker picking it up) is recorded on
+// metrics.QueueWaitHistogram
+func TestWorkerPool_ProcessJob_ObservesQueueWaitHistogram(t *testing.T) {
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	before := &dto.Metric{}
+	if err := metrics.QueueWaitHistogram.Write(before); err != nil {
+		t.Fatalf("failed to read histogram before: %v", err)
+	}
+
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.Start()
+
+	job := Job{
+		TargetURL: mockCollector.URL,
+		Body:      []byte("test"),
+		Headers:   http.Header{},
+	}
+	_, _ = pool.SubmitJob(job)
+	pool.Stop()
+
+	after := &dto.Metric{}
+	if err := metrics.QueueWaitHistogram.Write(after); err != nil {
+		t.Fatalf("failed to read histogram after: %v", err)
+	}
+	if got := after.GetHistogram().GetSampleCount() - before.GetHistogram().GetSampleCount(); got != 1 {
+		t.Errorf("expected QueueWaitHistogram sample count to increase by 1, got %d", got)
+	}
+}
+
+// TestWorkerPool_LazySpawn_NoWorkersUntilJobsArrive verifies that a
+// lazy-spawn pool starts with zero live workers instead of workerCount
+func TestWorkerPool_LazySpawn_NoWorkersUntilJobsArrive(t *testing.T) {
+	pool := NewPool(5, 10, 5*time.Second, httpclient.Config{})
+	pool.SetLazySpawn(200 * time.Millisecond)
+	pool.Start()
+	defer pool.Stop()
+
+	time.Sleep(20 * time.Millisecond)
+	if got := pool.liveWorkers.Load(); got != 0 {
+		t.Errorf("expected 0 live workers before any job is submitted, got %d", got)
+	}
+}
+
+// TestWorkerPool_LazySpawn_GrowsUpToMaxThenRetiresIdleWorkers verifies that
+// submitting a burst of jobs grows the pool up to workerCount, and that
+// workers retire again once idle past idleTimeout
+func TestWorkerPool_LazySpawn_GrowsUpToMaxThenRetiresIdleWorkers(t *testing.T) {
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	pool := NewPool(4, 10, 5*time.Second, httpclient.Config{})
+	pool.SetLazySpawn(300 * time.Millisecond)
+	pool.Start()
+	defer pool.Stop()
+
+	for i := 0; i < 4; i++ {
+		job := Job{
+			TargetURL: mockCollector.URL,
+			Body:      []byte("test"),
+			Headers:   http.Header{},
+		}
+		_, _ = pool.SubmitJob(job)
+	}
+
+	// Check while all 4 jobs are still in flight (well before the 200ms
+	// mock collector responds), so the idle timer hasn't started yet
+	time.Sleep(50 * time.Millisecond)
+	if got := pool.liveWorkers.Load(); got != 4 {
+		t.Errorf("expected pool to grow to 4 live workers under a burst of 4 jobs, got %d", got)
+	}
+
+	// Jobs finish around the 200ms mark, starting each worker's idle timer;
+	// wait past that plus idleTimeout for them all to retire
+	time.Sleep(200*time.Millisecond + 300*time.Millisecond + 100*time.Millisecond)
+	if got := pool.liveWorkers.Load(); got != 0 {
+		t.Errorf("expected all workers to retire after sitting idle past idleTimeout, got %d", got)
+	}
+}
+
+// TestWorkerPool_Resize_GrowsEagerPoolImmediately verifies that Resize grows
+// an eager (non-lazy-spawn) pool's live worker count right away, without
+// waiting for new job traffic
+func TestWorkerPool_Resize_GrowsEagerPoolImmediately(t *testing.T) {
+	pool := NewPool(2, 10, 5*time.Second, httpclient.Config{})
+	pool.Start()
+	defer pool.Stop()
+
+	time.Sleep(20 * time.Millisecond)
+	if got := pool.liveWorkers.Load(); got != 2 {
+		t.Fatalf("expected 2 live workers after Start(), got %d", got)
+	}
+
+	pool.Resize(5)
+
+	time.Sleep(20 * time.Millisecond)
+	if got := pool.liveWorkers.Load(); got != 5 {
+		t.Errorf("expected Resize(5) to grow to 5 live workers, got %d", got)
+	}
+}
+
+// TestWorkerPool_Resize_ShrinksPoolGradually verifies that Resize down
+// eventually brings live worker count down to the new target, without
+// interrupting jobs in flight (workers retire between jobs, not mid-job)
+func TestWorkerPool_Resize_ShrinksPoolGradually(t *testing.T) {
+	pool := NewPool(5, 10, 5*time.Second, httpclient.Config{})
+	pool.Start()
+	defer pool.Stop()
+
+	time.Sleep(20 * time.Millisecond)
+	if got := pool.liveWorkers.Load(); got != 5 {
+		t.Fatalf("expected 5 live workers after Start(), got %d", got)
+	}
+
+	pool.Resize(2)
+
+	time.Sleep(50 * time.Millisecond)
+	if got := pool.liveWorkers.Load(); got != 2 {
+		t.Errorf("expected Resize(2) to shrink to 2 live workers, got %d", got)
+	}
+}
+
 // TestWorkerPool_StartStopLifecycle verifies Start() and Stop() methods
 // AC3: Worker pool test verifies Start() and Stop() lifecycle methods
 func TestWorkerPool_StartStopLifecycle(t *testing.T) {
-	pool := NewPool(2, 10, 5*time.Second)
+	pool := NewPool(2, 10, 5*time.Second, httpclient.Config{})
 
 	// Verify pool can be started
 	pool.Start()
@@ -182,7 +634,7 @@ func TestWorkerPool_StartStopLifecycle(t *testing.T) {
         Body:      []byte("test"),
         Headers:   http.Header{},
     }
-	err := pool.SubmitJob(job)
+	_, err := pool.SubmitJob(job)
 	if err != nil {
 		t.Fatalf("failed to submit job after Start(): %v", err)
 	}
@@ -196,7 +648,7 @@ func TestWorkerPool_StartStopLifecycle(t *testing.T) {
 
 // TestWorkerPool_MultipleStartCalls verifies startOnce behavior
 func TestWorkerPool_MultipleStartCalls(t *testing.T) {
-	pool := NewPool(2, 10, 5*time.Second)
+	pool := NewPool(2, 10, 5*time.Second, httpclient.Config{})
 
 	// Start multiple times
 	pool.Start()
@@ -215,7 +667,7 @@ func TestWorkerPool_MultipleStartCalls(t *testing.T) {
         Body:      []byte("test"),
         Headers:   http.Header{},
     }
-	err := pool.SubmitJob(job)
+	_, err := pool.SubmitJob(job)
 	if err != nil {
 		t.Fatalf("pool not working after multiple Start() calls: %v", err)
 	}
@@ -225,7 +677,7 @@ func TestWorkerPool_MultipleStartCalls(t *testing.T) {
 
 // TestWorkerPool_GetQueueDepth verifies queue depth metric
 func TestWorkerPool_GetQueueDepth(t *testing.T) {
-	pool := NewPool(1, 10, 5*time.Second)
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
 	pool.Start()
 	defer pool.Stop()
 
@@ -248,7 +700,7 @@ func TestWorkerPool_GetQueueDepth(t *testing.T) {
             Body:      []byte("test"),
             Headers:   http.Header{},
         }
-		_ = pool.SubmitJob(job)
+		_, _ = pool.SubmitJob(job)
 	}
 
 	// Queue depth should be > 0 (some jobs waiting)
@@ -259,6 +711,144 @@ func TestWorkerPool_GetQueueDepth(t *testing.T) {
 	}
 }
 
+// TestWorkerPool_TenantQueueShare_RejectsOverLimitTenantWithoutStarvingOthers
+// verifies a single tenant's burst can't fill the whole queue: once it hits
+// its share limit further jobs for it are rejected, while another tenant is
+// still admitted.
+func TestWorkerPool_TenantQueueShare_RejectsOverLimitTenantWithoutStarvingOthers(t *testing.T) {
+    pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+    pool.SetTenantQueueShare("X-Tenant-Id", 2)
+
+    mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        time.Sleep(200 * time.Millisecond)
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer mockCollector.Close()
+    // Pool is never Started, so submitted jobs sit in the queue and the
+    // tenant's share is never released by processJob mid-test
+
+    noisyTenant := http.Header{"X-Tenant-Id": {"noisy"}}
+    for i := 0; i < 2; i++ {
+        job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: noisyTenant}
+        if _, err := pool.SubmitJob(job); err != nil {
+            t.Fatalf("expected job %d for noisy tenant to be admitted, got error: %v", i, err)
+        }
+    }
+
+    overLimit := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: noisyTenant}
+    if _, err := pool.SubmitJob(overLimit); err == nil {
+        t.Error("expected job past the tenant's queue share to be rejected")
+    }
+
+    quietTenant := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: http.Header{"X-Tenant-Id": {"quiet"}}}
+    if _, err := pool.SubmitJob(quietTenant); err != nil {
+        t.Errorf("expected another tenant to still be admitted, got error: %v", err)
+    }
+}
+
+// TestWorkerPool_TenantQueueShare_ReleasesSlotOnceJobIsDequeued verifies a
+// tenant's queue share frees up as its jobs are picked up by a worker, so it
+// isn't rejected forever after one burst.
+func TestWorkerPool_TenantQueueShare_ReleasesSlotOnceJobIsDequeued(t *testing.T) {
+    pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+    pool.SetTenantQueueShare("X-Tenant-Id", 1)
+    pool.Start()
+    defer pool.Stop()
+
+    mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer mockCollector.Close()
+
+    tenant := http.Header{"X-Tenant-Id": {"tenant-a"}}
+    for i := 0; i < 3; i++ {
+        job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: tenant}
+        if _, err := pool.SubmitJob(job); err != nil {
+            t.Fatalf("expected job %d to eventually be admitted once earlier ones drain, got error: %v", i, err)
+        }
+        time.Sleep(50 * time.Millisecond)
+    }
+}
+
+// TestWorkerPool_TenantQueueShare_ReportsPerTenantDepthMetric verifies each
+// tenant's pending count is reflected on metrics.TenantQueueDepthGauge, so a
+// dashboard can see which tenant is filling its partition of the queue.
+func TestWorkerPool_TenantQueueShare_ReportsPerTenantDepthMetric(t *testing.T) {
+    pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+    pool.SetTenantQueueShare("X-Client-Id", 5)
+
+    mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        time.Sleep(200 * time.Millisecond)
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer mockCollector.Close()
+    // Pool is never Started, so submitted jobs sit in the queue and the
+    // tenant's depth is never released by processJob mid-test
+
+    noisyTenant := http.Header{"X-Client-Id": {"noisy"}}
+    for i := 0; i < 3; i++ {
+        job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: noisyTenant}
+        if _, err := pool.SubmitJob(job); err != nil {
+            t.Fatalf("expected job %d for noisy tenant to be admitted, got error: %v", i, err)
+        }
+    }
+
+    if depth := testutil.ToFloat64(metrics.TenantQueueDepthGauge.WithLabelValues("noisy")); depth != 3 {
+        t.Errorf("expected tenant queue depth gauge for 'noisy' to be 3, got %v", depth)
+    }
+}
+
+func TestWorkerPool_TenantWeights_DequeuesProportionallyToWeight(t *testing.T) {
+    pool := NewPool(1, 20, 5*time.Second, httpclient.Config{})
+    pool.SetTenantQueueShare("X-Client-Id", 20)
+    pool.SetTenantWeights(map[string]int{"heavy": 3, "light": 1})
+
+    var mu sync.Mutex
+    var order []string
+    mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        mu.Lock()
+        order = append(order, r.Header.Get("X-Client-Id"))
+        mu.Unlock()
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer mockCollector.Close()
+
+    // Queue every job before Start so the first two weighted-scheduler
+    // rounds are deterministic: nothing has been dequeued yet
+    for i := 0; i < 6; i++ {
+        job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: http.Header{"X-Client-Id": {"heavy"}}}
+        if _, err := pool.SubmitJob(job); err != nil {
+            t.Fatalf("expected heavy tenant job %d to be admitted, got error: %v", i, err)
+        }
+    }
+    for i := 0; i < 6; i++ {
+        job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: http.Header{"X-Client-Id": {"light"}}}
+        if _, err := pool.SubmitJob(job); err != nil {
+            t.Fatalf("expected light tenant job %d to be admitted, got error: %v", i, err)
+        }
+    }
+
+    pool.Start()
+    defer pool.Stop()
+
+    time.Sleep(200 * time.Millisecond)
+
+    mu.Lock()
+    defer mu.Unlock()
+    // With weight 3:1 and both tenants still holding queued jobs, each of
+    // the first two rounds should dequeue 3 "heavy" jobs before a single
+    // "light" one
+    want := []string{"heavy", "heavy", "heavy", "light", "heavy", "heavy", "heavy", "light"}
+    if len(order) < len(want) {
+        t.Fatalf("expected at least %d deliveries, got %d: %v", len(want), len(order), order)
+    }
+    for i, tenant := range want {
+        if order[i] != tenant {
+            t.Errorf("delivery %d: expected tenant %q, got %q (full order: %v)", i, tenant, order[i], order)
+        }
+    }
+}
+
 // TestWorkerPool_ShutdownTimeout verifies timeout behavior
 func TestWorkerPool_ShutdownTimeout(t *testing.T) {
 	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -269,7 +859,7 @@ func TestWorkerPool_ShutdownTimeout(t *testing.T) {
 	defer mockCollector.Close()
 
 	// Create pool with short shutdown timeout (1 second)
-	pool := NewPool(2, 10, 1*time.Second)
+	pool := NewPool(2, 10, 1*time.Second, httpclient.Config{})
 	pool.Start()
 
 	// Submit long-running job
@@ -278,7 +868,7 @@ func TestWorkerPool_ShutdownTimeout(t *testing.T) {
 		Body:      []byte("test"),
 		Headers:   http.Header{},
 	}
-	_ = pool.SubmitJob(job)
+	_, _ = pool.SubmitJob(job)
 
 	// Give job time to start
 	time.Sleep(100 * time.Millisecond)
@@ -293,3 +883,509 @@ func TestWorkerPool_ShutdownTimeout(t *testing.T) {
 		t.Errorf("Stop() took %v, expected ~1s (timeout)", elapsed)
 	}
 }
+
+// TestWorkerPool_ShutdownSpill_DrainsQueuedJobsAndReplaysOnNextStart verifies
+// that jobs still sitting in the queue when shutdownTimeout is exceeded are
+// spilled to disk, and a fresh pool pointed at the same file replays and
+// delivers them on Start
+func TestWorkerPool_ShutdownSpill_DrainsQueuedJobsAndReplaysOnNextStart(t *testing.T) {
+	var delivered int32
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&delivered, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	release := make(chan struct{})
+	blockingCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer blockingCollector.Close()
+
+	spillFile := filepath.Join(t.TempDir(), "spill.json")
+
+	pool := NewPool(1, 10, 200*time.Millisecond, httpclient.Config{})
+	pool.SetSpillFile(spillFile)
+	pool.Start()
+
+	// Occupy the single worker so the jobs submitted below stay queued
+	if _, err := pool.SubmitJob(Job{TargetURL: blockingCollector.URL, Body: []byte("blocker"), Headers: http.Header{}}); err != nil {
+		t.Fatalf("submit blocker failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		if _, err := pool.SubmitJob(Job{TargetURL: mockCollector.URL, Body: []byte("queued"), Headers: http.Header{}}); err != nil {
+			t.Fatalf("submit %d failed: %v", i, err)
+		}
+	}
+
+	// Stop times out with the blocker still in flight, spilling the 3 jobs
+	// still sitting behind it in the queue
+	pool.Stop()
+	close(release)
+
+	if _, err := os.Stat(spillFile); err != nil {
+		t.Fatalf("expected spill file to exist after timed-out Stop: %v", err)
+	}
+
+	replay := NewPool(2, 10, 5*time.Second, httpclient.Config{})
+	replay.SetSpillFile(spillFile)
+	replay.Start()
+	defer replay.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&delivered) < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := atomic.LoadInt32(&delivered); got != 3 {
+		t.Errorf("expected all 3 spilled jobs to be replayed and delivered, got %d", got)
+	}
+	if _, err := os.Stat(spillFile); !os.IsNotExist(err) {
+		t.Errorf("expected spill file to be removed after replay, err=%v", err)
+	}
+}
+
+// TestWorkerPool_RetriesOnFailureThenSucceeds verifies a job that fails once
+// is retried and eventually delivered, per the configured retry policy
+func TestWorkerPool_RetriesOnFailureThenSucceeds(t *testing.T) {
+	var attempts int32
+
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond})
+	pool.Start()
+	defer pool.Stop()
+
+	job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: http.Header{}}
+	if _, err := pool.SubmitJob(job); err != nil {
+		t.Fatalf("failed to submit job: %v", err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Errorf("expected 2 attempts (1 failure + 1 retry that succeeds), got %d", got)
+	}
+}
+
+// TestWorkerPool_RetriesExhausted_DropsJob verifies a job is dropped after
+// exhausting all configured retry attempts against a persistently failing collector
+func TestWorkerPool_RetriesExhausted_DropsJob(t *testing.T) {
+	var attempts int32
+
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockCollector.Close()
+
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: 5 * time.Millisecond, MaxBackoff: 20 * time.Millisecond})
+	pool.Start()
+	defer pool.Stop()
+
+	job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: http.Header{}}
+	if _, err := pool.SubmitJob(job); err != nil {
+		t.Fatalf("failed to submit job: %v", err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("expected exactly 3 attempts (max_attempts=3), got %d", got)
+	}
+}
+
+// fakeDeadLetterWriter records jobs written to it, for testing without a real Store
+type fakeDeadLetterWriter struct {
+	mu       sync.Mutex
+	writes   []string
+}
+
+func (f *fakeDeadLetterWriter) Write(body []byte, targetURL string, headers http.Header, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.writes = append(f.writes, reason)
+	return nil
+}
+
+func (f *fakeDeadLetterWriter) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.writes)
+}
+
+// TestWorkerPool_DeadLettersJobAfterRetriesExhausted verifies a job that never
+// succeeds is handed to the configured DeadLetterWriter once retries are exhausted
+func TestWorkerPool_DeadLettersJobAfterRetriesExhausted(t *testing.T) {
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockCollector.Close()
+
+	dl := &fakeDeadLetterWriter{}
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: 5 * time.Millisecond})
+	pool.SetDeadLetterWriter(dl)
+	pool.Start()
+	defer pool.Stop()
+
+	job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: http.Header{}}
+	if _, err := pool.SubmitJob(job); err != nil {
+		t.Fatalf("failed to submit job: %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if dl.count() != 1 {
+		t.Errorf("expected 1 job written to dead-letter writer, got %d", dl.count())
+	}
+}
+
+// TestWorkerPool_DeadLettersJob_NonRetryable4xx_SkipsRetries verifies a 4xx
+// response is dead-lettered immediately without exhausting retry attempts
+func TestWorkerPool_DeadLettersJob_NonRetryable4xx_SkipsRetries(t *testing.T) {
+	var attempts int32
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer mockCollector.Close()
+
+	dl := &fakeDeadLetterWriter{}
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.SetRetryPolicy(RetryPolicy{MaxAttempts: 5, InitialBackoff: 5 * time.Millisecond})
+	pool.SetDeadLetterWriter(dl)
+	pool.Start()
+	defer pool.Stop()
+
+	job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: http.Header{}}
+	if _, err := pool.SubmitJob(job); err != nil {
+		t.Fatalf("failed to submit job: %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("expected exactly 1 attempt for non-retryable 4xx, got %d", got)
+	}
+	if dl.count() != 1 {
+		t.Errorf("expected 1 job written to dead-letter writer, got %d", dl.count())
+	}
+}
+
+// fakeDiskQueue is an in-memory stand-in for diskqueue.Queue, for testing
+// worker pool wiring without touching the filesystem
+type fakeDiskQueue struct {
+	mu      sync.Mutex
+	entries map[string]diskqueue.Entry
+	nextID  int
+}
+
+func newFakeDiskQueue() *fakeDiskQueue {
+	return &fakeDiskQueue{entries: make(map[string]diskqueue.Entry)}
+}
+
+func (f *fakeDiskQueue) Enqueue(body []byte, targetURL string, headers http.Header) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	id := fmt.Sprintf("%d", f.nextID)
+	f.entries[id] = diskqueue.Entry{ID: id, Body: body, TargetURL: targetURL, Headers: headers}
+	return id, nil
+}
+
+func (f *fakeDiskQueue) Complete(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.entries, id)
+	return nil
+}
+
+func (f *fakeDiskQueue) Replay() ([]diskqueue.Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entries := make([]diskqueue.Entry, 0, len(f.entries))
+	for _, e := range f.entries {
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (f *fakeDiskQueue) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.entries)
+}
+
+// TestWorkerPool_DiskQueue_RemovesEntryOnSuccessfulDelivery verifies a
+// successfully delivered job is removed from the disk-backed queue
+func TestWorkerPool_DiskQueue_RemovesEntryOnSuccessfulDelivery(t *testing.T) {
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	dq := newFakeDiskQueue()
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.SetDiskQueue(dq)
+	pool.Start()
+	defer pool.Stop()
+
+	job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: http.Header{}}
+	if _, err := pool.SubmitJob(job); err != nil {
+		t.Fatalf("failed to submit job: %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if dq.count() != 0 {
+		t.Errorf("expected job to be removed from disk queue after successful delivery, got %d entries left", dq.count())
+	}
+}
+
+// TestWorkerPool_DiskQueue_ReplaysEntriesOnStart verifies jobs left over on
+// disk from a previous restart are resubmitted when the pool starts
+func TestWorkerPool_DiskQueue_ReplaysEntriesOnStart(t *testing.T) {
+	var delivered int32
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&delivered, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	dq := newFakeDiskQueue()
+	if _, err := dq.Enqueue([]byte("leftover"), mockCollector.URL, http.Header{}); err != nil {
+		t.Fatalf("failed to seed disk queue: %v", err)
+	}
+
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.SetDiskQueue(dq)
+	pool.Start()
+	defer pool.Stop()
+
+	time.Sleep(200 * time.Millisecond)
+
+	if atomic.LoadInt32(&delivered) != 1 {
+		t.Errorf("expected replayed job to be delivered, got %d deliveries", delivered)
+	}
+	if dq.count() != 0 {
+		t.Errorf("expected replayed job to be removed from disk queue after delivery, got %d entries left", dq.count())
+	}
+}
+
+// TestWorkerPool_DiskQueue_StampsIdempotencyKeyOnReplay verifies a job
+// replayed from the disk-backed queue carries an X-Idempotency-Key header
+// equal to its disk queue entry id, so a crash-redelivered job is
+// distinguishable to the collector from a fresh one
+func TestWorkerPool_DiskQueue_StampsIdempotencyKeyOnReplay(t *testing.T) {
+	var gotHeader string
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Idempotency-Key")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	dq := newFakeDiskQueue()
+	id, err := dq.Enqueue([]byte("leftover"), mockCollector.URL, http.Header{})
+	if err != nil {
+		t.Fatalf("failed to seed disk queue: %v", err)
+	}
+
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.SetDiskQueue(dq)
+	pool.Start()
+	defer pool.Stop()
+
+	time.Sleep(200 * time.Millisecond)
+
+	if gotHeader != id {
+		t.Errorf("expected replayed job to carry X-Idempotency-Key %q, got %q", id, gotHeader)
+	}
+}
+
+// TestWorkerPool_HonorsRetryAfter_DelaysNextAttempt verifies a 429 with a
+// Retry-After header delays the retry by roughly that duration instead of
+// the retry policy's own (much shorter) backoff
+func TestWorkerPool_HonorsRetryAfter_DelaysNextAttempt(t *testing.T) {
+	var attempts int32
+	var mu sync.Mutex
+	var firstAttempt, secondAttempt time.Time
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			mu.Lock()
+			firstAttempt = time.Now()
+			mu.Unlock()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		mu.Lock()
+		secondAttempt = time.Now()
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+	pool.Start()
+	defer pool.Stop()
+
+	job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: http.Header{}}
+	if _, err := pool.SubmitJob(job); err != nil {
+		t.Fatalf("failed to submit job: %v", err)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Fatalf("expected exactly 2 attempts, got %d", got)
+	}
+	mu.Lock()
+	gap := secondAttempt.Sub(firstAttempt)
+	mu.Unlock()
+	if gap < 900*time.Millisecond {
+		t.Errorf("expected the retry to be delayed by roughly the 1s Retry-After, got gap %v", gap)
+	}
+}
+
+// TestWorkerPool_RetryAfterCooldown_AppliesToOtherPendingJobsForSameTarget
+// verifies a Retry-After observed by one job's attempt also delays a second,
+// independently-queued job for the same target
+func TestWorkerPool_RetryAfterCooldown_AppliesToOtherPendingJobsForSameTarget(t *testing.T) {
+	var attempts int32
+	var mu sync.Mutex
+	var throttledAt, secondJobDeliveredAt time.Time
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n == 1 {
+			mu.Lock()
+			throttledAt = time.Now()
+			mu.Unlock()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		mu.Lock()
+		secondJobDeliveredAt = time.Now()
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	pool := NewPool(2, 10, 5*time.Second, httpclient.Config{})
+	pool.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	pool.Start()
+	defer pool.Stop()
+
+	job := Job{TargetURL: mockCollector.URL, Body: []byte("test"), Headers: http.Header{}}
+	if _, err := pool.SubmitJob(job); err != nil {
+		t.Fatalf("failed to submit first job: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, err := pool.SubmitJob(job); err != nil {
+		t.Fatalf("failed to submit second job: %v", err)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Fatalf("expected exactly 2 attempts across both jobs, got %d", got)
+	}
+	mu.Lock()
+	gap := secondJobDeliveredAt.Sub(throttledAt)
+	mu.Unlock()
+	if gap < 900*time.Millisecond {
+		t.Errorf("expected the second job's delivery to wait out the first job's Retry-After cooldown, got gap %v", gap)
+	}
+}
+
+// TestParseRetryAfter_DeltaSeconds verifies the numeric delta-seconds form
+func TestParseRetryAfter_DeltaSeconds(t *testing.T) {
+	d, ok := parseRetryAfter("120")
+	if !ok || d != 120*time.Second {
+		t.Errorf("expected 120s, got %v (ok=%v)", d, ok)
+	}
+}
+
+// TestParseRetryAfter_HTTPDate verifies the HTTP-date form
+func TestParseRetryAfter_HTTPDate(t *testing.T) {
+	future := time.Now().Add(2 * time.Minute).UTC()
+	d, ok := parseRetryAfter(future.Format(http.TimeFormat))
+	if !ok {
+		t.Fatal("expected an HTTP-date Retry-After to parse")
+	}
+	if d <= 0 || d > 2*time.Minute+time.Second {
+		t.Errorf("expected roughly 2m, got %v", d)
+	}
+}
+
+// TestParseRetryAfter_Empty_ReturnsFalse verifies an absent header is reported as such
+func TestParseRetryAfter_Empty_ReturnsFalse(t *testing.T) {
+	if _, ok := parseRetryAfter(""); ok {
+		t.Error("expected an empty header to report ok=false")
+	}
+}
+
+// TestParseRetryAfter_Invalid_ReturnsFalse verifies a malformed value doesn't panic or parse
+func TestParseRetryAfter_Invalid_ReturnsFalse(t *testing.T) {
+	if _, ok := parseRetryAfter("not-a-valid-value"); ok {
+		t.Error("expected an invalid header to report ok=false")
+	}
+}
+
+// TestWorkerPool_JobTimeout_AbortsBeforeClientTimeout verifies that a job
+// with Timeout set is aborted once that timeout elapses, rather than waiting
+// out the much longer timeout baked into the pool's shared *http.Client
+func TestWorkerPool_JobTimeout_AbortsBeforeClientTimeout(t *testing.T) {
+	mockCollector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Second)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockCollector.Close()
+
+	pool := NewPool(1, 10, 5*time.Second, httpclient.Config{})
+	pool.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	tracker := jobtracking.NewTracker(10)
+	pool.SetJobTracker(tracker)
+	pool.Start()
+	defer pool.Stop()
+
+	start := time.Now()
+	jobID, err := pool.SubmitJob(Job{
+		TargetURL: mockCollector.URL,
+		Body:      []byte("test"),
+		Headers:   http.Header{},
+		Timeout:   50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error submitting job: %v", err)
+	}
+
+	deadline := time.After(1 * time.Second)
+	for {
+		if record, ok := tracker.Get(jobID); ok && record.Status == jobtracking.StatusFailed {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("expected the job to be marked failed well before the collector's 2s response")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 1*time.Second {
+		t.Errorf("expected the job's 50ms Timeout to abort delivery quickly, took %v", elapsed)
+	}
+}
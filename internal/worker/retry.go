@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy configures how a failed job is retried before being dropped
+// A MaxAttempts of 1 (the default) preserves the original drop-on-first-failure behavior
+type RetryPolicy struct {
+	MaxAttempts     int           // Total attempts including the first, before giving up (1 = no retries)
+	InitialBackoff  time.Duration // Delay before the first retry
+	MaxBackoff      time.Duration // Backoff is capped at this value
+	JitterFraction  float64       // Randomize each backoff by ±JitterFraction (0.0-1.0) to avoid thundering herd
+}
+
+// DefaultRetryPolicy disables retries, matching the pool's original behavior
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// backoffFor returns the delay to wait before the given retry attempt (1-indexed:
+// attempt 1 is the delay before the first retry, i.e. after the initial failure)
+func (r RetryPolicy) backoffFor(attempt int) time.Duration {
+	backoff := r.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if r.MaxBackoff > 0 && backoff > r.MaxBackoff {
+			backoff = r.MaxBackoff
+			break
+		}
+	}
+	if r.MaxBackoff > 0 && backoff > r.MaxBackoff {
+		backoff = r.MaxBackoff
+	}
+
+	if r.JitterFraction <= 0 {
+		return backoff
+	}
+	jitterRange := float64(backoff) * r.JitterFraction
+	delta := (rand.Float64()*2 - 1) * jitterRange
+	jittered := time.Duration(float64(backoff) + delta)
+	if jittered < 0 {
+		jittered = 0
+	}
+	return jittered
+}
+
+// shouldRetry reports whether attempt (the number of attempts made so far)
+// leaves attempts remaining under MaxAttempts
+func (r RetryPolicy) shouldRetry(attempt int) bool {
+	return attempt < r.MaxAttempts
+}
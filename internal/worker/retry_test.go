@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+
+	if !policy.shouldRetry(1) {
+		t.Error("expected retry after attempt 1 of 3")
+	}
+	if !policy.shouldRetry(2) {
+		t.Error("expected retry after attempt 2 of 3")
+	}
+	if policy.shouldRetry(3) {
+		t.Error("expected no retry after final attempt 3 of 3")
+	}
+}
+
+func TestDefaultRetryPolicy_NeverRetries(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	if policy.shouldRetry(1) {
+		t.Error("expected default policy to never retry")
+	}
+}
+
+func TestRetryPolicy_BackoffFor_ExponentialGrowthCappedAtMax(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+
+	if got := policy.backoffFor(1); got != 100*time.Millisecond {
+		t.Errorf("expected first backoff 100ms, got %v", got)
+	}
+	if got := policy.backoffFor(2); got != 200*time.Millisecond {
+		t.Errorf("expected second backoff 200ms, got %v", got)
+	}
+	if got := policy.backoffFor(3); got != 300*time.Millisecond {
+		t.Errorf("expected third backoff capped at 300ms, got %v", got)
+	}
+	if got := policy.backoffFor(10); got != 300*time.Millisecond {
+		t.Errorf("expected backoff to stay capped at 300ms, got %v", got)
+	}
+}
+
+func TestRetryPolicy_BackoffFor_JitterStaysNonNegative(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 10 * time.Millisecond, JitterFraction: 1.0}
+
+	for i := 0; i < 50; i++ {
+		if got := policy.backoffFor(1); got < 0 {
+			t.Fatalf("expected non-negative jittered backoff, got %v", got)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"io"
 	"log"
 	"os"
 )
@@ -32,3 +33,12 @@ func Fatal(format string, v ...interface{}) {
 	fatalLogger.Printf(format, v...)
 	os.Exit(1)
 }
+
+// SetOutput redirects all four log levels to w instead of the default
+// stdout/stderr, so tests and embedders can capture or discard log output.
+func SetOutput(w io.Writer) {
+	infoLogger.SetOutput(w)
+	warnLogger.SetOutput(w)
+	errorLogger.SetOutput(w)
+	fatalLogger.SetOutput(w)
+}